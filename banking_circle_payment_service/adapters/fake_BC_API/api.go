@@ -5,6 +5,7 @@ import (
 	"time"
 
 	models2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
 	spe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 
@@ -36,6 +37,20 @@ func (f *FakeBankingCircleAPI) RequestPayment(_ context.Context, request spe.Req
 	}
 }
 
+func (f *FakeBankingCircleAPI) RequestBulkPayment(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error) {
+	response := bpe.ResponseDto{BulkID: "456"}
+	for _, payment := range request.Payments {
+		result, _ := f.RequestPayment(ctx, payment.RequestDto, slice)
+		response.Payments = append(response.Payments, bpe.PaymentResultDto{
+			ClientReference:  payment.ClientReference,
+			PaymentID:        result.PaymentID,
+			Status:           result.Status,
+			BankingReference: models.BankingReference(payment.DebtorReference),
+		})
+	}
+	return response, nil
+}
+
 func (f *FakeBankingCircleAPI) CheckPaymentStatus(paymentInstructionID models.ProviderPaymentID) (ports.PaymentStatus, error) {
 	switch paymentInstructionID {
 	case accountNumberThatWillGetRejected:
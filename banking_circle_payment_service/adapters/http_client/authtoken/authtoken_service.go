@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -16,13 +18,21 @@ import (
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
+// AuthTokenService hands out the Banking Circle access token to the workers calling Banking Circle concurrently. The token
+// is refreshed in the background before it expires, a single request refreshes it whoever asks for it meanwhile.
 type AuthTokenService struct {
-	currentAuthToken string
-	authConfig       AuthConfig
-	httpClient       *http.Client
-	metricsClient    ports.MetricsClient
-	timer            *time.Timer
-	authURL          string
+	authConfig    AuthConfig
+	httpClient    *http.Client
+	metricsClient ports.MetricsClient
+	authURL       string
+
+	mu              sync.Mutex
+	token           string
+	issuedAt        time.Time
+	expiresAt       time.Time
+	inflight        *tokenRefresh
+	timer           *time.Timer
+	failedRefreshes int
 }
 
 type AuthConfig struct {
@@ -30,7 +40,13 @@ type AuthConfig struct {
 	APIUsername               string
 	APIPassword               string
 	TokenIntervalBeforeExpire time.Duration
-	Scheduler                 Scheduler
+	// RefreshJitter is the most the background refresh is brought forward by, so the instances don't all refresh at once.
+	RefreshJitter time.Duration
+	// RefreshRetryDelay is how long a failed background refresh waits to be tried again, doubling with every failure
+	// up to MaxRefreshRetryDelay.
+	RefreshRetryDelay    time.Duration
+	MaxRefreshRetryDelay time.Duration
+	Scheduler            Scheduler
 }
 
 type AuthResponseDto struct {
@@ -38,6 +54,12 @@ type AuthResponseDto struct {
 	ExpiresIn   string `json:"expires_in"`
 }
 
+// tokenRefresh is a request for a new token, the callers asking for a token while it runs wait for its outcome.
+type tokenRefresh struct {
+	done chan struct{}
+	err  error
+}
+
 func NewAuthTokenService(
 	authConfig AuthConfig,
 	client *http.Client,
@@ -46,45 +68,117 @@ func NewAuthTokenService(
 	if authConfig.Scheduler == nil {
 		authConfig.Scheduler = SchedulerFunc(time.AfterFunc)
 	}
-
-	authToken := AuthTokenService{
-		currentAuthToken: "",
-		authConfig:       authConfig,
-		httpClient:       client,
-		metricsClient:    metrics,
-		timer:            time.NewTimer(time.Second),
-		authURL:          authConfig.AuthorizationBaseURL + "/authorizations/authorize",
+	if authConfig.RefreshRetryDelay <= 0 {
+		authConfig.RefreshRetryDelay = defaultRefreshRetryDelay
+	}
+	if authConfig.MaxRefreshRetryDelay <= 0 {
+		authConfig.MaxRefreshRetryDelay = defaultMaxRefreshRetryDelay
 	}
 
-	return &authToken
+	return &AuthTokenService{
+		authConfig:    authConfig,
+		httpClient:    client,
+		metricsClient: metrics,
+		authURL:       authConfig.AuthorizationBaseURL + "/authorizations/authorize",
+	}
 }
 
 const (
 	clientResponseMetricName = "app_http_client_resp_time_ms"
+	tokenAgeMetricName       = "app_http_client_auth_token_age_sec"
+	tokenRefreshMetricName   = "app_http_client_auth_token_refresh"
+
+	defaultRefreshRetryDelay    = 5 * time.Second
+	defaultMaxRefreshRetryDelay = 2 * time.Minute
 )
 
+// GetAccessToken only asks Banking Circle for a token when there is none yet, or the one it has expired.
 func (b *AuthTokenService) GetAccessToken(ctx context.Context) (string, error) {
-	if b.currentAuthToken == "" {
-		err := b.requestToken(ctx)
-		if err != nil {
-			return "", errors.Wrap(err, "failed to authorize in the background")
+	token, issuedAt, valid := b.current()
+	if !valid {
+		if err := b.refresh(ctx, time.Now()); err != nil {
+			return "", errors.Wrap(err, "failed to authorize")
 		}
+		token, issuedAt, _ = b.current()
 	}
 
-	return b.currentAuthToken, nil
+	b.metricsClient.Histogram(ctx, tokenAgeMetricName, time.Since(issuedAt).Seconds(), []string{"banking_circle"})
+	return token, nil
 }
 
-func (b *AuthTokenService) requestToken(ctx context.Context) error {
-	err := b.authorize(ctx)
-	if err != nil {
-		b.currentAuthToken = ""
-		zapctx.Warn(ctx, "failed to request token", zap.Error(err))
-		return err
+// RefreshAccessToken replaces the token Banking Circle turned down a request started at rejectedSince with, a token
+// issued after the request started is kept, it was refreshed already.
+func (b *AuthTokenService) RefreshAccessToken(ctx context.Context, rejectedSince time.Time) error {
+	return b.refresh(ctx, rejectedSince)
+}
+
+func (b *AuthTokenService) current() (string, time.Time, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.token, b.issuedAt, b.token != "" && time.Now().Before(b.expiresAt)
+}
+
+// refresh gets a new token unless the one there is was issued after since, the callers asking meanwhile share the request.
+func (b *AuthTokenService) refresh(ctx context.Context, since time.Time) error {
+	b.mu.Lock()
+	if b.token != "" && b.issuedAt.After(since) && time.Now().Before(b.expiresAt) {
+		b.mu.Unlock()
+		return nil
 	}
+	if call := b.inflight; call != nil {
+		b.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	call := &tokenRefresh{done: make(chan struct{})}
+	b.inflight = call
+	b.mu.Unlock()
+
+	call.err = b.authorize(ctx)
 
+	b.mu.Lock()
+	b.inflight = nil
+	b.mu.Unlock()
+	close(call.done)
+
+	if call.err != nil {
+		zapctx.Warn(ctx, "failed to request token", zap.Error(call.err))
+		b.metricsClient.Count(ctx, tokenRefreshMetricName, 1, []string{"banking_circle", "failure"})
+		return call.err
+	}
+	b.metricsClient.Count(ctx, tokenRefreshMetricName, 1, []string{"banking_circle", "success"})
 	return nil
 }
 
+// refreshInBackground keeps the token it has while Banking Circle can't give a new one, it may still be valid.
+func (b *AuthTokenService) refreshInBackground() {
+	if err := b.refresh(context.Background(), time.Now()); err != nil {
+		b.mu.Lock()
+		b.failedRefreshes++
+		delay := b.authConfig.RefreshRetryDelay
+		for i := 1; i < b.failedRefreshes && delay < b.authConfig.MaxRefreshRetryDelay; i++ {
+			delay *= 2
+		}
+		if delay > b.authConfig.MaxRefreshRetryDelay {
+			delay = b.authConfig.MaxRefreshRetryDelay
+		}
+		b.scheduleRefresh(delay)
+		b.mu.Unlock()
+	}
+}
+
+// scheduleRefresh replaces the refresh scheduled before, it must be called holding the lock.
+func (b *AuthTokenService) scheduleRefresh(after time.Duration) {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.timer = b.authConfig.Scheduler.AfterFunc(after, b.refreshInBackground)
+}
+
 func (b *AuthTokenService) authorize(ctx context.Context) error {
 	authConfig := b.authConfig
 	req, err := http.NewRequest(http.MethodGet, b.authURL, nil)
@@ -94,7 +188,6 @@ func (b *AuthTokenService) authorize(ctx context.Context) error {
 	req.SetBasicAuth(authConfig.APIUsername, authConfig.APIPassword)
 
 	startTime := time.Now()
-
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
 		return errors.Wrap(err, "failed to request a new token")
@@ -121,22 +214,26 @@ func (b *AuthTokenService) authorize(ctx context.Context) error {
 		return errors.Wrap(err, "failed to unmarshal the response")
 	}
 
-	b.currentAuthToken = authResp.AccessToken
-
 	tokenExpirationSeconds, err := strconv.ParseInt(authResp.ExpiresIn, 10, 64)
 	if err != nil {
 		return errors.Wrap(err, "failed to convert expiration time")
 	}
+	expiresIn := time.Second * time.Duration(tokenExpirationSeconds)
 
-	nextAuthorizationAfterDuration := time.Second * time.Duration(tokenExpirationSeconds)
-	nextAuthorizationAfterDuration -= b.authConfig.TokenIntervalBeforeExpire
-	if nextAuthorizationAfterDuration < 0 {
-		return errors.Wrap(err, "next authorization schedule can not be negative duration")
+	nextAuthorizationAfterDuration := expiresIn - b.authConfig.TokenIntervalBeforeExpire
+	if nextAuthorizationAfterDuration <= 0 {
+		nextAuthorizationAfterDuration = expiresIn / 2
+	}
+	if jitter := b.authConfig.RefreshJitter; jitter > 0 && jitter < nextAuthorizationAfterDuration {
+		nextAuthorizationAfterDuration -= time.Duration(rand.Int63n(int64(jitter)))
 	}
 
-	b.authConfig.Scheduler.AfterFunc(nextAuthorizationAfterDuration, func() {
-		_ = b.requestToken(context.Background())
-	})
-
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.token = authResp.AccessToken
+	b.issuedAt = time.Now()
+	b.expiresAt = startTime.Add(expiresIn)
+	b.failedRefreshes = 0
+	b.scheduleRefresh(nextAuthorizationAfterDuration)
 	return nil
 }
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -165,4 +167,116 @@ func TestAuthTokenService_GetAccessToken(t *testing.T) {
 		is.True(err != nil)
 		is.Equal(actualToken, "")
 	})
+
+	t.Run("workers asking for a token at once share a single request for it", func(t *testing.T) {
+		var (
+			is        = is.New(t)
+			ctx       = context.Background()
+			authCalls int32
+		)
+
+		authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&authCalls, 1)
+			time.Sleep(50 * time.Millisecond)
+			_ = json.NewEncoder(w).Encode(firstAuthResponse)
+		}))
+		defer authServer.Close()
+
+		authService := authtoken.NewAuthTokenService(authtoken.AuthConfig{
+			AuthorizationBaseURL:      authServer.URL,
+			TokenIntervalBeforeExpire: TokenIntervalBeforeExpire,
+			Scheduler:                 &mocks.SchedulerMock{AfterFuncFunc: func(time.Duration, func()) *time.Timer { return time.NewTimer(time.Hour) }},
+		}, httpClient, testdoubles.DummyMetricsClient{})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				token, err := authService.GetAccessToken(ctx)
+				is.NoErr(err)
+				is.Equal(token, firstAuthResponse.AccessToken)
+			}()
+		}
+		wg.Wait()
+
+		is.Equal(atomic.LoadInt32(&authCalls), int32(1))
+	})
+
+	t.Run("a token turned down is only refreshed when it wasn't refreshed since the request started", func(t *testing.T) {
+		var (
+			is        = is.New(t)
+			ctx       = context.Background()
+			authCalls int
+		)
+
+		authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authCalls++
+			_ = json.NewEncoder(w).Encode(firstAuthResponse)
+		}))
+		defer authServer.Close()
+
+		authService := authtoken.NewAuthTokenService(authtoken.AuthConfig{
+			AuthorizationBaseURL:      authServer.URL,
+			TokenIntervalBeforeExpire: TokenIntervalBeforeExpire,
+			Scheduler:                 &mocks.SchedulerMock{AfterFuncFunc: func(time.Duration, func()) *time.Timer { return time.NewTimer(time.Hour) }},
+		}, httpClient, testdoubles.DummyMetricsClient{})
+
+		requestStarted := time.Now()
+		_, err := authService.GetAccessToken(ctx)
+		is.NoErr(err)
+
+		is.NoErr(authService.RefreshAccessToken(ctx, requestStarted))
+		is.Equal(authCalls, 1) // the token was issued after the request started
+
+		is.NoErr(authService.RefreshAccessToken(ctx, time.Now()))
+		is.Equal(authCalls, 2)
+	})
+
+	t.Run("a failed background refresh keeps the token, and is tried again with a backoff", func(t *testing.T) {
+		var (
+			is         = is.New(t)
+			ctx        = context.Background()
+			authFails  bool
+			refreshes  []func()
+			scheduling []time.Duration
+		)
+
+		authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authFails {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(firstAuthResponse)
+		}))
+		defer authServer.Close()
+
+		authService := authtoken.NewAuthTokenService(authtoken.AuthConfig{
+			AuthorizationBaseURL:      authServer.URL,
+			TokenIntervalBeforeExpire: TokenIntervalBeforeExpire,
+			RefreshJitter:             10 * time.Second,
+			RefreshRetryDelay:         time.Second,
+			MaxRefreshRetryDelay:      3 * time.Second,
+			Scheduler: &mocks.SchedulerMock{AfterFuncFunc: func(d time.Duration, f func()) *time.Timer {
+				scheduling = append(scheduling, d)
+				refreshes = append(refreshes, f)
+				return time.NewTimer(time.Hour)
+			}},
+		}, httpClient, testdoubles.DummyMetricsClient{})
+
+		_, err := authService.GetAccessToken(ctx)
+		is.NoErr(err)
+		refreshAt := time.Second * time.Duration(expiresIn-int(TokenIntervalBeforeExpire.Seconds()))
+		is.True(scheduling[0] <= refreshAt && scheduling[0] > refreshAt-10*time.Second) // refresh brought forward by the jitter
+
+		authFails = true
+		for i := 0; i < 3; i++ {
+			refreshes[len(refreshes)-1]()
+		}
+		is.Equal(scheduling[1:], []time.Duration{time.Second, 2 * time.Second, 3 * time.Second})
+
+		token, err := authService.GetAccessToken(ctx)
+		is.NoErr(err)
+		is.Equal(token, firstAuthResponse.AccessToken)
+	})
 }
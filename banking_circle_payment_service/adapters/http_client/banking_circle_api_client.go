@@ -1,4 +1,5 @@
 //go:generate moq -out mocks/http_doer_moq.go -pkg=mocks . HTTPDoer
+//go:generate moq -out mocks/access_token_refresher_moq.go -pkg=mocks . AccessTokenRefresher
 
 package http_client
 
@@ -16,6 +17,7 @@ import (
 	"github.com/google/uuid"
 
 	models2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
 	ports2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 
@@ -27,12 +29,18 @@ type BankingCircleAPIClient struct {
 	baseURL    string
 	httpClient HTTPDoer
 	observer   *bcClientObserver
+	tokens     AccessTokenRefresher
 }
 
 type HTTPDoer interface {
 	Do(r *http.Request) (*http.Response, error)
 }
 
+type AccessTokenRefresher interface {
+	// RefreshAccessToken replaces the token Banking Circle turned down a request started at rejectedSince with.
+	RefreshAccessToken(ctx context.Context, rejectedSince time.Time) error
+}
+
 type AuthResponseDto struct {
 	AccessToken string `json:"access_token"`
 	ExpiresIn   string `json:"expires_in"`
@@ -48,6 +56,11 @@ func NewAPIClient(client HTTPDoer, baseURL string, metricsClient ports.MetricsCl
 	}, nil
 }
 
+// RefreshTokenOnUnauthorised has a request Banking Circle answers with 401 sent once more, with a new token.
+func (b *BankingCircleAPIClient) RefreshTokenOnUnauthorised(tokens AccessTokenRefresher) {
+	b.tokens = tokens
+}
+
 func (b *BankingCircleAPIClient) RequestPayment(ctx context.Context, request spe.RequestDto, slice *[]string) (spe.ResponseDto, error) {
 	url := b.baseURL + "/payments/singles"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(request.ToJSON()))
@@ -86,6 +99,38 @@ func (b *BankingCircleAPIClient) RequestPayment(ctx context.Context, request spe
 	}
 }
 
+func (b *BankingCircleAPIClient) RequestBulkPayment(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error) {
+	url := b.baseURL + "/payments/bulk"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(request.ToJSON()))
+	if err != nil {
+		return bpe.ResponseDto{}, err
+	}
+
+	uniqueID := uuid.NewString()
+	*slice = append(*slice, uniqueID)
+	req.Header.Add("X-Request-ID", uniqueID)
+
+	body, status, err := b.getAndObserveResponse(req, "make_bulk_payment")
+	if err != nil {
+		return bpe.ResponseDto{}, err
+	}
+
+	switch status {
+	case http.StatusUnauthorized:
+		return bpe.ResponseDto{}, UnauthorisedWithBankingCircleError{URL: url, ResponseBody: string(body), UniqueID: UniqueID(uniqueID)}
+	case http.StatusCreated:
+		return bpe.NewResponseDTOFromJSON(body, request)
+	default:
+		return bpe.ResponseDto{}, UnrecognisedBankingCircleError{
+			URL:      url,
+			Action:   RequestBulkPayment,
+			Status:   status,
+			Body:     string(body),
+			UniqueID: UniqueID(uniqueID),
+		}
+	}
+}
+
 func (b *BankingCircleAPIClient) CheckPaymentStatus(paymentRequestID models.ProviderPaymentID) (ports2.PaymentStatus, error) {
 	url := fmt.Sprintf("%s/payments/singles/%s/status", b.baseURL, paymentRequestID)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -195,6 +240,25 @@ func (b *BankingCircleAPIClient) CheckAccountBalance(accountID string) (models2.
 
 func (b *BankingCircleAPIClient) getAndObserveResponse(req *http.Request, operation string) ([]byte, int, error) {
 	startTime := time.Now()
+	body, status, err := b.doAndObserve(req, operation, startTime)
+	if err != nil || status != http.StatusUnauthorized || b.tokens == nil {
+		return body, status, err
+	}
+
+	if err := b.tokens.RefreshAccessToken(req.Context(), startTime); err != nil {
+		b.observer.CouldntRefreshToken(req.Context(), operation, err)
+		return body, status, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return body, status, nil
+		}
+	}
+	return b.doAndObserve(retry, operation, time.Now())
+}
+
+func (b *BankingCircleAPIClient) doAndObserve(req *http.Request, operation string, startTime time.Time) ([]byte, int, error) {
 
 	resp, err := b.httpClient.Do(b.addHTTPTracing(req))
 	if err != nil {
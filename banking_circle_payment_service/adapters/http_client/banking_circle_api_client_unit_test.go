@@ -6,15 +6,18 @@ package http_client_test
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client/mocks"
 	models2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
@@ -120,6 +123,62 @@ func TestBankingCircleApiClient_RequestPayment(t *testing.T) {
 		is.Equal(actualResponse, emptyResponse)
 	})
 
+	t.Run("when bc returns unauthorised the token is refreshed and the request sent once more", func(t *testing.T) {
+		var (
+			ctx           = context.Background()
+			is            = is.New(t)
+			requestBodies []string
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requestBodies = append(requestBodies, string(body))
+			if len(requestBodies) == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			resAsJSON, _ := single_payment_endpoint.ResponseDto{PaymentID: "123"}.ToJSON()
+			_, _ = w.Write(resAsJSON)
+		}))
+		defer server.Close()
+
+		spyTokens := &mocks.AccessTokenRefresherMock{RefreshAccessTokenFunc: func(context.Context, time.Time) error { return nil }}
+		bankingCircleAPIClient := makeClientConfiguredTo(server.URL)
+		bankingCircleAPIClient.RefreshTokenOnUnauthorised(spyTokens)
+
+		actualResponse, err := bankingCircleAPIClient.RequestPayment(ctx, emptyRequest, &dummySlice)
+
+		is.NoErr(err)
+		is.Equal(actualResponse.PaymentID, models.ProviderPaymentID("123"))
+		is.Equal(len(spyTokens.RefreshAccessTokenCalls()), 1)
+		is.Equal(len(requestBodies), 2)
+		is.Equal(requestBodies[1], requestBodies[0]) // the payment is sent again as it was
+	})
+
+	t.Run("when bc returns unauthorised with the refreshed token too an error is returned", func(t *testing.T) {
+		var (
+			ctx      = context.Background()
+			is       = is.New(t)
+			requests int
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		bankingCircleAPIClient := makeClientConfiguredTo(server.URL)
+		bankingCircleAPIClient.RefreshTokenOnUnauthorised(&mocks.AccessTokenRefresherMock{RefreshAccessTokenFunc: func(context.Context, time.Time) error { return nil }})
+
+		_, err := bankingCircleAPIClient.RequestPayment(ctx, emptyRequest, &dummySlice)
+
+		_, isUnauthorisedErr := err.(http_client.UnauthorisedWithBankingCircleError)
+		is.True(isUnauthorisedErr)
+		is.Equal(requests, 2)
+	})
+
 	t.Run("when bc returns 400 return invalid payment error", func(t *testing.T) {
 		var (
 			ctx = context.Background()
@@ -192,6 +251,81 @@ func TestBankingCircleApiClient_RequestPayment(t *testing.T) {
 	})
 }
 
+func TestBankingCircleApiClient_RequestBulkPayment(t *testing.T) {
+	var (
+		dummyMetrics = testdoubles.DummyMetricsClient{}
+		request      = bulk_payment_endpoint.RequestDto{
+			Payments: []bulk_payment_endpoint.PaymentDto{{
+				ClientReference: "payment-instruction-id",
+				RequestDto:      single_payment_endpoint.RequestDto{DebtorReference: "Settlm 9876862 20210525"},
+			}},
+		}
+
+		makeClientConfiguredTo = func(baseURL string) *http_client.BankingCircleAPIClient {
+			bankingCircleAPIClient, _ := http_client.NewAPIClient(&http.Client{}, baseURL, dummyMetrics)
+			return bankingCircleAPIClient
+		}
+	)
+
+	t.Run("successful request to api", func(t *testing.T) {
+		var (
+			ctx             = context.Background()
+			is              = is.New(t)
+			uids            = make([]string, 0)
+			requestedPath   string
+			requestedMethod string
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"bulkId":"bulk-id","payments":[{"clientReference":"payment-instruction-id","paymentId":"123","status":"PendingProcessing"}]}`))
+			requestedPath = r.URL.Path
+			requestedMethod = r.Method
+		}))
+		defer server.Close()
+
+		bankingCircleAPIClient := makeClientConfiguredTo(server.URL)
+
+		actualResponse, err := bankingCircleAPIClient.RequestBulkPayment(ctx, request, &uids)
+
+		is.NoErr(err)
+		is.Equal(actualResponse, bulk_payment_endpoint.ResponseDto{
+			BulkID: "bulk-id",
+			Payments: []bulk_payment_endpoint.PaymentResultDto{{
+				ClientReference:  "payment-instruction-id",
+				PaymentID:        "123",
+				Status:           "PendingProcessing",
+				BankingReference: "Settlm 9876862 20210525",
+			}},
+		})
+		is.Equal(requestedPath, "/payments/bulk")
+		is.Equal(requestedMethod, http.MethodPost)
+		is.Equal(len(uids), 1)
+	})
+
+	t.Run("fails for an unexpected reason", func(t *testing.T) {
+		var (
+			ctx  = context.Background()
+			is   = is.New(t)
+			uids = make([]string, 0)
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("bad bulk"))
+		}))
+		defer server.Close()
+
+		bankingCircleAPIClient := makeClientConfiguredTo(server.URL)
+
+		_, err := bankingCircleAPIClient.RequestBulkPayment(ctx, request, &uids)
+
+		unrecognisedErr, isUnrecognisedErr := err.(http_client.UnrecognisedBankingCircleError)
+		is.True(isUnrecognisedErr)
+		is.Equal(unrecognisedErr.Status, http.StatusBadRequest)
+		is.Equal(unrecognisedErr.Action, http_client.RequestBulkPayment)
+	})
+}
+
 func TestBankingCircleApiClient_CheckPaymentStatus(t *testing.T) {
 	var (
 		dummyMetrics  = testdoubles.DummyMetricsClient{}
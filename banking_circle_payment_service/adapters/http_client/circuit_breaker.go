@@ -0,0 +1,283 @@
+package http_client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	models2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
+	spe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	ports2 "github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+
+	circuitStateMetricName      = "app_http_client_circuit_state"
+	circuitTransitionMetricName = "app_http_client_circuit_transition"
+	circuitRejectedMetricName   = "app_http_client_circuit_rejected"
+
+	defaultFailureThreshold = 5
+	defaultOpenFor          = time.Minute
+	defaultProbesToClose    = 2
+)
+
+type CircuitBreakerOptions struct {
+	// FailureThreshold is how many 5xx responses or timeouts in a row open the circuit.
+	FailureThreshold int
+	// OpenFor is how long the circuit stays open before it half-opens and probes Banking Circle.
+	OpenFor time.Duration
+	// ProbesToClose is how many probes in a row have to succeed for a half-open circuit to close.
+	ProbesToClose int
+	// Probe is a request to Banking Circle that doesn't change anything on its side.
+	Probe func(ctx context.Context) error
+	Now   func() time.Time
+}
+
+// CircuitBreaker stops the requests to Banking Circle once it keeps failing with server errors or timeouts, so the
+// payments aren't failed one after another while it is down. Once the circuit has been open for a while, it is half-opened
+// and only probe requests are sent until enough of them succeed, payments are only requested again on a closed circuit.
+type CircuitBreaker struct {
+	options CircuitBreakerOptions
+	metrics ports2.MetricsClient
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	probes   int
+	openedAt time.Time
+	probing  bool
+}
+
+var (
+	_ ports.BankingCircleCircuit = &CircuitBreaker{}
+	_ ports.BankingCircleAPI     = &CircuitBreakingBankingCircleClient{}
+)
+
+func NewCircuitBreaker(options CircuitBreakerOptions, metrics ports2.MetricsClient) *CircuitBreaker {
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = defaultFailureThreshold
+	}
+	if options.OpenFor <= 0 {
+		options.OpenFor = defaultOpenFor
+	}
+	if options.ProbesToClose <= 0 {
+		options.ProbesToClose = defaultProbesToClose
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	breaker := &CircuitBreaker{
+		options: options,
+		metrics: metrics,
+		state:   CircuitClosed,
+	}
+	breaker.publishState(context.Background())
+	return breaker
+}
+
+func (c *CircuitBreaker) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Closed probes Banking Circle once the circuit has been open for long enough, a single caller probes at a time and
+// the others are told the circuit isn't closed yet.
+func (c *CircuitBreaker) Closed(ctx context.Context) bool {
+	c.mu.Lock()
+	switch {
+	case c.state == CircuitClosed:
+		c.mu.Unlock()
+		return true
+	case c.probing, c.state == CircuitOpen && c.options.Now().Sub(c.openedAt) < c.options.OpenFor:
+		c.mu.Unlock()
+		return false
+	case c.state == CircuitOpen:
+		c.transition(ctx, CircuitHalfOpen)
+	}
+	c.probing = true
+	c.mu.Unlock()
+
+	var err error
+	if c.options.Probe != nil {
+		err = c.options.Probe(ctx)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probing = false
+	if err != nil && countsAsOutage(err) {
+		zapctx.Warn(ctx, "[CircuitBreaker] (Closed) Banking Circle probe failed, the circuit opens again", zap.Error(err))
+		c.open(ctx)
+		return false
+	}
+
+	c.probes++
+	if c.probes < c.options.ProbesToClose {
+		return false
+	}
+	c.failures = 0
+	c.transition(ctx, CircuitClosed)
+	return true
+}
+
+// Ping makes an open circuit show in the health check, a half-open one is already on its way to recovery.
+func (c *CircuitBreaker) Ping(_ context.Context) error {
+	if c.State() == CircuitOpen {
+		return CircuitOpenError{}
+	}
+	return nil
+}
+
+// allow rejects the requests of a circuit that isn't closed, the probes of a half-open circuit don't go through it.
+func (c *CircuitBreaker) allow(ctx context.Context, operation string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == CircuitClosed {
+		return nil
+	}
+	c.metrics.Count(ctx, circuitRejectedMetricName, 1, []string{"banking_circle", operation})
+	return CircuitOpenError{State: c.state}
+}
+
+// record counts the 5xx responses and timeouts in a row, any other outcome shows Banking Circle is up.
+func (c *CircuitBreaker) record(ctx context.Context, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state != CircuitClosed {
+		return
+	}
+	if err == nil || !countsAsOutage(err) {
+		c.failures = 0
+		return
+	}
+
+	c.failures++
+	if c.failures >= c.options.FailureThreshold {
+		zapctx.Error(ctx, "[CircuitBreaker] (record) Banking Circle keeps failing, the circuit opens",
+			zap.Int("failures", c.failures),
+			zap.Error(err),
+		)
+		c.open(ctx)
+	}
+}
+
+func (c *CircuitBreaker) open(ctx context.Context) {
+	c.openedAt = c.options.Now()
+	c.probes = 0
+	c.transition(ctx, CircuitOpen)
+}
+
+func (c *CircuitBreaker) transition(ctx context.Context, to CircuitState) {
+	if c.state == to {
+		return
+	}
+	zapctx.Info(ctx, "[CircuitBreaker] (transition) Banking Circle circuit changed state",
+		zap.String("from", string(c.state)),
+		zap.String("to", string(to)),
+	)
+	c.state = to
+	c.metrics.Count(ctx, circuitTransitionMetricName, 1, []string{"banking_circle", string(to)})
+	c.publishState(ctx)
+}
+
+// publishState sets the state gauge to 1 for the state the circuit is in and to 0 for the others.
+func (c *CircuitBreaker) publishState(ctx context.Context) {
+	for _, state := range []CircuitState{CircuitClosed, CircuitHalfOpen, CircuitOpen} {
+		var value float64
+		if state == c.state {
+			value = 1
+		}
+		c.metrics.Gauge(ctx, circuitStateMetricName, value, []string{"banking_circle", string(state)})
+	}
+}
+
+// CircuitBreakingBankingCircleClient calls Banking Circle through a CircuitBreaker. The retrying client goes around it,
+// so every failed attempt counts against the circuit and the retries stop as soon as it opens.
+type CircuitBreakingBankingCircleClient struct {
+	delegate ports.BankingCircleAPI
+	breaker  *CircuitBreaker
+}
+
+// NewCircuitBreakingBankingCircleClient probes Banking Circle with the rejection report of the day unless the options
+// bring a probe.
+func NewCircuitBreakingBankingCircleClient(
+	delegate ports.BankingCircleAPI,
+	options CircuitBreakerOptions,
+	metrics ports2.MetricsClient,
+) *CircuitBreakingBankingCircleClient {
+	if options.Probe == nil {
+		options.Probe = func(ctx context.Context) error {
+			_, err := delegate.GetRejectionReport(time.Now().UTC().Format("2006-01-02"))
+			return err
+		}
+	}
+	return &CircuitBreakingBankingCircleClient{
+		delegate: delegate,
+		breaker:  NewCircuitBreaker(options, metrics),
+	}
+}
+
+// Circuit is handed to the listeners to pause them, and to the health check.
+func (c *CircuitBreakingBankingCircleClient) Circuit() *CircuitBreaker {
+	return c.breaker
+}
+
+func (c *CircuitBreakingBankingCircleClient) RequestPayment(ctx context.Context, request spe.RequestDto, slice *[]string) (spe.ResponseDto, error) {
+	if err := c.breaker.allow(ctx, "make_payment"); err != nil {
+		return spe.ResponseDto{}, err
+	}
+	response, err := c.delegate.RequestPayment(ctx, request, slice)
+	c.breaker.record(ctx, err)
+	return response, err
+}
+
+func (c *CircuitBreakingBankingCircleClient) RequestBulkPayment(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error) {
+	if err := c.breaker.allow(ctx, "make_bulk_payment"); err != nil {
+		return bpe.ResponseDto{}, err
+	}
+	response, err := c.delegate.RequestBulkPayment(ctx, request, slice)
+	c.breaker.record(ctx, err)
+	return response, err
+}
+
+func (c *CircuitBreakingBankingCircleClient) CheckPaymentStatus(providerPaymentID models.ProviderPaymentID) (ports.PaymentStatus, error) {
+	ctx := context.Background()
+	if err := c.breaker.allow(ctx, "check_payment_status"); err != nil {
+		return "", err
+	}
+	status, err := c.delegate.CheckPaymentStatus(providerPaymentID)
+	c.breaker.record(ctx, err)
+	return status, err
+}
+
+func (c *CircuitBreakingBankingCircleClient) GetRejectionReport(date string) (models2.RejectionReport, error) {
+	ctx := context.Background()
+	if err := c.breaker.allow(ctx, "rejection_report"); err != nil {
+		return models2.RejectionReport{}, err
+	}
+	report, err := c.delegate.GetRejectionReport(date)
+	c.breaker.record(ctx, err)
+	return report, err
+}
+
+func (c *CircuitBreakingBankingCircleClient) CheckAccountBalance(accountID string) (models2.AccountBalance, error) {
+	ctx := context.Background()
+	if err := c.breaker.allow(ctx, "account_balance"); err != nil {
+		return models2.AccountBalance{}, err
+	}
+	balance, err := c.delegate.CheckAccountBalance(accountID)
+	c.breaker.record(ctx, err)
+	return balance, err
+}
@@ -0,0 +1,145 @@
+//go:build unit
+// +build unit
+
+package http_client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	portsMocks "github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestCircuitBreakingBankingCircleClient(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		serverError = http_client.UnrecognisedBankingCircleError{Status: http.StatusServiceUnavailable}
+	)
+
+	type fixture struct {
+		client   *http_client.CircuitBreakingBankingCircleClient
+		delegate *mocks.BankingCircleAPIMock
+		now      *time.Time
+		probeErr *error
+	}
+
+	newFixture := func(delegateErr error) fixture {
+		var (
+			now      = time.Date(2022, 9, 26, 10, 0, 0, 0, time.UTC)
+			probeErr error
+		)
+		delegate := &mocks.BankingCircleAPIMock{RequestPaymentFunc: func(ctx context.Context, request single_payment_endpoint.RequestDto, slice *[]string) (single_payment_endpoint.ResponseDto, error) {
+			return single_payment_endpoint.ResponseDto{}, delegateErr
+		}}
+		client := http_client.NewCircuitBreakingBankingCircleClient(delegate, http_client.CircuitBreakerOptions{
+			FailureThreshold: 3,
+			OpenFor:          time.Minute,
+			ProbesToClose:    2,
+			Probe:            func(ctx context.Context) error { return probeErr },
+			Now:              func() time.Time { return now },
+		}, testdoubles.DummyMetricsClient{})
+		return fixture{client: client, delegate: delegate, now: &now, probeErr: &probeErr}
+	}
+
+	requestPayments := func(client *http_client.CircuitBreakingBankingCircleClient, n int) (err error) {
+		for i := 0; i < n; i++ {
+			_, err = client.RequestPayment(ctx, single_payment_endpoint.RequestDto{}, nil)
+		}
+		return err
+	}
+
+	t.Run("opens on server errors in a row, and turns down the requests without calling Banking Circle", func(t *testing.T) {
+		is := is.New(t)
+		f := newFixture(serverError)
+
+		is.Equal(requestPayments(f.client, 3), serverError)
+		is.Equal(f.client.Circuit().State(), http_client.CircuitOpen)
+		is.True(f.client.Circuit().Ping(ctx) != nil)
+		is.True(!f.client.Circuit().Closed(ctx))
+
+		var circuitOpen http_client.CircuitOpenError
+		is.True(errors.As(requestPayments(f.client, 1), &circuitOpen))
+		is.Equal(len(f.delegate.RequestPaymentCalls()), 3)
+	})
+
+	t.Run("stays closed when Banking Circle turns a request down", func(t *testing.T) {
+		is := is.New(t)
+		f := newFixture(http_client.InvalidPaymentRequestError{ErrorMessage: "invalid creditor"})
+
+		_ = requestPayments(f.client, 5)
+
+		is.Equal(f.client.Circuit().State(), http_client.CircuitClosed)
+		is.Equal(len(f.delegate.RequestPaymentCalls()), 5)
+	})
+
+	t.Run("half-opens once open for long enough, and closes after enough probes succeed", func(t *testing.T) {
+		is := is.New(t)
+		f := newFixture(serverError)
+		_ = requestPayments(f.client, 3)
+
+		*f.now = f.now.Add(time.Minute)
+		is.True(!f.client.Circuit().Closed(ctx))
+		is.Equal(f.client.Circuit().State(), http_client.CircuitHalfOpen)
+		is.NoErr(f.client.Circuit().Ping(ctx))
+
+		is.True(f.client.Circuit().Closed(ctx))
+		is.Equal(f.client.Circuit().State(), http_client.CircuitClosed)
+	})
+
+	t.Run("opens again when a probe fails", func(t *testing.T) {
+		is := is.New(t)
+		f := newFixture(serverError)
+		_ = requestPayments(f.client, 3)
+
+		*f.now = f.now.Add(time.Minute)
+		*f.probeErr = serverError
+		is.True(!f.client.Circuit().Closed(ctx))
+		is.Equal(f.client.Circuit().State(), http_client.CircuitOpen)
+
+		*f.now = f.now.Add(30 * time.Second)
+		is.True(!f.client.Circuit().Closed(ctx))
+		is.Equal(f.client.Circuit().State(), http_client.CircuitOpen)
+	})
+
+	t.Run("publishes the state it is in as 1 and the other states as 0", func(t *testing.T) {
+		is := is.New(t)
+		state := map[string]float64{}
+		metrics := &portsMocks.MetricsClientMock{
+			CountFunc: func(context.Context, string, int64, []string) {},
+			GaugeFunc: func(_ context.Context, name string, value float64, tags []string) {
+				is.Equal(name, "app_http_client_circuit_state")
+				state[tags[1]] = value
+			},
+		}
+		client := http_client.NewCircuitBreakingBankingCircleClient(&mocks.BankingCircleAPIMock{
+			RequestPaymentFunc: func(context.Context, single_payment_endpoint.RequestDto, *[]string) (single_payment_endpoint.ResponseDto, error) {
+				return single_payment_endpoint.ResponseDto{}, serverError
+			},
+		}, http_client.CircuitBreakerOptions{FailureThreshold: 1}, metrics)
+		is.Equal(state, map[string]float64{"closed": 1, "half_open": 0, "open": 0})
+
+		_, _ = client.RequestPayment(ctx, single_payment_endpoint.RequestDto{}, nil)
+		is.Equal(state, map[string]float64{"closed": 0, "half_open": 0, "open": 1})
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	is := is.New(t)
+
+	is.True(http_client.IsRetryable(errors.New("connection reset")))
+	is.True(http_client.IsRetryable(http_client.UnrecognisedBankingCircleError{Status: http.StatusBadGateway}))
+	is.True(http_client.IsRetryable(http_client.UnrecognisedBankingCircleError{Status: http.StatusTooManyRequests}))
+	is.True(!http_client.IsRetryable(http_client.UnrecognisedBankingCircleError{Status: http.StatusConflict}))
+	is.True(!http_client.IsRetryable(http_client.InvalidPaymentRequestError{}))
+	is.True(!http_client.IsRetryable(http_client.PaymentNotFoundError{}))
+	is.True(!http_client.IsRetryable(http_client.CircuitOpenError{}))
+}
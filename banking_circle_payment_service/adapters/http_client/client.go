@@ -11,10 +11,12 @@ import (
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
-func NewBankingCircleHTTPClient(apiConfig BankingCircleAPIConfig, metricsClient ports.MetricsClient) (*http.Client, error) {
+// NewBankingCircleHTTPClient also returns the service the tokens of the client come from, for BankingCircleAPIClient to
+// refresh the token Banking Circle turns down.
+func NewBankingCircleHTTPClient(apiConfig BankingCircleAPIConfig, metricsClient ports.MetricsClient) (*http.Client, *authtoken.AuthTokenService, error) {
 	cert, err := tls.X509KeyPair([]byte(apiConfig.ClientCertificatePublicKey), []byte(apiConfig.ClientCertificatePrivateKey))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	secureTransport := &http.Transport{
@@ -30,6 +32,7 @@ func NewBankingCircleHTTPClient(apiConfig BankingCircleAPIConfig, metricsClient
 		APIUsername:               apiConfig.APIUsername,
 		APIPassword:               apiConfig.APIPassword,
 		TokenIntervalBeforeExpire: apiConfig.TokenIntervalBeforeExpire,
+		RefreshJitter:             apiConfig.TokenRefreshJitter,
 	}
 
 	httpTimeout := apiConfig.Timeout * time.Second
@@ -48,7 +51,7 @@ func NewBankingCircleHTTPClient(apiConfig BankingCircleAPIConfig, metricsClient
 		},
 		Timeout: httpTimeout,
 	}
-	return bcHTTPClient, nil
+	return bcHTTPClient, authTokenService, nil
 }
 
 type BankingCircleAPIConfig struct {
@@ -60,6 +63,7 @@ type BankingCircleAPIConfig struct {
 	ClientCertificatePrivateKey string
 	Timeout                     time.Duration
 	TokenIntervalBeforeExpire   time.Duration
+	TokenRefreshJitter          time.Duration
 	InsecureSkipVerify          bool
 }
 
@@ -68,13 +72,15 @@ type bcTransport struct {
 	getAuthToken func(ctx context.Context) (string, error)
 }
 
+// RoundTrip sets the headers on a copy of the request, so a request sent again goes with the token of the moment.
 func (t *bcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Add("Content-Type", "application/json")
 	authToken, err := t.getAuthToken(req.Context())
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("Authorization", "Bearer "+authToken)
+	req = req.Clone(req.Context())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+authToken)
 
 	return t.delegate.RoundTrip(req)
 }
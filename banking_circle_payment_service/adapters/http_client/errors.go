@@ -1,7 +1,11 @@
 package http_client
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
 )
@@ -12,9 +16,10 @@ type (
 )
 
 const (
-	RequestPayment  BankingCircleActionType = "making payment"
-	CheckingPayment BankingCircleActionType = "checking payment"
-	RejectionReport BankingCircleActionType = "rejection report"
+	RequestPayment     BankingCircleActionType = "making payment"
+	RequestBulkPayment BankingCircleActionType = "making bulk payment"
+	CheckingPayment    BankingCircleActionType = "checking payment"
+	RejectionReport    BankingCircleActionType = "rejection report"
 )
 
 type UnrecognisedBankingCircleError struct {
@@ -58,3 +63,46 @@ type InvalidPaymentRequestError struct {
 func (i InvalidPaymentRequestError) Error() string {
 	return fmt.Sprintf("banking circle reports payment with requestID %s as invalid: %s, %+v", i.UniqueID, i.ErrorMessage, i.Request)
 }
+
+type CircuitOpenError struct {
+	State CircuitState
+}
+
+func (c CircuitOpenError) Error() string {
+	return "banking circle isn't called while its circuit is open"
+}
+
+// IsRetryable tells whether asking Banking Circle again may succeed, a request it found invalid or a payment it
+// doesn't know about will fail the same way however many times it is asked.
+func IsRetryable(err error) bool {
+	var (
+		invalid      InvalidPaymentRequestError
+		notFound     PaymentNotFoundError
+		circuitOpen  CircuitOpenError
+		unrecognised UnrecognisedBankingCircleError
+	)
+	switch {
+	case errors.As(err, &invalid), errors.As(err, &notFound), errors.As(err, &circuitOpen):
+		return false
+	case errors.As(err, &unrecognised):
+		return unrecognised.Status >= http.StatusInternalServerError || unrecognised.Status == http.StatusTooManyRequests
+	default:
+		return true
+	}
+}
+
+// countsAsOutage tells whether an error shows Banking Circle is down, rather than it turning down a request.
+func countsAsOutage(err error) bool {
+	var (
+		unrecognised UnrecognisedBankingCircleError
+		netErr       net.Error
+	)
+	switch {
+	case errors.As(err, &unrecognised):
+		return unrecognised.Status >= http.StatusInternalServerError
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return true
+	default:
+		return false
+	}
+}
@@ -51,11 +51,12 @@ func newBankingCircleFakeClient(t *testing.T) *http_client.BankingCircleAPIClien
 		InsecureSkipVerify:          true,
 	}
 
-	httpClient, err := http_client.NewBankingCircleHTTPClient(config, dummyMetrics)
+	httpClient, tokens, err := http_client.NewBankingCircleHTTPClient(config, dummyMetrics)
 	assert.NoError(t, err)
 
 	client, err := http_client.NewAPIClient(httpClient, fakeBCBaseURL, dummyMetrics)
 	assert.NoError(t, err)
+	client.RefreshTokenOnUnauthorised(tokens)
 
 	return client
 }
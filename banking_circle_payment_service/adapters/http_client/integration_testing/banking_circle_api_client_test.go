@@ -133,11 +133,12 @@ func newBankingCircleSandboxClient(is *is.I) *http_client.BankingCircleAPIClient
 	}
 	dummyMetrics := testdoubles.DummyMetricsClient{}
 
-	httpClient, err := http_client.NewBankingCircleHTTPClient(config, dummyMetrics)
+	httpClient, tokens, err := http_client.NewBankingCircleHTTPClient(config, dummyMetrics)
 	is.NoErr(err)
 
 	client, err := http_client.NewAPIClient(httpClient, config.BaseURL, dummyMetrics)
 	is.NoErr(err)
+	client.RefreshTokenOnUnauthorised(tokens)
 	return client
 }
 
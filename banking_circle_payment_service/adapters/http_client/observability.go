@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
@@ -28,3 +31,11 @@ func (b bcClientObserver) reportResponse(ctx context.Context, responseTime int64
 func (b bcClientObserver) ReusedConnection(ctx context.Context, reused bool) {
 	b.metricsClient.Count(ctx, clientConnectionMetricName, 1, []string{fmt.Sprintf("%t", reused)})
 }
+
+// CouldntRefreshToken isn't counted, the token service counts the refreshes that failed already.
+func (b bcClientObserver) CouldntRefreshToken(ctx context.Context, operation string, err error) {
+	zapctx.Warn(ctx, "Banking Circle turned down the token, and it couldn't be refreshed",
+		zap.String("operation", operation),
+		zap.Error(err),
+	)
+}
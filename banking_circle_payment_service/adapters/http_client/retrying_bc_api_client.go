@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	models2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 
@@ -65,13 +66,21 @@ func (r RetryingBankingCircleClient) RequestPayment(ctx context.Context, request
 		if payment, err = r.delegate.RequestPayment(ctx, request, slice); err == nil {
 			return payment, nil
 		}
-		// todo: use retryable errors
+		if !IsRetryable(err) {
+			return payment, err
+		}
 		r.observeRetry(ctx, request, i, err)
 		r.options.Sleep()
 	}
 	return payment, err
 }
 
+// RequestBulkPayment isn't retried, Banking Circle may have taken some of the payments of a bulk that failed, and
+// requesting them again would pay those merchants twice.
+func (r RetryingBankingCircleClient) RequestBulkPayment(ctx context.Context, request bulk_payment_endpoint.RequestDto, slice *[]string) (bulk_payment_endpoint.ResponseDto, error) {
+	return r.delegate.RequestBulkPayment(ctx, request, slice)
+}
+
 func (r RetryingBankingCircleClient) CheckPaymentStatus(providerPaymentID models.ProviderPaymentID) (ports.PaymentStatus, error) {
 	return r.delegate.CheckPaymentStatus(providerPaymentID)
 }
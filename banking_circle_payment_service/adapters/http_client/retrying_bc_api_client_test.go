@@ -126,6 +126,39 @@ func TestRetryingBankingCircleClient_RequestPayment(t *testing.T) {
 			is.Equal(err, expectedErr)
 			is.Equal(spySleeper.Calls, maxNumberOfAttempts)
 		})
+
+		t.Run("gives up straight away on an error asking again won't fix", func(t *testing.T) {
+			for _, expectedErr := range []error{
+				http_client.InvalidPaymentRequestError{ErrorMessage: "invalid creditor"},
+				http_client.UnrecognisedBankingCircleError{Status: 409},
+				http_client.CircuitOpenError{State: http_client.CircuitOpen},
+			} {
+				var (
+					ctx = context.Background()
+					is  = is.New(t)
+				)
+
+				spyDelegate := &mocks.BankingCircleAPIMock{RequestPaymentFunc: func(ctx context.Context, request single_payment_endpoint.RequestDto, slice *[]string) (single_payment_endpoint.ResponseDto, error) {
+					return single_payment_endpoint.ResponseDto{}, expectedErr
+				}}
+				spySleeper := &spySleeper{}
+
+				client := http_client.NewRetryingBankingCircleClient(
+					spyDelegate,
+					&http_client.RetryingBCClientOptions{
+						Sleep:            spySleeper.DummySleep,
+						NumberOfAttempts: 5,
+					},
+					testdoubles.DummyMetricsClient{},
+				)
+
+				_, err := client.RequestPayment(ctx, single_payment_endpoint.RequestDto{}, nil)
+
+				is.Equal(err, expectedErr)
+				is.Equal(len(spyDelegate.RequestPaymentCalls()), 1)
+				is.Equal(spySleeper.Calls, 0)
+			}
+		})
 	})
 }
 
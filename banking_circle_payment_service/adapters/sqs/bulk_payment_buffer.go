@@ -0,0 +1,81 @@
+package sqs
+
+import (
+	"time"
+
+	awssqs "github.com/aws/aws-sdk-go/service/sqs"
+
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// BulkPaymentOptions has the payment instructions of Currencies requested in bulk. A bulk is requested once MaxSize
+// payment instructions are waiting, once the oldest of them waited for MaxWait, or once the queue has no more messages.
+// MaxWait must be well below the visibility timeout of the queue, or the messages waiting are received again.
+type BulkPaymentOptions struct {
+	UseCase    ports.MakeBankingCircleBulkPayment
+	Currencies []models.CurrencyCode
+	MaxSize    int
+	MaxWait    time.Duration
+}
+
+type bulkPaymentBuffer struct {
+	BulkPaymentOptions
+	currencies   map[models.CurrencyCode]bool
+	instructions []models.PaymentInstruction
+	messages     map[models.PaymentInstructionID][]*awssqs.Message
+	since        time.Time
+	now          func() time.Time
+}
+
+func newBulkPaymentBuffer(options BulkPaymentOptions) *bulkPaymentBuffer {
+	currencies := make(map[models.CurrencyCode]bool, len(options.Currencies))
+	for _, currency := range options.Currencies {
+		currencies[currency] = true
+	}
+
+	return &bulkPaymentBuffer{
+		BulkPaymentOptions: options,
+		currencies:         currencies,
+		messages:           make(map[models.PaymentInstructionID][]*awssqs.Message),
+		now:                time.Now,
+	}
+}
+
+// add keeps the message for the next bulk and tells whether it did, messages of other currencies and messages that
+// aren't payment instructions are left to the single payment path.
+func (b *bulkPaymentBuffer) add(message *awssqs.Message) bool {
+	instruction, err := models.NewPaymentInstructionFromJSON([]byte(*message.Body))
+	if err != nil || !b.currencies[instruction.IncomingInstruction.IsoCode()] {
+		return false
+	}
+
+	if len(b.instructions) == 0 {
+		b.since = b.now()
+	}
+	// a payment instruction received twice is requested once, and both of its messages are handled with it
+	if _, found := b.messages[instruction.ID()]; !found {
+		b.instructions = append(b.instructions, instruction)
+	}
+	b.messages[instruction.ID()] = append(b.messages[instruction.ID()], message)
+	return true
+}
+
+func (b *bulkPaymentBuffer) due(queueIsEmpty bool) bool {
+	if len(b.instructions) == 0 {
+		return false
+	}
+	return queueIsEmpty || len(b.instructions) >= b.MaxSize || b.now().Sub(b.since) >= b.MaxWait
+}
+
+func (b *bulkPaymentBuffer) take() ([]models.PaymentInstruction, map[models.PaymentInstructionID][]*awssqs.Message) {
+	instructions, messages := b.instructions, b.messages
+	b.release()
+	return instructions, messages
+}
+
+// release drops the waiting messages without handling them, they are received again once their visibility timeout runs out.
+func (b *bulkPaymentBuffer) release() {
+	b.instructions = nil
+	b.messages = make(map[models.PaymentInstructionID][]*awssqs.Message)
+}
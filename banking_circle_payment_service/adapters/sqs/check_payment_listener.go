@@ -2,6 +2,8 @@ package sqs
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
 	zapctx "github.com/saltpay/go-zap-ctx"
@@ -10,6 +12,7 @@ import (
 	awssqs "github.com/aws/aws-sdk-go/service/sqs"
 
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/use_cases"
 
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/sync"
@@ -17,6 +20,24 @@ import (
 	domainPorts "github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
+const (
+	defaultRecheckDelay    = 30 * time.Second
+	defaultMaxRecheckDelay = maxSQSDelay
+	// maxSQSDelay is the longest SQS delays the delivery of a message for.
+	maxSQSDelay = 15 * time.Minute
+	// maxSQSVisibility is the longest SQS hides a received message for.
+	maxSQSVisibility = 12 * time.Hour
+
+	checkPaymentListenerName = "CheckPaymentStatusListener"
+)
+
+// statusCheckMessage is a message of the unchecked queue, the PaymentProviderEvent of the payment Banking Circle accepted
+// and how many times its status was checked already.
+type statusCheckMessage struct {
+	models.PaymentProviderEvent
+	StatusCheckAttempts int `json:"statusCheckAttempts,omitempty"`
+}
+
 type CheckPaymentStatusListener struct {
 	useCasePaymentStatus ports.CheckBankingCirclePaymentStatus
 	uncheckedQueueClient sqs.Queue
@@ -25,6 +46,10 @@ type CheckPaymentStatusListener struct {
 	metrics              domainPorts.MetricsClient
 	workerPoolSize       int64
 	shouldListen         *sync.AtomicBool
+	callbackDeadline     time.Duration
+	recheckDelay         time.Duration
+	maxRecheckDelay      time.Duration
+	circuit              ports.BankingCircleCircuit
 }
 
 func NewCheckPaymentStatusListener(
@@ -46,7 +71,34 @@ func NewCheckPaymentStatusListener(
 		metrics:              metrics,
 		workerPoolSize:       workerPoolSize,
 		shouldListen:         shouldListen,
+		recheckDelay:         defaultRecheckDelay,
+		maxRecheckDelay:      defaultMaxRecheckDelay,
+	}
+}
+
+// RecheckWithBackoff sets how long the status of a payment still pending waits to be checked again, the delay doubles
+// with every check up to maxDelay. SQS delays a message for 15 minutes at most, so longer delays are clamped to it.
+func (cps *CheckPaymentStatusListener) RecheckWithBackoff(delay, maxDelay time.Duration) {
+	if maxDelay <= 0 || maxDelay > maxSQSDelay {
+		maxDelay = maxSQSDelay
+	}
+	if delay > maxDelay {
+		delay = maxDelay
 	}
+	cps.recheckDelay = delay
+	cps.maxRecheckDelay = maxDelay
+}
+
+// WaitForCallbacks leaves Banking Circle the deadline from when it accepted a payment to notify its outcome,
+// the status of the payment is only checked once the deadline passes. SQS hides a message for 12 hours at most, a
+// message received before a longer deadline passes is hidden again for what is left of it.
+func (cps *CheckPaymentStatusListener) WaitForCallbacks(deadline time.Duration) {
+	cps.callbackDeadline = deadline
+}
+
+// PauseWhileOpen stops checking the status of payments while the Banking Circle circuit is open.
+func (cps *CheckPaymentStatusListener) PauseWhileOpen(circuit ports.BankingCircleCircuit) {
+	cps.circuit = circuit
 }
 
 // Listen starts long-polling the SQS client, and executes the supplied use case for each message that comes through.
@@ -63,6 +115,11 @@ func (cps *CheckPaymentStatusListener) Listen(ctx context.Context) {
 			continue
 		}
 
+		if cps.circuit != nil && !cps.circuit.Closed(ctx) {
+			time.Sleep(waitTimeWhileCircuitOpen)
+			continue
+		}
+
 		batch, err := cps.uncheckedQueueClient.GetMessages(ctx)
 		if err != nil {
 			zapctx.Error(ctx, "failed to fetch sqs message", zap.Error(err))
@@ -77,21 +134,44 @@ func (cps *CheckPaymentStatusListener) Listen(ctx context.Context) {
 }
 
 func (cps *CheckPaymentStatusListener) processMessage(ctx context.Context, msg *awssqs.Message) {
-	paymentProviderEvent, err := models.NewPaymentProviderEventFromJSON([]byte(*msg.Body))
-	if err != nil {
+	var message statusCheckMessage
+	if err := json.Unmarshal([]byte(*msg.Body), &message); err != nil {
 		zapctx.Error(ctx, "error unmarshalling sqs message body", zap.Error(err))
-		cps.dlq(ctx, msg)
+		cps.dlq(ctx, msg, err)
+		return
+	}
+	paymentProviderEvent := message.PaymentProviderEvent
+
+	if wait := time.Until(paymentProviderEvent.CreatedOn.Add(cps.callbackDeadline)); wait > 0 {
+		if wait > maxSQSVisibility {
+			wait = maxSQSVisibility
+		}
+		if err := cps.uncheckedQueueClient.ChangeMessageVisibility(ctx, *msg.ReceiptHandle, wait); err != nil {
+			zapctx.Error(ctx, "error hiding message until the Banking Circle callback deadline, it is received again after its visibility timeout",
+				zap.String("id", string(paymentProviderEvent.PaymentProviderPaymentID)),
+				zap.Error(err),
+			)
+		}
 		return
 	}
 
-	err = cps.useCasePaymentStatus.Execute(ctx, paymentProviderEvent.PaymentInstruction, paymentProviderEvent.PaymentProviderPaymentID, paymentProviderEvent.BankingReference, paymentProviderEvent.CreatedOn)
+	err := cps.useCasePaymentStatus.Execute(ctx, paymentProviderEvent.PaymentInstruction, paymentProviderEvent.PaymentProviderPaymentID, paymentProviderEvent.BankingReference, paymentProviderEvent.CreatedOn)
 
-	if err != nil {
+	var recheckLater use_cases.RecheckLaterError
+	if errors.As(err, &recheckLater) {
+		if err := cps.recheck(ctx, message); err != nil {
+			zapctx.Error(ctx, "error rescheduling the Banking Circle payment status check, it is received again after its visibility timeout",
+				zap.String("id", string(paymentProviderEvent.PaymentProviderPaymentID)),
+				zap.Error(err),
+			)
+			return
+		}
+	} else if err != nil {
 		zapctx.Error(ctx, "error executing the Banking Circle check payment use case for payment instruction",
 			zap.String("id", string(paymentProviderEvent.PaymentProviderPaymentID)),
 			zap.Error(err),
 		)
-		cps.dlq(ctx, msg)
+		cps.dlq(ctx, msg, err)
 		return
 	}
 
@@ -104,6 +184,30 @@ func (cps *CheckPaymentStatusListener) processMessage(ctx context.Context, msg *
 	}
 }
 
+// recheck sends the message back to the unchecked queue, delayed by the backoff of the checks done already.
+func (cps *CheckPaymentStatusListener) recheck(ctx context.Context, message statusCheckMessage) error {
+	message.StatusCheckAttempts++
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	delay := cps.recheckDelay
+	for i := 1; i < message.StatusCheckAttempts && delay < cps.maxRecheckDelay; i++ {
+		delay *= 2
+	}
+	if delay > cps.maxRecheckDelay {
+		delay = cps.maxRecheckDelay
+	}
+
+	zapctx.Debug(ctx, "rescheduling the Banking Circle payment status check",
+		zap.String("id", string(message.PaymentProviderPaymentID)),
+		zap.Int("attempts", message.StatusCheckAttempts),
+		zap.Duration("delay", delay),
+	)
+	return cps.uncheckedQueueClient.SendMessageWithDelay(ctx, string(body), delay)
+}
+
 func (cps *CheckPaymentStatusListener) createWorkerPool(ctx context.Context, jobs <-chan *awssqs.Message) {
 	var w int64
 	for w = 1; w <= cps.workerPoolSize; w++ {
@@ -117,9 +221,10 @@ func (cps *CheckPaymentStatusListener) worker(ctx context.Context, messages <-ch
 	}
 }
 
-func (cps *CheckPaymentStatusListener) dlq(ctx context.Context, message *awssqs.Message) {
+func (cps *CheckPaymentStatusListener) dlq(ctx context.Context, message *awssqs.Message, failure error) {
 	if cps.dlqClient != nil {
-		if err := cps.dlqClient.SendMessage(ctx, *message.Body); err != nil {
+		deadLetter := sqs.NewDeadLetter(checkPaymentListenerName, cps.uncheckedQueueClient, message, failure)
+		if err := sqs.SendDeadLetter(ctx, cps.dlqClient, *message.Body, deadLetter); err != nil {
 			zapctx.Error(ctx, "could not send message to dlq",
 				zap.Any("message", *message),
 				zap.Error(err),
@@ -0,0 +1,225 @@
+//go:build unit
+// +build unit
+
+package sqs_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	awsSqs "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/sqs"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/use_cases"
+
+	awsSqsAdapterMock "github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs/mocks"
+	awsTestHelper "github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	domainModelsTestHelpers "github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+)
+
+func TestCheckPaymentStatusListener_Listen(t *testing.T) {
+	const callbackDeadline = 10 * time.Minute
+
+	newSubmittedMessage := func(t *testing.T, acceptedOn time.Time) *awsSqs.Message {
+		t.Helper()
+		event := models.PaymentProviderEvent{
+			CreatedOn:                acceptedOn,
+			Type:                     models.Submitted,
+			PaymentInstruction:       domainModelsTestHelpers.NewPaymentInstructionBuilder().Build(),
+			PaymentProviderName:      models.BC,
+			PaymentProviderPaymentID: "banking-circle-payment-id",
+		}
+		body, err := event.ToJSON()
+		is.New(t).NoErr(err)
+		return awsTestHelper.NewSQSMessage(string(body))
+	}
+
+	// newUncheckedQueue hands out the message once, and signals the first time it is hidden or deleted
+	newUncheckedQueue := func(message *awsSqs.Message, done chan<- struct{}) *awsSqsAdapterMock.QueueMock {
+		received := false
+		return &awsSqsAdapterMock.QueueMock{
+			GetMessagesFunc: func(context.Context) (*awsSqs.ReceiveMessageOutput, error) {
+				if received {
+					time.Sleep(10 * time.Millisecond)
+					return &awsSqs.ReceiveMessageOutput{}, nil
+				}
+				received = true
+				return &awsSqs.ReceiveMessageOutput{Messages: []*awsSqs.Message{message}}, nil
+			},
+			ChangeMessageVisibilityFunc: func(context.Context, string, time.Duration) error {
+				done <- struct{}{}
+				return nil
+			},
+			DeleteMessageFunc: func(context.Context, string) error {
+				done <- struct{}{}
+				return nil
+			},
+			SendMessageWithDelayFunc: func(context.Context, string, time.Duration) error {
+				return nil
+			},
+		}
+	}
+
+	newCheckStatusUseCase := func() *mocks.CheckBankingCirclePaymentStatusMock {
+		return &mocks.CheckBankingCirclePaymentStatusMock{
+			ExecuteFunc: func(context.Context, models.PaymentInstruction, models.ProviderPaymentID, models.BankingReference, time.Time) error {
+				return nil
+			},
+		}
+	}
+
+	t.Run("a payment is left for Banking Circle to notify its outcome until the callback deadline", func(t *testing.T) {
+		is := is.New(t)
+		done := make(chan struct{}, 1)
+		spyUncheckedQueue := newUncheckedQueue(newSubmittedMessage(t, time.Now()), done)
+		spyUseCase := newCheckStatusUseCase()
+
+		listener := sqs.NewCheckPaymentStatusListener(spyUseCase, spyUncheckedQueue, nil, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{}, workerPoolSize)
+		listener.WaitForCallbacks(callbackDeadline)
+		go listener.Listen(context.Background())
+		defer listener.StopListening()
+
+		select {
+		case <-done:
+		case <-time.After(sleepyTime):
+			t.Fatal("timed out waiting for the message to be hidden")
+		}
+
+		is.Equal(len(spyUseCase.ExecuteCalls()), 0)
+		is.Equal(len(spyUncheckedQueue.DeleteMessageCalls()), 0)
+		is.Equal(len(spyUncheckedQueue.ChangeMessageVisibilityCalls()), 1)
+		hiddenFor := spyUncheckedQueue.ChangeMessageVisibilityCalls()[0].Timeout
+		is.True(hiddenFor > callbackDeadline-time.Minute && hiddenFor <= callbackDeadline)
+	})
+
+	t.Run("a payment is hidden for as long as SQS allows when the callback deadline is further away", func(t *testing.T) {
+		is := is.New(t)
+		done := make(chan struct{}, 1)
+		spyUncheckedQueue := newUncheckedQueue(newSubmittedMessage(t, time.Now()), done)
+		spyUseCase := newCheckStatusUseCase()
+
+		listener := sqs.NewCheckPaymentStatusListener(spyUseCase, spyUncheckedQueue, nil, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{}, workerPoolSize)
+		listener.WaitForCallbacks(48 * time.Hour)
+		go listener.Listen(context.Background())
+		defer listener.StopListening()
+
+		select {
+		case <-done:
+		case <-time.After(sleepyTime):
+			t.Fatal("timed out waiting for the message to be hidden")
+		}
+
+		is.Equal(len(spyUseCase.ExecuteCalls()), 0)
+		is.Equal(len(spyUncheckedQueue.ChangeMessageVisibilityCalls()), 1)
+		is.Equal(spyUncheckedQueue.ChangeMessageVisibilityCalls()[0].Timeout, 12*time.Hour)
+	})
+
+	t.Run("the status of a payment is checked once the callback deadline passed", func(t *testing.T) {
+		is := is.New(t)
+		done := make(chan struct{}, 1)
+		spyUncheckedQueue := newUncheckedQueue(newSubmittedMessage(t, time.Now().Add(-callbackDeadline)), done)
+		spyUseCase := newCheckStatusUseCase()
+
+		listener := sqs.NewCheckPaymentStatusListener(spyUseCase, spyUncheckedQueue, nil, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{}, workerPoolSize)
+		listener.WaitForCallbacks(callbackDeadline)
+		go listener.Listen(context.Background())
+		defer listener.StopListening()
+
+		select {
+		case <-done:
+		case <-time.After(sleepyTime):
+			t.Fatal("timed out waiting for the message to be deleted")
+		}
+
+		is.Equal(len(spyUseCase.ExecuteCalls()), 1)
+		is.Equal(spyUseCase.ExecuteCalls()[0].ProviderPaymentID, models.ProviderPaymentID("banking-circle-payment-id"))
+		is.Equal(len(spyUncheckedQueue.ChangeMessageVisibilityCalls()), 0)
+		is.Equal(len(spyUncheckedQueue.DeleteMessageCalls()), 1)
+	})
+
+	t.Run("no status is checked while the Banking Circle circuit is open", func(t *testing.T) {
+		is := is.New(t)
+		spyUncheckedQueue := newUncheckedQueue(newSubmittedMessage(t, time.Now().Add(-callbackDeadline)), make(chan struct{}, 1))
+		circuitChecked := make(chan struct{}, 1)
+		openCircuit := &mocks.BankingCircleCircuitMock{ClosedFunc: func(context.Context) bool {
+			select {
+			case circuitChecked <- struct{}{}:
+			default:
+			}
+			return false
+		}}
+
+		listener := sqs.NewCheckPaymentStatusListener(newCheckStatusUseCase(), spyUncheckedQueue, nil, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{}, workerPoolSize)
+		listener.PauseWhileOpen(openCircuit)
+		go listener.Listen(context.Background())
+		defer listener.StopListening()
+
+		select {
+		case <-circuitChecked:
+		case <-time.After(sleepyTime):
+			t.Fatal("timed out waiting for the circuit to be checked")
+		}
+		is.Equal(len(spyUncheckedQueue.GetMessagesCalls()), 0)
+	})
+
+	t.Run("a payment still pending is checked again with a backoff of the checks done already", func(t *testing.T) {
+		for _, tc := range []struct {
+			attempts      int
+			maxDelay      time.Duration
+			expectedDelay time.Duration
+		}{
+			{attempts: 0, maxDelay: 15 * time.Minute, expectedDelay: time.Minute},
+			{attempts: 2, maxDelay: 15 * time.Minute, expectedDelay: 4 * time.Minute},
+			{attempts: 10, maxDelay: 15 * time.Minute, expectedDelay: 15 * time.Minute},
+			{attempts: 10, maxDelay: time.Hour, expectedDelay: 15 * time.Minute},
+		} {
+			is := is.New(t)
+			done := make(chan struct{}, 1)
+
+			var message map[string]interface{}
+			is.NoErr(json.Unmarshal([]byte(*newSubmittedMessage(t, time.Now().Add(-callbackDeadline)).Body), &message))
+			message["statusCheckAttempts"] = tc.attempts
+			body, err := json.Marshal(message)
+			is.NoErr(err)
+
+			spyUncheckedQueue := newUncheckedQueue(awsTestHelper.NewSQSMessage(string(body)), done)
+			pendingUseCase := &mocks.CheckBankingCirclePaymentStatusMock{
+				ExecuteFunc: func(context.Context, models.PaymentInstruction, models.ProviderPaymentID, models.BankingReference, time.Time) error {
+					return use_cases.RecheckLaterError{Status: ports.PendingProcessing}
+				},
+			}
+
+			listener := sqs.NewCheckPaymentStatusListener(pendingUseCase, spyUncheckedQueue, nil, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{}, workerPoolSize)
+			listener.WaitForCallbacks(callbackDeadline)
+			listener.RecheckWithBackoff(time.Minute, tc.maxDelay)
+			go listener.Listen(context.Background())
+
+			select {
+			case <-done:
+			case <-time.After(sleepyTime):
+				t.Fatal("timed out waiting for the message to be deleted")
+			}
+			listener.StopListening()
+
+			is.Equal(len(spyUncheckedQueue.SendMessageWithDelayCalls()), 1)
+			rescheduled := spyUncheckedQueue.SendMessageWithDelayCalls()[0]
+			is.Equal(rescheduled.Delay, tc.expectedDelay)
+
+			var rescheduledMessage struct {
+				PaymentProviderPaymentID models.ProviderPaymentID `json:"paymentProviderPaymentId"`
+				StatusCheckAttempts      int                      `json:"statusCheckAttempts"`
+			}
+			is.NoErr(json.Unmarshal([]byte(rescheduled.MessageBody), &rescheduledMessage))
+			is.Equal(rescheduledMessage.StatusCheckAttempts, tc.attempts+1)
+			is.Equal(rescheduledMessage.PaymentProviderPaymentID, models.ProviderPaymentID("banking-circle-payment-id"))
+			is.Equal(len(spyUncheckedQueue.DeleteMessageCalls()), 1)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package sqs
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	zapctx "github.com/saltpay/go-zap-ctx"
 	"go.uber.org/zap"
 
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/sync"
@@ -30,12 +32,16 @@ type PaymentInstructionEventListener struct {
 	numberOfDeleteRetries            int
 	numberOfFailedPaymentsThreshold  int
 	counter                          int32
+	bulk                             *bulkPaymentBuffer
+	circuit                          ports.BankingCircleCircuit
 }
 
 const (
 	waitTimeBetweenFeatureFlagChecks = 20 * time.Second
 	paymentsProcessingDisabled       = "app_settlements_provider_payments_processing_disabled"
 	paymentProviderTag               = "banking_circle"
+	waitTimeWhileCircuitOpen         = 5 * time.Second
+	makePaymentListenerName          = "PaymentInstructionEventListener"
 )
 
 func NewPaymentInstructionEventListener(
@@ -68,6 +74,17 @@ func NewPaymentInstructionEventListener(
 	}
 }
 
+// RequestInBulk has the payment instructions of the given currencies requested with bulk payments instead of one by one.
+func (pir *PaymentInstructionEventListener) RequestInBulk(options BulkPaymentOptions) {
+	pir.bulk = newBulkPaymentBuffer(options)
+}
+
+// PauseWhileOpen stops taking payment instructions from the queue while the Banking Circle circuit is open, they are
+// left on the queue instead of failing one after another.
+func (pir *PaymentInstructionEventListener) PauseWhileOpen(circuit ports.BankingCircleCircuit) {
+	pir.circuit = circuit
+}
+
 // Listen starts long-polling the SQS client, and executes the supplied use case for each message that comes through.
 func (pir *PaymentInstructionEventListener) Listen(ctx context.Context) {
 	jobs := make(chan *awssqs.Message)
@@ -81,9 +98,20 @@ func (pir *PaymentInstructionEventListener) Listen(ctx context.Context) {
 		if !pir.featureFlagSvc.IsIngestionEnabledFromBankingCircleUnprocessedQueue() {
 			zapctx.Error(ctx, "(Listen) payments processing disabled")
 			pir.metrics.Count(ctx, paymentsProcessingDisabled, 1, []string{paymentProviderTag})
+			if pir.bulk != nil {
+				pir.bulk.release()
+			}
 			time.Sleep(5 * time.Second)
 			continue
 		}
+		if pir.circuit != nil && !pir.circuit.Closed(ctx) {
+			zapctx.Warn(ctx, "(Listen) payments processing paused while the Banking Circle circuit is open")
+			if pir.bulk != nil {
+				pir.bulk.release()
+			}
+			time.Sleep(waitTimeWhileCircuitOpen)
+			continue
+		}
 		batch, err := pir.incomingQueueClient.GetMessages(ctx)
 		if err != nil {
 			zapctx.Error(ctx, "failed to fetch sqs message", zap.Error(err))
@@ -92,8 +120,15 @@ func (pir *PaymentInstructionEventListener) Listen(ctx context.Context) {
 		}
 
 		for _, message := range batch.Messages {
+			if pir.bulk != nil && pir.bulk.add(message) {
+				continue
+			}
 			jobs <- message
 		}
+
+		if pir.bulk != nil && pir.bulk.due(len(batch.Messages) == 0) {
+			pir.processBulk(ctx)
+		}
 	}
 }
 
@@ -101,7 +136,7 @@ func (pir *PaymentInstructionEventListener) processMessage(ctx context.Context,
 	paymentInstruction, err := models.NewPaymentInstructionFromJSON([]byte(*msg.Body))
 	if err != nil {
 		zapctx.Error(ctx, "error unmarshalling sqs message body", zap.Error(err))
-		pir.dlq(ctx, msg)
+		pir.dlq(ctx, msg, err)
 		return
 	}
 	zapctx.Debug(ctx, "flow_step #8: payment instruction received by the Banking Circle Payment Service",
@@ -110,18 +145,65 @@ func (pir *PaymentInstructionEventListener) processMessage(ctx context.Context,
 	)
 
 	_, err = pir.useCasePaymentRequest.Execute(ctx, paymentInstruction)
+	if isCircuitOpen(err) {
+		// the payment wasn't requested, the message is received again after its visibility timeout
+		return
+	}
 	if err != nil {
 		zapctx.Error(ctx, "error executing the Banking Circle make payment use case", zap.Error(err))
-		pir.dlq(ctx, msg)
-		if c := atomic.AddInt32(&pir.counter, 1); int(c) >= pir.numberOfFailedPaymentsThreshold {
-			zapctx.Info(ctx, "disabling payment ingestion")
-			if err = pir.featureFlagSvc.ToggleOffIngestionFromBankingCirclePayments(ctx); err != nil {
-				zapctx.Error(ctx, "error disabling feature flag", zap.Error(err))
+		pir.fail(ctx, msg, err)
+		return
+	}
+
+	pir.delete(ctx, msg)
+}
+
+func (pir *PaymentInstructionEventListener) processBulk(ctx context.Context) {
+	instructions, messages := pir.bulk.take()
+	zapctx.Debug(ctx, "flow_step #8: payment instructions received by the Banking Circle Payment Service for a bulk payment",
+		zap.Int("size", len(instructions)),
+	)
+
+	failed := pir.bulk.UseCase.Execute(ctx, instructions)
+	for _, instruction := range instructions {
+		err, isFailed := failed[instruction.ID()]
+		if isCircuitOpen(err) {
+			continue
+		}
+		if isFailed {
+			zapctx.Error(ctx, "error executing the Banking Circle make bulk payment use case",
+				zap.String("id", string(instruction.ID())),
+				zap.Error(err),
+			)
+		}
+
+		for _, msg := range messages[instruction.ID()] {
+			if isFailed {
+				pir.fail(ctx, msg, err)
+			} else {
+				pir.delete(ctx, msg)
 			}
 		}
-		return
 	}
+}
+
+// fail sends the message to the dlq, and disables the ingestion once too many payments failed.
+func (pir *PaymentInstructionEventListener) fail(ctx context.Context, msg *awssqs.Message, failure error) {
+	pir.dlq(ctx, msg, failure)
+	if c := atomic.AddInt32(&pir.counter, 1); int(c) >= pir.numberOfFailedPaymentsThreshold {
+		zapctx.Info(ctx, "disabling payment ingestion")
+		if err := pir.featureFlagSvc.ToggleOffIngestionFromBankingCirclePayments(ctx); err != nil {
+			zapctx.Error(ctx, "error disabling feature flag", zap.Error(err))
+		}
+	}
+}
+
+func isCircuitOpen(err error) bool {
+	var circuitOpen http_client.CircuitOpenError
+	return errors.As(err, &circuitOpen)
+}
 
+func (pir *PaymentInstructionEventListener) delete(ctx context.Context, msg *awssqs.Message) {
 	for i := 0; i < pir.numberOfDeleteRetries; i++ {
 		err := pir.incomingQueueClient.DeleteMessage(ctx, *msg.ReceiptHandle)
 		if err == nil {
@@ -152,9 +234,10 @@ func (pir *PaymentInstructionEventListener) worker(messages <-chan *awssqs.Messa
 	}
 }
 
-func (pir *PaymentInstructionEventListener) dlq(ctx context.Context, message *awssqs.Message) {
+func (pir *PaymentInstructionEventListener) dlq(ctx context.Context, message *awssqs.Message, failure error) {
 	if pir.dlqClient != nil {
-		if err := pir.dlqClient.SendMessage(ctx, *message.Body); err != nil {
+		deadLetter := sqs.NewDeadLetter(makePaymentListenerName, pir.incomingQueueClient, message, failure)
+		if err := sqs.SendDeadLetter(ctx, pir.dlqClient, *message.Body, deadLetter); err != nil {
 			zapctx.Error(ctx, "could not sent message to dlq",
 				zap.Any("message", message),
 				zap.Error(err),
@@ -16,6 +16,7 @@ import (
 	"github.com/matryer/is"
 	"github.com/pkg/errors"
 
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/sqs"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
@@ -40,7 +41,7 @@ const (
 
 func TestPaymentInstructionEventListener_Listen(t *testing.T) {
 	var (
-		dummyDLQ = &awsSqsAdapterMock.QueueMock{SendMessageFunc: func(context.Context, string) error { return nil }}
+		dummyDLQ = &awsSqsAdapterMock.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
 		is       = is.New(t)
 	)
 
@@ -68,7 +69,7 @@ func TestPaymentInstructionEventListener_Listen(t *testing.T) {
 			},
 		}
 
-		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageFunc: func(context.Context, string) error { return nil }}
+		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
 
 		listener := newListener(failingUseCaseMakePayment, spyIncomingQueue, spyDLQ, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{})
 		go listener.Listen(ctx)
@@ -104,7 +105,7 @@ func TestPaymentInstructionEventListener_Listen(t *testing.T) {
 			},
 		}
 
-		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageFunc: func(context.Context, string) error { return nil }}
+		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
 		mockedFeatureFlagService := &domainPortMocks.FeatureFlagServiceMock{
 			IsIngestionEnabledFromBankingCircleUncheckedQueueFunc: func() bool {
 				return true
@@ -154,7 +155,7 @@ func TestPaymentInstructionEventListener_Listen(t *testing.T) {
 			},
 		}
 
-		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageFunc: func(context.Context, string) error { return nil }}
+		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
 
 		listener := newListener(failingUseCaseMakePayment, spyIncomingQueue, spyDLQ, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{})
 		go listener.Listen(ctx)
@@ -184,7 +185,7 @@ func TestPaymentInstructionEventListener_Listen(t *testing.T) {
 			},
 		}
 
-		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageFunc: func(context.Context, string) error { return nil }}
+		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
 
 		listener := newListener(spyUseCaseMakePayment, spyIncomingQueue, spyDLQ, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{})
 		go listener.Listen(ctx)
@@ -237,7 +238,7 @@ func TestPaymentInstructionEventListener_Listen(t *testing.T) {
 		}
 
 		spyDLQ := &awsSqsAdapterMock.QueueMock{
-			SendMessageFunc: func(context.Context, string) error {
+			SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error {
 				return nil
 			},
 		}
@@ -254,6 +255,150 @@ func TestPaymentInstructionEventListener_Listen(t *testing.T) {
 		}
 	})
 
+	t.Run("requests the payment instructions of the bulk currencies in bulk", func(t *testing.T) {
+		is := is.New(t)
+		var (
+			ctx          = context.Background()
+			deleteCalled = make(chan struct{}, 10)
+			eurMessage   = func(pi models.PaymentInstruction) *awsSqs.Message {
+				body, err := pi.MustToJSON()
+				is.NoErr(err)
+				return awsTestHelper.NewSQSMessage(string(body))
+			}
+			eur           = models.Currency{IsoCode: models.EUR, IsoNumber: "978"}
+			accepted      = domainModelsTestHelpers.NewPaymentInstructionBuilder().WithCurrency(eur).Build()
+			failed        = domainModelsTestHelpers.NewPaymentInstructionBuilder().WithCurrency(eur).Build()
+			single        = domainModelsTestHelpers.NewPaymentInstructionBuilder().WithCurrency(models.Currency{IsoCode: models.CZK, IsoNumber: "203"}).Build()
+			singleMessage = eurMessage(single)
+			messages      = []*awsSqs.Message{eurMessage(accepted), singleMessage, eurMessage(failed), eurMessage(accepted)}
+			received      = false
+		)
+
+		useCaseMakePayment := &mocks.MakeBankingCirclePaymentMock{
+			ExecuteFunc: func(ctx context.Context, request models.PaymentInstruction) (models.ProviderPaymentID, error) {
+				return "", nil
+			},
+		}
+		useCaseMakeBulkPayment := &mocks.MakeBankingCircleBulkPaymentMock{
+			ExecuteFunc: func(ctx context.Context, instructions []models.PaymentInstruction) map[models.PaymentInstructionID]error {
+				return map[models.PaymentInstructionID]error{failed.ID(): testhelpers.RandomError()}
+			},
+		}
+		spyIncomingQueue := &awsSqsAdapterMock.QueueMock{
+			DeleteMessageFunc: func(context.Context, string) error {
+				deleteCalled <- struct{}{}
+				return nil
+			}, GetMessagesFunc: func(context.Context) (*awsSqs.ReceiveMessageOutput, error) {
+				if received {
+					return &awsSqs.ReceiveMessageOutput{}, nil
+				}
+				received = true
+				return &awsSqs.ReceiveMessageOutput{Messages: messages}, nil
+			},
+		}
+		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
+
+		listener := newListener(useCaseMakePayment, spyIncomingQueue, spyDLQ, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{})
+		listener.RequestInBulk(sqs.BulkPaymentOptions{
+			UseCase:    useCaseMakeBulkPayment,
+			Currencies: []models.CurrencyCode{models.EUR},
+			MaxSize:    10,
+			MaxWait:    time.Minute,
+		})
+		go listener.Listen(ctx)
+		defer listener.StopListening()
+
+		for range messages {
+			select {
+			case <-deleteCalled:
+			case <-time.After(sleepyTime):
+				t.Fatal("timed out waiting for delete message to be called")
+			}
+		}
+
+		is.Equal(len(useCaseMakeBulkPayment.ExecuteCalls()), 1)
+		bulk := useCaseMakeBulkPayment.ExecuteCalls()[0].Instructions
+		is.Equal(len(bulk), 2) // a payment instruction received twice is requested once
+		is.Equal(bulk[0].ID(), accepted.ID())
+		is.Equal(bulk[1].ID(), failed.ID())
+		is.Equal(len(useCaseMakePayment.ExecuteCalls()), 1)
+		is.Equal(useCaseMakePayment.ExecuteCalls()[0].Request.ID(), single.ID())
+		is.Equal(len(spyDLQ.SendMessageWithAttributesCalls()), 1)
+		is.Equal(spyDLQ.SendMessageWithAttributesCalls()[0].MessageBody, *messages[2].Body)
+	})
+
+	t.Run("does not get messages while the Banking Circle circuit is open", func(t *testing.T) {
+		ctx := context.Background()
+		spyIncomingQueue := &awsSqsAdapterMock.QueueMock{
+			GetMessagesFunc: func(context.Context) (*awsSqs.ReceiveMessageOutput, error) {
+				return &awsSqs.ReceiveMessageOutput{}, nil
+			},
+		}
+		circuitChecked := make(chan struct{}, 1)
+		openCircuit := &mocks.BankingCircleCircuitMock{ClosedFunc: func(context.Context) bool {
+			select {
+			case circuitChecked <- struct{}{}:
+			default:
+			}
+			return false
+		}}
+
+		listener := newListener(&mocks.MakeBankingCirclePaymentMock{}, spyIncomingQueue, dummyDLQ, testdoubles.FeatureFlagService{}, testdoubles.DummyMetricsClient{})
+		listener.PauseWhileOpen(openCircuit)
+		go listener.Listen(ctx)
+		defer listener.StopListening()
+
+		select {
+		case <-circuitChecked:
+			is.Equal(len(spyIncomingQueue.GetMessagesCalls()), 0)
+		case <-time.After(sleepyTime):
+			t.Fatal("timed out waiting for the circuit to be checked")
+		}
+	})
+
+	t.Run("leaves a payment instruction on the queue when the circuit opened before it was requested", func(t *testing.T) {
+		ctx := context.Background()
+		pi, err := models.PaymentInstruction{}.MustToJSON()
+		is.NoErr(err)
+
+		executed := make(chan struct{})
+		useCaseMakePayment := &mocks.MakeBankingCirclePaymentMock{
+			ExecuteFunc: func(ctx context.Context, request models.PaymentInstruction) (models.ProviderPaymentID, error) {
+				defer close(executed)
+				return "", http_client.CircuitOpenError{State: http_client.CircuitOpen}
+			},
+		}
+		received := false
+		spyIncomingQueue := &awsSqsAdapterMock.QueueMock{
+			GetMessagesFunc: func(context.Context) (*awsSqs.ReceiveMessageOutput, error) {
+				if received {
+					return &awsSqs.ReceiveMessageOutput{}, nil
+				}
+				received = true
+				return &awsSqs.ReceiveMessageOutput{Messages: []*awsSqs.Message{awsTestHelper.NewSQSMessage(string(pi))}}, nil
+			},
+			DeleteMessageFunc: func(context.Context, string) error { return nil },
+		}
+		spyDLQ := &awsSqsAdapterMock.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
+		featureFlagSvc := &domainPortMocks.FeatureFlagServiceMock{
+			IsIngestionEnabledFromBankingCircleUnprocessedQueueFunc: func() bool { return true },
+		}
+
+		listener := newListener(useCaseMakePayment, spyIncomingQueue, spyDLQ, featureFlagSvc, testdoubles.DummyMetricsClient{})
+		go listener.Listen(ctx)
+		defer listener.StopListening()
+
+		select {
+		case <-executed:
+			time.Sleep(50 * time.Millisecond)
+			is.Equal(len(spyIncomingQueue.DeleteMessageCalls()), 0)
+			is.Equal(len(spyDLQ.SendMessageWithAttributesCalls()), 0)
+			is.Equal(len(featureFlagSvc.ToggleOffIngestionFromBankingCirclePaymentsCalls()), 0)
+		case <-time.After(sleepyTime):
+			t.Fatal("timed out waiting for the use case to be executed")
+		}
+	})
+
 	t.Run("Using a feature flag to decide whether to ingest the unprocessed queue or not", func(t *testing.T) {
 		var (
 			dummyUseCaseMakePayment = &mocks.MakeBankingCirclePaymentMock{
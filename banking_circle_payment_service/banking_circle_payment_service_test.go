@@ -8,6 +8,7 @@ package banking_circle_payment_service_test
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -15,7 +16,9 @@ import (
 	"github.com/matryer/is"
 	"github.com/pkg/errors"
 
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client"
 	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
 	spe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
@@ -91,6 +94,18 @@ func TestBankingCircleMakePaymentUseCase_Execute(t *testing.T) {
 				is.Equal(len(mockBankingCircleAPIClient.RequestPaymentCalls()), 3)
 				is.Equal(mockBankingCircleAPIClient.RequestPaymentCalls()[2].Request, expectedRequestDto)
 			})
+			t.Run("from the source account of its routing rule", func(t *testing.T) {
+				ctx := context.Background()
+				incomingPaymentInstruction, expectedRequestDto := validPaymentInstructionAndExpectedRequestDto(string(models.EUR), "978", false)
+				incomingPaymentInstruction.SetSourceAccount("the-routed-iban")
+				expectedRequestDto.DebtorAccount.Account = "the-routed-iban"
+
+				_, err := makeBcPaymentUseCase.Execute(ctx, incomingPaymentInstruction)
+
+				is.NoErr(err)
+				is.Equal(len(mockBankingCircleAPIClient.RequestPaymentCalls()), 4)
+				is.Equal(mockBankingCircleAPIClient.RequestPaymentCalls()[3].Request, expectedRequestDto)
+			})
 		})
 		t.Run("notifying payment statuses", func(t *testing.T) {
 			ctx := context.Background()
@@ -125,7 +140,67 @@ func TestBankingCircleMakePaymentUseCase_Execute(t *testing.T) {
 				PaymentProviderName:      models.BC,
 				PaymentProviderPaymentID: paymentID,
 			})
+			is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
+			is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event, mockSubmissionNotifier.SendPaymentStatusCalls()[0].Event)
 		})
+
+		t.Run("a payment Banking Circle accepted isn't requested again when its acceptance can't be recorded", func(t *testing.T) {
+			ctx := context.Background()
+			mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{RequestPaymentFunc: func(context.Context, spe.RequestDto, *[]string) (spe.ResponseDto, error) {
+				return spe.ResponseDto{
+					PaymentID: paymentID,
+					Status:    string(ports.PendingProcessing),
+				}, nil
+			}}
+
+			mockPaymentNotifier := &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return errors.New("queue unavailable") }}
+			mockSubmissionNotifier := &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+
+			makeBcPaymentUseCase := NewMakeBankingCirclePayment(MakeBankingCirclePaymentOptions{
+				PaymentAPI:         mockBankingCircleAPIClient,
+				SourceAccounts:     sourceAccounts,
+				MetricsClient:      dummyMetrics,
+				PaymentNotifier:    mockPaymentNotifier,
+				SubmissionNotifier: mockSubmissionNotifier,
+				Now:                dummyNowFunc,
+			})
+
+			validPaymentReq, _ := validPaymentInstructionAndExpectedRequestDto(string(models.EUR), "978", true)
+
+			id, err := makeBcPaymentUseCase.Execute(ctx, validPaymentReq)
+			is.NoErr(err)
+			is.Equal(id, paymentID)
+			is.Equal(len(mockSubmissionNotifier.SendPaymentStatusCalls()), 1)
+		})
+	})
+	t.Run("from the first account in order of priority with enough funds to pay", func(t *testing.T) {
+		is := is.New(t)
+		mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{
+			RequestPaymentFunc: func(ctx context.Context, request spe.RequestDto, slice *[]string) (spe.ResponseDto, error) {
+				return spe.ResponseDto{PaymentID: paymentID, Status: string(ports.PendingProcessing)}, nil
+			},
+			CheckAccountBalanceFunc: func(accountID string) (bcmodels.AccountBalance, error) {
+				balances := map[string]float64{"main": 1000, "spare": 5000}
+				return bcmodels.AccountBalance{Result: []bcmodels.Balance{{Currency: models.EUR, BeginOfDayAmount: balances[accountID]}}}, nil
+			},
+		}
+		makeBcPaymentUseCase := NewMakeBankingCirclePayment(MakeBankingCirclePaymentOptions{
+			PaymentAPI: mockBankingCircleAPIClient,
+			SourceAccounts: bcmodels.SourceAccounts{
+				{Currency: "EUR", Priority: 2, AccountDetails: bcmodels.AccountDetails{Iban: "IBAN_EUR_SPARE", AccountID: "spare"}},
+				{Currency: "EUR", Priority: 1, AccountDetails: bcmodels.AccountDetails{Iban: "IBAN_EUR_MAIN", AccountID: "main"}},
+			},
+			MetricsClient:      dummyMetrics,
+			PaymentNotifier:    &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }},
+			SubmissionNotifier: &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }},
+			Now:                dummyNowFunc,
+		})
+		incomingPaymentInstruction, _ := validPaymentInstructionAndExpectedRequestDto(string(models.EUR), "978", false)
+
+		_, err := makeBcPaymentUseCase.Execute(context.Background(), incomingPaymentInstruction)
+
+		is.NoErr(err)
+		is.Equal(mockBankingCircleAPIClient.RequestPaymentCalls()[0].Request.DebtorAccount.Account, "IBAN_EUR_SPARE")
 	})
 	t.Run("Unhappy Path", func(t *testing.T) {
 		is := is.New(t)
@@ -170,6 +245,29 @@ func TestBankingCircleMakePaymentUseCase_Execute(t *testing.T) {
 				},
 			})
 		})
+		t.Run("Given the Banking Circle circuit is open Then send no event, the payment is requested again later", func(t *testing.T) {
+			ctx := context.Background()
+			circuitOpen := http_client.CircuitOpenError{State: http_client.CircuitOpen}
+			mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{RequestPaymentFunc: func(context.Context, spe.RequestDto, *[]string) (spe.ResponseDto, error) {
+				return spe.ResponseDto{}, circuitOpen
+			}}
+			mockPaymentNotifier := &mocks.PaymentNotifierMock{}
+
+			makeBcPaymentUseCase := NewMakeBankingCirclePayment(MakeBankingCirclePaymentOptions{
+				PaymentAPI:      mockBankingCircleAPIClient,
+				SourceAccounts:  sourceAccounts,
+				MetricsClient:   dummyMetrics,
+				PaymentNotifier: mockPaymentNotifier,
+				Now:             dummyNowFunc,
+			})
+
+			validPaymentReq, _ := validPaymentInstructionAndExpectedRequestDto(string(models.EUR), "978", false)
+
+			_, err := makeBcPaymentUseCase.Execute(ctx, validPaymentReq)
+			is.Equal(err, circuitOpen)
+			is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+		})
+
 		t.Run("Given calling BC RequestPayment results transient error Then send Failure event with TransportFailure code", func(t *testing.T) {
 			transportError := errors.New("oh damn")
 			ctx := context.Background()
@@ -213,6 +311,238 @@ func TestBankingCircleMakePaymentUseCase_Execute(t *testing.T) {
 	})
 }
 
+func TestBankingCircleMakeBulkPaymentUseCase_Execute(t *testing.T) {
+	sourceAccounts := testSourceAccounts()
+
+	// acceptingEverything takes every payment of the bulk, except the ones of the rejected payment instructions
+	acceptingEverything := func(rejected ...models.PaymentInstructionID) *mocks.BankingCircleAPIMock {
+		return &mocks.BankingCircleAPIMock{RequestBulkPaymentFunc: func(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error) {
+			response := bpe.ResponseDto{BulkID: "bulk-id"}
+			for _, payment := range request.Payments {
+				status := ports.PendingProcessing
+				for _, id := range rejected {
+					if string(id) == payment.ClientReference {
+						status = ports.Rejected
+					}
+				}
+				response.Payments = append(response.Payments, bpe.PaymentResultDto{
+					ClientReference:  payment.ClientReference,
+					PaymentID:        models.ProviderPaymentID("bc-" + payment.ClientReference),
+					Status:           string(status),
+					BankingReference: models.BankingReference(payment.DebtorReference),
+				})
+			}
+			return response, nil
+		}}
+	}
+
+	newUseCase := func(api ports.BankingCircleAPI, paymentNotifier, submissionNotifier ports.PaymentNotifier, maxBulkSize int) MakeBankingCircleBulkPayment {
+		return NewMakeBankingCircleBulkPayment(MakeBankingCirclePaymentOptions{
+			PaymentAPI:         api,
+			SourceAccounts:     sourceAccounts,
+			MetricsClient:      dummyMetrics,
+			PaymentNotifier:    paymentNotifier,
+			SubmissionNotifier: submissionNotifier,
+			Now:                dummyNowFunc,
+		}, maxBulkSize)
+	}
+
+	t.Run("requests a bulk payment for each source account, of at most the max bulk size, and submits every payment", func(t *testing.T) {
+		var (
+			is                     = is.New(t)
+			ctx                    = context.Background()
+			api                    = acceptingEverything()
+			mockPaymentNotifier    = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+			mockSubmissionNotifier = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+			instructions           []models.PaymentInstruction
+			expectedRequests       []spe.RequestDto
+		)
+		for _, c := range []struct {
+			currency, isoNumber string
+			highRisk            bool
+		}{{"EUR", "978", false}, {"CZK", "203", false}, {"EUR", "978", false}, {"EUR", "978", true}, {"EUR", "978", false}} {
+			instruction, requestDto := validPaymentInstructionAndExpectedRequestDto(c.currency, c.isoNumber, c.highRisk)
+			instructions = append(instructions, instruction)
+			expectedRequests = append(expectedRequests, requestDto)
+		}
+
+		failed := newUseCase(api, mockPaymentNotifier, mockSubmissionNotifier, 2).Execute(ctx, instructions)
+
+		is.Equal(len(failed), 0)
+		calls := api.RequestBulkPaymentCalls()
+		is.Equal(len(calls), 4)
+		is.Equal(calls[0].Request.DebtorAccount.Account, "IBAN_EUR")
+		is.Equal(calls[0].Request.Payments, []bpe.PaymentDto{
+			{ClientReference: string(instructions[0].ID()), RequestDto: expectedRequests[0]},
+			{ClientReference: string(instructions[2].ID()), RequestDto: expectedRequests[2]},
+		})
+		is.Equal(calls[1].Request.DebtorAccount.Account, "IBAN_EUR")
+		is.Equal(calls[1].Request.Payments, []bpe.PaymentDto{{ClientReference: string(instructions[4].ID()), RequestDto: expectedRequests[4]}})
+		is.Equal(calls[2].Request.DebtorAccount.Account, "IBAN_CZK")
+		is.Equal(calls[3].Request.DebtorAccount.Account, "IBAN_EUR_HR")
+
+		is.Equal(len(mockSubmissionNotifier.SendPaymentStatusCalls()), len(instructions))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), len(instructions))
+		is.Equal(mockSubmissionNotifier.SendPaymentStatusCalls()[0].Event, models.PaymentProviderEvent{
+			CreatedOn:                now,
+			Type:                     models.Submitted,
+			PaymentInstruction:       instructions[0],
+			PaymentProviderName:      models.BC,
+			PaymentProviderPaymentID: models.ProviderPaymentID("bc-" + string(instructions[0].ID())),
+			BankingReference:         models.BankingReference(expectedRequests[0].DebtorReference),
+		})
+	})
+
+	t.Run("retrieves the balances of the source accounts once, and takes the payments already assigned off them", func(t *testing.T) {
+		var (
+			is       = is.New(t)
+			ctx      = context.Background()
+			api      = acceptingEverything()
+			notifier = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+		)
+		api.CheckAccountBalanceFunc = func(accountID string) (bcmodels.AccountBalance, error) {
+			balances := map[string]float64{"main": 2000, "spare": 5000}
+			return bcmodels.AccountBalance{Result: []bcmodels.Balance{{Currency: models.EUR, BeginOfDayAmount: balances[accountID]}}}, nil
+		}
+		useCase := NewMakeBankingCircleBulkPayment(MakeBankingCirclePaymentOptions{
+			PaymentAPI: api,
+			SourceAccounts: bcmodels.SourceAccounts{
+				{Currency: "EUR", Priority: 1, AccountDetails: bcmodels.AccountDetails{Iban: "IBAN_EUR_MAIN", AccountID: "main"}},
+				{Currency: "EUR", Priority: 2, AccountDetails: bcmodels.AccountDetails{Iban: "IBAN_EUR_SPARE", AccountID: "spare"}},
+			},
+			MetricsClient:      dummyMetrics,
+			PaymentNotifier:    notifier,
+			SubmissionNotifier: notifier,
+			Now:                dummyNowFunc,
+		}, 10)
+		var instructions []models.PaymentInstruction
+		for i := 0; i < 3; i++ {
+			instruction, _ := validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			instructions = append(instructions, instruction)
+		}
+
+		failed := useCase.Execute(ctx, instructions)
+
+		is.Equal(len(failed), 0)
+		is.Equal(len(api.CheckAccountBalanceCalls()), 2)
+		calls := api.RequestBulkPaymentCalls()
+		is.Equal(len(calls), 2)
+		is.Equal(calls[0].Request.DebtorAccount.Account, "IBAN_EUR_MAIN")
+		is.Equal(len(calls[0].Request.Payments), 1)
+		is.Equal(calls[1].Request.DebtorAccount.Account, "IBAN_EUR_SPARE")
+		is.Equal(len(calls[1].Request.Payments), 2)
+	})
+
+	t.Run("sends a Failure event for the payments Banking Circle rejected, and no event for the ones it left out", func(t *testing.T) {
+		var (
+			is                     = is.New(t)
+			ctx                    = context.Background()
+			accepted, _            = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			rejected, _            = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			leftOut, _             = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			mockPaymentNotifier    = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+			mockSubmissionNotifier = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+		)
+		api := acceptingEverything(rejected.ID())
+		respond := api.RequestBulkPaymentFunc
+		api.RequestBulkPaymentFunc = func(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error) {
+			response, err := respond(ctx, request, slice)
+			response.Payments = response.Payments[:2]
+			return response, err
+		}
+
+		failed := newUseCase(api, mockPaymentNotifier, mockSubmissionNotifier, 10).Execute(ctx, []models.PaymentInstruction{accepted, rejected, leftOut})
+
+		is.Equal(len(failed), 0)
+		is.Equal(len(mockSubmissionNotifier.SendPaymentStatusCalls()), 1)
+		is.Equal(mockSubmissionNotifier.SendPaymentStatusCalls()[0].Event.PaymentInstruction, accepted)
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 2)
+		is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event.PaymentInstruction, accepted)
+		failure := mockPaymentNotifier.SendPaymentStatusCalls()[1].Event
+		is.Equal(failure.Type, models.Failure)
+		is.Equal(failure.PaymentInstruction, rejected)
+		is.Equal(failure.FailureReason, models.FailureReason{
+			Code:    models.RejectedCode,
+			Message: BankingCircleError{Status: ports.Rejected}.Error(),
+		})
+	})
+
+	t.Run("sends a Failure event with TransportFailure code for every payment of a bulk Banking Circle refused", func(t *testing.T) {
+		var (
+			is                  = is.New(t)
+			ctx                 = context.Background()
+			refusal             = http_client.UnrecognisedBankingCircleError{Status: http.StatusBadRequest, Action: http_client.RequestBulkPayment}
+			first, _            = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			second, _           = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			mockPaymentNotifier = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+			api                 = &mocks.BankingCircleAPIMock{RequestBulkPaymentFunc: func(context.Context, bpe.RequestDto, *[]string) (bpe.ResponseDto, error) {
+				return bpe.ResponseDto{}, refusal
+			}}
+		)
+
+		failed := newUseCase(api, mockPaymentNotifier, mockPaymentNotifier, 10).Execute(ctx, []models.PaymentInstruction{first, second})
+
+		is.Equal(len(failed), 0)
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 2)
+		is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[1].Event, models.PaymentProviderEvent{
+			CreatedOn:           now,
+			Type:                models.Failure,
+			PaymentInstruction:  second,
+			PaymentProviderName: models.BC,
+			FailureReason: models.FailureReason{
+				Code: models.TransportFailure,
+				Message: TransportError{
+					UnderlyingError: refusal,
+					ID:              second.ID(),
+					ContractNumber:  second.ContractNumber(),
+				}.Error(),
+			},
+		})
+	})
+
+	t.Run("sends no event for the payments of a bulk whose request failed without Banking Circle refusing it", func(t *testing.T) {
+		var (
+			is                  = is.New(t)
+			ctx                 = context.Background()
+			first, _            = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			second, _           = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			mockPaymentNotifier = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+		)
+
+		for _, requestErr := range []error{
+			errors.New("connection reset by peer"),
+			http_client.UnrecognisedBankingCircleError{Status: http.StatusBadGateway, Action: http_client.RequestBulkPayment},
+		} {
+			api := &mocks.BankingCircleAPIMock{RequestBulkPaymentFunc: func(context.Context, bpe.RequestDto, *[]string) (bpe.ResponseDto, error) {
+				return bpe.ResponseDto{}, requestErr
+			}}
+
+			failed := newUseCase(api, mockPaymentNotifier, mockPaymentNotifier, 10).Execute(ctx, []models.PaymentInstruction{first, second})
+
+			is.Equal(len(failed), 0)
+		}
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+
+	t.Run("returns the payment instructions whose outcome couldn't be sent", func(t *testing.T) {
+		var (
+			is                  = is.New(t)
+			ctx                 = context.Background()
+			notifierErr         = errors.New("queue is down")
+			noSourceAccount, _  = validPaymentInstructionAndExpectedRequestDto("FOO", "978", false)
+			accepted, _         = validPaymentInstructionAndExpectedRequestDto("EUR", "978", false)
+			mockPaymentNotifier = &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return notifierErr }}
+		)
+
+		failed := newUseCase(acceptingEverything(), mockPaymentNotifier, mockPaymentNotifier, 10).Execute(ctx, []models.PaymentInstruction{noSourceAccount, accepted})
+
+		is.Equal(failed, map[models.PaymentInstructionID]error{
+			noSourceAccount.ID(): notifierErr,
+			accepted.ID():        notifierErr,
+		})
+	})
+}
+
 func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 	incomingPaymentInstruction, _ := validPaymentInstructionAndExpectedRequestDto(string(models.EUR), "978", true)
 	is := is.New(t)
@@ -262,7 +592,7 @@ func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 				FailureReason:            models.FailureReason{},
 			})
 		})
-		t.Run("Given BC CheckPaymentStatus transient error should not break the loop but just try again and Then send Failure event with StuckInPending code", func(t *testing.T) {
+		t.Run("Given BC CheckPaymentStatus is still pending we Then ask for a recheck without any event, and send Processed event once processed", func(t *testing.T) {
 			ctx := context.Background()
 			mockBankingCircleAPIClient := stubAPIClientThatWillReturnUnprocessedTwiceThenProcessed()
 			mockPaymentNotifier := &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
@@ -274,6 +604,13 @@ func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 				Now:             dummyNowFunc,
 			})
 
+			for i := 0; i < 3; i++ {
+				var recheckLater RecheckLaterError
+				is.True(errors.As(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now), &recheckLater))
+				is.Equal(recheckLater.Status, ports.PendingProcessing)
+				is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+			}
+
 			is.NoErr(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now))
 			is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
 			is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event, models.PaymentProviderEvent{
@@ -288,7 +625,7 @@ func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 		})
 	})
 	t.Run("Unhappy Path", func(t *testing.T) {
-		t.Run("Given BC CheckPaymentStatus returns Rejected status we Then send StuckInPending payment event", func(t *testing.T) {
+		t.Run("Given BC CheckPaymentStatus returns PendingProcessing status past the check horizon we Then send StuckInPending payment event", func(t *testing.T) {
 			ctx := context.Background()
 			mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{CheckPaymentStatusFunc: func(providerPaymentId models.ProviderPaymentID) (ports.PaymentStatus, error) {
 				return ports.PendingProcessing, nil
@@ -297,12 +634,13 @@ func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 
 			checkBcPaymentStatusUseCase := NewCheckBankingCirclePaymentStatus(CheckBankingCirclePaymentStatusOptions{
 				PaymentAPI:      mockBankingCircleAPIClient,
+				CheckHorizon:    time.Hour,
 				MetricsClient:   dummyMetrics,
 				PaymentNotifier: mockPaymentNotifier,
 				Now:             dummyNowFunc,
 			})
 
-			err := checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now)
+			err := checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now.Add(-time.Hour))
 			is.True(strings.Contains(err.Error(), string(ports.PendingProcessing)))
 			is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
 			is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event, models.PaymentProviderEvent{
@@ -407,7 +745,7 @@ func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 			})
 		})
 
-		t.Run("Given BC CheckPaymentStatus returns an error we should Then send TransportFailure event", func(t *testing.T) {
+		t.Run("Given BC CheckPaymentStatus returns an error we should Then ask for a recheck without any event", func(t *testing.T) {
 			ctx := context.Background()
 			transportError := errors.New("oh damn")
 			mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{CheckPaymentStatusFunc: func(paymentID models.ProviderPaymentID) (ports.PaymentStatus, error) { return "", transportError }}
@@ -415,12 +753,55 @@ func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 
 			checkBcPaymentStatusUseCase := NewCheckBankingCirclePaymentStatus(CheckBankingCirclePaymentStatusOptions{
 				PaymentAPI:      mockBankingCircleAPIClient,
+				CheckHorizon:    time.Hour,
 				MetricsClient:   dummyMetrics,
 				PaymentNotifier: mockPaymentNotifier,
 				Now:             dummyNowFunc,
 			})
 
-			is.NoErr(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now))
+			err := checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now.Add(-59*time.Minute))
+			var recheckLater RecheckLaterError
+			is.True(errors.As(err, &recheckLater))
+			is.Equal(errors.Cause(recheckLater.Err), transportError)
+			is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+		})
+
+		t.Run("Given the Banking Circle circuit is open past the check horizon we should Then ask for a recheck without any event", func(t *testing.T) {
+			ctx := context.Background()
+			mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{CheckPaymentStatusFunc: func(paymentID models.ProviderPaymentID) (ports.PaymentStatus, error) {
+				return "", http_client.CircuitOpenError{State: http_client.CircuitOpen}
+			}}
+			mockPaymentNotifier := &mocks.PaymentNotifierMock{}
+
+			checkBcPaymentStatusUseCase := NewCheckBankingCirclePaymentStatus(CheckBankingCirclePaymentStatusOptions{
+				PaymentAPI:      mockBankingCircleAPIClient,
+				CheckHorizon:    time.Hour,
+				MetricsClient:   dummyMetrics,
+				PaymentNotifier: mockPaymentNotifier,
+				Now:             dummyNowFunc,
+			})
+
+			err := checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now.Add(-time.Hour))
+			var recheckLater RecheckLaterError
+			is.True(errors.As(err, &recheckLater))
+			is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+		})
+
+		t.Run("Given BC CheckPaymentStatus returns an error past the check horizon we should Then send TransportFailure event", func(t *testing.T) {
+			ctx := context.Background()
+			transportError := errors.New("oh damn")
+			mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{CheckPaymentStatusFunc: func(paymentID models.ProviderPaymentID) (ports.PaymentStatus, error) { return "", transportError }}
+			mockPaymentNotifier := &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+
+			checkBcPaymentStatusUseCase := NewCheckBankingCirclePaymentStatus(CheckBankingCirclePaymentStatusOptions{
+				PaymentAPI:      mockBankingCircleAPIClient,
+				CheckHorizon:    time.Hour,
+				MetricsClient:   dummyMetrics,
+				PaymentNotifier: mockPaymentNotifier,
+				Now:             dummyNowFunc,
+			})
+
+			is.NoErr(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now.Add(-time.Hour)))
 			is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
 			is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event, models.PaymentProviderEvent{
 				CreatedOn:                now,
@@ -440,6 +821,218 @@ func TestBankingCircleCheckPaymentStatusUseCase_Execute(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("Given BC CheckPaymentStatus returns a status someone has to act on we Then stop checking it without failing it", func(t *testing.T) {
+		ctx := context.Background()
+		mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{CheckPaymentStatusFunc: func(providerPaymentId models.ProviderPaymentID) (ports.PaymentStatus, error) {
+			return ports.PendingApproval, nil
+		}}
+		mockPaymentNotifier := &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+
+		checkBcPaymentStatusUseCase := NewCheckBankingCirclePaymentStatus(CheckBankingCirclePaymentStatusOptions{
+			PaymentAPI:      mockBankingCircleAPIClient,
+			CheckHorizon:    time.Hour,
+			MetricsClient:   dummyMetrics,
+			PaymentNotifier: mockPaymentNotifier,
+			Now:             dummyNowFunc,
+		})
+
+		is.NoErr(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now))
+		is.NoErr(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now.Add(-time.Hour)))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+
+	t.Run("Given BC CheckPaymentStatus returns Reversed status we Then send Reversal event", func(t *testing.T) {
+		ctx := context.Background()
+		mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{CheckPaymentStatusFunc: func(providerPaymentId models.ProviderPaymentID) (ports.PaymentStatus, error) {
+			return ports.Reversed, nil
+		}}
+		mockPaymentNotifier := &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+
+		checkBcPaymentStatusUseCase := NewCheckBankingCirclePaymentStatus(CheckBankingCirclePaymentStatusOptions{
+			PaymentAPI:      mockBankingCircleAPIClient,
+			MetricsClient:   dummyMetrics,
+			PaymentNotifier: mockPaymentNotifier,
+			Now:             dummyNowFunc,
+		})
+
+		is.NoErr(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
+		is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event, models.PaymentProviderEvent{
+			CreatedOn:                now,
+			Type:                     models.Reversal,
+			PaymentInstruction:       incomingPaymentInstruction,
+			PaymentProviderName:      models.BC,
+			PaymentProviderPaymentID: paymentID,
+			BankingReference:         bankingReference,
+		})
+	})
+
+	t.Run("Given Banking Circle notified the outcome of the payment already we Then don't check its status again", func(t *testing.T) {
+		ctx := context.Background()
+		settledPaymentInstruction := incomingPaymentInstruction
+		is.NoErr(settledPaymentInstruction.SubmitForProcessing())
+		is.NoErr(settledPaymentInstruction.SetStatus(models.Successful))
+		mockBankingCircleAPIClient := &mocks.BankingCircleAPIMock{}
+		mockPaymentNotifier := &mocks.PaymentNotifierMock{}
+		mockSubmittedPayments := &mocks.SubmittedPaymentFinderMock{FindSubmittedPaymentFunc: func(ctx context.Context, paymentID models.ProviderPaymentID) (models.PaymentInstruction, models.BankingReference, error) {
+			return settledPaymentInstruction, bankingReference, nil
+		}}
+
+		checkBcPaymentStatusUseCase := NewCheckBankingCirclePaymentStatus(CheckBankingCirclePaymentStatusOptions{
+			PaymentAPI:        mockBankingCircleAPIClient,
+			MetricsClient:     dummyMetrics,
+			PaymentNotifier:   mockPaymentNotifier,
+			SubmittedPayments: mockSubmittedPayments,
+			Now:               dummyNowFunc,
+		})
+
+		is.NoErr(checkBcPaymentStatusUseCase.Execute(ctx, incomingPaymentInstruction, paymentID, bankingReference, now))
+		is.Equal(len(mockBankingCircleAPIClient.CheckPaymentStatusCalls()), 0)
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+}
+
+func TestBankingCircleReceivePaymentStatusUseCase_Execute(t *testing.T) {
+	incomingPaymentInstruction, _ := validPaymentInstructionAndExpectedRequestDto(string(models.EUR), "978", true)
+	_ = incomingPaymentInstruction.SubmitForProcessing()
+	_ = incomingPaymentInstruction.SetStatus(models.StateSubmitted)
+
+	newUseCase := func(instruction models.PaymentInstruction, findErr error) (ReceiveBankingCirclePaymentStatus, *mocks.PaymentNotifierMock) {
+		mockPaymentNotifier := &mocks.PaymentNotifierMock{SendPaymentStatusFunc: func(context.Context, models.PaymentProviderEvent) error { return nil }}
+		mockSubmittedPayments := &mocks.SubmittedPaymentFinderMock{FindSubmittedPaymentFunc: func(ctx context.Context, paymentID models.ProviderPaymentID) (models.PaymentInstruction, models.BankingReference, error) {
+			return instruction, bankingReference, findErr
+		}}
+		return NewReceiveBankingCirclePaymentStatus(ReceiveBankingCirclePaymentStatusOptions{
+			SubmittedPayments: mockSubmittedPayments,
+			MetricsClient:     dummyMetrics,
+			PaymentNotifier:   mockPaymentNotifier,
+			Now:               dummyNowFunc,
+		}), mockPaymentNotifier
+	}
+
+	t.Run("Given BC notifies a processed payment we Then send Processed event", func(t *testing.T) {
+		is := is.New(t)
+		receiveUseCase, mockPaymentNotifier := newUseCase(incomingPaymentInstruction, nil)
+
+		is.NoErr(receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: string(ports.Processed)}))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
+		is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event, models.PaymentProviderEvent{
+			CreatedOn:                now,
+			Type:                     models.Processed,
+			PaymentInstruction:       incomingPaymentInstruction,
+			PaymentProviderName:      models.BC,
+			PaymentProviderPaymentID: paymentID,
+			BankingReference:         bankingReference,
+		})
+	})
+
+	t.Run("Given BC notifies a rejected payment we Then send Failure event including RejectedCode", func(t *testing.T) {
+		is := is.New(t)
+		receiveUseCase, mockPaymentNotifier := newUseCase(incomingPaymentInstruction, nil)
+
+		is.NoErr(receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: string(ports.Rejected)}))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
+		is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event, models.PaymentProviderEvent{
+			CreatedOn:                now,
+			Type:                     models.Failure,
+			PaymentInstruction:       incomingPaymentInstruction,
+			PaymentProviderName:      models.BC,
+			PaymentProviderPaymentID: paymentID,
+			BankingReference:         bankingReference,
+			FailureReason: models.FailureReason{
+				Code:    models.RejectedCode,
+				Message: BankingCircleError{Status: ports.Rejected}.Error(),
+			},
+		})
+	})
+
+	t.Run("Given BC notifies a pending payment we Then send no event", func(t *testing.T) {
+		is := is.New(t)
+		receiveUseCase, mockPaymentNotifier := newUseCase(incomingPaymentInstruction, nil)
+
+		is.NoErr(receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: string(ports.PendingProcessing)}))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+
+	t.Run("Given BC notifies a payment it holds we Then send no event", func(t *testing.T) {
+		is := is.New(t)
+		receiveUseCase, mockPaymentNotifier := newUseCase(incomingPaymentInstruction, nil)
+
+		for _, status := range []string{"Hold", "PendingApproval", "ScaPending"} {
+			is.NoErr(receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: status}))
+		}
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+
+	t.Run("Given BC notifies a payment whose outcome is known already we Then send no event", func(t *testing.T) {
+		is := is.New(t)
+		settledPaymentInstruction := incomingPaymentInstruction
+		is.NoErr(settledPaymentInstruction.SetStatus(models.Successful))
+		receiveUseCase, mockPaymentNotifier := newUseCase(settledPaymentInstruction, nil)
+
+		is.NoErr(receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: string(ports.Processed)}))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+
+	t.Run("Given BC notifies a reversal of a successful payment we Then send Reversal event", func(t *testing.T) {
+		is := is.New(t)
+		successfulPaymentInstruction := incomingPaymentInstruction
+		is.NoErr(successfulPaymentInstruction.SetStatus(models.Successful))
+		receiveUseCase, mockPaymentNotifier := newUseCase(successfulPaymentInstruction, nil)
+
+		is.NoErr(receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: string(ports.Reversed)}))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 1)
+		is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event.Type, models.Reversal)
+		is.Equal(mockPaymentNotifier.SendPaymentStatusCalls()[0].Event.OutcomeStatus(), models.Reversed)
+	})
+
+	t.Run("Given BC notifies a payment held for approval we Then send no event", func(t *testing.T) {
+		is := is.New(t)
+		receiveUseCase, mockPaymentNotifier := newUseCase(incomingPaymentInstruction, nil)
+
+		is.NoErr(receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: string(ports.PendingApproval)}))
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+
+	t.Run("Given BC notifies a payment we didn't submit we Then return the error", func(t *testing.T) {
+		is := is.New(t)
+		missing := errors.New("payment not found")
+		receiveUseCase, mockPaymentNotifier := newUseCase(models.PaymentInstruction{}, missing)
+
+		err := receiveUseCase.Execute(context.Background(), bcmodels.PaymentStatusNotification{PaymentID: paymentID, Status: string(ports.Processed)})
+		is.Equal(err, missing)
+		is.Equal(len(mockPaymentNotifier.SendPaymentStatusCalls()), 0)
+	})
+}
+
+func TestMapStatusToFailureCode(t *testing.T) {
+	is := is.New(t)
+	testData := []struct {
+		status   ports.PaymentStatus
+		expected models.PPEventFailureCode
+	}{
+		{ports.PendingProcessing, models.StuckInPending},
+		{ports.Approved, models.StuckInPending},
+		{ports.PendingCancellation, models.StuckInPending},
+		{ports.ScaPending, models.NeedsManualAction},
+		{ports.PendingApproval, models.NeedsManualAction},
+		{ports.PendingCancellationApproval, models.NeedsManualAction},
+		{ports.Hold, models.NeedsManualAction},
+		{ports.Unknown, models.NeedsManualAction},
+		{ports.Rejected, models.RejectedCode},
+		{ports.DeclinedByApprover, models.RejectedCode},
+		{ports.ScaDeclined, models.RejectedCode},
+		{ports.ScaFailed, models.RejectedCode},
+		{ports.ScaExpired, models.RejectedCode},
+		{ports.MissingFunding, models.MissingFunding},
+		{ports.Cancelled, models.CancelledCode},
+		{ports.PaymentStatus("SomethingNew"), models.UnhandledPaymentProviderStatus},
+	}
+
+	for _, data := range testData {
+		is.Equal(MapStatusToFailureCode(data.status), data.expected) // failure code of the status
+	}
 }
 
 func TestBankingCircleGetRejectionReportUseCase_Execute(t *testing.T) {
@@ -511,6 +1104,10 @@ func (s *stubAPIClient) RequestPayment(context.Context, spe.RequestDto, *[]strin
 	}, nil
 }
 
+func (s *stubAPIClient) RequestBulkPayment(context.Context, bpe.RequestDto, *[]string) (bpe.ResponseDto, error) {
+	return bpe.ResponseDto{}, nil
+}
+
 func (s *stubAPIClient) CheckPaymentStatus(paymentRequestID models.ProviderPaymentID) (ports.PaymentStatus, error) {
 	fmt.Println("--- CheckPaymentStatus called", paymentRequestID, s.checkPaymentStatusCallCount)
 	if paymentRequestID != "banking-circle-payment-id" {
@@ -568,7 +1165,7 @@ func validPaymentInstructionAndExpectedRequestDto(currency string, currencyIsoNu
 		Payment: models.Payment{
 			Sender: models.Sender{
 				Name:          "SAXO",
-				AccountNumber: "the-sender-iban",
+				AccountNumber: "",
 				BranchCode:    "",
 			},
 			Amount: "1234.56",
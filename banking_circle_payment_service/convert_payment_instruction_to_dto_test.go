@@ -62,3 +62,30 @@ func TestConvertPaymentInstructionToDTO(t *testing.T) {
 		is.Equal(dto.CreditorName, expectedName)
 	})
 }
+
+func TestParseDebtorReference(t *testing.T) {
+	var (
+		ctx = context.Background()
+		is  = is2.New(t)
+	)
+
+	t.Run("reads back the contract number and execution date the payment was requested with", func(t *testing.T) {
+		incoming := testhelpers.NewIncomingInstructionBuilder().Build()
+		paymentInstruction := models.PaymentInstruction{IncomingInstruction: incoming}
+		dto, err := use_cases.ConvertPaymentInstructionToDto(ctx, &paymentInstruction, use_cases.NewObserver(testdoubles.DummyMetricsClient{}))
+		is.NoErr(err)
+
+		contractNumber, executionDate, ok := use_cases.ParseDebtorReference(dto.DebtorReference)
+
+		is.True(ok)
+		is.Equal(contractNumber, incoming.Merchant.ContractNumber)
+		is.Equal(executionDate.Format("2006-01-02"), incoming.Payment.ExecutionDate.Format("2006-01-02"))
+	})
+
+	t.Run("a reference the payment wasn't requested with can't be read", func(t *testing.T) {
+		for _, reference := range []string{"", "Invoice 123 20220920", "Settlm 123", "Settlm 12345678901234567890 2022"} {
+			_, _, ok := use_cases.ParseDebtorReference(reference)
+			is.True(!ok)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package bulk_payment_endpoint
+
+import (
+	"encoding/json"
+
+	spe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
+)
+
+// RequestDto is the representation of the request body submitted to the
+// bulk payment endpoint of the Banking Circle API, every payment of a bulk is made from the same debtor account.
+type RequestDto struct {
+	DebtorAccount spe.DebtorAccount `json:"debtorAccount"`
+	Payments      []PaymentDto      `json:"payments"`
+}
+
+// PaymentDto is a single payment of the bulk, Banking Circle reports its outcome under the ClientReference.
+type PaymentDto struct {
+	ClientReference string `json:"clientReference"`
+	spe.RequestDto
+}
+
+func (r RequestDto) ToJSON() []byte {
+	out, _ := json.Marshal(r)
+	return out
+}
@@ -0,0 +1,46 @@
+package bulk_payment_endpoint
+
+import (
+	"encoding/json"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type ResponseDto struct {
+	BulkID   string             `json:"bulkId"`
+	Payments []PaymentResultDto `json:"payments"`
+}
+
+type PaymentResultDto struct {
+	ClientReference  string                   `json:"clientReference"`
+	PaymentID        models.ProviderPaymentID `json:"paymentId"`
+	Status           string                   `json:"status"`
+	BankingReference models.BankingReference  `json:"bankingReference"`
+}
+
+// ByClientReference indexes the results of the bulk by the ClientReference of their payment.
+func (r ResponseDto) ByClientReference() map[string]PaymentResultDto {
+	results := make(map[string]PaymentResultDto, len(r.Payments))
+	for _, result := range r.Payments {
+		results[result.ClientReference] = result
+	}
+	return results
+}
+
+// NewResponseDTOFromJSON reads the results of the bulk, the banking reference of each result is the debtor
+// reference of its payment in the request.
+func NewResponseDTOFromJSON(in []byte, request RequestDto) (ResponseDto, error) {
+	var out ResponseDto
+	if err := json.Unmarshal(in, &out); err != nil {
+		return out, err
+	}
+
+	debtorReferences := make(map[string]string, len(request.Payments))
+	for _, payment := range request.Payments {
+		debtorReferences[payment.ClientReference] = payment.DebtorReference
+	}
+	for i, result := range out.Payments {
+		out.Payments[i].BankingReference = models.BankingReference(debtorReferences[result.ClientReference])
+	}
+	return out, nil
+}
@@ -0,0 +1,42 @@
+//go:build unit
+// +build unit
+
+package bulk_payment_endpoint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
+	spe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+func TestNewResponseDTOFromJSON(t *testing.T) {
+	request := bpe.RequestDto{Payments: []bpe.PaymentDto{
+		{ClientReference: "pi-1", RequestDto: spe.RequestDto{DebtorReference: "Settlm 1 20210525"}},
+		{ClientReference: "pi-2", RequestDto: spe.RequestDto{DebtorReference: "Settlm 2 20210525"}},
+	}}
+	body := `{"bulkId":"bulk-1","payments":[{"clientReference":"pi-2","paymentId":"bc-2","status":"Rejected"},{"clientReference":"pi-1","paymentId":"bc-1","status":"PendingProcessing"}]}`
+
+	response, err := bpe.NewResponseDTOFromJSON([]byte(body), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, "bulk-1", response.BulkID)
+	assert.Equal(t, map[string]bpe.PaymentResultDto{
+		"pi-1": {ClientReference: "pi-1", PaymentID: "bc-1", Status: "PendingProcessing", BankingReference: models.BankingReference("Settlm 1 20210525")},
+		"pi-2": {ClientReference: "pi-2", PaymentID: "bc-2", Status: "Rejected", BankingReference: models.BankingReference("Settlm 2 20210525")},
+	}, response.ByClientReference())
+}
+
+func TestRequestDto_ToJSON(t *testing.T) {
+	request := bpe.RequestDto{
+		DebtorAccount: spe.DebtorAccount{Account: "IBAN_EUR"},
+		Payments:      []bpe.PaymentDto{{ClientReference: "pi-1", RequestDto: spe.RequestDto{DebtorReference: "Settlm 1 20210525"}}},
+	}
+
+	assert.Contains(t, string(request.ToJSON()), `"payments":[{"clientReference":"pi-1","requestedExecutionDate"`)
+	assert.Contains(t, string(request.ToJSON()), `"debtorReference":"Settlm 1 20210525"`)
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// PaymentStatusNotification is the body of the call Banking Circle makes when the status of a payment changes.
+type PaymentStatusNotification struct {
+	PaymentID models.ProviderPaymentID `json:"paymentId"`
+	Status    string                   `json:"status"`
+}
+
+func NewPaymentStatusNotificationFromJSON(in []byte) (PaymentStatusNotification, error) {
+	var out PaymentStatusNotification
+	err := json.Unmarshal(in, &out)
+	return out, err
+}
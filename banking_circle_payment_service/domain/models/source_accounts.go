@@ -1,8 +1,12 @@
 package models
 
+import "sort"
+
 type SourceAccount struct {
-	Currency       string         `json:"currency"`
-	IsHighRisk     bool           `json:"isHighRisk"`
+	Currency   string `json:"currency"`
+	IsHighRisk bool   `json:"isHighRisk"`
+	// Priority orders the accounts of the same currency and risk class, the lowest one is tried first.
+	Priority       int            `json:"priority,omitempty"`
 	AccountDetails AccountDetails `json:"accountDetails"`
 }
 
@@ -12,15 +16,42 @@ type AccountDetails struct {
 	MaxIntraDayLoan float64 `json:"max_intra_day_loan"`
 }
 
+// AccountFunds is the balance and intraday loan of a source account.
+type AccountFunds struct {
+	Iban            string
+	Balance         float64
+	MaxIntraDayLoan float64
+}
+
 type SourceAccounts []SourceAccount
 
 // TODO: should currency have its own type here?
+// FindAccountNumber finds the account of the currency and risk class with the highest priority.
 func (s SourceAccounts) FindAccountNumber(currency string, isHighRisk bool) (AccountDetails, bool) {
+	accounts := s.FindAccounts(currency, isHighRisk)
+	if len(accounts) == 0 {
+		return AccountDetails{}, false
+	}
+
+	return accounts[0], true
+}
+
+// FindAccounts finds the accounts of the currency and risk class in order of priority, the accounts with the same
+// priority are kept in the order they are configured in.
+func (s SourceAccounts) FindAccounts(currency string, isHighRisk bool) []AccountDetails {
+	var matching SourceAccounts
 	for _, bcAccount := range s {
 		if bcAccount.Currency == currency && bcAccount.IsHighRisk == isHighRisk {
-			return bcAccount.AccountDetails, true
+			matching = append(matching, bcAccount)
 		}
 	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].Priority < matching[j].Priority
+	})
 
-	return AccountDetails{}, false
+	accounts := make([]AccountDetails, 0, len(matching))
+	for _, bcAccount := range matching {
+		accounts = append(accounts, bcAccount.AccountDetails)
+	}
+	return accounts
 }
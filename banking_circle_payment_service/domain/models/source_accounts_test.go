@@ -56,3 +56,20 @@ func TestSourceAccounts_FindAccountNumber(t *testing.T) {
 		is.True(!ok)
 	})
 }
+
+func TestSourceAccounts_FindAccounts(t *testing.T) {
+	is := is.New(t)
+	mockSourceAccounts := models.SourceAccounts{
+		{Currency: "EUR", Priority: 2, AccountDetails: models.AccountDetails{Iban: "IBAN_EUR_SPARE"}},
+		{Currency: "EUR", IsHighRisk: true, AccountDetails: models.AccountDetails{Iban: "IBAN_EUR_HR"}},
+		{Currency: "EUR", Priority: 1, AccountDetails: models.AccountDetails{Iban: "IBAN_EUR_MAIN"}},
+		{Currency: "EUR", Priority: 2, AccountDetails: models.AccountDetails{Iban: "IBAN_EUR_SPARE_2"}},
+	}
+
+	accounts := mockSourceAccounts.FindAccounts("EUR", false)
+
+	is.Equal(accounts, []models.AccountDetails{{Iban: "IBAN_EUR_MAIN"}, {Iban: "IBAN_EUR_SPARE"}, {Iban: "IBAN_EUR_SPARE_2"}})
+	account, ok := mockSourceAccounts.FindAccountNumber("EUR", false)
+	is.True(ok)
+	is.Equal(account.Iban, "IBAN_EUR_MAIN")
+}
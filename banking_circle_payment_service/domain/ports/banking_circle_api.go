@@ -6,6 +6,7 @@ import (
 	"context"
 
 	models2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
 	spe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 )
@@ -15,26 +16,24 @@ type PaymentStatus string
 // all available statuses from banking circle
 // when calling endpoints [GET]/api/v1/payments/singles/{payment-id}
 // and [POST] /api/v1/payments/singles
-// SPS prefix are our defined values.
 const (
-	Processed         PaymentStatus = "Processed"         // both
-	PendingProcessing PaymentStatus = "PendingProcessing" // both
-	Rejected          PaymentStatus = "Rejected"          // payments/singles/{payment-id}
-	MissingFunding    PaymentStatus = "MissingFunding"    // both
-	// SPSUnknownValue   PaymentStatus = ""
-	// ScaPending                  PaymentStatus = "ScaPending"                  // both
-	// PendingApproval             PaymentStatus = "PendingApproval"             // both
-	// Approved                    PaymentStatus = "Approved"                    // both
-	// Unknown                     PaymentStatus = "Unknown"                     // payments/singles/{payment-id}
-	// ScaExpired                  PaymentStatus = "ScaExpired"                  // payments/singles/{payment-id}
-	// ScaFailed                   PaymentStatus = "ScaFailed"                   // payments/singles/{payment-id}
-	// Hold                        PaymentStatus = "Hold"                        // payments/singles/{payment-id}
-	// PendingCancellation         PaymentStatus = "PendingCancellation"         // payments/singles/{payment-id}
-	// PendingCancellationApproval PaymentStatus = "PendingCancellationApproval" // payments/singles/{payment-id}
-	// DeclinedByApprover          PaymentStatus = "DeclinedByApprover"          // payments/singles/{payment-id}
-	// Cancelled                   PaymentStatus = "Cancelled"                   // payments/singles/{payment-id}
-	// Reversed                    PaymentStatus = "Reversed"                    // payments/singles/{payment-id}
-	// ScaDeclined                 PaymentStatus = "ScaDeclined"                 // payments/singles/{payment-id}.
+	Processed                   PaymentStatus = "Processed"                   // both
+	PendingProcessing           PaymentStatus = "PendingProcessing"           // both
+	Rejected                    PaymentStatus = "Rejected"                    // payments/singles/{payment-id}
+	MissingFunding              PaymentStatus = "MissingFunding"              // both
+	ScaPending                  PaymentStatus = "ScaPending"                  // both
+	PendingApproval             PaymentStatus = "PendingApproval"             // both
+	Approved                    PaymentStatus = "Approved"                    // both
+	Unknown                     PaymentStatus = "Unknown"                     // payments/singles/{payment-id}
+	ScaExpired                  PaymentStatus = "ScaExpired"                  // payments/singles/{payment-id}
+	ScaFailed                   PaymentStatus = "ScaFailed"                   // payments/singles/{payment-id}
+	Hold                        PaymentStatus = "Hold"                        // payments/singles/{payment-id}
+	PendingCancellation         PaymentStatus = "PendingCancellation"         // payments/singles/{payment-id}
+	PendingCancellationApproval PaymentStatus = "PendingCancellationApproval" // payments/singles/{payment-id}
+	DeclinedByApprover          PaymentStatus = "DeclinedByApprover"          // payments/singles/{payment-id}
+	Cancelled                   PaymentStatus = "Cancelled"                   // payments/singles/{payment-id}
+	Reversed                    PaymentStatus = "Reversed"                    // payments/singles/{payment-id}
+	ScaDeclined                 PaymentStatus = "ScaDeclined"                 // payments/singles/{payment-id}.
 )
 
 type BankingCirclePaymentRequester interface {
@@ -42,6 +41,12 @@ type BankingCirclePaymentRequester interface {
 	RequestPayment(ctx context.Context, request spe.RequestDto, slice *[]string) (spe.ResponseDto, error)
 }
 
+type BankingCircleBulkPaymentRequester interface {
+	// RequestBulkPayment makes a BC bulk payment request call for payments made from the same account, and returns
+	// the paymentID of every payment Banking Circle took, under the client reference it was requested with.
+	RequestBulkPayment(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error)
+}
+
 type BankingCirclePaymentStatusChecker interface {
 	// CheckPaymentStatus uses a paymentID to check the status of the request, and returns the status.
 	// This paymentID is how the BankingCircle API internally tracks the request, and will be different
@@ -63,6 +68,7 @@ type BankingCircleRejectionReport interface {
 
 type BankingCircleAPI interface {
 	BankingCirclePaymentRequester
+	BankingCircleBulkPaymentRequester
 	BankingCirclePaymentStatusChecker
 	BankingCircleAccountBalanceChecker
 	BankingCircleRejectionReport
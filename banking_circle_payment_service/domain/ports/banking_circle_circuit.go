@@ -0,0 +1,12 @@
+//go:generate moq -out mocks/banking_circle_circuit_moq.go -pkg mocks . BankingCircleCircuit
+
+package ports
+
+import (
+	"context"
+)
+
+type BankingCircleCircuit interface {
+	// Closed tells whether Banking Circle can be called, the listeners stop taking messages while it can't.
+	Closed(ctx context.Context) bool
+}
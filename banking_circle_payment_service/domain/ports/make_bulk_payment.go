@@ -0,0 +1,15 @@
+//go:generate moq -out mocks/make_banking_circle_bulk_payment_moq.go -pkg mocks . MakeBankingCircleBulkPayment
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type MakeBankingCircleBulkPayment interface {
+	// Execute returns the payment instructions whose outcome couldn't be sent, the others are either with Banking Circle
+	// or failed already.
+	Execute(ctx context.Context, instructions []models.PaymentInstruction) (failed map[models.PaymentInstructionID]error)
+}
@@ -6,6 +6,7 @@ package mocks
 import (
 	"context"
 	models2 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
 	spe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 	ppEvents "github.com/saltpay/settlements-payments-system/internal/domain/models"
@@ -31,6 +32,9 @@ var _ ports.BankingCircleAPI = &BankingCircleAPIMock{}
 // 			GetRejectionReportFunc: func(date string) (models2.RejectionReport, error) {
 // 				panic("mock out the GetRejectionReport method")
 // 			},
+// 			RequestBulkPaymentFunc: func(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error) {
+// 				panic("mock out the RequestBulkPayment method")
+// 			},
 // 			RequestPaymentFunc: func(ctx context.Context, request spe.RequestDto, slice *[]string) (spe.ResponseDto, error) {
 // 				panic("mock out the RequestPayment method")
 // 			},
@@ -50,6 +54,9 @@ type BankingCircleAPIMock struct {
 	// GetRejectionReportFunc mocks the GetRejectionReport method.
 	GetRejectionReportFunc func(date string) (models2.RejectionReport, error)
 
+	// RequestBulkPaymentFunc mocks the RequestBulkPayment method.
+	RequestBulkPaymentFunc func(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error)
+
 	// RequestPaymentFunc mocks the RequestPayment method.
 	RequestPaymentFunc func(ctx context.Context, request spe.RequestDto, slice *[]string) (spe.ResponseDto, error)
 
@@ -70,6 +77,15 @@ type BankingCircleAPIMock struct {
 			// Date is the date argument value.
 			Date string
 		}
+		// RequestBulkPayment holds details about calls to the RequestBulkPayment method.
+		RequestBulkPayment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Request is the request argument value.
+			Request bpe.RequestDto
+			// Slice is the slice argument value.
+			Slice *[]string
+		}
 		// RequestPayment holds details about calls to the RequestPayment method.
 		RequestPayment []struct {
 			// Ctx is the ctx argument value.
@@ -83,6 +99,7 @@ type BankingCircleAPIMock struct {
 	lockCheckAccountBalance sync.RWMutex
 	lockCheckPaymentStatus  sync.RWMutex
 	lockGetRejectionReport  sync.RWMutex
+	lockRequestBulkPayment  sync.RWMutex
 	lockRequestPayment      sync.RWMutex
 }
 
@@ -104,7 +121,8 @@ func (mock *BankingCircleAPIMock) CheckAccountBalance(accountID string) (models2
 
 // CheckAccountBalanceCalls gets all the calls that were made to CheckAccountBalance.
 // Check the length with:
-//     len(mockedBankingCircleAPI.CheckAccountBalanceCalls())
+//
+// 	len(mockedBankingCircleAPI.CheckAccountBalanceCalls())
 func (mock *BankingCircleAPIMock) CheckAccountBalanceCalls() []struct {
 	AccountID string
 } {
@@ -135,7 +153,8 @@ func (mock *BankingCircleAPIMock) CheckPaymentStatus(paymentID ppEvents.Provider
 
 // CheckPaymentStatusCalls gets all the calls that were made to CheckPaymentStatus.
 // Check the length with:
-//     len(mockedBankingCircleAPI.CheckPaymentStatusCalls())
+//
+// 	len(mockedBankingCircleAPI.CheckPaymentStatusCalls())
 func (mock *BankingCircleAPIMock) CheckPaymentStatusCalls() []struct {
 	PaymentID ppEvents.ProviderPaymentID
 } {
@@ -166,7 +185,8 @@ func (mock *BankingCircleAPIMock) GetRejectionReport(date string) (models2.Rejec
 
 // GetRejectionReportCalls gets all the calls that were made to GetRejectionReport.
 // Check the length with:
-//     len(mockedBankingCircleAPI.GetRejectionReportCalls())
+//
+// 	len(mockedBankingCircleAPI.GetRejectionReportCalls())
 func (mock *BankingCircleAPIMock) GetRejectionReportCalls() []struct {
 	Date string
 } {
@@ -179,6 +199,46 @@ func (mock *BankingCircleAPIMock) GetRejectionReportCalls() []struct {
 	return calls
 }
 
+// RequestBulkPayment calls RequestBulkPaymentFunc.
+func (mock *BankingCircleAPIMock) RequestBulkPayment(ctx context.Context, request bpe.RequestDto, slice *[]string) (bpe.ResponseDto, error) {
+	if mock.RequestBulkPaymentFunc == nil {
+		panic("BankingCircleAPIMock.RequestBulkPaymentFunc: method is nil but BankingCircleAPI.RequestBulkPayment was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Request bpe.RequestDto
+		Slice   *[]string
+	}{
+		Ctx:     ctx,
+		Request: request,
+		Slice:   slice,
+	}
+	mock.lockRequestBulkPayment.Lock()
+	mock.calls.RequestBulkPayment = append(mock.calls.RequestBulkPayment, callInfo)
+	mock.lockRequestBulkPayment.Unlock()
+	return mock.RequestBulkPaymentFunc(ctx, request, slice)
+}
+
+// RequestBulkPaymentCalls gets all the calls that were made to RequestBulkPayment.
+// Check the length with:
+//
+// 	len(mockedBankingCircleAPI.RequestBulkPaymentCalls())
+func (mock *BankingCircleAPIMock) RequestBulkPaymentCalls() []struct {
+	Ctx     context.Context
+	Request bpe.RequestDto
+	Slice   *[]string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Request bpe.RequestDto
+		Slice   *[]string
+	}
+	mock.lockRequestBulkPayment.RLock()
+	calls = mock.calls.RequestBulkPayment
+	mock.lockRequestBulkPayment.RUnlock()
+	return calls
+}
+
 // RequestPayment calls RequestPaymentFunc.
 func (mock *BankingCircleAPIMock) RequestPayment(ctx context.Context, request spe.RequestDto, slice *[]string) (spe.ResponseDto, error) {
 	if mock.RequestPaymentFunc == nil {
@@ -201,7 +261,8 @@ func (mock *BankingCircleAPIMock) RequestPayment(ctx context.Context, request sp
 
 // RequestPaymentCalls gets all the calls that were made to RequestPayment.
 // Check the length with:
-//     len(mockedBankingCircleAPI.RequestPaymentCalls())
+//
+// 	len(mockedBankingCircleAPI.RequestPaymentCalls())
 func (mock *BankingCircleAPIMock) RequestPaymentCalls() []struct {
 	Ctx     context.Context
 	Request spe.RequestDto
@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 	"sync"
 )
@@ -18,9 +19,12 @@ var _ ports.RetrieveBankingCircleAccountFunds = &RetrieveBankingCircleAccountFun
 //
 // 		// make and configure a mocked ports.RetrieveBankingCircleAccountFunds
 // 		mockedRetrieveBankingCircleAccountFunds := &RetrieveBankingCircleAccountFundsMock{
-// 			ExecuteFunc: func(currency string, highRisk bool) (float64, float64, error) {
+// 			ExecuteFunc: func(currency string, highRisk bool, amount float64) (float64, float64, error) {
 // 				panic("mock out the Execute method")
 // 			},
+// 			AccountsFundsFunc: func(currency string, highRisk bool) ([]models.AccountFunds, error) {
+// 				panic("mock out the AccountsFunds method")
+// 			},
 // 		}
 //
 // 		// use mockedRetrieveBankingCircleAccountFunds in code that requires ports.RetrieveBankingCircleAccountFunds
@@ -29,7 +33,10 @@ var _ ports.RetrieveBankingCircleAccountFunds = &RetrieveBankingCircleAccountFun
 // 	}
 type RetrieveBankingCircleAccountFundsMock struct {
 	// ExecuteFunc mocks the Execute method.
-	ExecuteFunc func(currency string, highRisk bool) (float64, float64, error)
+	ExecuteFunc func(currency string, highRisk bool, amount float64) (float64, float64, error)
+
+	// AccountsFundsFunc mocks the AccountsFunds method.
+	AccountsFundsFunc func(currency string, highRisk bool) ([]models.AccountFunds, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -39,42 +46,93 @@ type RetrieveBankingCircleAccountFundsMock struct {
 			Currency string
 			// HighRisk is the highRisk argument value.
 			HighRisk bool
+			// Amount is the amount argument value.
+			Amount float64
+		}
+		// AccountsFunds holds details about calls to the AccountsFunds method.
+		AccountsFunds []struct {
+			// Currency is the currency argument value.
+			Currency string
+			// HighRisk is the highRisk argument value.
+			HighRisk bool
 		}
 	}
-	lockExecute sync.RWMutex
+	lockExecute       sync.RWMutex
+	lockAccountsFunds sync.RWMutex
 }
 
 // Execute calls ExecuteFunc.
-func (mock *RetrieveBankingCircleAccountFundsMock) Execute(currency string, highRisk bool) (float64, float64, error) {
+func (mock *RetrieveBankingCircleAccountFundsMock) Execute(currency string, highRisk bool, amount float64) (float64, float64, error) {
 	if mock.ExecuteFunc == nil {
 		panic("RetrieveBankingCircleAccountFundsMock.ExecuteFunc: method is nil but RetrieveBankingCircleAccountFunds.Execute was just called")
 	}
 	callInfo := struct {
 		Currency string
 		HighRisk bool
+		Amount   float64
 	}{
 		Currency: currency,
 		HighRisk: highRisk,
+		Amount:   amount,
 	}
 	mock.lockExecute.Lock()
 	mock.calls.Execute = append(mock.calls.Execute, callInfo)
 	mock.lockExecute.Unlock()
-	return mock.ExecuteFunc(currency, highRisk)
+	return mock.ExecuteFunc(currency, highRisk, amount)
 }
 
 // ExecuteCalls gets all the calls that were made to Execute.
 // Check the length with:
-//     len(mockedRetrieveBankingCircleAccountFunds.ExecuteCalls())
+//
+// 	len(mockedRetrieveBankingCircleAccountFunds.ExecuteCalls())
 func (mock *RetrieveBankingCircleAccountFundsMock) ExecuteCalls() []struct {
 	Currency string
 	HighRisk bool
+	Amount   float64
 } {
 	var calls []struct {
 		Currency string
 		HighRisk bool
+		Amount   float64
 	}
 	mock.lockExecute.RLock()
 	calls = mock.calls.Execute
 	mock.lockExecute.RUnlock()
 	return calls
 }
+
+// AccountsFunds calls AccountsFundsFunc.
+func (mock *RetrieveBankingCircleAccountFundsMock) AccountsFunds(currency string, highRisk bool) ([]models.AccountFunds, error) {
+	if mock.AccountsFundsFunc == nil {
+		panic("RetrieveBankingCircleAccountFundsMock.AccountsFundsFunc: method is nil but RetrieveBankingCircleAccountFunds.AccountsFunds was just called")
+	}
+	callInfo := struct {
+		Currency string
+		HighRisk bool
+	}{
+		Currency: currency,
+		HighRisk: highRisk,
+	}
+	mock.lockAccountsFunds.Lock()
+	mock.calls.AccountsFunds = append(mock.calls.AccountsFunds, callInfo)
+	mock.lockAccountsFunds.Unlock()
+	return mock.AccountsFundsFunc(currency, highRisk)
+}
+
+// AccountsFundsCalls gets all the calls that were made to AccountsFunds.
+// Check the length with:
+//
+// 	len(mockedRetrieveBankingCircleAccountFunds.AccountsFundsCalls())
+func (mock *RetrieveBankingCircleAccountFundsMock) AccountsFundsCalls() []struct {
+	Currency string
+	HighRisk bool
+} {
+	var calls []struct {
+		Currency string
+		HighRisk bool
+	}
+	mock.lockAccountsFunds.RLock()
+	calls = mock.calls.AccountsFunds
+	mock.lockAccountsFunds.RUnlock()
+	return calls
+}
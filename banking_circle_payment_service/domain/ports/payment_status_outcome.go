@@ -0,0 +1,40 @@
+package ports
+
+// PaymentStatusOutcome is what a PaymentStatus means for the payment instruction of the payment.
+type PaymentStatusOutcome string
+
+const (
+	// OutcomeSucceeded is a payment Banking Circle processed, it is final unless Banking Circle reverses it.
+	OutcomeSucceeded PaymentStatusOutcome = "succeeded"
+	// OutcomeFailed is a payment Banking Circle won't process.
+	OutcomeFailed PaymentStatusOutcome = "failed"
+	// OutcomePending is a payment Banking Circle is still working on, its status changes without anyone acting on it.
+	OutcomePending PaymentStatusOutcome = "pending"
+	// OutcomeNeedsManualAction is a payment Banking Circle holds until someone acts on it, e.g. approves it.
+	OutcomeNeedsManualAction PaymentStatusOutcome = "needs_manual_action"
+	// OutcomeReversed is a payment Banking Circle took back, usually after having processed it.
+	OutcomeReversed PaymentStatusOutcome = "reversed"
+)
+
+// Outcome maps the status of a payment to what it means for its payment instruction, a status Banking Circle
+// doesn't document is a failure.
+func (s PaymentStatus) Outcome() PaymentStatusOutcome {
+	switch s {
+	case Processed:
+		return OutcomeSucceeded
+	case PendingProcessing, Approved, PendingCancellation:
+		return OutcomePending
+	case ScaPending, PendingApproval, PendingCancellationApproval, Hold, Unknown:
+		return OutcomeNeedsManualAction
+	case Reversed:
+		return OutcomeReversed
+	default:
+		return OutcomeFailed
+	}
+}
+
+// IsSettled tells whether the payment has an outcome that won't change without Banking Circle reversing it.
+func (s PaymentStatus) IsSettled() bool {
+	outcome := s.Outcome()
+	return outcome != OutcomePending && outcome != OutcomeNeedsManualAction
+}
@@ -0,0 +1,19 @@
+//go:generate moq -out mocks/receive_banking_circle_payment_status_moq.go -pkg mocks . ReceiveBankingCirclePaymentStatus SubmittedPaymentFinder
+
+package ports
+
+import (
+	"context"
+
+	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type ReceiveBankingCirclePaymentStatus interface {
+	Execute(ctx context.Context, notification bcmodels.PaymentStatusNotification) error
+}
+
+type SubmittedPaymentFinder interface {
+	// FindSubmittedPayment returns the payment instruction as it is now, from the paymentID Banking Circle accepted it under.
+	FindSubmittedPayment(ctx context.Context, paymentID models.ProviderPaymentID) (models.PaymentInstruction, models.BankingReference, error)
+}
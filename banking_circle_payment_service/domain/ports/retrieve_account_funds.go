@@ -2,6 +2,12 @@
 
 package ports
 
+import "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+
+// RetrieveBankingCircleAccountFunds retrieves the balance and intraday loan of the source account a payment of the
+// amount would be made from.
 type RetrieveBankingCircleAccountFunds interface {
-	Execute(currency string, highRisk bool) (float64, float64, error)
+	Execute(currency string, highRisk bool, amount float64) (float64, float64, error)
+	// AccountsFunds retrieves the funds of each source account of the currency and risk class, in order of priority.
+	AccountsFunds(currency string, highRisk bool) ([]models.AccountFunds, error)
 }
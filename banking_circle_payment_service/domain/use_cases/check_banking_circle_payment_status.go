@@ -2,26 +2,34 @@ package use_cases
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client"
+
 	bcStatus "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
 
 	internalmodels "github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
+const defaultCheckHorizon = 24 * time.Hour
+
 type CheckBankingCirclePaymentStatus struct {
 	CheckBankingCirclePaymentStatusOptions
 	observer observer
 }
 
 type CheckBankingCirclePaymentStatusOptions struct {
-	PaymentAPI         bcStatus.BankingCircleAPI
-	StatusCheckDelay   time.Duration
-	MaxCheckIterations int
-	MetricsClient      ports.MetricsClient
-	PaymentNotifier    bcStatus.PaymentNotifier
-	Now                func() time.Time
+	PaymentAPI bcStatus.BankingCircleAPI
+	// CheckHorizon is how long after Banking Circle accepted a payment its status is checked for, once it
+	// passes a payment still pending is reported as a failure. A payment held for manual action isn't checked again.
+	CheckHorizon    time.Duration
+	MetricsClient   ports.MetricsClient
+	PaymentNotifier bcStatus.PaymentNotifier
+	// SubmittedPayments is optional, with it a payment Banking Circle notified the outcome of already isn't checked again.
+	SubmittedPayments bcStatus.SubmittedPaymentFinder
+	Now               func() time.Time
 }
 
 func NewCheckBankingCirclePaymentStatus(options CheckBankingCirclePaymentStatusOptions) CheckBankingCirclePaymentStatus {
@@ -30,8 +38,8 @@ func NewCheckBankingCirclePaymentStatus(options CheckBankingCirclePaymentStatusO
 			return time.Now().UTC()
 		}
 	}
-	if options.MaxCheckIterations == 0 {
-		options.MaxCheckIterations = 10
+	if options.CheckHorizon == 0 {
+		options.CheckHorizon = defaultCheckHorizon
 	}
 	return CheckBankingCirclePaymentStatus{
 		CheckBankingCirclePaymentStatusOptions: options,
@@ -39,33 +47,80 @@ func NewCheckBankingCirclePaymentStatus(options CheckBankingCirclePaymentStatusO
 	}
 }
 
-// Execute will initiate the Banking Circle payment, then continuously check its status.
-// Once the payment has processed (successfully or otherwise), it will send the outcome to
-// the Payment Status Notifier via a PaymentProviderEvent.
-// Execute will only return an error if it could not do any of the above.
+// Execute checks the status of the Banking Circle payment once. Once the payment has processed (successfully or
+// otherwise), it will send the outcome to the Payment Status Notifier via a PaymentProviderEvent.
+// Until the check horizon from start passes, a payment still pending or a failed check returns a RecheckLaterError
+// instead, so its status is checked again later. A payment Banking Circle holds until someone acts on it is alerted on
+// and left in flight instead, its outcome is notified once someone did.
+// Execute will only return another error if it could not do any of the above.
 func (m CheckBankingCirclePaymentStatus) Execute(ctx context.Context, instruction internalmodels.PaymentInstruction, paymentID internalmodels.ProviderPaymentID, bankingReference internalmodels.BankingReference, start time.Time) error {
-	status, err := m.loopCheckPaymentStatus(ctx, paymentID, instruction)
+	if m.hasOutcome(ctx, paymentID) {
+		return nil
+	}
+
+	var (
+		checkingFor   = m.Now().Sub(start)
+		beforeHorizon = checkingFor < m.CheckHorizon
+	)
+
+	status, err := m.PaymentAPI.CheckPaymentStatus(paymentID)
 	if err != nil {
+		m.observer.CheckPaymentFailed(ctx, instruction, err)
+		var circuitOpen http_client.CircuitOpenError
+		if beforeHorizon || errors.As(err, &circuitOpen) {
+			return RecheckLaterError{Err: err}
+		}
+
+		m.observer.CheckPaymentStatusTotallyFailed(ctx, instruction.ID(), err, checkingFor)
 		return m.sendFailedEvent(ctx, instruction, paymentID, bankingReference, internalmodels.FailureReason{
-			Code:    internalmodels.TransportFailure,
-			Message: err.Error(),
+			Code: internalmodels.TransportFailure,
+			Message: TransportError{
+				UnderlyingError: err,
+				ID:              instruction.ID(),
+				ContractNumber:  instruction.ContractNumber(),
+			}.Error(),
 		})
 	}
-	processingSucceeded := status == bcStatus.Processed
 
-	if processingSucceeded {
+	m.observer.CheckPaymentSucceeded(ctx, instruction, status)
+	if status == bcStatus.MissingFunding {
+		m.observer.MissingFunds(ctx, instruction.IncomingInstruction.AccountNumber(), string(instruction.IncomingInstruction.IsoCode()))
+	}
+
+	if status.Outcome() == bcStatus.OutcomeNeedsManualAction {
+		m.observer.AwaitingManualAction(ctx, instruction, status, checkingFor)
+		return nil
+	}
+
+	if !status.IsSettled() {
+		m.observer.StillPending(ctx, instruction.ID(), instruction.ContractNumber(), status, checkingFor)
+		if beforeHorizon {
+			return RecheckLaterError{Status: status}
+		}
+	} else {
+		m.observer.NoLongerPending(ctx, instruction.ID(), instruction.ContractNumber(), status, checkingFor)
+	}
+
+	processingSucceeded := status.Outcome() == bcStatus.OutcomeSucceeded
+
+	switch {
+	case processingSucceeded:
 		if err := m.sendProcessedEvent(ctx, instruction, paymentID, bankingReference); err != nil {
 			return err
 		}
-	} else {
+	case status.Outcome() == bcStatus.OutcomeReversed:
+		if err := m.sendReversedEvent(ctx, instruction, paymentID, bankingReference); err != nil {
+			return err
+		}
+	default:
 		if err := m.sendFailedEvent(ctx, instruction, paymentID, bankingReference, internalmodels.FailureReason{
-			Code:    mapStatusToFailureCode(status),
+			Code:    MapStatusToFailureCode(status),
 			Message: BankingCircleError{Status: status}.Error(),
 		}); err != nil {
 			return err
 		}
 
-		if status == bcStatus.PendingProcessing {
+		if !status.IsSettled() {
 			return BankingCircleError{Status: status}
 		}
 	}
@@ -75,6 +130,25 @@ func (m CheckBankingCirclePaymentStatus) Execute(ctx context.Context, instructio
 	return nil
 }
 
+// hasOutcome tells whether the outcome of the payment is known already, when it can't tell the payment is checked.
+func (m CheckBankingCirclePaymentStatus) hasOutcome(ctx context.Context, paymentID internalmodels.ProviderPaymentID) bool {
+	if m.SubmittedPayments == nil {
+		return false
+	}
+
+	instruction, _, err := m.SubmittedPayments.FindSubmittedPayment(ctx, paymentID)
+	if err != nil {
+		m.observer.CouldntFindSubmittedPayment(ctx, paymentID, err)
+		return false
+	}
+	if instruction.IsInFlight() {
+		return false
+	}
+
+	m.observer.OutcomeAlreadyKnown(ctx, instruction)
+	return true
+}
+
 func (m CheckBankingCirclePaymentStatus) sendFailedEvent(
 	ctx context.Context,
 	paymentInstruction internalmodels.PaymentInstruction,
@@ -90,61 +164,36 @@ func (m CheckBankingCirclePaymentStatus) sendFailedEvent(
 	return m.PaymentNotifier.SendPaymentStatus(ctx, event)
 }
 
-func (m CheckBankingCirclePaymentStatus) sendProcessedEvent(ctx context.Context, paymentInstruction internalmodels.PaymentInstruction, paymentID internalmodels.ProviderPaymentID, bankingReference internalmodels.BankingReference) error {
-	event, err := internalmodels.NewPaymentProviderEvent(m.Now(), internalmodels.Processed, paymentInstruction, internalmodels.BC, paymentID, bankingReference, nil)
+func (m CheckBankingCirclePaymentStatus) sendReversedEvent(ctx context.Context, paymentInstruction internalmodels.PaymentInstruction, paymentID internalmodels.ProviderPaymentID, bankingReference internalmodels.BankingReference) error {
+	event, err := internalmodels.NewPaymentProviderEvent(m.Now(), internalmodels.Reversal, paymentInstruction, internalmodels.BC, paymentID, bankingReference, nil)
 	if err != nil {
 		return err
 	}
 	return m.PaymentNotifier.SendPaymentStatus(ctx, event)
 }
 
-func (m CheckBankingCirclePaymentStatus) loopCheckPaymentStatus(ctx context.Context, bankingCirclePaymentID internalmodels.ProviderPaymentID, instruction internalmodels.PaymentInstruction) (bcStatus.PaymentStatus, error) {
-	var finalStatus bcStatus.PaymentStatus
-
-	for i := 0; i < m.MaxCheckIterations; i++ {
-		status, err := m.PaymentAPI.CheckPaymentStatus(bankingCirclePaymentID)
-
-		if err != nil {
-			m.observer.CheckPaymentFailed(ctx, instruction, err)
-
-			if i == m.MaxCheckIterations-1 {
-				m.observer.CheckPaymentStatusTotallyFailed(ctx, instruction.ID(), err, m.MaxCheckIterations)
-				return "", TransportError{
-					UnderlyingError: err,
-					ID:              instruction.ID(),
-					ContractNumber:  instruction.ContractNumber(),
-				}
-			}
-		} else {
-			m.observer.CheckPaymentSucceeded(ctx, instruction, status)
-
-			if status == bcStatus.MissingFunding {
-				m.observer.MissingFunds(ctx, instruction.IncomingInstruction.AccountNumber(), string(instruction.IncomingInstruction.IsoCode()))
-			}
-
-			if status != bcStatus.PendingProcessing {
-				m.observer.NoLongerPending(ctx, instruction.ID(), instruction.ContractNumber(), status, i)
-				return status, nil
-			}
-
-			m.observer.StillPending(ctx, instruction.ID(), instruction.ContractNumber(), i)
-		}
-
-		time.Sleep(m.StatusCheckDelay)
-		finalStatus = status
+func (m CheckBankingCirclePaymentStatus) sendProcessedEvent(ctx context.Context, paymentInstruction internalmodels.PaymentInstruction, paymentID internalmodels.ProviderPaymentID, bankingReference internalmodels.BankingReference) error {
+	event, err := internalmodels.NewPaymentProviderEvent(m.Now(), internalmodels.Processed, paymentInstruction, internalmodels.BC, paymentID, bankingReference, nil)
+	if err != nil {
+		return err
 	}
-
-	return finalStatus, nil
+	return m.PaymentNotifier.SendPaymentStatus(ctx, event)
 }
 
-func mapStatusToFailureCode(bankingCircleStatus bcStatus.PaymentStatus) internalmodels.PPEventFailureCode {
+// MapStatusToFailureCode is the failure code of a payment Banking Circle didn't process. The statuses of payments still
+// pending map to the code of payments that stopped being waited for.
+func MapStatusToFailureCode(bankingCircleStatus bcStatus.PaymentStatus) internalmodels.PPEventFailureCode {
 	switch bankingCircleStatus {
-	case bcStatus.PendingProcessing:
+	case bcStatus.PendingProcessing, bcStatus.Approved, bcStatus.PendingCancellation:
 		return internalmodels.StuckInPending
-	case bcStatus.Rejected:
+	case bcStatus.ScaPending, bcStatus.PendingApproval, bcStatus.PendingCancellationApproval, bcStatus.Hold, bcStatus.Unknown:
+		return internalmodels.NeedsManualAction
+	case bcStatus.Rejected, bcStatus.DeclinedByApprover, bcStatus.ScaDeclined, bcStatus.ScaFailed, bcStatus.ScaExpired:
 		return internalmodels.RejectedCode
 	case bcStatus.MissingFunding:
 		return internalmodels.MissingFunding
+	case bcStatus.Cancelled:
+		return internalmodels.CancelledCode
 	default:
 		return internalmodels.UnhandledPaymentProviderStatus
 	}
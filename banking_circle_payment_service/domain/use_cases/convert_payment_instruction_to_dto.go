@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -15,7 +16,11 @@ import (
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 )
 
-const maxLength = 35
+const (
+	maxLength = 35
+	// debtorReferencePrefix starts the reference a payment is requested with, followed by its contract number and execution date.
+	debtorReferencePrefix = "Settlm"
+)
 
 var (
 	// validCharactersRegex is used to sanitize data before sending to Banking Circle.
@@ -33,7 +38,7 @@ func ConvertPaymentInstructionToDto(ctx context.Context, paymentInstruction *mod
 		Country:              "",
 	}
 	reqDto.DebtorViban = ""
-	debtorRef := fmt.Sprintf("Settlm %s %s", paymentInstruction.IncomingInstruction.Merchant.ContractNumber, flattenDate(paymentInstruction.IncomingInstruction.Payment.ExecutionDate))
+	debtorRef := fmt.Sprintf("%s %s %s", debtorReferencePrefix, paymentInstruction.IncomingInstruction.Merchant.ContractNumber, flattenDate(paymentInstruction.IncomingInstruction.Payment.ExecutionDate))
 	reqDto.DebtorReference = cleanInput(ctx, debtorRef, observer, "DebtorReference")
 	reqDto.DebtorNarrativeToSelf = paymentInstruction.IncomingInstruction.Merchant.ContractNumber
 	reqDto.CurrencyOfTransfer = string(paymentInstruction.IncomingInstruction.IsoCode())
@@ -90,6 +95,21 @@ func countryCityCombo(address models.Address) string {
 	return address.Country + " " + address.City
 }
 
+// ParseDebtorReference reads the contract number and execution date back from the reference of a payment,
+// ok is false for a reference the payment wasn't requested with, or one truncated before its execution date.
+func ParseDebtorReference(reference string) (contractNumber string, executionDate time.Time, ok bool) {
+	fields := strings.Fields(reference)
+	if len(fields) < 3 || fields[0] != debtorReferencePrefix {
+		return "", time.Time{}, false
+	}
+
+	executionDate, err := time.Parse("20060102", fields[len(fields)-1])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return strings.Join(fields[1:len(fields)-1], " "), executionDate, true
+}
+
 // flattenDate takes a time object and returns a date of format yyyymmdd.
 func flattenDate(date time.Time) string {
 	return date.Format("20060102")
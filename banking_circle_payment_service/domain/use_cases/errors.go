@@ -34,6 +34,24 @@ func (b BankingCircleError) Error() string {
 	return fmt.Sprintf("Payment provider status %+v", b.Status)
 }
 
+// RecheckLaterError is returned while a payment has no outcome yet, its status should be checked again later.
+type RecheckLaterError struct {
+	Status ports.PaymentStatus
+	// Err is the error of the status check, it is nil when Banking Circle answered.
+	Err error
+}
+
+func (r RecheckLaterError) Error() string {
+	if r.Err != nil {
+		return fmt.Sprintf("payment status should be checked again, error: %v", r.Err)
+	}
+	return fmt.Sprintf("payment status should be checked again, payment provider status %+v", r.Status)
+}
+
+func (r RecheckLaterError) Unwrap() error {
+	return r.Err
+}
+
 type InvalidAccountIDError struct {
 	AccountID string
 }
@@ -0,0 +1,180 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/adapters/http_client"
+
+	bpe "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/bulk_payment_endpoint"
+	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models/single_payment_endpoint"
+	bcStatus "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+
+	internalmodels "github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+const defaultMaxBulkSize = 500
+
+// MakeBankingCircleBulkPayment requests the payments of many payment instructions at once, with a bulk payment
+// for each of their source accounts.
+type MakeBankingCircleBulkPayment struct {
+	payments    MakeBankingCirclePayment
+	maxBulkSize int
+}
+
+var _ bcStatus.MakeBankingCircleBulkPayment = MakeBankingCircleBulkPayment{}
+
+func NewMakeBankingCircleBulkPayment(options MakeBankingCirclePaymentOptions, maxBulkSize int) MakeBankingCircleBulkPayment {
+	if maxBulkSize <= 0 {
+		maxBulkSize = defaultMaxBulkSize
+	}
+
+	return MakeBankingCircleBulkPayment{
+		payments:    NewMakeBankingCirclePayment(options),
+		maxBulkSize: maxBulkSize,
+	}
+}
+
+type bulkPayment struct {
+	instruction internalmodels.PaymentInstruction
+	requestDTO  single_payment_endpoint.RequestDto
+}
+
+// Execute sends a PaymentProviderEvent for every payment of the bulks, as MakeBankingCirclePayment does for a single
+// payment. A payment Banking Circle leaves out of its response gets no event, so it is picked up as a stuck payment,
+// and so do the payments of a bulk request that failed without Banking Circle refusing it.
+// Execute only returns the payment instructions whose event couldn't be sent, and the ones left unrequested while the
+// Banking Circle circuit is open.
+func (m MakeBankingCircleBulkPayment) Execute(ctx context.Context, instructions []internalmodels.PaymentInstruction) map[internalmodels.PaymentInstructionID]error {
+	start := m.payments.Now()
+	failed := make(map[internalmodels.PaymentInstructionID]error)
+
+	var (
+		accounts  []string
+		byAccount = make(map[string][]bulkPayment)
+		funds     = newSourceAccountFunds(m.payments.PaymentAPI)
+	)
+	for _, instruction := range instructions {
+		requestDTO, err := m.payments.createRequestDTO(ctx, instruction, funds)
+		if err != nil {
+			if sendFail := m.payments.sendFailedEvent(ctx, instruction, "", internalmodels.FailureReason{
+				Code:    internalmodels.NoSourceAccount,
+				Message: err.Error(),
+			}, ""); sendFail != nil {
+				failed[instruction.ID()] = sendFail
+			}
+			continue
+		}
+
+		account := requestDTO.DebtorAccount.Account
+		if _, found := byAccount[account]; !found {
+			accounts = append(accounts, account)
+		}
+		byAccount[account] = append(byAccount[account], bulkPayment{instruction: instruction, requestDTO: requestDTO})
+	}
+
+	for _, account := range accounts {
+		payments := byAccount[account]
+		for len(payments) > 0 {
+			size := m.maxBulkSize
+			if len(payments) < size {
+				size = len(payments)
+			}
+			m.requestBulk(ctx, payments[:size], start, failed)
+			payments = payments[size:]
+		}
+	}
+
+	return failed
+}
+
+func (m MakeBankingCircleBulkPayment) requestBulk(ctx context.Context, payments []bulkPayment, start time.Time, failed map[internalmodels.PaymentInstructionID]error) {
+	request := bpe.RequestDto{DebtorAccount: payments[0].requestDTO.DebtorAccount}
+	for _, payment := range payments {
+		request.Payments = append(request.Payments, bpe.PaymentDto{
+			ClientReference: string(payment.instruction.ID()),
+			RequestDto:      payment.requestDTO,
+		})
+	}
+
+	var uidSlice []string
+	resp, err := m.payments.PaymentAPI.RequestBulkPayment(ctx, request, &uidSlice)
+	m.payments.observer.RequestedBulkPayment(ctx, request.DebtorAccount.Account, resp.BulkID, len(payments))
+	var circuitOpen http_client.CircuitOpenError
+	if errors.As(err, &circuitOpen) {
+		for _, payment := range payments {
+			m.payments.observer.RequestPaymentPaused(ctx, payment.instruction, err)
+			failed[payment.instruction.ID()] = err
+		}
+		return
+	}
+	if err != nil && !refusedByBankingCircle(err) {
+		// Banking Circle may have taken some of the payments before the request failed, failing them would have them
+		// requested again and pay those merchants twice
+		for _, payment := range payments {
+			m.payments.observer.BulkPaymentOutcomeUnknown(ctx, payment.instruction, uidSlice, err)
+		}
+		return
+	}
+	if err != nil {
+		for _, payment := range payments {
+			m.payments.observer.RequestPaymentFailed(ctx, payment.instruction, uidSlice, err)
+			if sendFail := m.payments.sendFailedEvent(ctx, payment.instruction, "", internalmodels.FailureReason{
+				Code: internalmodels.TransportFailure,
+				Message: TransportError{
+					UnderlyingError: err,
+					ID:              payment.instruction.ID(),
+					ContractNumber:  payment.instruction.ContractNumber(),
+				}.Error(),
+			}, ""); sendFail != nil {
+				failed[payment.instruction.ID()] = sendFail
+			}
+		}
+		return
+	}
+
+	results := resp.ByClientReference()
+	for _, payment := range payments {
+		result, found := results[string(payment.instruction.ID())]
+		if !found {
+			m.payments.observer.MissingFromBulkPayment(ctx, payment.instruction, resp.BulkID)
+			continue
+		}
+
+		if status := bcStatus.PaymentStatus(result.Status); status.Outcome() == bcStatus.OutcomeFailed {
+			m.payments.observer.RequestPaymentFailed(ctx, payment.instruction, uidSlice, BankingCircleError{Status: status})
+			if sendFail := m.payments.sendFailedEvent(ctx, payment.instruction, result.PaymentID, internalmodels.FailureReason{
+				Code:    MapStatusToFailureCode(status),
+				Message: BankingCircleError{Status: status}.Error(),
+			}, result.BankingReference); sendFail != nil {
+				failed[payment.instruction.ID()] = sendFail
+			}
+			continue
+		}
+
+		// like a single payment, the status of a payment Banking Circle took is checked once it is submitted
+		m.payments.observer.RequestPaymentSucceeded(ctx, payment.instruction, single_payment_endpoint.ResponseDto{
+			PaymentID:        result.PaymentID,
+			Status:           result.Status,
+			BankingReference: result.BankingReference,
+		})
+		if err := m.payments.sendSubmittedEvent(ctx, payment.instruction, result.PaymentID, result.BankingReference, start); err != nil {
+			failed[payment.instruction.ID()] = err
+		}
+	}
+}
+
+// refusedByBankingCircle tells whether Banking Circle answered the bulk payment request with a client error, in which
+// case it took none of its payments.
+func refusedByBankingCircle(err error) bool {
+	var (
+		unauthorised http_client.UnauthorisedWithBankingCircleError
+		unrecognised http_client.UnrecognisedBankingCircleError
+	)
+	if errors.As(err, &unauthorised) {
+		return true
+	}
+	return errors.As(err, &unrecognised) && unrecognised.Status >= http.StatusBadRequest && unrecognised.Status < http.StatusInternalServerError
+}
@@ -2,6 +2,7 @@ package use_cases
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -53,7 +54,7 @@ func NewMakeBankingCirclePayment(options MakeBankingCirclePaymentOptions) MakeBa
 func (m MakeBankingCirclePayment) Execute(ctx context.Context, instruction internalmodels.PaymentInstruction) (paymentID internalmodels.ProviderPaymentID, err error) {
 	start := m.Now()
 
-	requestDTO, err := m.createRequestDTO(ctx, instruction)
+	requestDTO, err := m.createRequestDTO(ctx, instruction, newSourceAccountFunds(m.PaymentAPI))
 	if err != nil {
 		sendFail := m.sendFailedEvent(ctx, instruction, "", internalmodels.FailureReason{
 			Code:    internalmodels.NoSourceAccount,
@@ -66,6 +67,11 @@ func (m MakeBankingCirclePayment) Execute(ctx context.Context, instruction inter
 	}
 
 	resp, err := m.requestPayment(ctx, instruction, requestDTO)
+	var circuitOpen http_client.CircuitOpenError
+	if errors.As(err, &circuitOpen) {
+		// the payment wasn't requested, it is requested again once Banking Circle is back
+		return "", err
+	}
 	if err != nil {
 		sendFail := m.sendFailedEvent(ctx, instruction, "", internalmodels.FailureReason{
 			Code: internalmodels.TransportFailure,
@@ -104,8 +110,8 @@ func (m MakeBankingCirclePayment) sendFailedEvent(
 	return m.PaymentNotifier.SendPaymentStatus(ctx, event)
 }
 
-func (m MakeBankingCirclePayment) createRequestDTO(ctx context.Context, instruction internalmodels.PaymentInstruction) (single_payment_endpoint.RequestDto, error) {
-	if err := addSourceAccount(&instruction, m.SourceAccounts); err != nil {
+func (m MakeBankingCirclePayment) createRequestDTO(ctx context.Context, instruction internalmodels.PaymentInstruction, funds *sourceAccountFunds) (single_payment_endpoint.RequestDto, error) {
+	if err := m.addSourceAccount(ctx, &instruction, funds); err != nil {
 		m.observer.CouldntRequestBCRequest(ctx, instruction.ID(), err)
 		return single_payment_endpoint.RequestDto{}, err
 	}
@@ -130,6 +136,8 @@ func (m MakeBankingCirclePayment) requestPayment(ctx context.Context, instructio
 			m.observer.RequestPaymentFailedUnauthorized(ctx, instruction, uidSlice, err)
 		case http_client.InvalidPaymentRequestError:
 			m.observer.RequestPaymentFailedBadRequest(ctx, instruction, uidSlice, err)
+		case http_client.CircuitOpenError:
+			m.observer.RequestPaymentPaused(ctx, instruction, err)
 		default:
 			m.observer.RequestPaymentFailed(ctx, instruction, uidSlice, err)
 		}
@@ -146,21 +154,51 @@ func (m MakeBankingCirclePayment) sendSubmittedEvent(ctx context.Context, instru
 	if err != nil {
 		return err
 	}
-	return m.SubmissionNotifier.SendPaymentStatus(ctx, event)
+	if err := m.SubmissionNotifier.SendPaymentStatus(ctx, event); err != nil {
+		return err
+	}
+
+	// the payment is with Banking Circle already, failing here would request it again, so the payment instruction
+	// is left without the Banking Circle payment ID instead
+	if err := m.PaymentNotifier.SendPaymentStatus(ctx, event); err != nil {
+		m.observer.CouldntNotifyAcceptance(ctx, instruction.ID(), id, err)
+	}
+	return nil
 }
 
-func addSourceAccount(paymentInstruction *internalmodels.PaymentInstruction, sourceAccounts bcmodels.SourceAccounts) error {
-	sourceAccount, accountFound := sourceAccounts.FindAccountNumber(
+// addSourceAccount keeps the source account a routing rule has set on the payment instruction. Otherwise, when the
+// currency and risk class have a few accounts, the first one in order of priority with enough balance and intraday
+// loan to pay is chosen, and Banking Circle holds the payment for missing funding only when none of them has. The
+// payment is taken off the funds of the chosen account, for the next payments of the same bulk payment.
+func (m MakeBankingCirclePayment) addSourceAccount(ctx context.Context, paymentInstruction *internalmodels.PaymentInstruction, funds *sourceAccountFunds) error {
+	if paymentInstruction.IncomingInstruction.Payment.Sender.AccountNumber != "" {
+		return nil
+	}
+	accounts := bcmodels.SourceAccounts(m.SourceAccounts).FindAccounts(
 		string(paymentInstruction.IncomingInstruction.IsoCode()),
 		paymentInstruction.IncomingInstruction.Merchant.HighRisk,
 	)
-	if !accountFound {
+	if len(accounts) == 0 {
 		return NoSourceAccountError{
 			IsoCode:  string(paymentInstruction.IncomingInstruction.IsoCode()),
 			HighRisk: paymentInstruction.IncomingInstruction.Merchant.HighRisk,
 		}
 	}
 
+	sourceAccount := accounts[0]
+	amount, err := strconv.ParseFloat(paymentInstruction.IncomingInstruction.Payment.Amount, 64)
+	if len(accounts) > 1 && err == nil {
+		choice, err := chooseSourceAccount(funds, accounts, amount)
+		if err != nil {
+			m.observer.CouldntChooseSourceAccount(ctx, paymentInstruction.ID(), sourceAccount.Iban, err)
+		} else {
+			sourceAccount = choice.account
+		}
+	}
+	if err == nil {
+		funds.assign(sourceAccount, amount)
+	}
+
 	paymentInstruction.SetSourceAccount(sourceAccount.Iban)
 	return nil
 }
@@ -34,6 +34,10 @@ const (
 	missingFunds                  = "app_settlements_provider_missing_funds"
 	dataFieldCleaned              = "app_settlements_provider_data_cleaned"
 	successfulPaymentCounterName  = "app_settlements_provider_success_payment"
+	bulkPaymentSizeName           = "app_settlements_provider_bulk_payment_size"
+	missingFromBulkPayment        = "app_settlements_provider_bulk_payment_result_missing"
+	bulkPaymentOutcomeUnknown     = "app_settlements_provider_bulk_payment_outcome_unknown"
+	awaitingManualAction          = "app_settlements_provider_awaiting_manual_action"
 )
 
 var (
@@ -77,6 +81,15 @@ func (m observer) RequestPaymentFailedBadRequest(ctx context.Context, instructio
 	)
 }
 
+// RequestPaymentPaused isn't counted, the circuit breaker counts the requests it turns down already.
+func (m observer) RequestPaymentPaused(ctx context.Context, instruction models.PaymentInstruction, err error) {
+	zapctx.Warn(ctx, "Banking Circle payment for payment instruction not requested, it is requested again once Banking Circle is back",
+		zap.String("id", string(instruction.ID())),
+		zap.String("merchant_contract_number", instruction.ContractNumber()),
+		zap.Error(err),
+	)
+}
+
 func (m observer) CheckPaymentSucceeded(ctx context.Context, instruction models.PaymentInstruction, status ports2.PaymentStatus) {
 	m.MetricsClient.Count(ctx, checkPaymentStatusCounterName, 1, append(successTags, string(instruction.IncomingInstruction.IsoCode())))
 	zapctx.Debug(ctx, "flow_step #10a: checked status in Banking Circle for payment instruction",
@@ -87,7 +100,7 @@ func (m observer) CheckPaymentSucceeded(ctx context.Context, instruction models.
 
 func (m observer) CheckPaymentFailed(ctx context.Context, instruction models.PaymentInstruction, err error) {
 	m.MetricsClient.Count(ctx, checkPaymentStatusCounterName, 1, append(failedTags, string(instruction.IncomingInstruction.IsoCode())))
-	zapctx.Info(ctx, "flow_step #10b: ERROR when checking status in Banking Circle for payment instruction. Retrying...",
+	zapctx.Info(ctx, "flow_step #10b: ERROR when checking status in Banking Circle for payment instruction",
 		zap.String("id", string(instruction.ID())),
 		zap.String("contract_number", instruction.ContractNumber()),
 		zap.Error(err),
@@ -102,20 +115,31 @@ func (m observer) PaymentIsUnprocessed(ctx context.Context, id models.PaymentIns
 	)
 }
 
-func (m observer) StillPending(ctx context.Context, id models.PaymentInstructionID, mid string, attempts int) {
-	zapctx.Debug(ctx, "[CheckBankingCirclePaymentStatus] (loopCheckPaymentStatus) payment instruction status is still pending",
+func (m observer) AwaitingManualAction(ctx context.Context, instruction models.PaymentInstruction, status ports2.PaymentStatus, checkingFor time.Duration) {
+	m.MetricsClient.Count(ctx, awaitingManualAction, 1, []string{paymentProviderTag, string(status)})
+	zapctx.Error(ctx, "[CheckBankingCirclePaymentStatus] (Execute) payment instruction is held by Banking Circle until someone acts on it",
+		zap.String("id", string(instruction.ID())),
+		zap.String("merchant_contract_number", instruction.ContractNumber()),
+		zap.String("status", string(status)),
+		zap.Duration("checking_for", checkingFor),
+	)
+}
+
+func (m observer) StillPending(ctx context.Context, id models.PaymentInstructionID, mid string, status ports2.PaymentStatus, checkingFor time.Duration) {
+	zapctx.Debug(ctx, "[CheckBankingCirclePaymentStatus] (Execute) payment instruction status is still pending",
 		zap.String("id", string(id)),
 		zap.String("merchant_contract_number", mid),
-		zap.Int("iteration", attempts),
+		zap.String("status", string(status)),
+		zap.Duration("checking_for", checkingFor),
 	)
 }
 
-func (m observer) NoLongerPending(ctx context.Context, id models.PaymentInstructionID, mid string, status ports2.PaymentStatus, attempts int) {
-	zapctx.Debug(ctx, "[CheckBankingCirclePaymentStatus] (loopCheckPaymentStatus) status is no longer pending for payment instruction",
+func (m observer) NoLongerPending(ctx context.Context, id models.PaymentInstructionID, mid string, status ports2.PaymentStatus, checkingFor time.Duration) {
+	zapctx.Debug(ctx, "[CheckBankingCirclePaymentStatus] (Execute) status is no longer pending for payment instruction",
 		zap.String("id", string(id)),
 		zap.String("merchant_contract_number", mid),
 		zap.String("status", string(status)),
-		zap.Int("iteration", attempts),
+		zap.Duration("checking_for", checkingFor),
 	)
 }
 
@@ -126,10 +150,60 @@ func (m observer) RequestedPayment(ctx context.Context, instructionID models.Pay
 	)
 }
 
-func (m observer) CheckPaymentStatusTotallyFailed(ctx context.Context, id models.PaymentInstructionID, err error, retries int) {
+func (m observer) RequestedBulkPayment(ctx context.Context, sourceAccount string, bulkID string, size int) {
+	zapctx.Debug(ctx, "flow_step #9: bulk of payment instructions sent to Banking Circle API",
+		zap.String("source_account", sourceAccount),
+		zap.String("banking_circle_bulk_id", bulkID),
+		zap.Int("size", size),
+	)
+	m.MetricsClient.Histogram(ctx, bulkPaymentSizeName, float64(size), []string{paymentProviderTag})
+}
+
+func (m observer) MissingFromBulkPayment(ctx context.Context, instruction models.PaymentInstruction, bulkID string) {
+	zapctx.Error(ctx, "[MakeBankingCircleBulkPayment] (Execute) Banking Circle left the payment instruction out of the bulk payment response",
+		zap.String("id", string(instruction.ID())),
+		zap.String("merchant_contract_number", instruction.ContractNumber()),
+		zap.String("banking_circle_bulk_id", bulkID),
+	)
+	m.MetricsClient.Count(ctx, missingFromBulkPayment, 1, []string{paymentProviderTag, string(instruction.IncomingInstruction.IsoCode())})
+}
+
+func (m observer) BulkPaymentOutcomeUnknown(ctx context.Context, instruction models.PaymentInstruction, slice []string, err error) {
+	zapctx.Error(ctx, "[MakeBankingCircleBulkPayment] (Execute) Banking Circle may have taken the payment of a bulk payment request that failed, its status needs checking",
+		zap.String("id", string(instruction.ID())),
+		zap.String("merchant_contract_number", instruction.ContractNumber()),
+		zap.Strings("uuids", slice),
+		zap.Error(err),
+	)
+	m.MetricsClient.Count(ctx, bulkPaymentOutcomeUnknown, 1, []string{paymentProviderTag, string(instruction.IncomingInstruction.IsoCode())})
+}
+
+func (m observer) CouldntNotifyAcceptance(ctx context.Context, instructionID models.PaymentInstructionID, paymentID models.ProviderPaymentID, err error) {
+	zapctx.Error(ctx, "[MakeBankingCirclePayment] (Execute) Could not record that Banking Circle accepted the payment instruction",
+		zap.String("id", string(instructionID)),
+		zap.String("banking_circle_id", string(paymentID)),
+		zap.Error(err),
+	)
+}
+
+func (m observer) CouldntFindSubmittedPayment(ctx context.Context, paymentID models.ProviderPaymentID, err error) {
+	zapctx.Warn(ctx, "[CheckBankingCirclePaymentStatus] (Execute) could not find the payment instruction, checking its status anyway",
+		zap.String("banking_circle_id", string(paymentID)),
+		zap.Error(err),
+	)
+}
+
+func (m observer) OutcomeAlreadyKnown(ctx context.Context, instruction models.PaymentInstruction) {
+	zapctx.Debug(ctx, "[CheckBankingCirclePaymentStatus] (Execute) payment instruction has its outcome already, not checking its status",
+		zap.String("id", string(instruction.ID())),
+		zap.String("status", string(instruction.GetStatus())),
+	)
+}
+
+func (m observer) CheckPaymentStatusTotallyFailed(ctx context.Context, id models.PaymentInstructionID, err error, checkingFor time.Duration) {
 	zapctx.Error(ctx, "[CheckBankingCirclePaymentStatus] (Execute) Banking Circle check payment status call totally failed for payment instruction",
 		zap.String("id", string(id)),
-		zap.Int("retries", retries),
+		zap.Duration("checking_for", checkingFor),
 		zap.Error(err),
 	)
 }
@@ -165,6 +239,14 @@ func (m observer) CouldntRequestBCRequest(ctx context.Context, id models.Payment
 	)
 }
 
+func (m observer) CouldntChooseSourceAccount(ctx context.Context, id models.PaymentInstructionID, fallback string, err error) {
+	zapctx.Warn(ctx, "[MakeBankingCirclePayment] (addSourceAccount) Could not retrieve the balances of the source accounts, paying from the first one in order of priority",
+		zap.String("id", string(id)),
+		zap.String("source_account", fallback),
+		zap.Error(err),
+	)
+}
+
 func (m observer) MissingFunds(ctx context.Context, accountNumber, currency string) {
 	zapctx.Info(ctx, "[CheckBankingCirclePaymentStatus] (Execute) Missing funds for account",
 		zap.String("account_number", accountNumber))
@@ -0,0 +1,108 @@
+package use_cases
+
+import (
+	"context"
+	"time"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bcStatus "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+
+	internalmodels "github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const paymentStatusNotificationCounterName = "app_settlements_provider_payment_status_notification"
+
+type ReceiveBankingCirclePaymentStatus struct {
+	ReceiveBankingCirclePaymentStatusOptions
+}
+
+type ReceiveBankingCirclePaymentStatusOptions struct {
+	SubmittedPayments bcStatus.SubmittedPaymentFinder
+	MetricsClient     ports.MetricsClient
+	PaymentNotifier   bcStatus.PaymentNotifier
+	Now               func() time.Time
+}
+
+var _ bcStatus.ReceiveBankingCirclePaymentStatus = ReceiveBankingCirclePaymentStatus{}
+
+func NewReceiveBankingCirclePaymentStatus(options ReceiveBankingCirclePaymentStatusOptions) ReceiveBankingCirclePaymentStatus {
+	if options.Now == nil {
+		options.Now = func() time.Time {
+			return time.Now().UTC()
+		}
+	}
+
+	return ReceiveBankingCirclePaymentStatus{ReceiveBankingCirclePaymentStatusOptions: options}
+}
+
+// Execute sends the outcome Banking Circle notified for a payment to the Payment Status Notifier, like
+// CheckBankingCirclePaymentStatus does once it polled the outcome. Notifications of payments without an outcome yet
+// and of payments that have their outcome already are ignored, Banking Circle may notify the same status more than
+// once. A reversal is the exception, it is the outcome of a payment that succeeded already.
+func (r ReceiveBankingCirclePaymentStatus) Execute(ctx context.Context, notification bcmodels.PaymentStatusNotification) error {
+	status := bcStatus.PaymentStatus(notification.Status)
+
+	instruction, bankingReference, err := r.SubmittedPayments.FindSubmittedPayment(ctx, notification.PaymentID)
+	if err != nil {
+		r.count(ctx, "not_found", status)
+		return err
+	}
+
+	if !status.IsSettled() || !r.canTrack(instruction, status) {
+		zapctx.Debug(ctx, "[ReceiveBankingCirclePaymentStatus] (Execute) ignoring payment status notification",
+			zap.String("id", string(instruction.ID())),
+			zap.String("banking_circle_id", string(notification.PaymentID)),
+			zap.String("status", string(status)),
+			zap.String("payment_instruction_status", string(instruction.GetStatus())),
+		)
+		r.count(ctx, "ignored", status)
+		return nil
+	}
+
+	var (
+		eventType = internalmodels.Processed
+		reason    *internalmodels.FailureReason
+	)
+	switch status.Outcome() {
+	case bcStatus.OutcomeReversed:
+		eventType = internalmodels.Reversal
+	case bcStatus.OutcomeFailed:
+		eventType = internalmodels.Failure
+		reason = &internalmodels.FailureReason{
+			Code:    MapStatusToFailureCode(status),
+			Message: BankingCircleError{Status: status}.Error(),
+		}
+	}
+
+	event, err := internalmodels.NewPaymentProviderEvent(r.Now(), eventType, instruction, internalmodels.BC, notification.PaymentID, bankingReference, reason)
+	if err != nil {
+		return err
+	}
+	if err := r.PaymentNotifier.SendPaymentStatus(ctx, event); err != nil {
+		return err
+	}
+
+	zapctx.Info(ctx, "[ReceiveBankingCirclePaymentStatus] (Execute) payment status notified by Banking Circle",
+		zap.String("id", string(instruction.ID())),
+		zap.String("banking_circle_id", string(notification.PaymentID)),
+		zap.String("status", string(status)),
+	)
+	r.count(ctx, "notified", status)
+	return nil
+}
+
+// canTrack tells whether the payment instruction can still take the outcome of the status.
+func (r ReceiveBankingCirclePaymentStatus) canTrack(instruction internalmodels.PaymentInstruction, status bcStatus.PaymentStatus) bool {
+	if status.Outcome() == bcStatus.OutcomeReversed {
+		return instruction.IsInFlight() || instruction.GetStatus() == internalmodels.Successful
+	}
+	return instruction.IsInFlight()
+}
+
+func (r ReceiveBankingCirclePaymentStatus) count(ctx context.Context, result string, status bcStatus.PaymentStatus) {
+	r.MetricsClient.Count(ctx, paymentStatusNotificationCounterName, 1, []string{result, paymentProviderTag, string(status)})
+}
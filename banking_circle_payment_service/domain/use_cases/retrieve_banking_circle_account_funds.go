@@ -22,22 +22,142 @@ func NewRetrieveBankingCircleAccountFunds(options RetrieveBankingCircleAccountFu
 	}
 }
 
-func (c RetrieveBankingCircleAccountFunds) Execute(currency string, highRisk bool) (float64, float64, error) {
-	account, ok := c.SourceAccounts.FindAccountNumber(currency, highRisk)
-	if !ok {
+// Execute retrieves the funds of the first account of the currency and risk class, in order of priority, whose balance
+// and intraday loan cover the amount. When none of them does, the funds of the first account whose balance could be
+// retrieved are returned, so that the payments are held for missing funding.
+func (c RetrieveBankingCircleAccountFunds) Execute(currency string, highRisk bool, amount float64) (float64, float64, error) {
+	accounts := c.SourceAccounts.FindAccounts(currency, highRisk)
+	if len(accounts) == 0 {
 		return 0, 0, fmt.Errorf("[CheckBankingCircleAccountFunds Execute] account not found for the given currency: %s and highrisk: %t", currency, highRisk)
 	}
 
-	accountBalance, err := c.PaymentAPI.CheckAccountBalance(account.AccountID)
+	choice, err := chooseSourceAccount(newSourceAccountFunds(c.PaymentAPI), accounts, amount)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	if len(accountBalance.Result) != 1 {
-		return 0, 0, fmt.Errorf("[CheckBankingCircleAccountFunds Execute] expected a single entry but got %d", len(accountBalance.Result))
+	return choice.balance, choice.account.MaxIntraDayLoan, nil
+}
+
+// AccountsFunds leaves out the accounts whose balance couldn't be retrieved, it fails only when none could be.
+func (c RetrieveBankingCircleAccountFunds) AccountsFunds(currency string, highRisk bool) ([]bcmodels.AccountFunds, error) {
+	accounts := c.SourceAccounts.FindAccounts(currency, highRisk)
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("[CheckBankingCircleAccountFunds AccountsFunds] account not found for the given currency: %s and highrisk: %t", currency, highRisk)
+	}
+
+	var (
+		funds    = make([]bcmodels.AccountFunds, 0, len(accounts))
+		firstErr error
+	)
+	for _, account := range accounts {
+		balance, err := accountBalance(c.PaymentAPI, account)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		funds = append(funds, bcmodels.AccountFunds{Iban: account.Iban, Balance: balance, MaxIntraDayLoan: account.MaxIntraDayLoan})
+	}
+	if len(funds) == 0 {
+		return nil, firstErr
+	}
+	return funds, nil
+}
+
+type sourceAccountChoice struct {
+	account bcmodels.AccountDetails
+	balance float64
+}
+
+// covers tells whether the balance and the intraday loan of the account are enough to pay the amount.
+func (s sourceAccountChoice) covers(amount float64) bool {
+	return amount <= s.balance+s.account.MaxIntraDayLoan
+}
+
+// chooseSourceAccount falls back to the next account in order of priority until one covers the amount, the first
+// account whose balance could be retrieved is chosen when none does.
+func chooseSourceAccount(funds *sourceAccountFunds, accounts []bcmodels.AccountDetails, amount float64) (sourceAccountChoice, error) {
+	var (
+		fallback *sourceAccountChoice
+		firstErr error
+	)
+	for _, account := range accounts {
+		balance, err := funds.balance(account)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		choice := sourceAccountChoice{account: account, balance: balance}
+		if choice.covers(amount) {
+			return choice, nil
+		}
+		if fallback == nil {
+			fallback = &choice
+		}
+	}
+
+	if fallback != nil {
+		return *fallback, nil
+	}
+	return sourceAccountChoice{}, firstErr
+}
+
+// sourceAccountFunds retrieves the balance of each source account once, and takes the amounts of the payments assigned
+// to an account off its balance, so the payments of a bulk payment don't all count on the same funds.
+type sourceAccountFunds struct {
+	api      ports.BankingCircleAPI
+	balances map[string]float64
+	errs     map[string]error
+}
+
+func newSourceAccountFunds(api ports.BankingCircleAPI) *sourceAccountFunds {
+	return &sourceAccountFunds{
+		api:      api,
+		balances: make(map[string]float64),
+		errs:     make(map[string]error),
+	}
+}
+
+func (f *sourceAccountFunds) balance(account bcmodels.AccountDetails) (float64, error) {
+	if balance, found := f.balances[account.AccountID]; found {
+		return balance, nil
+	}
+	if err, found := f.errs[account.AccountID]; found {
+		return 0, err
+	}
+
+	balance, err := accountBalance(f.api, account)
+	if err != nil {
+		f.errs[account.AccountID] = err
+		return 0, err
+	}
+	f.balances[account.AccountID] = balance
+	return balance, nil
+}
+
+// assign takes the amount of a payment off the balance of its source account, when the balance was retrieved.
+func (f *sourceAccountFunds) assign(account bcmodels.AccountDetails, amount float64) {
+	if balance, found := f.balances[account.AccountID]; found {
+		f.balances[account.AccountID] = balance - amount
+	}
+}
+
+func accountBalance(api ports.BankingCircleAPI, account bcmodels.AccountDetails) (float64, error) {
+	balances, err := api.CheckAccountBalance(account.AccountID)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(balances.Result) != 1 {
+		return 0, fmt.Errorf("[CheckBankingCircleAccountFunds Execute] expected a single entry but got %d", len(balances.Result))
 	}
 
-	results := accountBalance.Result[0]
+	results := balances.Result[0]
 
-	return results.BeginOfDayAmount + results.IntraDayAmount, account.MaxIntraDayLoan, nil
+	return results.BeginOfDayAmount + results.IntraDayAmount, nil
 }
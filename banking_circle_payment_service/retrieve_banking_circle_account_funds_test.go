@@ -61,7 +61,7 @@ func TestBankingCircleRetrieveAccountFundsUseCase_Execute(t *testing.T) {
 				},
 			)
 
-			actualAmount, intraDayLoan, err := checkBCAccountFundsUseCase.Execute(currencyCode, false)
+			actualAmount, intraDayLoan, err := checkBCAccountFundsUseCase.Execute(currencyCode, false, 100)
 
 			is.NoErr(err)
 			is.Equal(len(mockBankingCircleAPIClient.CheckAccountBalanceCalls()), 1)
@@ -92,7 +92,7 @@ func TestBankingCircleRetrieveAccountFundsUseCase_Execute(t *testing.T) {
 				},
 			)
 
-			actualAmount, _, err := checkBCAccountFundsUseCase.Execute(currencyCode, false)
+			actualAmount, _, err := checkBCAccountFundsUseCase.Execute(currencyCode, false, 100)
 
 			is.NoErr(err)
 			is.Equal(len(mockBankingCircleAPIClient.CheckAccountBalanceCalls()), 1)
@@ -124,7 +124,7 @@ func TestBankingCircleRetrieveAccountFundsUseCase_Execute(t *testing.T) {
 				},
 			)
 
-			_, _, err := checkBCAccountFundsUseCase.Execute("EUR", false)
+			_, _, err := checkBCAccountFundsUseCase.Execute("EUR", false, 100)
 
 			isError := err != nil
 
@@ -143,7 +143,7 @@ func TestBankingCircleRetrieveAccountFundsUseCase_Execute(t *testing.T) {
 				},
 			)
 
-			_, _, err := checkBCAccountFundsUseCase.Execute("EUR", false)
+			_, _, err := checkBCAccountFundsUseCase.Execute("EUR", false, 100)
 
 			isError := err != nil
 
@@ -164,10 +164,119 @@ func TestBankingCircleRetrieveAccountFundsUseCase_Execute(t *testing.T) {
 				},
 			)
 
-			_, _, err := checkBCAccountFundsUseCase.Execute("EUR", false)
+			_, _, err := checkBCAccountFundsUseCase.Execute("EUR", false, 100)
 
 			is.Equal(err, dummyError)
 			is.Equal(len(mockBankingCircleAPIClient.CheckAccountBalanceCalls()), 1)
 		})
 	})
+
+	t.Run("with a few accounts for the currency", func(t *testing.T) {
+		sourceAccounts := models.SourceAccounts{
+			{Currency: "EUR", Priority: 1, AccountDetails: models.AccountDetails{AccountID: "main", Iban: "MAIN-IBAN", MaxIntraDayLoan: 100}},
+			{Currency: "EUR", Priority: 2, AccountDetails: models.AccountDetails{AccountID: "spare", Iban: "SPARE-IBAN", MaxIntraDayLoan: 50}},
+		}
+		newAPIClient := func(balances map[string]float64) *mocks.BankingCircleAPIMock {
+			return &mocks.BankingCircleAPIMock{CheckAccountBalanceFunc: func(accountID string) (models.AccountBalance, error) {
+				balance, ok := balances[accountID]
+				if !ok {
+					return models.AccountBalance{}, fmt.Errorf("oops")
+				}
+				return models.AccountBalance{Result: []models.Balance{{Currency: "EUR", BeginOfDayAmount: balance}}}, nil
+			}}
+		}
+
+		t.Run("falls back to the next account when the first one can't pay the amount", func(t *testing.T) {
+			is := is.New(t)
+			checkBCAccountFundsUseCase := NewRetrieveBankingCircleAccountFunds(RetrieveBankingCircleAccountFundsOptions{
+				SourceAccounts: sourceAccounts,
+				PaymentAPI:     newAPIClient(map[string]float64{"main": 500, "spare": 1000}),
+			})
+
+			balance, intraDayLoan, err := checkBCAccountFundsUseCase.Execute("EUR", false, 700)
+
+			is.NoErr(err)
+			is.Equal(balance, float64(1000))
+			is.Equal(intraDayLoan, float64(50))
+		})
+
+		t.Run("counts the intraday loan of the account in", func(t *testing.T) {
+			is := is.New(t)
+			apiClient := newAPIClient(map[string]float64{"main": 500, "spare": 1000})
+			checkBCAccountFundsUseCase := NewRetrieveBankingCircleAccountFunds(RetrieveBankingCircleAccountFundsOptions{
+				SourceAccounts: sourceAccounts,
+				PaymentAPI:     apiClient,
+			})
+
+			balance, _, err := checkBCAccountFundsUseCase.Execute("EUR", false, 600)
+
+			is.NoErr(err)
+			is.Equal(balance, float64(500))
+			is.Equal(len(apiClient.CheckAccountBalanceCalls()), 1)
+		})
+
+		t.Run("returns the funds of the first account when none can pay the amount", func(t *testing.T) {
+			is := is.New(t)
+			checkBCAccountFundsUseCase := NewRetrieveBankingCircleAccountFunds(RetrieveBankingCircleAccountFundsOptions{
+				SourceAccounts: sourceAccounts,
+				PaymentAPI:     newAPIClient(map[string]float64{"main": 500, "spare": 1000}),
+			})
+
+			balance, _, err := checkBCAccountFundsUseCase.Execute("EUR", false, 5000)
+
+			is.NoErr(err)
+			is.Equal(balance, float64(500))
+		})
+
+		t.Run("skips the accounts whose balance can't be retrieved", func(t *testing.T) {
+			is := is.New(t)
+			checkBCAccountFundsUseCase := NewRetrieveBankingCircleAccountFunds(RetrieveBankingCircleAccountFundsOptions{
+				SourceAccounts: sourceAccounts,
+				PaymentAPI:     newAPIClient(map[string]float64{"spare": 100}),
+			})
+
+			balance, _, err := checkBCAccountFundsUseCase.Execute("EUR", false, 5000)
+
+			is.NoErr(err)
+			is.Equal(balance, float64(100))
+		})
+
+		t.Run("lists the funds of the accounts in order of priority", func(t *testing.T) {
+			is := is.New(t)
+			checkBCAccountFundsUseCase := NewRetrieveBankingCircleAccountFunds(RetrieveBankingCircleAccountFundsOptions{
+				SourceAccounts: sourceAccounts,
+				PaymentAPI:     newAPIClient(map[string]float64{"main": 500, "spare": 1000}),
+			})
+
+			funds, err := checkBCAccountFundsUseCase.AccountsFunds("EUR", false)
+
+			is.NoErr(err)
+			is.Equal(funds, []models.AccountFunds{
+				{Iban: "MAIN-IBAN", Balance: 500, MaxIntraDayLoan: 100},
+				{Iban: "SPARE-IBAN", Balance: 1000, MaxIntraDayLoan: 50},
+			})
+		})
+
+		t.Run("leaves out the accounts whose balance can't be retrieved, and fails when none can be", func(t *testing.T) {
+			is := is.New(t)
+			checkBCAccountFundsUseCase := NewRetrieveBankingCircleAccountFunds(RetrieveBankingCircleAccountFundsOptions{
+				SourceAccounts: sourceAccounts,
+				PaymentAPI:     newAPIClient(map[string]float64{"spare": 100}),
+			})
+
+			funds, err := checkBCAccountFundsUseCase.AccountsFunds("EUR", false)
+
+			is.NoErr(err)
+			is.Equal(funds, []models.AccountFunds{{Iban: "SPARE-IBAN", Balance: 100, MaxIntraDayLoan: 50}})
+
+			checkBCAccountFundsUseCase = NewRetrieveBankingCircleAccountFunds(RetrieveBankingCircleAccountFundsOptions{
+				SourceAccounts: sourceAccounts,
+				PaymentAPI:     newAPIClient(map[string]float64{}),
+			})
+
+			_, err = checkBCAccountFundsUseCase.AccountsFunds("EUR", false)
+
+			is.True(err != nil)
+		})
+	})
 }
@@ -2,9 +2,11 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
+	"sort"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,6 +15,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/saltpay/settlements-payments-system/internal/adapters/env"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/routing"
 	"github.com/saltpay/settlements-payments-system/internal/projectpath"
 )
 
@@ -61,11 +65,21 @@ type Config struct {
 	BankingCircleAPITimeout                   time.Duration `split_words:"true"`
 	BankingCircleAPIInsecureSkipVerify        bool          `split_words:"true"`
 	BankingCircleTokenIntervalBeforeExpire    time.Duration `split_words:"true"`
+	BankingCircleTokenRefreshJitter           time.Duration `split_words:"true"`
 	BankingCircleStatusCheckDelay             int64         `split_words:"true"`
 	BankingCircleMakePaymentDelayMilliseconds int64         `split_words:"true"`
-	BankingCircleMaxCheckIterations           int64         `split_words:"true"`
+	BankingCircleStatusCheckHorizon           time.Duration `split_words:"true"`
+	BankingCircleStatusCheckMaxDelay          time.Duration `split_words:"true"`
 	BankingCircleMakePaymentWorkerPoolSize    int64         `split_words:"true"`
 	BankingCircleCheckPaymentWorkerPoolSize   int64         `split_words:"true"`
+	BankingCircleBulkPaymentCurrencies        []string      `split_words:"true"`
+	BankingCircleBulkPaymentMaxSize           int           `split_words:"true"`
+	BankingCircleBulkPaymentMaxWait           time.Duration `split_words:"true"`
+	BankingCircleWebhookSecretName            string        `split_words:"true"`
+	BankingCircleCallbackDeadline             time.Duration `split_words:"true"`
+	BankingCircleCircuitFailureThreshold      int           `split_words:"true"`
+	BankingCircleCircuitOpenFor               time.Duration `split_words:"true"`
+	BankingCircleCircuitProbesToClose         int           `split_words:"true"`
 	FeatureFlagServiceURL                     string        `split_words:"true"`
 	FeatureFlagServiceAPIKeySecretName        string        `split_words:"true"`
 	UseFakeBankingCircleAPI                   bool          `split_words:"true"`
@@ -80,9 +94,34 @@ type Config struct {
 	FeatureFlagServiceAdminAPIKeySecretName   string        `split_words:"true"`
 	FailedPaymentsThreshold                   int           `split_words:"true"`
 	EnablePostPaymentEndpoint                 bool          `split_words:"true"`
+	PendingFundingRecheckInterval             time.Duration `split_words:"true"`
+	PaymentOutboxRelayInterval                time.Duration `split_words:"true"`
+	StuckPaymentSweepInterval                 time.Duration `split_words:"true"`
+	StuckPaymentThresholds                    Thresholds    `split_words:"true"`
+	RejectionReportIngestInterval             time.Duration `split_words:"true"`
+	PaymentRoutingRules                       RoutingRules  `split_words:"true"`
 	Kafka                                     KafkaConfig
 }
 
+// Thresholds are durations set per payment provider, as in `banking_circle:2h,islandsbanki:6h`.
+type Thresholds map[models.PaymentProviderType]time.Duration
+
+// RoutingRules route the payment instructions, as a JSON list of routing rules tried in order, e.g.
+// `[{"name":"saxo","senders":["SAXO*"],"paymentProvider":"banking_circle"}]`.
+type RoutingRules []routing.Rule
+
+func (r *RoutingRules) Decode(value string) error {
+	return json.Unmarshal([]byte(value), (*[]routing.Rule)(r))
+}
+
+// Router routes with the default rules when none are configured.
+func (r RoutingRules) Router() (routing.Rules, error) {
+	if len(r) == 0 {
+		return routing.DefaultRules(), nil
+	}
+	return routing.NewRules(r)
+}
+
 type KafkaConfig struct {
 	Endpoint           []string `split_words:"true"`
 	UsernameSecretName string   `split_words:"true"`
@@ -91,10 +130,33 @@ type KafkaConfig struct {
 }
 
 type KafkaTopics struct {
-	Transactions                    string `split_words:"true"`
-	AcquiringHostTransactionUpdates string `split_words:"true"`
-	UnprocessedISBPayments          string `split_words:"true"`
-	PaymentStateUpdates             string `split_words:"true"`
+	Transactions                    string      `split_words:"true"`
+	TransactionsRetries             RetryTopics `split_words:"true"`
+	TransactionsDeadLetters         string      `split_words:"true"`
+	TransactionsAcks                string      `split_words:"true"`
+	AcquiringHostTransactionUpdates string      `split_words:"true"`
+	UnprocessedISBPayments          string      `split_words:"true"`
+	PaymentStateUpdates             string      `split_words:"true"`
+	PaymentStateUpdatesRetries      RetryTopics `split_words:"true"`
+	PaymentStateUpdatesDeadLetters  string      `split_words:"true"`
+}
+
+// RetryTopics are the retry topics of a topic with the delay of each, as in `transactions-retry-1:1m,transactions-retry-2:10m`.
+type RetryTopics map[string]time.Duration
+
+// Ordered are the retry topics from the shortest delay up, the order a failed message is retried on them.
+func (r RetryTopics) Ordered() []string {
+	topics := make([]string, 0, len(r))
+	for topic := range r {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if r[topics[i]] == r[topics[j]] {
+			return topics[i] < topics[j]
+		}
+		return r[topics[i]] < r[topics[j]]
+	})
+	return topics
 }
 
 // LoadConfig loads the app config from environment variables.
@@ -22,6 +22,7 @@ require (
 	github.com/saltpay/go-mux-tracing v1.0.1
 	github.com/saltpay/go-postgres-tracing v1.0.1
 	github.com/saltpay/go-zap-ctx v1.3.0
+	github.com/segmentio/kafka-go v0.4.30
 	github.com/stretchr/testify v1.8.0
 	go.opentelemetry.io/otel v1.6.1
 	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.6.1
@@ -66,7 +67,6 @@ require (
 	github.com/prometheus/client_model v0.2.0 // indirect
 	github.com/prometheus/common v0.32.1 // indirect
 	github.com/prometheus/procfs v0.7.3 // indirect
-	github.com/segmentio/kafka-go v0.4.30 // indirect
 	github.com/twmb/murmur3 v1.1.5 // indirect
 	github.com/xdg/scram v0.0.0-20180814205039-7eeb5667e42c // indirect
 	github.com/xdg/stringprep v1.0.0 // indirect
@@ -0,0 +1,242 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// The message attributes of a dead-lettered message, they are kept when the message is redriven so that its attempts
+// add up and its first failure is not forgotten when it fails again.
+const (
+	deadLetterErrorAttribute         = "dlq-error"
+	deadLetterErrorClassAttribute    = "dlq-error-class"
+	deadLetterOriginQueueAttribute   = "dlq-origin-queue"
+	deadLetterListenerAttribute      = "dlq-listener"
+	deadLetterAttemptsAttribute      = "dlq-attempts"
+	deadLetterFirstFailedAtAttribute = "dlq-first-failed-at"
+)
+
+// maxDeadLetterErrorLength keeps the error text well within the 256KB SQS allows a message with its attributes.
+const maxDeadLetterErrorLength = 1024
+
+// DeadLetter is why and where a message failed, it travels with the message to the dead letter queue as its message attributes.
+type DeadLetter struct {
+	Error         string    `json:"error,omitempty"`
+	ErrorClass    string    `json:"errorClass,omitempty"`
+	OriginQueue   string    `json:"originQueue,omitempty"`
+	Listener      string    `json:"listener,omitempty"`
+	Attempts      int       `json:"attempts,omitempty"`
+	FirstFailedAt time.Time `json:"firstFailedAt"`
+}
+
+// NewDeadLetter describes the failure of the message received from the origin queue by the listener. The attempts are
+// the times SQS delivered the message, added to the attempts of the earlier failures of a redriven message.
+func NewDeadLetter(listener string, origin Queue, message *sqs.Message, err error) DeadLetter {
+	deadLetter, redriven := DeadLetterOf(message)
+	if !redriven {
+		deadLetter = DeadLetter{FirstFailedAt: time.Now().UTC()}
+	}
+
+	deadLetter.Listener = listener
+	deadLetter.OriginQueue = queueNameOf(origin)
+	deadLetter.Attempts += receiveCountOf(message)
+	deadLetter.Error, deadLetter.ErrorClass = "", ""
+	if err != nil {
+		deadLetter.Error = truncateError(err.Error())
+		deadLetter.ErrorClass = errorClassOf(err)
+	}
+	return deadLetter
+}
+
+// DeadLetterOf reads the dead letter a message was sent to a dead letter queue with, if it was.
+func DeadLetterOf(message *sqs.Message) (DeadLetter, bool) {
+	attributes := message.MessageAttributes
+	if _, exists := attributes[deadLetterListenerAttribute]; !exists {
+		return DeadLetter{}, false
+	}
+
+	deadLetter := DeadLetter{
+		Error:       stringAttribute(attributes, deadLetterErrorAttribute),
+		ErrorClass:  stringAttribute(attributes, deadLetterErrorClassAttribute),
+		OriginQueue: stringAttribute(attributes, deadLetterOriginQueueAttribute),
+		Listener:    stringAttribute(attributes, deadLetterListenerAttribute),
+	}
+	deadLetter.Attempts, _ = strconv.Atoi(stringAttribute(attributes, deadLetterAttemptsAttribute))
+	deadLetter.FirstFailedAt, _ = time.Parse(time.RFC3339, stringAttribute(attributes, deadLetterFirstFailedAtAttribute))
+	return deadLetter, true
+}
+
+func (d DeadLetter) MessageAttributes() map[string]*sqs.MessageAttributeValue {
+	attributes := map[string]*sqs.MessageAttributeValue{
+		deadLetterListenerAttribute:      stringAttributeValue(d.Listener),
+		deadLetterAttemptsAttribute:      {DataType: aws.String("Number"), StringValue: aws.String(strconv.Itoa(d.Attempts))},
+		deadLetterFirstFailedAtAttribute: stringAttributeValue(d.FirstFailedAt.Format(time.RFC3339)),
+	}
+	// SQS rejects empty attribute values
+	for name, value := range map[string]string{
+		deadLetterErrorAttribute:       d.Error,
+		deadLetterErrorClassAttribute:  d.ErrorClass,
+		deadLetterOriginQueueAttribute: d.OriginQueue,
+	} {
+		if value != "" {
+			attributes[name] = stringAttributeValue(value)
+		}
+	}
+	return attributes
+}
+
+// SendDeadLetter sends the body of a failed message to the dead letter queue, along with why and where it failed.
+func SendDeadLetter(ctx context.Context, dlq Queue, body string, deadLetter DeadLetter) error {
+	return dlq.SendMessageWithAttributes(ctx, body, deadLetter.MessageAttributes())
+}
+
+type DeadLetterMessage struct {
+	MessageID string `json:"messageId"`
+	Body      string `json:"body"`
+}
+
+// DeadLetterGroup are the messages of a dead letter queue that failed in the same listener, from the same queue, with
+// the same class of error and the same error once its identifiers and values are left out. The messages sent to the
+// queue before their failure was recorded are grouped together.
+type DeadLetterGroup struct {
+	Listener      string              `json:"listener,omitempty"`
+	OriginQueue   string              `json:"originQueue,omitempty"`
+	ErrorClass    string              `json:"errorClass,omitempty"`
+	ErrorPattern  string              `json:"errorPattern,omitempty"`
+	Errors        []string            `json:"errors,omitempty"`
+	Count         int                 `json:"count"`
+	MaxAttempts   int                 `json:"maxAttempts,omitempty"`
+	FirstFailedAt *time.Time          `json:"firstFailedAt,omitempty"`
+	Messages      []DeadLetterMessage `json:"messages"`
+}
+
+// GroupDeadLetters groups the messages of a dead letter queue by their listener, origin queue, error class and error
+// pattern, the largest groups first. Most errors are made with errors.New or fmt.Errorf, their class alone tells
+// nothing of the failure.
+func GroupDeadLetters(messages []*sqs.Message) []DeadLetterGroup {
+	type groupKey struct{ listener, originQueue, errorClass, errorPattern string }
+
+	var (
+		keys   []groupKey
+		groups = make(map[groupKey]*DeadLetterGroup)
+	)
+	for _, message := range messages {
+		deadLetter, _ := DeadLetterOf(message)
+		key := groupKey{deadLetter.Listener, deadLetter.OriginQueue, deadLetter.ErrorClass, errorPatternOf(deadLetter.Error)}
+
+		group, exists := groups[key]
+		if !exists {
+			group = &DeadLetterGroup{
+				Listener:     key.listener,
+				OriginQueue:  key.originQueue,
+				ErrorClass:   key.errorClass,
+				ErrorPattern: key.errorPattern,
+			}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+
+		group.Count++
+		group.Messages = append(group.Messages, DeadLetterMessage{
+			MessageID: aws.StringValue(message.MessageId),
+			Body:      aws.StringValue(message.Body),
+		})
+		if deadLetter.Error != "" && !contains(group.Errors, deadLetter.Error) {
+			group.Errors = append(group.Errors, deadLetter.Error)
+		}
+		if deadLetter.Attempts > group.MaxAttempts {
+			group.MaxAttempts = deadLetter.Attempts
+		}
+		if failedAt := deadLetter.FirstFailedAt; !failedAt.IsZero() && (group.FirstFailedAt == nil || failedAt.Before(*group.FirstFailedAt)) {
+			group.FirstFailedAt = &failedAt
+		}
+	}
+
+	grouped := make([]DeadLetterGroup, 0, len(keys))
+	for _, key := range keys {
+		grouped = append(grouped, *groups[key])
+	}
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return grouped[i].Count > grouped[j].Count
+	})
+	return grouped
+}
+
+func queueNameOf(queue Queue) string {
+	if named, ok := queue.(interface{ QueueName() string }); ok {
+		return named.QueueName()
+	}
+	return ""
+}
+
+func receiveCountOf(message *sqs.Message) int {
+	count, err := strconv.Atoi(aws.StringValue(message.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]))
+	if err != nil || count < 1 {
+		return 1
+	}
+	return count
+}
+
+// errorClassOf is the type of the innermost error, e.g. *json.SyntaxError or models.VersionConflictError.
+func errorClassOf(err error) string {
+	for unwrapped := errors.Unwrap(err); unwrapped != nil; unwrapped = errors.Unwrap(err) {
+		err = unwrapped
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// truncateError cuts the error text at a rune boundary, SQS only takes valid UTF-8 attribute values.
+func truncateError(text string) string {
+	if len(text) > maxDeadLetterErrorLength {
+		cut := maxDeadLetterErrorLength
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+	return strings.ToValidUTF8(text, string(utf8.RuneError))
+}
+
+var (
+	quotedValuePattern = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	uuidPattern        = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	numberPattern      = regexp.MustCompile(`[0-9]+(\.[0-9]+)?`)
+)
+
+// errorPatternOf leaves the quoted values, ids and numbers out of an error, so that the same failure of different
+// messages reads the same.
+func errorPatternOf(text string) string {
+	text = quotedValuePattern.ReplaceAllString(text, "<value>")
+	text = uuidPattern.ReplaceAllString(text, "<id>")
+	return numberPattern.ReplaceAllString(text, "<n>")
+}
+
+func stringAttribute(attributes map[string]*sqs.MessageAttributeValue, name string) string {
+	if value, exists := attributes[name]; exists && value != nil {
+		return aws.StringValue(value.StringValue)
+	}
+	return ""
+}
+
+func stringAttributeValue(value string) *sqs.MessageAttributeValue {
+	return &sqs.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
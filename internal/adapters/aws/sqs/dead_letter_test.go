@@ -0,0 +1,118 @@
+//go:build unit
+// +build unit
+
+package sqs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsSqs "github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs/mocks"
+)
+
+func TestDeadLetter(t *testing.T) {
+	t.Run("travels with the message as its message attributes", func(t *testing.T) {
+		is := is.New(t)
+		message := &awsSqs.Message{
+			MessageId:  aws.String("message-1"),
+			Body:       aws.String("{"),
+			Attributes: map[string]*string{awsSqs.MessageSystemAttributeNameApproximateReceiveCount: aws.String("3")},
+		}
+		failure := fmt.Errorf("unable to read the payment instruction: %w", json.Unmarshal([]byte("{"), &struct{}{}))
+
+		deadLetter := sqs.NewDeadLetter("PaymentInstructionEventListener", &mocks.QueueMock{}, message, failure)
+
+		read, ok := sqs.DeadLetterOf(&awsSqs.Message{MessageAttributes: deadLetter.MessageAttributes()})
+		is.True(ok)
+		is.Equal(read.Listener, "PaymentInstructionEventListener")
+		is.Equal(read.Error, failure.Error())
+		is.Equal(read.ErrorClass, "*json.SyntaxError")
+		is.Equal(read.Attempts, 3)
+		is.True(time.Since(read.FirstFailedAt) < time.Minute)
+	})
+
+	t.Run("a redriven message that fails again adds up its attempts and keeps its first failure", func(t *testing.T) {
+		is := is.New(t)
+		firstFailedAt := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+		earlier := sqs.DeadLetter{Listener: "CheckPaymentStatusListener", Error: "timeout", Attempts: 2, FirstFailedAt: firstFailedAt}
+		redriven := &awsSqs.Message{MessageId: aws.String("message-1"), MessageAttributes: earlier.MessageAttributes()}
+
+		deadLetter := sqs.NewDeadLetter("CheckPaymentStatusListener", &mocks.QueueMock{}, redriven, fmt.Errorf("still failing"))
+
+		is.Equal(deadLetter.Attempts, 3)
+		is.Equal(deadLetter.FirstFailedAt, firstFailedAt)
+		is.Equal(deadLetter.Error, "still failing")
+	})
+
+	t.Run("a long error is cut at a rune boundary", func(t *testing.T) {
+		is := is.New(t)
+		failure := errors.New(strings.Repeat("a", 1023) + "é is not a valid IBAN")
+
+		deadLetter := sqs.NewDeadLetter("PaymentInstructionEventListener", &mocks.QueueMock{}, &awsSqs.Message{MessageId: aws.String("message-1")}, failure)
+
+		is.Equal(deadLetter.Error, strings.Repeat("a", 1023))
+		is.True(utf8.ValidString(deadLetter.Error))
+	})
+
+	t.Run("a message sent to a dead letter queue without its failure isn't a dead letter", func(t *testing.T) {
+		is := is.New(t)
+		_, ok := sqs.DeadLetterOf(&awsSqs.Message{MessageId: aws.String("message-1")})
+		is.True(!ok)
+	})
+}
+
+func TestGroupDeadLetters(t *testing.T) {
+	is := is.New(t)
+	deadLetterMessage := func(id string, deadLetter sqs.DeadLetter) *awsSqs.Message {
+		return &awsSqs.Message{MessageId: aws.String(id), Body: aws.String(id), MessageAttributes: deadLetter.MessageAttributes()}
+	}
+	var (
+		earliest = time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+		timeout  = sqs.DeadLetter{Listener: "UfxFileListener", OriginQueue: "ufx", ErrorClass: "*url.Error", Error: "timeout", Attempts: 1, FirstFailedAt: earliest.Add(time.Hour)}
+		refused  = sqs.DeadLetter{Listener: "UfxFileListener", OriginQueue: "ufx", ErrorClass: "*url.Error", Error: "connection refused", Attempts: 4, FirstFailedAt: earliest}
+		invalid  = sqs.DeadLetter{Listener: "UfxFileListener", OriginQueue: "ufx", ErrorClass: "*json.SyntaxError", Error: "invalid character", Attempts: 1, FirstFailedAt: earliest}
+		notFound = func(id string) sqs.DeadLetter {
+			return sqs.DeadLetter{Listener: "UfxFileListener", OriginQueue: "ufx", ErrorClass: "*errors.errorString", Error: fmt.Sprintf("payment instruction %q not found", id), Attempts: 1, FirstFailedAt: earliest}
+		}
+		unreadable = sqs.DeadLetter{Listener: "UfxFileListener", OriginQueue: "ufx", ErrorClass: "*errors.errorString", Error: "unable to read file 2022-09-01.ufx", Attempts: 1, FirstFailedAt: earliest}
+	)
+
+	groups := sqs.GroupDeadLetters([]*awsSqs.Message{
+		deadLetterMessage("message-1", invalid),
+		deadLetterMessage("message-2", timeout),
+		deadLetterMessage("message-3", refused),
+		deadLetterMessage("message-4", timeout),
+		deadLetterMessage("message-5", notFound("pi-1")),
+		deadLetterMessage("message-6", notFound("pi-2")),
+		deadLetterMessage("message-7", notFound("pi-3")),
+		deadLetterMessage("message-8", unreadable),
+		{MessageId: aws.String("message-9"), Body: aws.String("message-9")},
+	})
+
+	is.Equal(len(groups), 6)
+	is.Equal(groups[0].ErrorClass, "*errors.errorString")
+	is.Equal(groups[0].ErrorPattern, "payment instruction <value> not found")
+	is.Equal(groups[0].Count, 3)
+	is.Equal(groups[0].Errors, []string{`payment instruction "pi-1" not found`, `payment instruction "pi-2" not found`, `payment instruction "pi-3" not found`})
+	is.Equal(groups[1].ErrorClass, "*url.Error")
+	is.Equal(groups[1].Count, 2)
+	is.Equal(groups[1].Errors, []string{"timeout"})
+	is.Equal(*groups[1].FirstFailedAt, earliest.Add(time.Hour))
+	is.Equal(groups[2].ErrorClass, "*json.SyntaxError")
+	is.Equal(groups[3].Errors, []string{"connection refused"})
+	is.Equal(groups[3].MaxAttempts, 4)
+	is.Equal(*groups[3].FirstFailedAt, earliest)
+	is.Equal(groups[4].ErrorPattern, "unable to read file <n>-<n>-<n>.ufx")
+	is.Equal(groups[5].Listener, "")
+	is.Equal(groups[5].Messages, []sqs.DeadLetterMessage{{MessageID: "message-9", Body: "message-9"}})
+}
@@ -5,9 +5,10 @@ package mocks
 
 import (
 	"context"
-	awssqs "github.com/aws/aws-sdk-go/service/sqs"
+	awsSqs "github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs"
 	"sync"
+	"time"
 )
 
 // Ensure, that QueueMock does implement sqs.Queue.
@@ -23,10 +24,13 @@ var _ sqs.Queue = &QueueMock{}
 // 			AttributesFunc: func(contextMoqParam context.Context) (sqs.QueueAttributes, error) {
 // 				panic("mock out the Attributes method")
 // 			},
+// 			ChangeMessageVisibilityFunc: func(ctx context.Context, messageHandle string, timeout time.Duration) error {
+// 				panic("mock out the ChangeMessageVisibility method")
+// 			},
 // 			DeleteMessageFunc: func(ctx context.Context, messageHandle string) error {
 // 				panic("mock out the DeleteMessage method")
 // 			},
-// 			GetMessagesFunc: func(contextMoqParam context.Context) (*awssqs.ReceiveMessageOutput, error) {
+// 			GetMessagesFunc: func(contextMoqParam context.Context) (*awsSqs.ReceiveMessageOutput, error) {
 // 				panic("mock out the GetMessages method")
 // 			},
 // 			PeekAllMessagesFunc: func(contextMoqParam context.Context) (sqs.DLQInformation, error) {
@@ -38,6 +42,12 @@ var _ sqs.Queue = &QueueMock{}
 // 			SendMessageFunc: func(contextMoqParam context.Context, s string) error {
 // 				panic("mock out the SendMessage method")
 // 			},
+// 			SendMessageWithAttributesFunc: func(ctx context.Context, messageBody string, attributes map[string]*awsSqs.MessageAttributeValue) error {
+// 				panic("mock out the SendMessageWithAttributes method")
+// 			},
+// 			SendMessageWithDelayFunc: func(ctx context.Context, messageBody string, delay time.Duration) error {
+// 				panic("mock out the SendMessageWithDelay method")
+// 			},
 // 		}
 //
 // 		// use mockedQueue in code that requires sqs.Queue
@@ -48,11 +58,14 @@ type QueueMock struct {
 	// AttributesFunc mocks the Attributes method.
 	AttributesFunc func(contextMoqParam context.Context) (sqs.QueueAttributes, error)
 
+	// ChangeMessageVisibilityFunc mocks the ChangeMessageVisibility method.
+	ChangeMessageVisibilityFunc func(ctx context.Context, messageHandle string, timeout time.Duration) error
+
 	// DeleteMessageFunc mocks the DeleteMessage method.
 	DeleteMessageFunc func(ctx context.Context, messageHandle string) error
 
 	// GetMessagesFunc mocks the GetMessages method.
-	GetMessagesFunc func(contextMoqParam context.Context) (*awssqs.ReceiveMessageOutput, error)
+	GetMessagesFunc func(contextMoqParam context.Context) (*awsSqs.ReceiveMessageOutput, error)
 
 	// PeekAllMessagesFunc mocks the PeekAllMessages method.
 	PeekAllMessagesFunc func(contextMoqParam context.Context) (sqs.DLQInformation, error)
@@ -63,6 +76,12 @@ type QueueMock struct {
 	// SendMessageFunc mocks the SendMessage method.
 	SendMessageFunc func(contextMoqParam context.Context, s string) error
 
+	// SendMessageWithAttributesFunc mocks the SendMessageWithAttributes method.
+	SendMessageWithAttributesFunc func(ctx context.Context, messageBody string, attributes map[string]*awsSqs.MessageAttributeValue) error
+
+	// SendMessageWithDelayFunc mocks the SendMessageWithDelay method.
+	SendMessageWithDelayFunc func(ctx context.Context, messageBody string, delay time.Duration) error
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Attributes holds details about calls to the Attributes method.
@@ -70,6 +89,15 @@ type QueueMock struct {
 			// ContextMoqParam is the contextMoqParam argument value.
 			ContextMoqParam context.Context
 		}
+		// ChangeMessageVisibility holds details about calls to the ChangeMessageVisibility method.
+		ChangeMessageVisibility []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// MessageHandle is the messageHandle argument value.
+			MessageHandle string
+			// Timeout is the timeout argument value.
+			Timeout time.Duration
+		}
 		// DeleteMessage holds details about calls to the DeleteMessage method.
 		DeleteMessage []struct {
 			// Ctx is the ctx argument value.
@@ -99,13 +127,34 @@ type QueueMock struct {
 			// S is the s argument value.
 			S string
 		}
+		// SendMessageWithAttributes holds details about calls to the SendMessageWithAttributes method.
+		SendMessageWithAttributes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// MessageBody is the messageBody argument value.
+			MessageBody string
+			// Attributes is the attributes argument value.
+			Attributes map[string]*awsSqs.MessageAttributeValue
+		}
+		// SendMessageWithDelay holds details about calls to the SendMessageWithDelay method.
+		SendMessageWithDelay []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// MessageBody is the messageBody argument value.
+			MessageBody string
+			// Delay is the delay argument value.
+			Delay time.Duration
+		}
 	}
-	lockAttributes      sync.RWMutex
-	lockDeleteMessage   sync.RWMutex
-	lockGetMessages     sync.RWMutex
-	lockPeekAllMessages sync.RWMutex
-	lockPurge           sync.RWMutex
-	lockSendMessage     sync.RWMutex
+	lockAttributes                sync.RWMutex
+	lockChangeMessageVisibility   sync.RWMutex
+	lockDeleteMessage             sync.RWMutex
+	lockGetMessages               sync.RWMutex
+	lockPeekAllMessages           sync.RWMutex
+	lockPurge                     sync.RWMutex
+	lockSendMessage               sync.RWMutex
+	lockSendMessageWithAttributes sync.RWMutex
+	lockSendMessageWithDelay      sync.RWMutex
 }
 
 // Attributes calls AttributesFunc.
@@ -126,7 +175,8 @@ func (mock *QueueMock) Attributes(contextMoqParam context.Context) (sqs.QueueAtt
 
 // AttributesCalls gets all the calls that were made to Attributes.
 // Check the length with:
-//     len(mockedQueue.AttributesCalls())
+//
+// 	len(mockedQueue.AttributesCalls())
 func (mock *QueueMock) AttributesCalls() []struct {
 	ContextMoqParam context.Context
 } {
@@ -139,6 +189,46 @@ func (mock *QueueMock) AttributesCalls() []struct {
 	return calls
 }
 
+// ChangeMessageVisibility calls ChangeMessageVisibilityFunc.
+func (mock *QueueMock) ChangeMessageVisibility(ctx context.Context, messageHandle string, timeout time.Duration) error {
+	if mock.ChangeMessageVisibilityFunc == nil {
+		panic("QueueMock.ChangeMessageVisibilityFunc: method is nil but Queue.ChangeMessageVisibility was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		MessageHandle string
+		Timeout       time.Duration
+	}{
+		Ctx:           ctx,
+		MessageHandle: messageHandle,
+		Timeout:       timeout,
+	}
+	mock.lockChangeMessageVisibility.Lock()
+	mock.calls.ChangeMessageVisibility = append(mock.calls.ChangeMessageVisibility, callInfo)
+	mock.lockChangeMessageVisibility.Unlock()
+	return mock.ChangeMessageVisibilityFunc(ctx, messageHandle, timeout)
+}
+
+// ChangeMessageVisibilityCalls gets all the calls that were made to ChangeMessageVisibility.
+// Check the length with:
+//
+// 	len(mockedQueue.ChangeMessageVisibilityCalls())
+func (mock *QueueMock) ChangeMessageVisibilityCalls() []struct {
+	Ctx           context.Context
+	MessageHandle string
+	Timeout       time.Duration
+} {
+	var calls []struct {
+		Ctx           context.Context
+		MessageHandle string
+		Timeout       time.Duration
+	}
+	mock.lockChangeMessageVisibility.RLock()
+	calls = mock.calls.ChangeMessageVisibility
+	mock.lockChangeMessageVisibility.RUnlock()
+	return calls
+}
+
 // DeleteMessage calls DeleteMessageFunc.
 func (mock *QueueMock) DeleteMessage(ctx context.Context, messageHandle string) error {
 	if mock.DeleteMessageFunc == nil {
@@ -159,7 +249,8 @@ func (mock *QueueMock) DeleteMessage(ctx context.Context, messageHandle string)
 
 // DeleteMessageCalls gets all the calls that were made to DeleteMessage.
 // Check the length with:
-//     len(mockedQueue.DeleteMessageCalls())
+//
+// 	len(mockedQueue.DeleteMessageCalls())
 func (mock *QueueMock) DeleteMessageCalls() []struct {
 	Ctx           context.Context
 	MessageHandle string
@@ -175,7 +266,7 @@ func (mock *QueueMock) DeleteMessageCalls() []struct {
 }
 
 // GetMessages calls GetMessagesFunc.
-func (mock *QueueMock) GetMessages(contextMoqParam context.Context) (*awssqs.ReceiveMessageOutput, error) {
+func (mock *QueueMock) GetMessages(contextMoqParam context.Context) (*awsSqs.ReceiveMessageOutput, error) {
 	if mock.GetMessagesFunc == nil {
 		panic("QueueMock.GetMessagesFunc: method is nil but Queue.GetMessages was just called")
 	}
@@ -192,7 +283,8 @@ func (mock *QueueMock) GetMessages(contextMoqParam context.Context) (*awssqs.Rec
 
 // GetMessagesCalls gets all the calls that were made to GetMessages.
 // Check the length with:
-//     len(mockedQueue.GetMessagesCalls())
+//
+// 	len(mockedQueue.GetMessagesCalls())
 func (mock *QueueMock) GetMessagesCalls() []struct {
 	ContextMoqParam context.Context
 } {
@@ -223,7 +315,8 @@ func (mock *QueueMock) PeekAllMessages(contextMoqParam context.Context) (sqs.DLQ
 
 // PeekAllMessagesCalls gets all the calls that were made to PeekAllMessages.
 // Check the length with:
-//     len(mockedQueue.PeekAllMessagesCalls())
+//
+// 	len(mockedQueue.PeekAllMessagesCalls())
 func (mock *QueueMock) PeekAllMessagesCalls() []struct {
 	ContextMoqParam context.Context
 } {
@@ -254,7 +347,8 @@ func (mock *QueueMock) Purge(contextMoqParam context.Context) error {
 
 // PurgeCalls gets all the calls that were made to Purge.
 // Check the length with:
-//     len(mockedQueue.PurgeCalls())
+//
+// 	len(mockedQueue.PurgeCalls())
 func (mock *QueueMock) PurgeCalls() []struct {
 	ContextMoqParam context.Context
 } {
@@ -287,7 +381,8 @@ func (mock *QueueMock) SendMessage(contextMoqParam context.Context, s string) er
 
 // SendMessageCalls gets all the calls that were made to SendMessage.
 // Check the length with:
-//     len(mockedQueue.SendMessageCalls())
+//
+// 	len(mockedQueue.SendMessageCalls())
 func (mock *QueueMock) SendMessageCalls() []struct {
 	ContextMoqParam context.Context
 	S               string
@@ -301,3 +396,83 @@ func (mock *QueueMock) SendMessageCalls() []struct {
 	mock.lockSendMessage.RUnlock()
 	return calls
 }
+
+// SendMessageWithAttributes calls SendMessageWithAttributesFunc.
+func (mock *QueueMock) SendMessageWithAttributes(ctx context.Context, messageBody string, attributes map[string]*awsSqs.MessageAttributeValue) error {
+	if mock.SendMessageWithAttributesFunc == nil {
+		panic("QueueMock.SendMessageWithAttributesFunc: method is nil but Queue.SendMessageWithAttributes was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		MessageBody string
+		Attributes  map[string]*awsSqs.MessageAttributeValue
+	}{
+		Ctx:         ctx,
+		MessageBody: messageBody,
+		Attributes:  attributes,
+	}
+	mock.lockSendMessageWithAttributes.Lock()
+	mock.calls.SendMessageWithAttributes = append(mock.calls.SendMessageWithAttributes, callInfo)
+	mock.lockSendMessageWithAttributes.Unlock()
+	return mock.SendMessageWithAttributesFunc(ctx, messageBody, attributes)
+}
+
+// SendMessageWithAttributesCalls gets all the calls that were made to SendMessageWithAttributes.
+// Check the length with:
+//
+// 	len(mockedQueue.SendMessageWithAttributesCalls())
+func (mock *QueueMock) SendMessageWithAttributesCalls() []struct {
+	Ctx         context.Context
+	MessageBody string
+	Attributes  map[string]*awsSqs.MessageAttributeValue
+} {
+	var calls []struct {
+		Ctx         context.Context
+		MessageBody string
+		Attributes  map[string]*awsSqs.MessageAttributeValue
+	}
+	mock.lockSendMessageWithAttributes.RLock()
+	calls = mock.calls.SendMessageWithAttributes
+	mock.lockSendMessageWithAttributes.RUnlock()
+	return calls
+}
+
+// SendMessageWithDelay calls SendMessageWithDelayFunc.
+func (mock *QueueMock) SendMessageWithDelay(ctx context.Context, messageBody string, delay time.Duration) error {
+	if mock.SendMessageWithDelayFunc == nil {
+		panic("QueueMock.SendMessageWithDelayFunc: method is nil but Queue.SendMessageWithDelay was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		MessageBody string
+		Delay       time.Duration
+	}{
+		Ctx:         ctx,
+		MessageBody: messageBody,
+		Delay:       delay,
+	}
+	mock.lockSendMessageWithDelay.Lock()
+	mock.calls.SendMessageWithDelay = append(mock.calls.SendMessageWithDelay, callInfo)
+	mock.lockSendMessageWithDelay.Unlock()
+	return mock.SendMessageWithDelayFunc(ctx, messageBody, delay)
+}
+
+// SendMessageWithDelayCalls gets all the calls that were made to SendMessageWithDelay.
+// Check the length with:
+//
+// 	len(mockedQueue.SendMessageWithDelayCalls())
+func (mock *QueueMock) SendMessageWithDelayCalls() []struct {
+	Ctx         context.Context
+	MessageBody string
+	Delay       time.Duration
+} {
+	var calls []struct {
+		Ctx         context.Context
+		MessageBody string
+		Delay       time.Duration
+	}
+	mock.lockSendMessageWithDelay.RLock()
+	calls = mock.calls.SendMessageWithDelay
+	mock.lockSendMessageWithDelay.RUnlock()
+	return calls
+}
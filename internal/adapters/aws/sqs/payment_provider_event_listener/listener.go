@@ -2,6 +2,7 @@ package payment_provider_event_listener
 
 import (
 	"context"
+	"errors"
 
 	awssqs "github.com/aws/aws-sdk-go/service/sqs"
 	zapctx "github.com/saltpay/go-zap-ctx"
@@ -17,6 +18,7 @@ type PaymentProviderEventListener struct {
 	incomingSQSClient   sqs.Queue
 	dlqClient           sqs.Queue
 	trackPaymentOutcome ports.TrackPaymentOutcome
+	paymentRepo         ports.GetPaymentInstructionFromRepo
 	observer            PaymentUpdateObservability
 	shouldListen        *sync.AtomicBool
 }
@@ -28,12 +30,20 @@ type PaymentUpdateObservability interface {
 	GotBadMessage(ctx context.Context, goString string, err error)
 	DebugLog(message string)
 	QueueProblem(ctx context.Context, action string, err error)
+	VersionConflict(ctx context.Context, err models.VersionConflictError, attempt int)
 }
 
+const listenerName = "PaymentProviderEventListener"
+
+// maxConflictRetries is how many times an event is reapplied to a freshly read payment instruction
+// when the payment instruction keeps being updated concurrently.
+const maxConflictRetries = 3
+
 func New(
 	sqsClient sqs.Queue,
 	dlqClient sqs.Queue,
 	trackPaymentOutcome ports.TrackPaymentOutcome,
+	paymentRepo ports.GetPaymentInstructionFromRepo,
 	metricsClient ports.MetricsClient,
 ) *PaymentProviderEventListener {
 	shouldListen := sync.New()
@@ -43,6 +53,7 @@ func New(
 		incomingSQSClient:   sqsClient,
 		dlqClient:           dlqClient,
 		trackPaymentOutcome: trackPaymentOutcome,
+		paymentRepo:         paymentRepo,
 		observer: &loggingAndMetricsObservability{
 			metricsClient: metricsClient,
 		},
@@ -74,12 +85,14 @@ func (p *PaymentProviderEventListener) processMessages(ctx context.Context, rmo
 		paymentProviderEvent, err := models.NewPaymentProviderEventFromJSON([]byte(*message.Body))
 		if err != nil {
 			p.observer.GotBadMessage(ctx, message.GoString(), err)
-			p.dlq(ctx, message)
+			p.dlq(ctx, message, err)
 			continue
 		}
 
-		if err := p.trackPaymentOutcome.Execute(ctx, paymentProviderEvent); err != nil {
-			p.dlq(ctx, message)
+		// a rejected status transition is already recorded on the payment instruction, redriving it can't succeed
+		var illegalTransition models.IllegalTransitionError
+		if err := p.trackOutcome(ctx, paymentProviderEvent); err != nil && !errors.As(err, &illegalTransition) {
+			p.dlq(ctx, message, err)
 			continue
 		}
 		p.observer.ExecutedUseCase(paymentProviderEvent)
@@ -93,8 +106,29 @@ func (p *PaymentProviderEventListener) processMessages(ctx context.Context, rmo
 	}
 }
 
-func (p *PaymentProviderEventListener) dlq(ctx context.Context, message *awssqs.Message) {
-	if err := p.dlqClient.SendMessage(ctx, *message.Body); err != nil {
+// trackOutcome reloads the payment instruction and reapplies the event to it when the payment instruction
+// the event was raised for has been updated by someone else since.
+func (p *PaymentProviderEventListener) trackOutcome(ctx context.Context, event models.PaymentProviderEvent) error {
+	err := p.trackPaymentOutcome.Execute(ctx, event)
+
+	var conflict models.VersionConflictError
+	for attempt := 1; attempt <= maxConflictRetries && errors.As(err, &conflict); attempt++ {
+		p.observer.VersionConflict(ctx, conflict, attempt)
+
+		current, getErr := p.paymentRepo.Get(ctx, event.PaymentInstruction.ID())
+		if getErr != nil {
+			return getErr
+		}
+		event.PaymentInstruction = current
+		err = p.trackPaymentOutcome.Execute(ctx, event)
+	}
+
+	return err
+}
+
+func (p *PaymentProviderEventListener) dlq(ctx context.Context, message *awssqs.Message, failure error) {
+	deadLetter := sqs.NewDeadLetter(listenerName, p.incomingSQSClient, message, failure)
+	if err := sqs.SendDeadLetter(ctx, p.dlqClient, *message.Body, deadLetter); err != nil {
 		p.observer.QueueProblem(ctx, "dlq-ing message", err)
 	}
 	if err := p.incomingSQSClient.DeleteMessage(ctx, *message.ReceiptHandle); err != nil {
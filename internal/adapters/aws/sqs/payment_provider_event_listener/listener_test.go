@@ -11,11 +11,13 @@ import (
 	awssqs "github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/matryer/is"
 
+	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs"
 	mocks2 "github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs/mocks"
 	payment_provider_event_listener2 "github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs/payment_provider_event_listener"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs/testhelpers"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	testhelpers3 "github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
 	testhelpers2 "github.com/saltpay/settlements-payments-system/internal/testhelpers"
 )
@@ -51,6 +53,7 @@ func TestPaymentProviderEventListener_Listen(t *testing.T) {
 			spyIncomingQueue,
 			&mocks2.QueueMock{},
 			spyUseCase,
+			&mocks.GetPaymentInstructionFromRepoMock{},
 			testdoubles.DummyMetricsClient{},
 		)
 
@@ -84,11 +87,12 @@ func TestPaymentProviderEventListener_Listen(t *testing.T) {
 			},
 		}
 
+		useCaseErr := testhelpers2.RandomError()
 		failingUseCase := &mocks.TrackPaymentOutcomeMock{ExecuteFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
-			return testhelpers2.RandomError()
+			return useCaseErr
 		}}
 
-		spyDLQ := &mocks2.QueueMock{SendMessageFunc: func(context.Context, string) error {
+		spyDLQ := &mocks2.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awssqs.MessageAttributeValue) error {
 			return nil
 		}}
 
@@ -96,6 +100,7 @@ func TestPaymentProviderEventListener_Listen(t *testing.T) {
 			spyIncomingQueue,
 			spyDLQ,
 			failingUseCase,
+			&mocks.GetPaymentInstructionFromRepoMock{},
 			testdoubles.DummyMetricsClient{},
 		)
 
@@ -104,6 +109,12 @@ func TestPaymentProviderEventListener_Listen(t *testing.T) {
 		select {
 		case <-deleteCalled:
 			testhelpers.AssertMessageWasDLQd(t, spyDLQ, spyIncomingQueue, *msg)
+
+			deadLetter, ok := sqs.DeadLetterOf(&awssqs.Message{MessageAttributes: spyDLQ.SendMessageWithAttributesCalls()[0].Attributes})
+			is.True(ok)
+			is.Equal(deadLetter.Listener, "PaymentProviderEventListener")
+			is.Equal(deadLetter.Error, useCaseErr.Error())
+			is.Equal(deadLetter.Attempts, 1)
 		case <-time.After(timeout):
 			t.Fatal("timed out waiting for delete message to be called")
 		}
@@ -126,7 +137,7 @@ func TestPaymentProviderEventListener_Listen(t *testing.T) {
 			},
 		}
 
-		spyDLQ := &mocks2.QueueMock{SendMessageFunc: func(context.Context, string) error {
+		spyDLQ := &mocks2.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awssqs.MessageAttributeValue) error {
 			return nil
 		}}
 
@@ -134,6 +145,7 @@ func TestPaymentProviderEventListener_Listen(t *testing.T) {
 			spyIncomingQueue,
 			spyDLQ,
 			&mocks.TrackPaymentOutcomeMock{},
+			&mocks.GetPaymentInstructionFromRepoMock{},
 			testdoubles.DummyMetricsClient{},
 		)
 
@@ -148,6 +160,164 @@ func TestPaymentProviderEventListener_Listen(t *testing.T) {
 	})
 }
 
+func TestPaymentProviderEventListener_IllegalTransition(t *testing.T) {
+	t.Run("deletes the event without DLQing it when the payment instruction rejected the status change", func(t *testing.T) {
+		var (
+			ctx          = context.Background()
+			is           = is.New(t)
+			instruction  = testhelpers3.NewPaymentInstructionBuilder().WithStatus(models.Successful).Build()
+			deleteCalled = make(chan struct{})
+		)
+		sqsMessage, err := paymentProviderEventToSQSMessage(models.PaymentProviderEvent{Type: models.Failure, PaymentInstruction: instruction})
+		is.NoErr(err)
+		spyIncomingQueue := &mocks2.QueueMock{
+			DeleteMessageFunc: func(context.Context, string) error {
+				deleteCalled <- struct{}{}
+				return nil
+			},
+			GetMessagesFunc: deliverOnce(sqsMessage),
+		}
+		rejectingUseCase := &mocks.TrackPaymentOutcomeMock{ExecuteFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+			return models.IllegalTransitionError{ID: ppEvent.PaymentInstruction.ID(), From: models.Successful, To: models.Failed}
+		}}
+		spyDLQ := &mocks2.QueueMock{}
+
+		listener := payment_provider_event_listener2.New(
+			spyIncomingQueue,
+			spyDLQ,
+			rejectingUseCase,
+			&mocks.GetPaymentInstructionFromRepoMock{},
+			testdoubles.DummyMetricsClient{},
+		)
+
+		go listener.Listen(ctx)
+		defer listener.StopListening()
+
+		select {
+		case <-deleteCalled:
+			is.Equal(len(rejectingUseCase.ExecuteCalls()), 1)
+			is.Equal(len(spyDLQ.SendMessageWithAttributesCalls()), 0)
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for delete message to be called")
+		}
+	})
+}
+
+func TestPaymentProviderEventListener_VersionConflict(t *testing.T) {
+	t.Run("reapplies the event to the reloaded payment instruction when it was updated concurrently", func(t *testing.T) {
+		var (
+			ctx          = context.Background()
+			is           = is.New(t)
+			instruction  = testhelpers3.NewPaymentInstructionBuilder().WithStatus(models.SubmittedForProcessing).Build()
+			ppEvent      = models.PaymentProviderEvent{Type: models.Processed, PaymentInstruction: instruction}
+			reloaded     = testhelpers3.NewPaymentInstructionBuilder().WithStatus(models.SubmittedForProcessing).Build()
+			deleteCalled = make(chan struct{})
+		)
+		sqsMessage, err := paymentProviderEventToSQSMessage(ppEvent)
+		is.NoErr(err)
+		spyIncomingQueue := &mocks2.QueueMock{
+			DeleteMessageFunc: func(context.Context, string) error {
+				deleteCalled <- struct{}{}
+				return nil
+			},
+			GetMessagesFunc: deliverOnce(sqsMessage),
+		}
+		spyUseCase := &mocks.TrackPaymentOutcomeMock{ExecuteFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+			if ppEvent.PaymentInstruction.ID() != reloaded.ID() {
+				return models.VersionConflictError{ID: ppEvent.PaymentInstruction.ID(), ExpectedVersion: 1, ActualVersion: 2}
+			}
+			return nil
+		}}
+		spyRepo := &mocks.GetPaymentInstructionFromRepoMock{
+			GetFunc: func(ctx context.Context, id models.PaymentInstructionID) (models.PaymentInstruction, error) {
+				return reloaded, nil
+			},
+		}
+		spyDLQ := &mocks2.QueueMock{}
+
+		listener := payment_provider_event_listener2.New(
+			spyIncomingQueue,
+			spyDLQ,
+			spyUseCase,
+			spyRepo,
+			testdoubles.DummyMetricsClient{},
+		)
+
+		go listener.Listen(ctx)
+		defer listener.StopListening()
+
+		select {
+		case <-deleteCalled:
+			is.Equal(len(spyUseCase.ExecuteCalls()), 2)
+			is.Equal(spyRepo.GetCalls()[0].PaymentInstructionID, instruction.ID())
+			is.Equal(spyUseCase.ExecuteCalls()[1].PpEvent.PaymentInstruction.ID(), reloaded.ID())
+			is.Equal(len(spyDLQ.SendMessageWithAttributesCalls()), 0)
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for delete message to be called")
+		}
+	})
+
+	t.Run("DLQs the event when the payment instruction keeps conflicting", func(t *testing.T) {
+		var (
+			ctx          = context.Background()
+			is           = is.New(t)
+			instruction  = testhelpers3.NewPaymentInstructionBuilder().Build()
+			deleteCalled = make(chan struct{})
+		)
+		sqsMessage, err := paymentProviderEventToSQSMessage(models.PaymentProviderEvent{Type: models.Processed, PaymentInstruction: instruction})
+		is.NoErr(err)
+		spyIncomingQueue := &mocks2.QueueMock{
+			DeleteMessageFunc: func(context.Context, string) error {
+				deleteCalled <- struct{}{}
+				return nil
+			},
+			GetMessagesFunc: deliverOnce(sqsMessage),
+		}
+		conflictingUseCase := &mocks.TrackPaymentOutcomeMock{ExecuteFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+			return models.VersionConflictError{ID: ppEvent.PaymentInstruction.ID()}
+		}}
+		stubRepo := &mocks.GetPaymentInstructionFromRepoMock{
+			GetFunc: func(ctx context.Context, id models.PaymentInstructionID) (models.PaymentInstruction, error) {
+				return instruction, nil
+			},
+		}
+		spyDLQ := &mocks2.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awssqs.MessageAttributeValue) error {
+			return nil
+		}}
+
+		listener := payment_provider_event_listener2.New(
+			spyIncomingQueue,
+			spyDLQ,
+			conflictingUseCase,
+			stubRepo,
+			testdoubles.DummyMetricsClient{},
+		)
+
+		go listener.Listen(ctx)
+		defer listener.StopListening()
+
+		select {
+		case <-deleteCalled:
+			is.Equal(len(conflictingUseCase.ExecuteCalls()), 4)
+			testhelpers.AssertMessageWasDLQd(t, spyDLQ, spyIncomingQueue, *sqsMessage)
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for delete message to be called")
+		}
+	})
+}
+
+// deliverOnce returns the message on the first poll only, so the listener doesn't process it again while the test asserts.
+func deliverOnce(message *awssqs.Message) func(context.Context) (*awssqs.ReceiveMessageOutput, error) {
+	delivered := false
+	return func(context.Context) (*awssqs.ReceiveMessageOutput, error) {
+		if delivered {
+			return &awssqs.ReceiveMessageOutput{}, nil
+		}
+		delivered = true
+		return &awssqs.ReceiveMessageOutput{Messages: []*awssqs.Message{message}}, nil
+	}
+}
+
 func paymentProviderEventToSQSMessage(paymentProviderEvent models.PaymentProviderEvent) (*awssqs.Message, error) {
 	paymentProviderEventJSON, err := paymentProviderEvent.ToJSON()
 	if err != nil {
@@ -10,6 +10,8 @@ import (
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
+const versionConflictMetricName = "app_payment_instruction_version_conflict"
+
 type loggingAndMetricsObservability struct {
 	metricsClient ports.MetricsClient
 }
@@ -18,6 +20,16 @@ func (l *loggingAndMetricsObservability) QueueProblem(ctx context.Context, actio
 	zapctx.Error(ctx, "could not perform action on queue", zap.String("action", action), zap.Error(err))
 }
 
+func (l *loggingAndMetricsObservability) VersionConflict(ctx context.Context, err models.VersionConflictError, attempt int) {
+	zapctx.Warn(ctx, "payment instruction was updated concurrently, reapplying the event",
+		zap.String("payment_instruction_id", string(err.ID)),
+		zap.Int("expected_version", err.ExpectedVersion),
+		zap.Int("actual_version", err.ActualVersion),
+		zap.Int("attempt", attempt),
+	)
+	l.metricsClient.Count(ctx, versionConflictMetricName, 1, []string{"payment_provider_events"})
+}
+
 func (l *loggingAndMetricsObservability) GotBadMessage(ctx context.Context, message string, err error) {
 	zapctx.Error(ctx, "could not parse status update from queue", zap.String("message", message), zap.Error(err))
 }
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/sqs"
 )
@@ -15,6 +16,12 @@ type Queue interface {
 	DeleteMessage(ctx context.Context, messageHandle string) error
 	GetMessages(context.Context) (*sqs.ReceiveMessageOutput, error)
 	SendMessage(context.Context, string) error
+	// SendMessageWithAttributes sends a message with message attributes, e.g. the DeadLetter of a failed message.
+	SendMessageWithAttributes(ctx context.Context, messageBody string, attributes map[string]*sqs.MessageAttributeValue) error
+	// SendMessageWithDelay sends a message that is only received once the delay passes, SQS delays up to 15 minutes.
+	SendMessageWithDelay(ctx context.Context, messageBody string, delay time.Duration) error
+	// ChangeMessageVisibility hides a received message for the timeout from now, instead of its visibility timeout.
+	ChangeMessageVisibility(ctx context.Context, messageHandle string, timeout time.Duration) error
 	PeekAllMessages(context.Context) (DLQInformation, error)
 	Purge(context.Context) error
 	Attributes(context.Context) (QueueAttributes, error)
@@ -39,11 +46,20 @@ func (q QueueName) IsDlq() bool {
 	return strings.Contains(string(q), "-dlq")
 }
 
+// SourceQueue is the queue the dead letter queue takes the messages of, as in `bc-unprocessed` for `bc-unprocessed-dlq`.
+func (q QueueName) SourceQueue() (QueueName, bool) {
+	if !q.IsDlq() {
+		return "", false
+	}
+	return QueueName(strings.TrimSuffix(string(q), "-dlq")), true
+}
+
 type QueueClientMapping map[QueueName]Queue
 
 type DLQInformation struct {
 	Count    int
 	Messages []string
+	Groups   []DeadLetterGroup
 }
 
 type QueueAttributes map[string]*string
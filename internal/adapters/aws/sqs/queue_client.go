@@ -77,6 +77,7 @@ func (sqsClient *QueueClient) GetMessages(ctx context.Context) (*sqs.ReceiveMess
 	msgResult, err := sqsClient.sqsSvc.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
 		AttributeNames: []*string{
 			aws.String(sqs.MessageSystemAttributeNameSentTimestamp),
+			aws.String(sqs.MessageSystemAttributeNameApproximateReceiveCount),
 		},
 		MessageAttributeNames: []*string{
 			aws.String(sqs.QueueAttributeNameAll),
@@ -104,7 +105,10 @@ func (sqsClient *QueueClient) PeekAllMessages(ctx context.Context) (DLQInformati
 		return DLQInformation{}, err
 	}
 
-	dlqInformation := DLQInformation{}
+	var (
+		dlqInformation = DLQInformation{}
+		peeked         []*sqs.Message
+	)
 
 	wantMessageCount := dlqMessageCount
 	timeout := time.Now().Add(time.Second * time.Duration(*sqsClient.visibilityTimeoutSeconds))
@@ -118,10 +122,12 @@ func (sqsClient *QueueClient) PeekAllMessages(ctx context.Context) (DLQInformati
 		for _, message := range messages.Messages {
 			dlqInformation.Messages = append(dlqInformation.Messages, *message.Body)
 		}
+		peeked = append(peeked, messages.Messages...)
 		wantMessageCount -= len(messages.Messages)
 	}
 
 	dlqInformation.Count = len(dlqInformation.Messages)
+	dlqInformation.Groups = GroupDeadLetters(peeked)
 	return dlqInformation, nil
 }
 
@@ -133,6 +139,33 @@ func (sqsClient *QueueClient) SendMessage(ctx context.Context, messageBody strin
 	return err
 }
 
+func (sqsClient *QueueClient) SendMessageWithAttributes(ctx context.Context, messageBody string, attributes map[string]*sqs.MessageAttributeValue) error {
+	_, err := sqsClient.sqsSvc.SendMessageWithContext(ctx, &sqs.SendMessageInput{
+		QueueUrl:          sqsClient.queueURL,
+		MessageBody:       aws.String(messageBody),
+		MessageAttributes: attributes,
+	})
+	return err
+}
+
+func (sqsClient *QueueClient) SendMessageWithDelay(ctx context.Context, messageBody string, delay time.Duration) error {
+	_, err := sqsClient.sqsSvc.SendMessageWithContext(ctx, &sqs.SendMessageInput{
+		QueueUrl:     sqsClient.queueURL,
+		MessageBody:  aws.String(messageBody),
+		DelaySeconds: aws.Int64(int64(delay.Seconds())),
+	})
+	return err
+}
+
+func (sqsClient *QueueClient) ChangeMessageVisibility(ctx context.Context, messageHandle string, timeout time.Duration) error {
+	_, err := sqsClient.sqsSvc.ChangeMessageVisibilityWithContext(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          sqsClient.queueURL,
+		ReceiptHandle:     aws.String(messageHandle),
+		VisibilityTimeout: aws.Int64(int64(timeout.Seconds())),
+	})
+	return err
+}
+
 func (sqsClient *QueueClient) DeleteMessage(ctx context.Context, messageHandle string) error {
 	// ideally we would call sqsClient.sqsSvc.DeleteMessage(),
 	// however, for the required retry capabilities, we need to take a look at the HTTP status code,
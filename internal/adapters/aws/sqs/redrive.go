@@ -0,0 +1,123 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+
+	awsSqs "github.com/aws/aws-sdk-go/service/sqs"
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// maxRedriveReceives bounds how many times a dead letter queue is received from in one redrive.
+const maxRedriveReceives = 100
+
+type RedriveResult struct {
+	Queue       QueueName                   `json:"queue"`
+	SourceQueue QueueName                   `json:"sourceQueue"`
+	DryRun      bool                        `json:"dryRun"`
+	Count       int                         `json:"count"`
+	Messages    []models.RedrivenDlqMessage `json:"messages"`
+}
+
+// Redrive moves the selected messages of the dead letter queue back to its source queue, the others are made visible
+// again once the dead letter queue has been gone through. The messages redriven before an error are in the result.
+func Redrive(ctx context.Context, name QueueName, dlq Queue, source Queue, request models.DlqRedriveRequest) (RedriveResult, error) {
+	sourceName, _ := name.SourceQueue()
+	result := RedriveResult{Queue: name, SourceQueue: sourceName, DryRun: request.DryRun, Messages: []models.RedrivenDlqMessage{}}
+	if err := request.ValidateSelection(); err != nil {
+		return result, err
+	}
+
+	var (
+		seen    = make(map[string]bool)
+		skipped []string
+	)
+	defer func() {
+		for _, handle := range skipped {
+			if err := dlq.ChangeMessageVisibility(ctx, handle, 0); err != nil {
+				zapctx.Warn(ctx, "[Redrive] message left on the dead letter queue is only visible again after its visibility timeout", zap.Error(err))
+			}
+		}
+	}()
+
+	for i := 0; i < maxRedriveReceives; i++ {
+		received, err := dlq.GetMessages(ctx)
+		if err != nil {
+			return result, err
+		}
+
+		unseen := 0
+		for _, message := range received.Messages {
+			if message.MessageId == nil || seen[*message.MessageId] {
+				continue
+			}
+			seen[*message.MessageId] = true
+			unseen++
+
+			redriven := redrivenMessageOf(message)
+			if !request.Selects(redriven) || request.DryRun {
+				skipped = append(skipped, *message.ReceiptHandle)
+				if request.DryRun && request.Selects(redriven) {
+					redriven.Body = *message.Body
+					result.Messages = append(result.Messages, redriven)
+				}
+				continue
+			}
+
+			body := *message.Body
+			if request.Body != "" {
+				body, redriven.Edited = request.Body, true
+			}
+			// the dead letter goes along, so that the message keeps its attempts and first failure if it fails again
+			if err := source.SendMessageWithAttributes(ctx, body, message.MessageAttributes); err != nil {
+				skipped = append(skipped, *message.ReceiptHandle)
+				return result.counted(), err
+			}
+			if err := dlq.DeleteMessage(ctx, *message.ReceiptHandle); err != nil {
+				// the message is on both queues now, it is reported so that it isn't redriven twice
+				zapctx.Error(ctx, "[Redrive] redriven message couldn't be deleted from the dead letter queue",
+					zap.String("message_id", redriven.MessageID),
+					zap.Error(err),
+				)
+			}
+			result.Messages = append(result.Messages, redriven)
+		}
+
+		if unseen == 0 {
+			break
+		}
+	}
+
+	return result.counted(), nil
+}
+
+func (r RedriveResult) counted() RedriveResult {
+	r.Count = len(r.Messages)
+	return r
+}
+
+// messageBody reads the payment instruction of the messages of the payments queues, that are either a payment
+// instruction or a payment provider event about one.
+type messageBody struct {
+	models.PaymentInstructionDTO
+	PaymentInstruction *models.PaymentInstructionDTO `json:"paymentInstruction"`
+}
+
+func redrivenMessageOf(message *awsSqs.Message) models.RedrivenDlqMessage {
+	redriven := models.RedrivenDlqMessage{MessageID: *message.MessageId}
+
+	var body messageBody
+	if message.Body == nil || json.Unmarshal([]byte(*message.Body), &body) != nil {
+		return redriven
+	}
+	instruction := body.PaymentInstructionDTO
+	if body.PaymentInstruction != nil {
+		instruction = *body.PaymentInstruction
+	}
+	redriven.PaymentInstructionID = instruction.ID
+	redriven.ContractNumber = instruction.IncomingInstruction.Merchant.ContractNumber
+	return redriven
+}
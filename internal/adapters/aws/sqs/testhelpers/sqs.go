@@ -15,8 +15,8 @@ func AssertMessageWasDLQd(t *testing.T, DLQ *mocks.QueueMock, incomingQueue *moc
 	t.Helper()
 	is := is.New(t)
 
-	is.True(len(DLQ.SendMessageCalls()) > 0) // DLQ was called
-	is.Equal(DLQ.SendMessageCalls()[0].S, *message.Body)
+	is.True(len(DLQ.SendMessageWithAttributesCalls()) > 0) // DLQ was called
+	is.Equal(DLQ.SendMessageWithAttributesCalls()[0].MessageBody, *message.Body)
 
 	AssertMessageWasDeleted(t, incomingQueue, message)
 }
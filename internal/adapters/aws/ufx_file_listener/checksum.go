@@ -0,0 +1,104 @@
+package ufx_file_listener
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/ufx_file_listener/internal/ufx"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// ChecksumMismatchError is returned when the FileTrailer CheckSum of a UFX file does not reconcile with the Docs
+// that were actually parsed from it, which usually means the file was truncated or corrupted on the way from Way4.
+type ChecksumMismatchError struct {
+	FileName                string `json:"fileName"`
+	ExpectedRecsCount       string `json:"expectedRecsCount"`
+	ActualRecsCount         string `json:"actualRecsCount"`
+	ExpectedHashTotalAmount string `json:"expectedHashTotalAmount"`
+	ActualHashTotalAmount   string `json:"actualHashTotalAmount"`
+	Reason                  string `json:"reason"`
+}
+
+func (c ChecksumMismatchError) Error() string {
+	return fmt.Sprintf(
+		"ufx file %s failed checksum verification: %s (expected %s records totalling %s, parsed %s records totalling %s)",
+		c.FileName, c.Reason, c.ExpectedRecsCount, c.ExpectedHashTotalAmount, c.ActualRecsCount, c.ActualHashTotalAmount,
+	)
+}
+
+// newCheckSum computes the CheckSum Way4 would have written in the FileTrailer for the given Docs.
+func newCheckSum(docs []ufx.Doc) (ufx.CheckSum, error) {
+	total, err := hashTotalAmount(docs)
+	if err != nil {
+		return ufx.CheckSum{}, err
+	}
+	return ufx.CheckSum{
+		RecsCount:       strconv.Itoa(len(docs)),
+		HashTotalAmount: total.FloatString(2),
+	}, nil
+}
+
+// checkSumOf computes the CheckSum of a UFX file from the instructions converted from it.
+func checkSumOf(instructions models.IncomingInstructions) models.UfxCheckSum {
+	docs := make([]ufx.Doc, len(instructions))
+	for i, instruction := range instructions {
+		docs[i] = ufx.Doc{ContractNumber: instruction.Merchant.ContractNumber, Amount: instruction.Payment.Amount}
+	}
+
+	checkSum, err := newCheckSum(docs)
+	if err != nil {
+		return models.UfxCheckSum{RecsCount: strconv.Itoa(len(instructions))}
+	}
+	return models.UfxCheckSum{RecsCount: checkSum.RecsCount, HashTotalAmount: checkSum.HashTotalAmount}
+}
+
+func hashTotalAmount(docs []ufx.Doc) (*big.Rat, error) {
+	total := new(big.Rat)
+	for _, doc := range docs {
+		amount, ok := new(big.Rat).SetString(strings.TrimSpace(doc.Amount))
+		if !ok {
+			return nil, fmt.Errorf("error parsing amount %q of contract %s", doc.Amount, doc.ContractNumber)
+		}
+		total.Add(total, amount)
+	}
+	return total, nil
+}
+
+func verifyCheckSum(fileName string, checkSum ufx.CheckSum, docs []ufx.Doc) error {
+	actualTotal, err := hashTotalAmount(docs)
+	if err != nil {
+		return err
+	}
+
+	mismatch := ChecksumMismatchError{
+		FileName:                fileName,
+		ExpectedRecsCount:       checkSum.RecsCount,
+		ActualRecsCount:         strconv.Itoa(len(docs)),
+		ExpectedHashTotalAmount: checkSum.HashTotalAmount,
+		ActualHashTotalAmount:   actualTotal.FloatString(2),
+	}
+
+	expectedCount, err := strconv.Atoi(strings.TrimSpace(checkSum.RecsCount))
+	if err != nil {
+		mismatch.Reason = "missing or invalid RecsCount"
+		return mismatch
+	}
+	expectedTotal, ok := new(big.Rat).SetString(strings.TrimSpace(checkSum.HashTotalAmount))
+	if !ok {
+		mismatch.Reason = "missing or invalid HashTotalAmount"
+		return mismatch
+	}
+
+	if expectedCount != len(docs) {
+		mismatch.Reason = "record count mismatch"
+		return mismatch
+	}
+	if expectedTotal.Cmp(actualTotal) != 0 {
+		mismatch.Reason = "hash total amount mismatch"
+		return mismatch
+	}
+
+	return nil
+}
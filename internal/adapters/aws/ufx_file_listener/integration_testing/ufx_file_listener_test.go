@@ -6,6 +6,7 @@ package integration_testing_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -61,11 +62,25 @@ func TestUFXListener(t *testing.T) {
 				MsgReceivedNotificationChan: useCaseMakePaymentReceivedMessageChan,
 			}
 			mockCheckPaymentAccountFundsAvailability := &mocks.CheckPaymentAccountFundsAvailabilityMock{
-				ExecuteFunc: func(ctx context.Context, code models.CurrencyCode, amount float64, highRisk bool) (bool, error) {
-					return true, nil
+				ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {},
+				HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+					return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, true, nil
 				},
 			}
 
+			ufxFileLedger := &mocks.UfxFileLedgerMock{
+				GetUfxFileFunc: func(ctx context.Context, filename string) (models.UfxFile, error) {
+					return models.UfxFile{}, errors.New("not found")
+				},
+				SaveUfxFileFunc: func(ctx context.Context, file models.UfxFile) error {
+					return nil
+				},
+			}
+			managePendingFunding := &mocks.ManagePendingFundingMock{
+				HoldBackFunc: func(ctx context.Context, filename string, summary models.IncomingInstructionsSummary, instructions models.IncomingInstructions) (models.PendingFunding, error) {
+					return models.PendingFunding{}, nil
+				},
+			}
 			metricsClient := testdoubles.DummyMetricsClient{}
 			featureFlag := testdoubles.FeatureFlagService{}
 
@@ -86,10 +101,12 @@ func TestUFXListener(t *testing.T) {
 			ufxFileHandler := ufx_file_listener.New(
 				mockMakePaymentUseCase,
 				mockCheckPaymentAccountFundsAvailability,
+				managePendingFunding,
 				&ufx_file_listener.UfxToPaymentInstructionsConverter{},
 				&s3PaymentFilesClient,
 				&sqsInboundQueueClient,
 				&sqsDLQ,
+				ufxFileLedger,
 				appConfig.UfxProcessingMaxMessages,
 				metricsClient,
 				featureFlag,
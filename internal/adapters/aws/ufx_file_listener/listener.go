@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -31,10 +32,12 @@ type FileStore interface {
 type UfxFileListener struct {
 	makePayment                          ports.MakePayment
 	checkPaymentAccountFundsAvailability ports.CheckPaymentAccountFundsAvailability
+	managePendingFunding                 ports.ManagePendingFunding
 	ufxConverter                         UfxConverter
 	fileStoreClient                      FileStore
 	ufxQueueClient                       sqsInternal.Queue
 	dlqClient                            sqsInternal.Queue
+	ufxFileLedger                        ports.UfxFileLedger
 	ufxProcessingMaxMessages             int64
 	metricsClient                        ports.MetricsClient
 	shouldListen                         *sync.AtomicBool
@@ -44,10 +47,12 @@ type UfxFileListener struct {
 func New(
 	makePayment ports.MakePayment,
 	checkPaymentAccountFundsAvailability ports.CheckPaymentAccountFundsAvailability,
+	managePendingFunding ports.ManagePendingFunding,
 	ufxConverter UfxConverter,
 	s3Client FileStore,
 	sqsClient sqsInternal.Queue,
 	dlqClient sqsInternal.Queue,
+	ufxFileLedger ports.UfxFileLedger,
 	ufxProcessingMaxMessages int64,
 	metricsClient ports.MetricsClient,
 	featureFlagSvc ports.FeatureFlagService,
@@ -57,10 +62,12 @@ func New(
 	return UfxFileListener{
 		makePayment:                          makePayment,
 		checkPaymentAccountFundsAvailability: checkPaymentAccountFundsAvailability,
+		managePendingFunding:                 managePendingFunding,
 		ufxConverter:                         ufxConverter,
 		fileStoreClient:                      s3Client,
 		ufxQueueClient:                       sqsClient,
 		dlqClient:                            dlqClient,
+		ufxFileLedger:                        ufxFileLedger,
 		ufxProcessingMaxMessages:             ufxProcessingMaxMessages,
 		metricsClient:                        metricsClient,
 		shouldListen:                         shouldListen,
@@ -72,8 +79,14 @@ const (
 	clientStorageMetricName        = "app_storage_client_resp_time_ms"
 	instructionsReceivedMetricName = "app_payment_instructions_received"
 	useCaseExecutionMetricName     = "app_use_case_execution_time_ms"
+	checksumMismatchMetricName     = "app_ufx_checksum_mismatch"
+	redeliveryRejectedMetricName   = "app_ufx_file_redelivery_rejected"
 )
 
+const listenerName = "UfxFileListener"
+
+var errEmptyFileName = errors.New("[UfxFileListener] (processMessages) empty file name in the S3 event")
+
 func (ufl *UfxFileListener) Listen(ctx context.Context) {
 	for {
 		if !ufl.shouldListen.IsSet() {
@@ -99,7 +112,7 @@ func (ufl *UfxFileListener) Listen(ctx context.Context) {
 				continue
 			}
 			if filename == "" {
-				ufl.dlq(ctx, newS3FileMessage)
+				ufl.dlq(ctx, newS3FileMessage, errEmptyFileName)
 				zapctx.Debug(ctx, "skipping message because filename picked from SQS queue is empty")
 				continue
 			}
@@ -109,15 +122,54 @@ func (ufl *UfxFileListener) Listen(ctx context.Context) {
 }
 
 func (ufl *UfxFileListener) handleNewS3FileMessage(ctx context.Context, message *sqs.Message) {
-	ufxContents, fileName, err := ufl.getFileFromS3(ctx, message)
+	fileName, err := ufl.extractFileNameFromSqsMessage(ctx, message)
 	if err != nil {
-		zapctx.Error(ctx, "", zap.Error(err))
+		ufl.dlq(ctx, message, err)
+		zapctx.Error(ctx, "[UfxFileListener] Error reading S3 file name from SQS message", zap.Error(err))
+		return
+	}
+
+	if ufl.isAlreadyFullyPaid(ctx, fileName) {
+		ufl.rejectRedelivery(ctx, message, fileName)
+		return
+	}
+
+	ufxFile := models.UfxFile{
+		Filename: path.Base(fileName),
+		S3Key:    fileName,
+		State:    models.UfxFileReceived,
+	}
+	ufl.recordUfxFile(ctx, ufxFile)
+
+	ufxContents, err := ufl.fetchFileFromS3(ctx, fileName)
+	if err != nil {
+		ufl.dlq(ctx, message, err)
+		zapctx.Error(ctx, "[UfxFileListener] Error fetching S3 file", zap.String("file_name", fileName), zap.Error(err))
 		return
 	}
 
 	incomingInstructions, err := ufl.convertUfxToIncomingInstructions(ctx, ufxContents, fileName)
+	var checksumMismatch ChecksumMismatchError
+	if errors.As(err, &checksumMismatch) {
+		ufl.quarantine(ctx, message, checksumMismatch)
+		ufxFile.CheckSum = models.UfxCheckSum{
+			RecsCount:       checksumMismatch.ExpectedRecsCount,
+			HashTotalAmount: checksumMismatch.ExpectedHashTotalAmount,
+		}
+		ufxFile.State = models.UfxFileQuarantined
+		ufxFile.StateReason = checksumMismatch.Error()
+		ufl.recordUfxFile(ctx, ufxFile)
+		zapctx.Error(ctx, "ufx file checksum does not match its contents, no payments were made",
+			zap.String("file_name", fileName),
+			zap.Any("discrepancy", checksumMismatch),
+		)
+		return
+	}
 	if err != nil {
-		ufl.dlq(ctx, message)
+		ufl.dlq(ctx, message, err)
+		ufxFile.State = models.UfxFileQuarantined
+		ufxFile.StateReason = err.Error()
+		ufl.recordUfxFile(ctx, ufxFile)
 		zapctx.Error(ctx, "error converting ufx from file to PaymentInstructions",
 			zap.String("file_name", fileName),
 			zap.Error(err),
@@ -125,18 +177,41 @@ func (ufl *UfxFileListener) handleNewS3FileMessage(ctx context.Context, message
 		return
 	}
 
+	ufxFile.State = models.UfxFileConverted
+	ufxFile.RecordCount = len(incomingInstructions)
+	ufxFile.CheckSum = checkSumOf(incomingInstructions)
+	if len(incomingInstructions) > 0 {
+		ufxFile.Sender = incomingInstructions[0].Metadata.Sender
+	}
+
 	summary, err := incomingInstructions.SumByCurrency()
 	if err != nil {
+		ufxFile.StateReason = err.Error()
+		ufl.recordUfxFile(ctx, ufxFile)
 		zapctx.Error(ctx, "error summing payments by currency",
 			zap.Error(err),
 		)
 		return
 	}
+	ufxFile.CurrencyTotals = summary
+	ufl.recordUfxFile(ctx, ufxFile)
 
-	validPaymentInstructions := ufl.validateBalances(ctx, incomingInstructions, summary, fileName)
+	validPaymentInstructions, heldBackCurrencies, holds := ufl.validateBalances(ctx, incomingInstructions, summary, fileName)
 
 	executionErrors := ufl.makeAllPayments(ctx, validPaymentInstructions)
 	ufl.handleUseCaseExecutionErrors(ctx, executionErrors)
+	// the payment instructions took their reservations out of the holds by now
+	for _, hold := range holds {
+		ufl.checkPaymentAccountFundsAvailability.ReleaseHold(ctx, hold)
+	}
+
+	ufxFile.HeldBackCurrencies = heldBackCurrencies
+	ufxFile.State = models.UfxFileFullyPaid
+	if len(heldBackCurrencies) > 0 || len(executionErrors) > 0 {
+		ufxFile.State = models.UfxFilePartiallyPaid
+		ufxFile.StateReason = fmt.Sprintf("%d currencies held back, %d payment instructions failed", len(heldBackCurrencies), len(executionErrors))
+	}
+	ufl.recordUfxFile(ctx, ufxFile)
 
 	if err := ufl.ufxQueueClient.DeleteMessage(ctx, *message.ReceiptHandle); err != nil {
 		zapctx.Error(ctx, "error deleting message for s3 file from ufx queue",
@@ -149,35 +224,87 @@ func (ufl *UfxFileListener) handleNewS3FileMessage(ctx context.Context, message
 	zapctx.Info(ctx, "successfully deleted sqs message for s3 file", zap.String("file_name", fileName))
 }
 
-func (ufl *UfxFileListener) getFileFromS3(ctx context.Context, message *sqs.Message) (io.Reader, string, error) {
-	fileName, err := ufl.extractFileNameFromSqsMessage(ctx, message)
+func (ufl *UfxFileListener) isAlreadyFullyPaid(ctx context.Context, fileName string) bool {
+	ufxFile, err := ufl.ufxFileLedger.GetUfxFile(ctx, path.Base(fileName))
 	if err != nil {
-		ufl.dlq(ctx, message)
-		return nil, "", fmt.Errorf("[UfxFileListener] Error reading S3 file name from SQS message. Err: %s", err)
+		// a file we have never seen, or a ledger we can't read, must not block payments; the payment store
+		// still rejects duplicated instructions.
+		return false
 	}
+	return ufxFile.IsFullyPaid()
+}
 
-	ufxFileContentsReader, err := ufl.fetchFileFromS3(ctx, fileName)
-	if err != nil {
-		ufl.dlq(ctx, message)
-		return nil, "", fmt.Errorf("[UfxFileListener] Error fetching S3 file %s. Err: %s", fileName, err)
+func (ufl *UfxFileListener) rejectRedelivery(ctx context.Context, message *sqs.Message, fileName string) {
+	ufl.metricsClient.Count(ctx, redeliveryRejectedMetricName, 1, []string{"way4_ufx"})
+	zapctx.Error(ctx, "[UfxFileListener] rejecting re-delivery of an already fully paid ufx file",
+		zap.String("file_name", fileName),
+	)
+	if err := ufl.ufxQueueClient.DeleteMessage(ctx, *message.ReceiptHandle); err != nil {
+		zapctx.Error(ctx, "error deleting message for s3 file from ufx queue",
+			zap.String("file_name", fileName),
+			zap.Error(err),
+		)
+	}
+}
+
+func (ufl *UfxFileListener) recordUfxFile(ctx context.Context, ufxFile models.UfxFile) {
+	if err := ufl.ufxFileLedger.SaveUfxFile(ctx, ufxFile); err != nil {
+		zapctx.Error(ctx, "[UfxFileListener] error recording ufx file in the ledger",
+			zap.String("file_name", ufxFile.Filename),
+			zap.String("state", string(ufxFile.State)),
+			zap.Error(err),
+		)
 	}
+}
 
-	return ufxFileContentsReader, fileName, nil
+func (ufl *UfxFileListener) dlq(ctx context.Context, message *sqs.Message, failure error) {
+	ufl.sendToDlq(ctx, message, *message.Body, failure)
 }
 
-func (ufl *UfxFileListener) dlq(ctx context.Context, message *sqs.Message) {
+func (ufl *UfxFileListener) sendToDlq(ctx context.Context, message *sqs.Message, body string, failure error) {
+	deadLetter := sqsInternal.NewDeadLetter(listenerName, ufl.ufxQueueClient, message, failure)
 	if err := ufl.ufxQueueClient.DeleteMessage(ctx, *message.ReceiptHandle); err != nil {
 		zapctx.Error(ctx, "error deleting message",
 			zap.Error(err),
 		)
 	}
-	if err := ufl.dlqClient.SendMessage(ctx, *message.Body); err != nil {
+	if err := sqsInternal.SendDeadLetter(ctx, ufl.dlqClient, body, deadLetter); err != nil {
 		zapctx.Error(ctx, "error sending message to DLQ",
 			zap.Error(err),
 		)
 	}
 }
 
+// quarantine sends the message to the DLQ with the checksum discrepancy report added next to the original S3 event
+// records, so the file can still be redriven once Way4 has re-sent it.
+func (ufl *UfxFileListener) quarantine(ctx context.Context, message *sqs.Message, discrepancy ChecksumMismatchError) {
+	ufl.metricsClient.Count(ctx, checksumMismatchMetricName, 1, []string{"way4_ufx"})
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(*message.Body), &body); err != nil {
+		zapctx.Error(ctx, "error parsing message body, sending it to DLQ unchanged", zap.Error(err))
+		ufl.dlq(ctx, message, discrepancy)
+		return
+	}
+
+	report, err := json.Marshal(discrepancy)
+	if err != nil {
+		zapctx.Error(ctx, "error marshalling checksum discrepancy report, sending message to DLQ unchanged", zap.Error(err))
+		ufl.dlq(ctx, message, discrepancy)
+		return
+	}
+	body["ChecksumDiscrepancy"] = report
+
+	quarantinedBody, err := json.Marshal(body)
+	if err != nil {
+		zapctx.Error(ctx, "error marshalling quarantined message, sending it to DLQ unchanged", zap.Error(err))
+		ufl.dlq(ctx, message, discrepancy)
+		return
+	}
+
+	ufl.sendToDlq(ctx, message, string(quarantinedBody), discrepancy)
+}
+
 func (ufl *UfxFileListener) extractFileNameFromSqsMessage(ctx context.Context, message *sqs.Message) (string, error) {
 	type (
 		messageBody struct {
@@ -280,32 +407,51 @@ func (ufl *UfxFileListener) handleUseCaseExecutionErrors(ctx context.Context, in
 	}
 }
 
-func (ufl *UfxFileListener) validateBalances(ctx context.Context, instructions models.IncomingInstructions, sumByCurrency []models.IncomingInstructionsSummary, filename string) models.IncomingInstructions {
+// validateBalances holds the funds of every currency with enough of them, its payment instructions reserve their
+// amounts out of the hold.
+func (ufl *UfxFileListener) validateBalances(ctx context.Context, instructions models.IncomingInstructions, sumByCurrency []models.IncomingInstructionsSummary, filename string) (models.IncomingInstructions, []models.CurrencyCode, []models.FundsHoldID) {
+	var (
+		heldBackCurrencies []models.CurrencyCode
+		holds              []models.FundsHoldID
+	)
 	for _, sum := range sumByCurrency {
 		if sum.CurrencyCode == models.ISK {
 			continue
 		}
-		hasBalance, err := ufl.checkPaymentAccountFundsAvailability.Execute(ctx, sum.CurrencyCode, sum.Amount, sum.HighRisk)
+		hold, hasBalance, err := ufl.checkPaymentAccountFundsAvailability.Hold(ctx, models.NewFundsHoldID(), sum.CurrencyCode, sum.Amount, sum.HighRisk)
 		if err != nil {
 			// Keep processing other currencies, if account for currency X is not found or has some other problem,
 			// remove it from the instructions slice.
 			zapctx.Error(ctx, "[UfxFileListener] Error checking balance for currency", zap.Error(err))
 		}
 
-		if !hasBalance {
-			instructions = instructions.FilterOutCurrency(sum.CurrencyCode)
+		if !hasBalance && err == nil {
+			// Not enough funds, keep the batch until the account is funded
+			ufl.holdBack(ctx, filename, sum, instructions.ReturnCurrency(sum.CurrencyCode))
 		}
 
-		if !hasBalance && err == nil {
-			// Not enough funds error, generate replayPayment endpoint
-			zapctx.Error(ctx, "replay payment for missing funds",
-				zap.String("method", "POST"),
-				zap.String("path", fmt.Sprintf("/replay-payment?action=%s&currency=%s&file=%s\\", replay_payment.PayCurrencyFromFile, sum.CurrencyCode, filename)),
-			)
+		if !hasBalance {
+			instructions = instructions.FilterOutCurrency(sum.CurrencyCode)
+			heldBackCurrencies = append(heldBackCurrencies, sum.CurrencyCode)
+			continue
 		}
+		instructions = instructions.WithFundsHold(hold)
+		holds = append(holds, hold.ID)
 	}
 
-	return instructions
+	return instructions, heldBackCurrencies, holds
+}
+
+func (ufl *UfxFileListener) holdBack(ctx context.Context, filename string, sum models.IncomingInstructionsSummary, instructions models.IncomingInstructions) {
+	_, err := ufl.managePendingFunding.HoldBack(ctx, filename, sum, instructions)
+	if err != nil {
+		// the batch could not be persisted, fall back to a manual replay
+		zapctx.Error(ctx, "replay payment for missing funds",
+			zap.String("method", "POST"),
+			zap.String("path", fmt.Sprintf("/replay-payment?action=%s&currency=%s&file=%s\\", replay_payment.PayCurrencyFromFile, sum.CurrencyCode, filename)),
+			zap.Error(err),
+		)
+	}
 }
 
 func (ufl *UfxFileListener) StopListening() {
@@ -5,6 +5,8 @@ package ufx_file_listener_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -43,8 +45,9 @@ func TestUfxFileListener_Listen(t *testing.T) {
 			stubFileStore                            = &mocks2.FileStoreMock{}
 			spyUseCase                               = &mocks.MakePaymentMock{}
 			stubCheckPaymentAccountFundsAvailability = &mocks.CheckPaymentAccountFundsAvailabilityMock{
-				ExecuteFunc: func(ctx context.Context, code models.CurrencyCode, amount float64, highRisk bool) (bool, error) {
-					return true, nil
+				ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {},
+				HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+					return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, true, nil
 				},
 			}
 			metricsClient = &mocks.MetricsClientMock{
@@ -59,10 +62,12 @@ func TestUfxFileListener_Listen(t *testing.T) {
 		listener := ufx_file_listener.New(
 			spyUseCase,
 			stubCheckPaymentAccountFundsAvailability,
+			newStubManagePendingFunding(),
 			&ufx_file_listener.UfxToPaymentInstructionsConverter{},
 			stubFileStore,
 			spyIncomingQueue,
 			&mocks3.QueueMock{},
+			newStubUfxFileLedger(),
 			10,
 			metricsClient,
 			mockFeatureFlagService,
@@ -91,8 +96,13 @@ func TestUfxFileListener_Listen(t *testing.T) {
 
 		select {
 		case <-deleteCalled:
-			is.True(len(spyUseCase.ExecuteCalls()) > 0)                                                        // called the use case
-			is.Equal(spyUseCase.ExecuteCalls()[0].IncomingInstruction, ufxAndIncomingInst.IncomingInstruction) // use case was called from the extracted instruction
+			is.True(len(spyUseCase.ExecuteCalls()) > 0) // called the use case
+			made := spyUseCase.ExecuteCalls()[0].IncomingInstruction
+			hold := made.FundsHold
+			is.Equal(hold.Currency, models.EUR)                                           // reserves out of the hold of its currency
+			is.Equal(hold.ID, stubCheckPaymentAccountFundsAvailability.HoldCalls()[0].ID) // of the hold of the file
+			made.FundsHold = models.FundsHold{}
+			is.Equal(made, ufxAndIncomingInst.IncomingInstruction) // use case was called from the extracted instruction
 			testhelpers.AssertMessageWasDeleted(t, spyIncomingQueue, *msg)
 		case <-time.After(timeout):
 			t.Fatal("timed out waiting for message to be deleted")
@@ -119,10 +129,12 @@ func TestUfxFileListener_Listen(t *testing.T) {
 		listener := ufx_file_listener.New(
 			stubMakePayment,
 			stubCheckPaymentAccountFundsAvailability,
+			newStubManagePendingFunding(),
 			&ufx_file_listener.UfxToPaymentInstructionsConverter{},
 			stubFileStore,
 			spyIncomingQueue,
 			spyDLQ,
+			newStubUfxFileLedger(),
 			10,
 			testdoubles.DummyMetricsClient{},
 			mockFeatureFlagService,
@@ -132,7 +144,7 @@ func TestUfxFileListener_Listen(t *testing.T) {
 			return strings.NewReader(invalidFileContents), nil
 		}
 
-		spyDLQ.SendMessageFunc = func(context.Context, string) error {
+		spyDLQ.SendMessageWithAttributesFunc = func(context.Context, string, map[string]*sqs.MessageAttributeValue) error {
 			dlqCalled <- true
 			return nil
 		}
@@ -179,10 +191,12 @@ func TestUfxFileListener_Listen(t *testing.T) {
 		listener := ufx_file_listener.New(
 			stubMakePayment,
 			stubCheckPaymentAccountFundsAvailability,
+			newStubManagePendingFunding(),
 			&ufx_file_listener.UfxToPaymentInstructionsConverter{},
 			stubFileStore,
 			stubIncomingQueue,
 			spyDLQ,
+			newStubUfxFileLedger(),
 			10,
 			testdoubles.DummyMetricsClient{},
 			mockFeatureFlagService,
@@ -192,7 +206,7 @@ func TestUfxFileListener_Listen(t *testing.T) {
 			return strings.NewReader(invalidFileContents), nil
 		}
 
-		spyDLQ.SendMessageFunc = func(context.Context, string) error {
+		spyDLQ.SendMessageWithAttributesFunc = func(context.Context, string, map[string]*sqs.MessageAttributeValue) error {
 			dlqCalled <- true
 			return nil
 		}
@@ -217,14 +231,99 @@ func TestUfxFileListener_Listen(t *testing.T) {
 		}
 	})
 
+	t.Run("quarantine file to DLQ with a discrepancy report if the trailer checksum does not match", func(t *testing.T) {
+		var (
+			ctx       = context.Background()
+			msg       = testhelpers.NewSQSMessage(`{"Records": [{"s3": {"object": {"key": "truncated.xml"}}}]}`)
+			dlqCalled = make(chan bool)
+		)
+
+		// Given a UFX file whose trailer claims more records than it contains
+		truncatedFileContents := strings.Replace(
+			testhelpers2.ValidUfxAndIncomingInstruction().UfxFileContents,
+			"<RecsCount>1</RecsCount>",
+			"<RecsCount>2</RecsCount>",
+			1,
+		)
+
+		spyMakePayment := &mocks.MakePaymentMock{}
+		stubCheckPaymentAccountFundsAvailability := &mocks.CheckPaymentAccountFundsAvailabilityMock{}
+		stubFileStore := &mocks2.FileStoreMock{}
+		spyIncomingQueue := &mocks3.QueueMock{}
+		spyDLQ := &mocks3.QueueMock{}
+		mockFeatureFlagService := &mocks.FeatureFlagServiceMock{IsIngestionEnabledFromUfxFileNotificationQueueFunc: func() bool {
+			return true
+		}}
+
+		listener := ufx_file_listener.New(
+			spyMakePayment,
+			stubCheckPaymentAccountFundsAvailability,
+			newStubManagePendingFunding(),
+			&ufx_file_listener.UfxToPaymentInstructionsConverter{},
+			stubFileStore,
+			spyIncomingQueue,
+			spyDLQ,
+			newStubUfxFileLedger(),
+			10,
+			testdoubles.DummyMetricsClient{},
+			mockFeatureFlagService,
+		)
+
+		stubFileStore.ReadFileFunc = func(context.Context, string) (io.Reader, error) {
+			return strings.NewReader(truncatedFileContents), nil
+		}
+
+		spyDLQ.SendMessageWithAttributesFunc = func(context.Context, string, map[string]*sqs.MessageAttributeValue) error {
+			dlqCalled <- true
+			return nil
+		}
+
+		spyIncomingQueue.GetMessagesFunc = func(context.Context) (*sqs.ReceiveMessageOutput, error) {
+			return &sqs.ReceiveMessageOutput{
+				Messages: []*sqs.Message{msg},
+			}, nil
+		}
+
+		spyIncomingQueue.DeleteMessageFunc = func(context.Context, string) error {
+			return nil
+		}
+
+		go listener.Listen(ctx)
+
+		select {
+		case <-dlqCalled:
+			// Then no payment is made
+			assert.Empty(t, spyMakePayment.ExecuteCalls())
+
+			// And the message is removed from the incoming queue
+			testhelpers.AssertMessageWasDeleted(t, spyIncomingQueue, *msg)
+
+			// And the DLQ message keeps the original records and carries the discrepancy report
+			var quarantined struct {
+				Records             json.RawMessage
+				ChecksumDiscrepancy ufx_file_listener.ChecksumMismatchError
+			}
+			assert.NoError(t, json.Unmarshal([]byte(spyDLQ.SendMessageWithAttributesCalls()[0].MessageBody), &quarantined))
+			assert.NotEmpty(t, quarantined.Records)
+			assert.Equal(t, "truncated.xml", quarantined.ChecksumDiscrepancy.FileName)
+			assert.Equal(t, "2", quarantined.ChecksumDiscrepancy.ExpectedRecsCount)
+			assert.Equal(t, "1", quarantined.ChecksumDiscrepancy.ActualRecsCount)
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for message to be sent to DLQ")
+		}
+	})
+
 	t.Run("feature flag", func(t *testing.T) {
 		var (
 			mockMakePayment = &mocks.MakePaymentMock{ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
 				return "", nil
 			}}
-			mockCheckPaymentAccountFundsAvailability = &mocks.CheckPaymentAccountFundsAvailabilityMock{ExecuteFunc: func(ctx context.Context, code models.CurrencyCode, amount float64, highRisk bool) (bool, error) {
-				return true, nil
-			}}
+			mockCheckPaymentAccountFundsAvailability = &mocks.CheckPaymentAccountFundsAvailabilityMock{
+				ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {},
+				HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+					return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, true, nil
+				},
+			}
 			ufxAndIncomingInst = testhelpers2.ValidUfxAndIncomingInstruction()
 
 			mockFileStore = &mocks2.FileStoreMock{ReadFileFunc: func(ctx context.Context, filename string) (io.Reader, error) {
@@ -257,10 +356,12 @@ func TestUfxFileListener_Listen(t *testing.T) {
 
 			listener := ufx_file_listener.New(mockMakePayment,
 				mockCheckPaymentAccountFundsAvailability,
+				newStubManagePendingFunding(),
 				&ufx_file_listener.UfxToPaymentInstructionsConverter{},
 				mockFileStore,
 				mockIncomingQueue,
 				mockDLQ,
+				newStubUfxFileLedger(),
 				10,
 				testdoubles.DummyMetricsClient{},
 				mockFeatureFlagService)
@@ -290,10 +391,12 @@ func TestUfxFileListener_Listen(t *testing.T) {
 
 			listener := ufx_file_listener.New(mockMakePayment,
 				mockCheckPaymentAccountFundsAvailability,
+				newStubManagePendingFunding(),
 				&ufx_file_listener.UfxToPaymentInstructionsConverter{},
 				mockFileStore,
 				mockIncomingQueue,
 				mockDLQ,
+				newStubUfxFileLedger(),
 				10,
 				testdoubles.DummyMetricsClient{},
 				mockFeatureFlagService)
@@ -311,3 +414,198 @@ func TestUfxFileListener_Listen(t *testing.T) {
 		})
 	})
 }
+
+func TestUfxFileListener_Ledger(t *testing.T) {
+	newListener := func(makePayment *mocks.MakePaymentMock, hasFunds bool, fileStore *mocks2.FileStoreMock, incomingQueue *mocks3.QueueMock, ledger *mocks.UfxFileLedgerMock, pendingFunding *mocks.ManagePendingFundingMock) ufx_file_listener.UfxFileListener {
+		return ufx_file_listener.New(
+			makePayment,
+			&mocks.CheckPaymentAccountFundsAvailabilityMock{
+				ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {},
+				HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+					return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, hasFunds, nil
+				},
+			},
+			pendingFunding,
+			&ufx_file_listener.UfxToPaymentInstructionsConverter{},
+			fileStore,
+			incomingQueue,
+			&mocks3.QueueMock{},
+			ledger,
+			10,
+			testdoubles.DummyMetricsClient{},
+			&mocks.FeatureFlagServiceMock{IsIngestionEnabledFromUfxFileNotificationQueueFunc: func() bool {
+				return true
+			}},
+		)
+	}
+
+	t.Run("rejects the re-delivery of a file that was already fully paid", func(t *testing.T) {
+		var (
+			ctx          = context.Background()
+			msg          = testhelpers.NewSQSMessage(`{"Records": [{"s3": {"object": {"key": "paid.xml"}}}]}`)
+			deleteCalled = make(chan bool)
+		)
+
+		// Given a file the ledger already knows as fully paid
+		spyLedger := newStubUfxFileLedger()
+		spyLedger.GetUfxFileFunc = func(ctx context.Context, filename string) (models.UfxFile, error) {
+			return models.UfxFile{Filename: filename, State: models.UfxFileFullyPaid}, nil
+		}
+		spyMakePayment := &mocks.MakePaymentMock{}
+		spyFileStore := &mocks2.FileStoreMock{}
+		spyIncomingQueue := &mocks3.QueueMock{
+			GetMessagesFunc: deliverOnce(msg),
+			DeleteMessageFunc: func(context.Context, string) error {
+				deleteCalled <- true
+				return nil
+			},
+		}
+
+		// When the file is delivered again
+		listener := newListener(spyMakePayment, true, spyFileStore, spyIncomingQueue, spyLedger, newStubManagePendingFunding())
+		go listener.Listen(ctx)
+
+		select {
+		case <-deleteCalled:
+			// Then the message is dropped without fetching the file or paying anything
+			testhelpers.AssertMessageWasDeleted(t, spyIncomingQueue, *msg)
+			assert.Empty(t, spyFileStore.ReadFileCalls())
+			assert.Empty(t, spyMakePayment.ExecuteCalls())
+			assert.Empty(t, spyLedger.SaveUfxFileCalls())
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for message to be deleted")
+		}
+	})
+
+	t.Run("records the lifecycle of a file until it is fully paid", func(t *testing.T) {
+		var (
+			ctx                = context.Background()
+			msg                = testhelpers.NewSQSMessage(`{"Records": [{"s3": {"object": {"key": "inbound/test.xml"}}}]}`)
+			deleteCalled       = make(chan bool)
+			ufxAndIncomingInst = testhelpers2.ValidUfxAndIncomingInstruction()
+		)
+
+		spyLedger := newStubUfxFileLedger()
+		stubMakePayment := &mocks.MakePaymentMock{
+			ExecuteFunc: func(context.Context, models.IncomingInstruction) (models.PaymentInstructionID, error) {
+				return "", nil
+			},
+		}
+		stubFileStore := &mocks2.FileStoreMock{
+			ReadFileFunc: func(context.Context, string) (io.Reader, error) {
+				return strings.NewReader(ufxAndIncomingInst.UfxFileContents), nil
+			},
+		}
+		stubIncomingQueue := &mocks3.QueueMock{
+			GetMessagesFunc: deliverOnce(msg),
+			DeleteMessageFunc: func(context.Context, string) error {
+				deleteCalled <- true
+				return nil
+			},
+		}
+
+		listener := newListener(stubMakePayment, true, stubFileStore, stubIncomingQueue, spyLedger, newStubManagePendingFunding())
+		go listener.Listen(ctx)
+
+		select {
+		case <-deleteCalled:
+			saves := spyLedger.SaveUfxFileCalls()
+			var states []models.UfxFileState
+			for _, save := range saves {
+				states = append(states, save.File.State)
+			}
+			assert.Equal(t, []models.UfxFileState{models.UfxFileReceived, models.UfxFileConverted, models.UfxFileFullyPaid}, states)
+
+			paidFile := saves[len(saves)-1].File
+			assert.Equal(t, "test.xml", paidFile.Filename)
+			assert.Equal(t, "inbound/test.xml", paidFile.S3Key)
+			assert.Equal(t, ufxAndIncomingInst.IncomingInstruction.Metadata.Sender, paidFile.Sender)
+			assert.Equal(t, 1, paidFile.RecordCount)
+			assert.Equal(t, models.UfxCheckSum{RecsCount: "1", HashTotalAmount: "10.00"}, paidFile.CheckSum)
+			assert.Len(t, paidFile.CurrencyTotals, 1)
+			assert.Empty(t, paidFile.HeldBackCurrencies)
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for message to be deleted")
+		}
+	})
+
+	t.Run("records a file as partially paid and holds the currency back as pending funding when funds are missing", func(t *testing.T) {
+		var (
+			ctx                = context.Background()
+			msg                = testhelpers.NewSQSMessage(`{"Records": [{"s3": {"object": {"key": "test.ufx"}}}]}`)
+			deleteCalled       = make(chan bool)
+			ufxAndIncomingInst = testhelpers2.ValidUfxAndIncomingInstruction()
+		)
+
+		spyLedger := newStubUfxFileLedger()
+		spyMakePayment := &mocks.MakePaymentMock{}
+		stubFileStore := &mocks2.FileStoreMock{
+			ReadFileFunc: func(context.Context, string) (io.Reader, error) {
+				return strings.NewReader(ufxAndIncomingInst.UfxFileContents), nil
+			},
+		}
+		stubIncomingQueue := &mocks3.QueueMock{
+			GetMessagesFunc: deliverOnce(msg),
+			DeleteMessageFunc: func(context.Context, string) error {
+				deleteCalled <- true
+				return nil
+			},
+		}
+
+		// Given there are no funds for the currency of the file
+		spyPendingFunding := newStubManagePendingFunding()
+		listener := newListener(spyMakePayment, false, stubFileStore, stubIncomingQueue, spyLedger, spyPendingFunding)
+		go listener.Listen(ctx)
+
+		select {
+		case <-deleteCalled:
+			saves := spyLedger.SaveUfxFileCalls()
+			lastSave := saves[len(saves)-1].File
+			assert.Empty(t, spyMakePayment.ExecuteCalls())
+			assert.Equal(t, models.UfxFilePartiallyPaid, lastSave.State)
+			assert.Equal(t, []models.CurrencyCode{ufxAndIncomingInst.IncomingInstruction.Payment.Currency.IsoCode}, lastSave.HeldBackCurrencies)
+
+			// And the held back batch is kept as pending funding
+			holdBacks := spyPendingFunding.HoldBackCalls()
+			assert.Len(t, holdBacks, 1)
+			assert.Equal(t, "test.ufx", holdBacks[0].Filename)
+			assert.Equal(t, ufxAndIncomingInst.IncomingInstruction.Payment.Currency.IsoCode, holdBacks[0].Summary.CurrencyCode)
+			assert.Equal(t, models.IncomingInstructions{ufxAndIncomingInst.IncomingInstruction}, holdBacks[0].Instructions)
+		case <-time.After(timeout):
+			t.Fatal("timed out waiting for message to be deleted")
+		}
+	})
+}
+
+func newStubManagePendingFunding() *mocks.ManagePendingFundingMock {
+	return &mocks.ManagePendingFundingMock{
+		HoldBackFunc: func(ctx context.Context, filename string, summary models.IncomingInstructionsSummary, instructions models.IncomingInstructions) (models.PendingFunding, error) {
+			return models.NewPendingFunding(filename, summary, instructions), nil
+		},
+	}
+}
+
+func newStubUfxFileLedger() *mocks.UfxFileLedgerMock {
+	return &mocks.UfxFileLedgerMock{
+		GetUfxFileFunc: func(ctx context.Context, filename string) (models.UfxFile, error) {
+			return models.UfxFile{}, errors.New("not found")
+		},
+		SaveUfxFileFunc: func(ctx context.Context, file models.UfxFile) error {
+			return nil
+		},
+	}
+}
+
+func deliverOnce(msg *sqs.Message) func(context.Context) (*sqs.ReceiveMessageOutput, error) {
+	messages := make(chan *sqs.Message, 1)
+	messages <- msg
+	return func(context.Context) (*sqs.ReceiveMessageOutput, error) {
+		select {
+		case m := <-messages:
+			return &sqs.ReceiveMessageOutput{Messages: []*sqs.Message{m}}, nil
+		default:
+			time.Sleep(10 * time.Millisecond)
+			return &sqs.ReceiveMessageOutput{}, nil
+		}
+	}
+}
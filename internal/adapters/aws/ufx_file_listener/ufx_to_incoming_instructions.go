@@ -26,6 +26,7 @@ func NewUfxToPaymentInstructionsConverter() UfxToPaymentInstructionsConverter {
 func (ufxConverter *UfxToPaymentInstructionsConverter) ConvertUfx(ctx context.Context, ufxFileContents io.Reader, ufxFileName string) (models.IncomingInstructions, error) {
 	decoder := xml.NewDecoder(ufxFileContents)
 	var fileHeader ufx.FileHeader
+	var fileTrailer ufx.FileTrailer
 	var docs []ufx.Doc
 	for {
 		token, err := decoder.Token()
@@ -53,9 +54,19 @@ func (ufxConverter *UfxToPaymentInstructionsConverter) ConvertUfx(ctx context.Co
 				}
 				docs = append(docs, doc)
 			}
+			if ty.Name.Local == "FileTrailer" {
+				err = decoder.DecodeElement(&fileTrailer, &ty)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing the UFX XML: %s", err.Error())
+				}
+			}
 		}
 	}
 
+	if err := verifyCheckSum(ufxFileName, fileTrailer.CheckSum, docs); err != nil {
+		return nil, err
+	}
+
 	return ufxConverter.createIncomingInstructions(ctx, docs, ufxFileName, fileHeader)
 }
 
@@ -96,8 +107,14 @@ func (ufxConverter *UfxToPaymentInstructionsConverter) FilterCurrency(ufxFileCon
 		}
 	}
 
+	checkSum, err := newCheckSum(docs)
+	if err != nil {
+		return nil, fmt.Errorf("error computing the checksum of the filtered file: %s", err.Error())
+	}
+
 	root.Header = fileHeader
 	root.DocList.Docs = append(root.DocList.Docs, docs...)
+	root.FileTrailer.CheckSum = checkSum
 
 	filteredXML, err := xml.MarshalIndent(root, "  ", "    ")
 	if err != nil {
@@ -120,6 +120,50 @@ func TestUfxToPaymentInstructionsConverter_ConvertUfx(t *testing.T) {
 	})
 }
 
+func TestUfxToPaymentInstructionsConverter_ConvertUfx_CheckSum(t *testing.T) {
+	validFile, _ := ioutil.ReadFile("test-ufx-is.xml")
+
+	t.Run("returns a checksum mismatch error when the record count does not match the parsed docs", func(t *testing.T) {
+		ctx := context.Background()
+		file := strings.Replace(string(validFile), "<RecsCount>4</RecsCount>", "<RecsCount>5</RecsCount>", 1)
+
+		ufxFileConverter := ufl.NewUfxToPaymentInstructionsConverter()
+		incomingInstructions, err := ufxFileConverter.ConvertUfx(ctx, strings.NewReader(file), "truncated.xml")
+
+		var mismatch ufl.ChecksumMismatchError
+		assert.ErrorAs(t, err, &mismatch)
+		assert.Nil(t, incomingInstructions)
+		assert.Equal(t, "truncated.xml", mismatch.FileName)
+		assert.Equal(t, "5", mismatch.ExpectedRecsCount)
+		assert.Equal(t, "4", mismatch.ActualRecsCount)
+	})
+
+	t.Run("returns a checksum mismatch error when the hash total amount does not match the parsed docs", func(t *testing.T) {
+		ctx := context.Background()
+		file := strings.Replace(string(validFile), "<HashTotalAmount>40</HashTotalAmount>", "<HashTotalAmount>40.01</HashTotalAmount>", 1)
+
+		ufxFileConverter := ufl.NewUfxToPaymentInstructionsConverter()
+		_, err := ufxFileConverter.ConvertUfx(ctx, strings.NewReader(file), "corrupted.xml")
+
+		var mismatch ufl.ChecksumMismatchError
+		assert.ErrorAs(t, err, &mismatch)
+		assert.Equal(t, "40.01", mismatch.ExpectedHashTotalAmount)
+		assert.Equal(t, "40.00", mismatch.ActualHashTotalAmount)
+	})
+
+	t.Run("returns a checksum mismatch error when the file has no trailer", func(t *testing.T) {
+		ctx := context.Background()
+		file := string(validFile)
+		file = file[:strings.Index(file, "<FileTrailer>")] + "</DocFile>"
+
+		ufxFileConverter := ufl.NewUfxToPaymentInstructionsConverter()
+		_, err := ufxFileConverter.ConvertUfx(ctx, strings.NewReader(file), "no-trailer.xml")
+
+		var mismatch ufl.ChecksumMismatchError
+		assert.ErrorAs(t, err, &mismatch)
+	})
+}
+
 func TestUfxToPaymentInstructionConverter_FilterCurrency(t *testing.T) {
 	is := is.New(t)
 	t.Run("Filters the XML for a specified currency", func(t *testing.T) {
@@ -140,6 +184,19 @@ func TestUfxToPaymentInstructionConverter_FilterCurrency(t *testing.T) {
 		is.True(isCurrencyInFile(stringifiedFile, expectedHufCurrency))
 		is.True(isCurrencyInFile(stringifiedFile, notExpectedGbpCurrency) != true)
 	})
+	t.Run("Writes a trailer checksum matching the filtered docs", func(t *testing.T) {
+		ctx := context.Background()
+		originalFileBytes, _ := ioutil.ReadFile("test-ufx-with-unordered-currencies.xml")
+
+		ufxFileConverter := ufl.UfxToPaymentInstructionsConverter{}
+
+		xmlFile, err := ufxFileConverter.FilterCurrency(bytes.NewReader(originalFileBytes), models.HUF)
+		is.NoErr(err)
+
+		incomingInstructions, err := ufxFileConverter.ConvertUfx(ctx, bytes.NewReader(xmlFile), "filtered.xml")
+		is.NoErr(err)
+		is.True(len(incomingInstructions) > 0)
+	})
 }
 
 func isCurrencyInFile(file string, currency string) bool {
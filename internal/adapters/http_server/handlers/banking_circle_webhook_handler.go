@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bcports "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+)
+
+const (
+	// bankingCircleSignatureHeader has the hex encoded HMAC-SHA256 of the signing time, a dot and the request body,
+	// keyed with the webhook secret.
+	bankingCircleSignatureHeader = "X-Signature"
+	// bankingCircleSignatureTimestampHeader has the unix time in seconds the notification was signed at.
+	bankingCircleSignatureTimestampHeader = "X-Signature-Timestamp"
+	// bankingCircleSignatureTolerance is how far from now a notification can be signed, a signed notification is
+	// only received once within it.
+	bankingCircleSignatureTolerance = 5 * time.Minute
+	// bankingCircleMaxNotificationBytes is far more than a payment status notification takes.
+	bankingCircleMaxNotificationBytes = 64 << 10
+)
+
+type BankingCircleWebhookHandler struct {
+	receivePaymentStatus bcports.ReceiveBankingCirclePaymentStatus
+	secret               []byte
+	now                  func() time.Time
+
+	mu sync.Mutex
+	// seen has the signatures received within the tolerance, with the time they can be forgotten at
+	seen map[string]time.Time
+}
+
+func NewBankingCircleWebhookHandler(receivePaymentStatus bcports.ReceiveBankingCirclePaymentStatus, secret string) *BankingCircleWebhookHandler {
+	return &BankingCircleWebhookHandler{
+		receivePaymentStatus: receivePaymentStatus,
+		secret:               []byte(secret),
+		now:                  time.Now,
+		seen:                 make(map[string]time.Time),
+	}
+}
+
+// PostPaymentStatus receives the payment status notifications of Banking Circle. A notification of a payment that
+// isn't found is answered with a not found, so Banking Circle notifies it again once its acceptance is recorded.
+// A notification signed too long ago, or one received already, is unauthorized.
+func (b *BankingCircleWebhookHandler) PostPaymentStatus(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, bankingCircleMaxNotificationBytes))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	signature := r.Header.Get(bankingCircleSignatureHeader)
+	timestamp := r.Header.Get(bankingCircleSignatureTimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	signedAt := time.Unix(seconds, 0)
+	if err != nil || !b.recent(signedAt) || !b.validSignature(timestamp, body, signature) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	notification, err := bcmodels.NewPaymentStatusNotificationFromJSON(body)
+	if err != nil || notification.PaymentID == "" {
+		http.Error(w, fmt.Sprintf("failed to decode request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if !b.claim(signature, signedAt) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := b.receivePaymentStatus.Execute(r.Context(), notification); err != nil {
+		// a notification that wasn't received can be sent again
+		b.release(signature)
+
+		if missingError, isMissingErr := err.(postgresql.PaymentInstructionMissingError); isMissingErr {
+			http.Error(w, missingError.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to receive payment status: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *BankingCircleWebhookHandler) validSignature(timestamp string, body []byte, signature string) bool {
+	if len(b.secret) == 0 {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, b.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func (b *BankingCircleWebhookHandler) recent(signedAt time.Time) bool {
+	age := b.now().Sub(signedAt)
+	return age <= bankingCircleSignatureTolerance && age >= -bankingCircleSignatureTolerance
+}
+
+// claim reports false for a signature received already. The signatures are only kept by this instance, a replay
+// reaching another instance is received again and changes nothing, as the payment has its status already.
+func (b *BankingCircleWebhookHandler) claim(signature string, signedAt time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for seen, forgetAt := range b.seen {
+		if now.After(forgetAt) {
+			delete(b.seen, seen)
+		}
+	}
+
+	// a signature decoded from either case of hex is the same signature
+	key := strings.ToLower(signature)
+	if _, found := b.seen[key]; found {
+		return false
+	}
+	b.seen[key] = signedAt.Add(bankingCircleSignatureTolerance)
+	return true
+}
+
+func (b *BankingCircleWebhookHandler) release(signature string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.seen, strings.ToLower(signature))
+}
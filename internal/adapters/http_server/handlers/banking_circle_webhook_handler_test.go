@@ -0,0 +1,141 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bcmocks "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+)
+
+func TestBankingCircleWebhookHandler_PostPaymentStatus(t *testing.T) {
+	const (
+		secret = "webhook-secret"
+		body   = `{"paymentId":"banking-circle-payment-id","status":"Processed"}`
+	)
+	now := func() string {
+		return strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	sign := func(timestamp, body, secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "." + body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	postTo := func(handler *handlers.BankingCircleWebhookHandler, body, timestamp, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/banking-circle/payment-status", strings.NewReader(body))
+		req.Header.Set("X-Signature", signature)
+		req.Header.Set("X-Signature-Timestamp", timestamp)
+		res := httptest.NewRecorder()
+		handler.PostPaymentStatus(res, req)
+		return res
+	}
+
+	// post sends the body signed now to a handler of its own
+	post := func(receive *bcmocks.ReceiveBankingCirclePaymentStatusMock, body, signature string) *httptest.ResponseRecorder {
+		return postTo(handlers.NewBankingCircleWebhookHandler(receive, secret), body, now(), signature)
+	}
+
+	receiving := func(err error) *bcmocks.ReceiveBankingCirclePaymentStatusMock {
+		return &bcmocks.ReceiveBankingCirclePaymentStatusMock{
+			ExecuteFunc: func(ctx context.Context, notification bcmodels.PaymentStatusNotification) error {
+				return err
+			},
+		}
+	}
+
+	t.Run("receives a signed payment status notification", func(t *testing.T) {
+		is := is.New(t)
+		receive := receiving(nil)
+
+		res := post(receive, body, sign(now(), body, secret))
+
+		is.Equal(res.Code, http.StatusNoContent)
+		is.Equal(len(receive.ExecuteCalls()), 1)
+		is.Equal(receive.ExecuteCalls()[0].Notification, bcmodels.PaymentStatusNotification{PaymentID: "banking-circle-payment-id", Status: "Processed"})
+	})
+
+	t.Run("rejects a notification that isn't signed with the webhook secret", func(t *testing.T) {
+		is := is.New(t)
+		receive := receiving(nil)
+
+		for _, signature := range []string{"", "not-hex", sign(now(), body, "another-secret")} {
+			res := post(receive, body, signature)
+			is.Equal(res.Code, http.StatusUnauthorized)
+		}
+		is.Equal(len(receive.ExecuteCalls()), 0)
+	})
+
+	t.Run("returns not found when the payment isn't found", func(t *testing.T) {
+		is := is.New(t)
+
+		res := post(receiving(postgresql.PaymentInstructionMissingError{ProviderPaymentID: "banking-circle-payment-id"}), body, sign(now(), body, secret))
+
+		is.Equal(res.Code, http.StatusNotFound)
+	})
+
+	t.Run("returns an internal server error when the notification can't be received", func(t *testing.T) {
+		is := is.New(t)
+
+		res := post(receiving(errors.New("queue is down")), body, sign(now(), body, secret))
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+	})
+
+	t.Run("rejects a notification signed outside the tolerance", func(t *testing.T) {
+		is := is.New(t)
+		receive := receiving(nil)
+		handler := handlers.NewBankingCircleWebhookHandler(receive, secret)
+
+		for _, signedAt := range []time.Time{time.Now().Add(-10 * time.Minute), time.Now().Add(10 * time.Minute)} {
+			timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+			res := postTo(handler, body, timestamp, sign(timestamp, body, secret))
+			is.Equal(res.Code, http.StatusUnauthorized)
+		}
+		is.Equal(len(receive.ExecuteCalls()), 0)
+	})
+
+	t.Run("rejects a notification received already, unless it failed the first time", func(t *testing.T) {
+		is := is.New(t)
+		failing := receiving(errors.New("queue is down"))
+		receive := receiving(nil)
+		timestamp := now()
+		signature := sign(timestamp, body, secret)
+
+		failingHandler := handlers.NewBankingCircleWebhookHandler(failing, secret)
+		is.Equal(postTo(failingHandler, body, timestamp, signature).Code, http.StatusInternalServerError)
+		failing.ExecuteFunc = receive.ExecuteFunc
+		is.Equal(postTo(failingHandler, body, timestamp, signature).Code, http.StatusNoContent)
+		is.Equal(postTo(failingHandler, body, timestamp, signature).Code, http.StatusUnauthorized)
+		is.Equal(len(failing.ExecuteCalls()), 2)
+	})
+
+	t.Run("rejects a body larger than a notification can be", func(t *testing.T) {
+		is := is.New(t)
+		receive := receiving(nil)
+		large := `{"paymentId":"banking-circle-payment-id","status":"Processed","padding":"` + strings.Repeat("x", 1<<20) + `"}`
+		timestamp := now()
+
+		res := postTo(handlers.NewBankingCircleWebhookHandler(receive, secret), large, timestamp, sign(timestamp, large, secret))
+
+		is.Equal(res.Code, http.StatusBadRequest)
+		is.Equal(len(receive.ExecuteCalls()), 0)
+	})
+}
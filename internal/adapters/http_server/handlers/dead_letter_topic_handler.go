@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/dead_letters"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type DeadLetterTopicHandler struct {
+	topics       dead_letters.TopicMapping
+	redriveAudit ports.DlqRedriveAudit
+}
+
+func NewDeadLetterTopicHandler(topics dead_letters.TopicMapping, redriveAudit ports.DlqRedriveAudit) *DeadLetterTopicHandler {
+	return &DeadLetterTopicHandler{
+		topics:       topics,
+		redriveAudit: redriveAudit,
+	}
+}
+
+func (d *DeadLetterTopicHandler) GetDltInformation(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx           = r.Context()
+		topicName     = mux.Vars(r)["name"]
+		topic, exists = d.topics[topicName]
+	)
+
+	ctx = zapctx.WithFields(ctx, zap.String("topic_name", topicName))
+
+	if !exists {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	dltInformation, err := dead_letters.Peek(ctx, topic)
+	if err != nil {
+		zapctx.Error(ctx, "fail to peek messages", zap.Error(err))
+		http.Error(w, "Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(dltInformation)
+}
+
+func (d *DeadLetterTopicHandler) RedriveDlt(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx           = r.Context()
+		topicName     = mux.Vars(r)["name"]
+		topic, exists = d.topics[topicName]
+	)
+
+	ctx = zapctx.WithFields(ctx, zap.String("topic_name", topicName))
+
+	if !exists {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	var request models.DlqRedriveRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid redrive request: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := request.ValidateSelection(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, redriveErr := dead_letters.Redrive(ctx, topicName, topic, request, d.redriveAudit)
+
+	auditErr := auditRedrive(ctx, d.redriveAudit, r, models.DlqRedrive{
+		Queue:       result.Topic,
+		SourceQueue: result.SourceTopic,
+		Messages:    result.Messages,
+	}, result.DryRun)
+
+	setJSON(w)
+	if redriveErr != nil || auditErr != nil {
+		zapctx.Error(ctx, "[DeadLetterTopicHandler] (RedriveDlt) redrive stopped", zap.NamedError("redrive_error", redriveErr), zap.NamedError("audit_error", auditErr))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,306 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/matryer/is"
+	"github.com/saltpay/go-kafka-driver"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/middleware/auth"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/dead_letters"
+	dltMocks "github.com/saltpay/settlements-payments-system/internal/adapters/kafka/dead_letters/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners"
+	producerMocks "github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	portsMocks "github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+const transactionsDlt = "settlements-payments-system-transactions-dlt"
+
+func TestDeadLetterTopicHandler(t *testing.T) {
+	deadLetters := []kafka.Message{
+		{
+			Partition: 0,
+			Offset:    7,
+			Key:       []byte("first"),
+			Value:     []byte(`{"merchant":{"contractNumber":"1111111"}}`),
+			Headers: []kafka.Header{
+				{Key: "trace-id", Value: []byte("abc")},
+				{Key: listeners.OriginalTopicHeader, Value: []byte("settlements-payments-system-transactions")},
+				{Key: listeners.ListenerHeader, Value: []byte("PaymentsListener")},
+				{Key: listeners.ErrorHeader, Value: []byte("error making payment: database is down")},
+				{Key: listeners.AttemptsHeader, Value: []byte("3")},
+				{Key: listeners.FirstFailedAtHeader, Value: []byte("2022-09-01T10:00:00Z")},
+			},
+		},
+		{
+			Partition: 0,
+			Offset:    8,
+			Key:       []byte("second"),
+			Value:     []byte(`{"merchant":{"contractNumber":"2222222"}}`),
+		},
+	}
+	newTopics := func(source *producerMocks.ProducerMock) dead_letters.TopicMapping {
+		return dead_letters.TopicMapping{
+			transactionsDlt: {
+				Reader:      &dltMocks.TopicReaderMock{ReadAllFunc: func(context.Context) ([]kafka.Message, error) { return deadLetters, nil }},
+				SourceTopic: "settlements-payments-system-transactions",
+				Source:      source,
+			},
+		}
+	}
+	newSource := func() *producerMocks.ProducerMock {
+		return &producerMocks.ProducerMock{WriteMessageFunc: func(context.Context, kafka.Message) error { return nil }}
+	}
+	newAudit := func() *portsMocks.DlqRedriveAuditMock {
+		return &portsMocks.DlqRedriveAuditMock{
+			RecordDlqRedriveFunc:         func(context.Context, models.DlqRedrive) error { return nil },
+			RedrivenDlqMessageIDsFunc:    func(context.Context, string) ([]string, error) { return nil, nil },
+			RecordRedrivenDlqMessageFunc: func(context.Context, string, string) (bool, error) { return true, nil },
+			ForgetRedrivenDlqMessageFunc: func(context.Context, string, string) error { return nil },
+		}
+	}
+	serve := func(handler *handlers.DeadLetterTopicHandler, method, target, body string) *httptest.ResponseRecorder {
+		r := mux.NewRouter()
+		r.HandleFunc("/internal/dead-letter-topics/{name}", handler.GetDltInformation).Methods(http.MethodGet)
+		r.HandleFunc("/internal/dead-letter-topics/{name}/redrive", handler.RedriveDlt).Methods(http.MethodPost)
+
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(auth.WithUser(req.Context(), "jane.doe"))
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		return res
+	}
+
+	t.Run("peeks the messages of a dead letter topic with why they failed", func(t *testing.T) {
+		is := is.New(t)
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(newSource()), nil)
+
+		res := serve(handler, http.MethodGet, "/internal/dead-letter-topics/"+transactionsDlt, "")
+
+		is.Equal(res.Code, http.StatusOK)
+		var information dead_letters.DLTInformation
+		is.NoErr(json.NewDecoder(res.Body).Decode(&information))
+		is.Equal(information.Count, 2)
+		is.Equal(information.Messages[0].ID, "0/7")
+		is.Equal(information.Messages[0].Listener, "PaymentsListener")
+		is.Equal(information.Messages[0].Attempts, 3)
+		is.Equal(information.Messages[0].Error, "error making payment: database is down")
+	})
+
+	t.Run("returns not found for an unknown dead letter topic", func(t *testing.T) {
+		is := is.New(t)
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(newSource()), nil)
+
+		is.Equal(serve(handler, http.MethodGet, "/internal/dead-letter-topics/unknown", "").Code, http.StatusNotFound)
+		is.Equal(serve(handler, http.MethodPost, "/internal/dead-letter-topics/unknown/redrive", "").Code, http.StatusNotFound)
+	})
+
+	t.Run("returns a server error when the dead letter topic can't be read", func(t *testing.T) {
+		is := is.New(t)
+		handler := handlers.NewDeadLetterTopicHandler(dead_letters.TopicMapping{
+			transactionsDlt: {Reader: &dltMocks.TopicReaderMock{ReadAllFunc: func(context.Context) ([]kafka.Message, error) {
+				return nil, errors.New("kafka is down")
+			}}},
+		}, nil)
+
+		res := serve(handler, http.MethodGet, "/internal/dead-letter-topics/"+transactionsDlt, "")
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+	})
+
+	t.Run("redrives the selected messages to the topic they failed on and audits who did it", func(t *testing.T) {
+		is := is.New(t)
+		source := newSource()
+		audit := newAudit()
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(source), audit)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"contractNumbers":["1111111"]}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		var result dead_letters.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		is.Equal(result.Count, 1)
+		is.Equal(result.Messages[0].MessageID, "0/7")
+
+		is.Equal(len(source.WriteMessageCalls()), 1)
+		redriven := source.WriteMessageCalls()[0].Message
+		is.Equal(string(redriven.Key), "first")
+		headers := map[string]string{}
+		for _, h := range redriven.Headers {
+			headers[h.Key] = string(h.Value)
+		}
+		is.Equal(headers, map[string]string{
+			"trace-id":                    "abc",
+			listeners.FirstFailedAtHeader: "2022-09-01T10:00:00Z",
+		})
+
+		is.Equal(len(audit.RecordRedrivenDlqMessageCalls()), 1)
+		is.Equal(audit.RecordRedrivenDlqMessageCalls()[0].Queue, transactionsDlt)
+		is.Equal(audit.RecordRedrivenDlqMessageCalls()[0].MessageID, "0/7")
+
+		is.Equal(len(audit.RecordDlqRedriveCalls()), 1)
+		recorded := audit.RecordDlqRedriveCalls()[0].Redrive
+		is.Equal(recorded.RedrivenBy, "jane.doe")
+		is.Equal(recorded.Queue, transactionsDlt)
+		is.Equal(recorded.SourceQueue, "settlements-payments-system-transactions")
+	})
+
+	t.Run("lists the messages it would redrive on a dry run without redriving or auditing them", func(t *testing.T) {
+		is := is.New(t)
+		source := newSource()
+		audit := newAudit()
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(source), audit)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"dryRun":true,"all":true}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		var result dead_letters.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		is.True(result.DryRun)
+		is.Equal(result.Count, 2)
+		is.Equal(result.Messages[1].Body, `{"merchant":{"contractNumber":"2222222"}}`)
+		is.Equal(len(source.WriteMessageCalls()), 0)
+		is.Equal(len(audit.RecordRedrivenDlqMessageCalls()), 0)
+		is.Equal(len(audit.RecordDlqRedriveCalls()), 0)
+	})
+
+	t.Run("returns a server error with what was redriven when the source topic can't be written to", func(t *testing.T) {
+		is := is.New(t)
+		source := &producerMocks.ProducerMock{WriteMessageFunc: func(context.Context, kafka.Message) error { return errors.New("kafka is down") }}
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(source), nil)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"all":true}`)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+		var result dead_letters.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		is.Equal(result.Count, 0)
+	})
+
+	t.Run("selects the state updates of every version by the ID of their payment instruction", func(t *testing.T) {
+		is := is.New(t)
+		source := newSource()
+		handler := handlers.NewDeadLetterTopicHandler(dead_letters.TopicMapping{
+			transactionsDlt: {
+				Reader: &dltMocks.TopicReaderMock{ReadAllFunc: func(context.Context) ([]kafka.Message, error) {
+					return []kafka.Message{
+						{Partition: 0, Offset: 1, Value: []byte(`{"payment_instruction_id":"pi-1","updated_state":"PROCESSED"}`)},
+						{Partition: 0, Offset: 2, Value: []byte(`{"paymentInstructionId":"pi-1","updatedState":"PROCESSED"}`)},
+						{Partition: 0, Offset: 3, Value: []byte(`{"paymentInstructionId":"pi-2","updatedState":"PROCESSED"}`)},
+					}, nil
+				}},
+				SourceTopic: "settlements-payments-system-state-updates",
+				Source:      source,
+			},
+		}, nil)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"paymentInstructionIds":["pi-1"]}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		var result dead_letters.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		is.Equal(result.Count, 2)
+		is.Equal(result.Messages[0].MessageID, "0/1")
+		is.Equal(result.Messages[1].MessageID, "0/2")
+		is.Equal(result.Messages[1].PaymentInstructionID, models.PaymentInstructionID("pi-1"))
+	})
+
+	t.Run("rejects a redrive that selects no message without all", func(t *testing.T) {
+		is := is.New(t)
+		source := newSource()
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(source), nil)
+
+		is.Equal(serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", "").Code, http.StatusBadRequest)
+		is.Equal(serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"dryRun":true}`).Code, http.StatusBadRequest)
+		is.Equal(len(source.WriteMessageCalls()), 0)
+	})
+
+	t.Run("skips the messages an earlier or concurrent redrive recorded", func(t *testing.T) {
+		is := is.New(t)
+		source := newSource()
+		audit := newAudit()
+		audit.RecordRedrivenDlqMessageFunc = func(ctx context.Context, queue string, messageID string) (bool, error) {
+			return messageID != "0/7", nil
+		}
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(source), audit)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"all":true}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		var result dead_letters.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		is.Equal(result.Count, 1)
+		is.Equal(result.Messages[0].MessageID, "0/8")
+		is.Equal(result.AlreadyRedriven, []string{"0/7"})
+		is.Equal(len(source.WriteMessageCalls()), 1)
+		is.Equal(string(source.WriteMessageCalls()[0].Message.Key), "second")
+	})
+
+	t.Run("lists the messages an earlier redrive moved on a dry run", func(t *testing.T) {
+		is := is.New(t)
+		audit := newAudit()
+		audit.RedrivenDlqMessageIDsFunc = func(context.Context, string) ([]string, error) { return []string{"0/7"}, nil }
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(newSource()), audit)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"dryRun":true,"all":true}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		var result dead_letters.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		is.Equal(result.Count, 1)
+		is.Equal(result.AlreadyRedriven, []string{"0/7"})
+		is.Equal(audit.RedrivenDlqMessageIDsCalls()[0].Queue, transactionsDlt)
+	})
+
+	t.Run("returns a server error without redriving when a message can't be recorded", func(t *testing.T) {
+		is := is.New(t)
+		source := newSource()
+		audit := newAudit()
+		audit.RecordRedrivenDlqMessageFunc = func(context.Context, string, string) (bool, error) { return false, errors.New("database is down") }
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(source), audit)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"all":true}`)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+		is.Equal(len(source.WriteMessageCalls()), 0)
+	})
+
+	t.Run("forgets the record of a message that couldn't be written back", func(t *testing.T) {
+		is := is.New(t)
+		source := &producerMocks.ProducerMock{WriteMessageFunc: func(context.Context, kafka.Message) error { return errors.New("kafka is down") }}
+		audit := newAudit()
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(source), audit)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"all":true}`)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+		is.Equal(len(audit.ForgetRedrivenDlqMessageCalls()), 1)
+		is.Equal(audit.ForgetRedrivenDlqMessageCalls()[0].MessageID, "0/7")
+	})
+
+	t.Run("returns a server error when the redrive can't be audited", func(t *testing.T) {
+		is := is.New(t)
+		audit := newAudit()
+		audit.RecordDlqRedriveFunc = func(context.Context, models.DlqRedrive) error { return errors.New("database is down") }
+		handler := handlers.NewDeadLetterTopicHandler(newTopics(newSource()), audit)
+
+		res := serve(handler, http.MethodPost, "/internal/dead-letter-topics/"+transactionsDlt+"/redrive", `{"all":true}`)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+		var result dead_letters.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		is.Equal(result.Count, 2)
+	})
+}
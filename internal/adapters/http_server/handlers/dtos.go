@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 )
@@ -22,3 +23,30 @@ func NewPaymentResponseFromJSON(in io.Reader) (PaymentResponse, error) {
 }
 
 type DLQURLs []string
+
+type StuckPaymentInstructionResponse struct {
+	ID                models.PaymentInstructionID     `json:"id"`
+	ContractNumber    string                          `json:"contractNumber"`
+	Currency          models.CurrencyCode             `json:"currency"`
+	Amount            string                          `json:"amount"`
+	Status            models.PaymentInstructionStatus `json:"status"`
+	PaymentProvider   models.PaymentProviderType      `json:"paymentProvider"`
+	ProviderPaymentID models.ProviderPaymentID        `json:"providerPaymentId,omitempty"`
+	StuckSince        time.Time                       `json:"stuckSince"`
+	// NeedsAttention is set when the payment provider can't be asked about the payment, someone has to follow it up.
+	NeedsAttention bool `json:"needsAttention"`
+}
+
+func NewStuckPaymentInstructionResponse(stuck models.StuckPaymentInstruction) StuckPaymentInstructionResponse {
+	return StuckPaymentInstructionResponse{
+		ID:                stuck.PaymentInstruction.ID(),
+		ContractNumber:    stuck.PaymentInstruction.ContractNumber(),
+		Currency:          stuck.PaymentInstruction.IncomingInstruction.IsoCode(),
+		Amount:            stuck.PaymentInstruction.IncomingInstruction.Payment.Amount,
+		Status:            stuck.PaymentInstruction.GetStatus(),
+		PaymentProvider:   stuck.PaymentInstruction.PaymentProvider(),
+		ProviderPaymentID: stuck.ProviderPaymentID,
+		StuckSince:        stuck.StuckSince,
+		NeedsAttention:    !stuck.CanBeRechecked(),
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type FileHandler struct {
+	ufxFileLedger ports.UfxFileLedger
+}
+
+func NewFileHandler(ufxFileLedger ports.UfxFileLedger) *FileHandler {
+	return &FileHandler{
+		ufxFileLedger: ufxFileLedger,
+	}
+}
+
+func (f *FileHandler) ListFiles(w http.ResponseWriter, r *http.Request) {
+	state := models.UfxFileState(r.URL.Query().Get("state"))
+
+	files, err := f.ufxFileLedger.ListUfxFiles(r.Context(), state)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list files: %v", err), http.StatusInternalServerError)
+		return
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(files)
+}
+
+func (f *FileHandler) GetFile(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	file, err := f.ufxFileLedger.GetUfxFile(r.Context(), name)
+	if err != nil {
+		missingError, isMissingErr := err.(postgresql.UfxFileMissingError)
+		if isMissingErr {
+			http.Error(w, missingError.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to get file: %v", err), http.StatusInternalServerError)
+		return
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(file)
+}
@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestFileHandler_ListFiles(t *testing.T) {
+	t.Run("returns the files in the requested state", func(t *testing.T) {
+		is := is.New(t)
+		ledger := &mocks.UfxFileLedgerMock{
+			ListUfxFilesFunc: func(ctx context.Context, state models.UfxFileState) ([]models.UfxFile, error) {
+				return []models.UfxFile{{Filename: "OIC_SAXO_BORGUN_20221101_1.xml", State: state}}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/files?state=QUARANTINED", nil)
+		res := httptest.NewRecorder()
+		handlers.NewFileHandler(ledger).ListFiles(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(ledger.ListUfxFilesCalls()[0].State, models.UfxFileQuarantined)
+
+		var files []models.UfxFile
+		is.NoErr(json.NewDecoder(res.Body).Decode(&files))
+		is.Equal(len(files), 1)
+		is.Equal(files[0].State, models.UfxFileQuarantined)
+	})
+
+	t.Run("returns 500 if the ledger can't be read", func(t *testing.T) {
+		is := is.New(t)
+		ledger := &mocks.UfxFileLedgerMock{
+			ListUfxFilesFunc: func(ctx context.Context, state models.UfxFileState) ([]models.UfxFile, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/files", nil)
+		res := httptest.NewRecorder()
+		handlers.NewFileHandler(ledger).ListFiles(res, req)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+	})
+}
+
+func TestFileHandler_GetFile(t *testing.T) {
+	t.Run("returns the ledger entry of the file", func(t *testing.T) {
+		is := is.New(t)
+		name := "OIC_SAXO_BORGUN_20221101_1.xml"
+		ledger := &mocks.UfxFileLedgerMock{
+			GetUfxFileFunc: func(ctx context.Context, filename string) (models.UfxFile, error) {
+				return models.UfxFile{Filename: filename, State: models.UfxFileFullyPaid, RecordCount: 3}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/files/"+name, nil)
+		req = mux.SetURLVars(req, map[string]string{"name": name})
+		res := httptest.NewRecorder()
+		handlers.NewFileHandler(ledger).GetFile(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+
+		var file models.UfxFile
+		is.NoErr(json.NewDecoder(res.Body).Decode(&file))
+		is.Equal(file.Filename, name)
+		is.Equal(file.State, models.UfxFileFullyPaid)
+		is.Equal(file.RecordCount, 3)
+	})
+
+	t.Run("returns 404 if the file was never received", func(t *testing.T) {
+		is := is.New(t)
+		ledger := &mocks.UfxFileLedgerMock{
+			GetUfxFileFunc: func(ctx context.Context, filename string) (models.UfxFile, error) {
+				return models.UfxFile{}, postgresql.UfxFileMissingError{Filename: filename}
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/files/unknown.xml", nil)
+		req = mux.SetURLVars(req, map[string]string{"name": "unknown.xml"})
+		res := httptest.NewRecorder()
+		handlers.NewFileHandler(ledger).GetFile(res, req)
+
+		is.Equal(res.Code, http.StatusNotFound)
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,6 +15,9 @@ import (
 
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/sqs"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/ufx_downloader"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/middleware/auth"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
 type action string
@@ -35,6 +39,7 @@ type InternalHandler struct {
 	allowPurge    bool
 	queues        sqs.QueueClientMapping
 	ufxDownloader ufx_downloader.UfxDownloader
+	redriveAudit  ports.DlqRedriveAudit
 }
 
 func NewInternalHandler(queues sqs.QueueClientMapping, allowPurge bool, ufxDownloader ufx_downloader.UfxDownloader) *InternalHandler {
@@ -70,6 +75,88 @@ func (i *InternalHandler) GetDlqInformation(w http.ResponseWriter, r *http.Reque
 	_ = json.NewEncoder(w).Encode(dlqInformation)
 }
 
+// AuditRedrivesWith records who redrove which messages of the dead letter queues, they are only logged otherwise.
+func (i *InternalHandler) AuditRedrivesWith(redriveAudit ports.DlqRedriveAudit) {
+	i.redriveAudit = redriveAudit
+}
+
+func (i *InternalHandler) RedriveDlq(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		queueName = sqs.QueueName(mux.Vars(r)["name"])
+	)
+
+	ctx = zapctx.WithFields(ctx, zap.String("queue_name", string(queueName)))
+
+	if !queueName.IsDlq() {
+		http.Error(w, fmt.Sprintf("%s is not a dead letter queue", queueName), http.StatusBadRequest)
+		return
+	}
+	dlq, exists := i.queues[queueName]
+	if !exists {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	sourceName, _ := queueName.SourceQueue()
+	source, exists := i.queues[sourceName]
+	if !exists {
+		http.Error(w, fmt.Sprintf("source queue %s not found", sourceName), http.StatusNotFound)
+		return
+	}
+
+	var request models.DlqRedriveRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid redrive request: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := request.ValidateSelection(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, redriveErr := sqs.Redrive(ctx, queueName, dlq, source, request)
+
+	auditErr := auditRedrive(ctx, i.redriveAudit, r, models.DlqRedrive{
+		Queue:       string(result.Queue),
+		SourceQueue: string(result.SourceQueue),
+		Messages:    result.Messages,
+	}, result.DryRun)
+
+	setJSON(w)
+	if redriveErr != nil || auditErr != nil {
+		zapctx.Error(ctx, "[InternalHandler] (RedriveDlq) redrive stopped", zap.NamedError("redrive_error", redriveErr), zap.NamedError("audit_error", auditErr))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// auditRedrive records who redrove the messages, a dry run or a redrive of no message is only logged. The messages are
+// redriven already when it fails, the redrive is reported as failed so that someone records it.
+func auditRedrive(ctx context.Context, redriveAudit ports.DlqRedriveAudit, r *http.Request, redrive models.DlqRedrive, dryRun bool) error {
+	redrive.RedrivenBy = auth.User(r.Context())
+	if redrive.RedrivenBy == "" {
+		redrive.RedrivenBy = "unknown"
+	}
+	redrive.RedrivenAt = time.Now()
+
+	zapctx.Info(ctx, "dead letters redriven",
+		zap.String("queue", redrive.Queue),
+		zap.String("redriven_by", redrive.RedrivenBy),
+		zap.Bool("dry_run", dryRun),
+		zap.Int("count", len(redrive.Messages)),
+	)
+	if dryRun || len(redrive.Messages) == 0 || redriveAudit == nil {
+		return nil
+	}
+	if err := redriveAudit.RecordDlqRedrive(ctx, redrive); err != nil {
+		zapctx.Error(ctx, "failed to record the redrive", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
 func (i *InternalHandler) GetDlqUrls(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 	for queueName := range i.queues {
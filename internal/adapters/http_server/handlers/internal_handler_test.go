@@ -5,6 +5,8 @@ package handlers_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
+	awsSqs "github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/gorilla/mux"
 	"github.com/matryer/is"
 
@@ -24,7 +27,10 @@ import (
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/ufx_downloader"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/ufx_downloader/mocks"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/middleware/auth"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	portsMocks "github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
 	testhelpers2 "github.com/saltpay/settlements-payments-system/internal/testhelpers"
 )
 
@@ -352,3 +358,196 @@ func generateS3Mock(listObjects []*s3.Object, serveError error) *mocks.S3ClientM
 		},
 	}
 }
+
+func TestRedriveDeadLetterQueue(t *testing.T) {
+	newDeadLetterQueue := func(t *testing.T, instructions ...models.PaymentInstruction) *mocks2.QueueMock {
+		messages := make([]*awsSqs.Message, 0, len(instructions))
+		for i, instruction := range instructions {
+			body, err := instruction.MustToJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			messages = append(messages, &awsSqs.Message{
+				MessageId:     aws.String(fmt.Sprintf("message-%d", i)),
+				ReceiptHandle: aws.String(fmt.Sprintf("handle-%d", i)),
+				Body:          aws.String(string(body)),
+				MessageAttributes: sqs.DeadLetter{
+					Listener:      "PaymentInstructionEventListener",
+					Attempts:      1,
+					FirstFailedAt: time.Now(),
+				}.MessageAttributes(),
+			})
+		}
+		received := false
+		return &mocks2.QueueMock{
+			GetMessagesFunc: func(context.Context) (*awsSqs.ReceiveMessageOutput, error) {
+				// the received messages are invisible until they are deleted or made visible again
+				if received {
+					return &awsSqs.ReceiveMessageOutput{}, nil
+				}
+				received = true
+				return &awsSqs.ReceiveMessageOutput{Messages: messages}, nil
+			},
+			DeleteMessageFunc:           func(context.Context, string) error { return nil },
+			ChangeMessageVisibilityFunc: func(context.Context, string, time.Duration) error { return nil },
+		}
+	}
+	newSourceQueue := func() *mocks2.QueueMock {
+		return &mocks2.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error { return nil }}
+	}
+	redrive := func(handler *handlers.InternalHandler, queueName string, body string) *httptest.ResponseRecorder {
+		r := mux.NewRouter()
+		r.HandleFunc("/internal/dead-letter-queues/{name}/redrive", handler.RedriveDlq).Methods(http.MethodPost)
+
+		req := httptest.NewRequest(http.MethodPost, "/internal/dead-letter-queues/"+queueName+"/redrive", strings.NewReader(body))
+		req = req.WithContext(auth.WithUser(req.Context(), "jane.doe"))
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		return res
+	}
+	decodeResult := func(is *is.I, res *httptest.ResponseRecorder) sqs.RedriveResult {
+		var result sqs.RedriveResult
+		is.NoErr(json.NewDecoder(res.Body).Decode(&result))
+		return result
+	}
+
+	var (
+		first  = testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(testhelpers.NewIncomingInstructionBuilder().WithMerchantContractNumber("1111111").Build()).Build()
+		second = testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(testhelpers.NewIncomingInstructionBuilder().WithMerchantContractNumber("2222222").Build()).Build()
+	)
+
+	t.Run("redrives all the messages back to the source queue and audits who did it", func(t *testing.T) {
+		is := is.New(t)
+		dlq, source := newDeadLetterQueue(t, first, second), newSourceQueue()
+		audit := &portsMocks.DlqRedriveAuditMock{RecordDlqRedriveFunc: func(context.Context, models.DlqRedrive) error { return nil }}
+
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPaymentsDlq: dlq, sqs.BcUnprocessedPayments: source}, false, ufx_downloader.UfxDownloader{})
+		handler.AuditRedrivesWith(audit)
+
+		res := redrive(handler, "bc-unprocessed-dlq", `{"all": true}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		result := decodeResult(is, res)
+		is.Equal(result.SourceQueue, sqs.BcUnprocessedPayments)
+		is.Equal(result.Count, 2)
+		is.Equal(len(source.SendMessageWithAttributesCalls()), 2)
+		is.Equal(len(dlq.DeleteMessageCalls()), 2)
+		_, keptDeadLetter := sqs.DeadLetterOf(&awsSqs.Message{MessageAttributes: source.SendMessageWithAttributesCalls()[0].Attributes})
+		is.True(keptDeadLetter)
+
+		is.Equal(len(audit.RecordDlqRedriveCalls()), 1)
+		recorded := audit.RecordDlqRedriveCalls()[0].Redrive
+		is.Equal(recorded.RedrivenBy, "jane.doe")
+		is.Equal(recorded.Queue, "bc-unprocessed-dlq")
+		is.Equal(recorded.Messages[0].PaymentInstructionID, first.ID())
+	})
+
+	t.Run("redrives the messages selected by contract number, and makes the others visible again", func(t *testing.T) {
+		is := is.New(t)
+		dlq, source := newDeadLetterQueue(t, first, second), newSourceQueue()
+
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPaymentsDlq: dlq, sqs.BcUnprocessedPayments: source}, false, ufx_downloader.UfxDownloader{})
+
+		res := redrive(handler, "bc-unprocessed-dlq", `{"contractNumbers": ["2222222"]}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		result := decodeResult(is, res)
+		is.Equal(result.Count, 1)
+		is.Equal(result.Messages[0].PaymentInstructionID, second.ID())
+		is.Equal(len(source.SendMessageWithAttributesCalls()), 1)
+		is.Equal(dlq.DeleteMessageCalls()[0].MessageHandle, "handle-1")
+		is.Equal(len(dlq.ChangeMessageVisibilityCalls()), 1)
+		is.Equal(dlq.ChangeMessageVisibilityCalls()[0].MessageHandle, "handle-0")
+	})
+
+	t.Run("a dry run reports the selected messages and leaves them on the dead letter queue", func(t *testing.T) {
+		is := is.New(t)
+		dlq, source := newDeadLetterQueue(t, first, second), newSourceQueue()
+		audit := &portsMocks.DlqRedriveAuditMock{}
+
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPaymentsDlq: dlq, sqs.BcUnprocessedPayments: source}, false, ufx_downloader.UfxDownloader{})
+		handler.AuditRedrivesWith(audit)
+
+		res := redrive(handler, "bc-unprocessed-dlq", fmt.Sprintf(`{"dryRun": true, "paymentInstructionIds": [%q]}`, first.ID()))
+
+		is.Equal(res.Code, http.StatusOK)
+		result := decodeResult(is, res)
+		is.True(result.DryRun)
+		is.Equal(result.Count, 1)
+		is.True(result.Messages[0].Body != "")
+		is.Equal(len(source.SendMessageWithAttributesCalls()), 0)
+		is.Equal(len(dlq.DeleteMessageCalls()), 0)
+		is.Equal(len(dlq.ChangeMessageVisibilityCalls()), 2)
+		is.Equal(len(audit.RecordDlqRedriveCalls()), 0)
+	})
+
+	t.Run("redrives a single message with an edited body", func(t *testing.T) {
+		is := is.New(t)
+		dlq, source := newDeadLetterQueue(t, first, second), newSourceQueue()
+
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPaymentsDlq: dlq, sqs.BcUnprocessedPayments: source}, false, ufx_downloader.UfxDownloader{})
+
+		res := redrive(handler, "bc-unprocessed-dlq", `{"messageIds": ["message-0"], "body": "{\"id\": \"fixed\"}"}`)
+
+		is.Equal(res.Code, http.StatusOK)
+		result := decodeResult(is, res)
+		is.Equal(result.Count, 1)
+		is.True(result.Messages[0].Edited)
+		is.Equal(source.SendMessageWithAttributesCalls()[0].MessageBody, `{"id": "fixed"}`)
+	})
+
+	t.Run("an edited body needs exactly one message to be selected", func(t *testing.T) {
+		is := is.New(t)
+		dlq, source := newDeadLetterQueue(t, first, second), newSourceQueue()
+
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPaymentsDlq: dlq, sqs.BcUnprocessedPayments: source}, false, ufx_downloader.UfxDownloader{})
+
+		res := redrive(handler, "bc-unprocessed-dlq", `{"body": "{}"}`)
+
+		is.Equal(res.Code, http.StatusBadRequest)
+		is.Equal(len(dlq.GetMessagesCalls()), 0)
+	})
+
+	t.Run("rejects a redrive that selects no message without all", func(t *testing.T) {
+		is := is.New(t)
+		dlq, source := newDeadLetterQueue(t, first, second), newSourceQueue()
+
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPaymentsDlq: dlq, sqs.BcUnprocessedPayments: source}, false, ufx_downloader.UfxDownloader{})
+
+		is.Equal(redrive(handler, "bc-unprocessed-dlq", "").Code, http.StatusBadRequest)
+		is.Equal(redrive(handler, "bc-unprocessed-dlq", `{"dryRun": true}`).Code, http.StatusBadRequest)
+		is.Equal(len(dlq.GetMessagesCalls()), 0)
+		is.Equal(len(source.SendMessageWithAttributesCalls()), 0)
+	})
+
+	t.Run("only dead letter queues with a source queue can be redriven", func(t *testing.T) {
+		is := is.New(t)
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPayments: newSourceQueue(), sqs.UfxFileEventsDlq: newDeadLetterQueue(t)}, false, ufx_downloader.UfxDownloader{})
+
+		is.Equal(redrive(handler, "bc-unprocessed", "").Code, http.StatusBadRequest)
+		is.Equal(redrive(handler, "processed-dlq", "").Code, http.StatusNotFound)
+		is.Equal(redrive(handler, "ufx-dlq", "").Code, http.StatusNotFound)
+	})
+
+	t.Run("reports the messages redriven before the source queue failed", func(t *testing.T) {
+		is := is.New(t)
+		dlq := newDeadLetterQueue(t, first, second)
+		sent := 0
+		source := &mocks2.QueueMock{SendMessageWithAttributesFunc: func(context.Context, string, map[string]*awsSqs.MessageAttributeValue) error {
+			if sent++; sent > 1 {
+				return errors.New("sqs is down")
+			}
+			return nil
+		}}
+
+		handler := handlers.NewInternalHandler(sqs.QueueClientMapping{sqs.BcUnprocessedPaymentsDlq: dlq, sqs.BcUnprocessedPayments: source}, false, ufx_downloader.UfxDownloader{})
+
+		res := redrive(handler, "bc-unprocessed-dlq", `{"all": true}`)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+		result := decodeResult(is, res)
+		is.Equal(result.Count, 1)
+		is.Equal(len(dlq.DeleteMessageCalls()), 1)
+		is.Equal(len(dlq.ChangeMessageVisibilityCalls()), 1)
+	})
+}
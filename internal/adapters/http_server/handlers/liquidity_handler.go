@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type LiquidityHandler struct {
+	reportLiquidity ports.ReportLiquidity
+}
+
+func NewLiquidityHandler(reportLiquidity ports.ReportLiquidity) *LiquidityHandler {
+	return &LiquidityHandler{
+		reportLiquidity: reportLiquidity,
+	}
+}
+
+// GetLiquidity reports the balance, reserved funds and free headroom of the source accounts of the currencies in the
+// query, as in `?currency=EUR&currency=GBP`, or of the ones with outstanding reservations.
+func (h *LiquidityHandler) GetLiquidity(w http.ResponseWriter, r *http.Request) {
+	var currencies []models.CurrencyCode
+	for _, currency := range r.URL.Query()["currency"] {
+		currencies = append(currencies, models.CurrencyCode(currency))
+	}
+
+	headroom, err := h.reportLiquidity.Headroom(r.Context(), currencies)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to report the liquidity: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(headroom)
+}
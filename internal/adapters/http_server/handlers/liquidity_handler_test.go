@@ -0,0 +1,51 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestLiquidityHandler(t *testing.T) {
+	t.Run("reports the headroom of the currencies in the query", func(t *testing.T) {
+		is := is.New(t)
+		headroom := models.NewLiquidityHeadroom(models.EUR, false, 1000, 200, 300)
+		reportLiquidity := &mocks.ReportLiquidityMock{HeadroomFunc: func(ctx context.Context, currencies []models.CurrencyCode) ([]models.LiquidityHeadroom, error) {
+			return []models.LiquidityHeadroom{headroom}, nil
+		}}
+
+		res := httptest.NewRecorder()
+		handlers.NewLiquidityHandler(reportLiquidity).GetLiquidity(res, httptest.NewRequest(http.MethodGet, "/liquidity?currency=EUR&currency=GBP", nil))
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(reportLiquidity.HeadroomCalls()[0].Currencies, []models.CurrencyCode{models.EUR, models.GBP})
+		var reported []models.LiquidityHeadroom
+		is.NoErr(json.NewDecoder(res.Body).Decode(&reported))
+		is.Equal(reported, []models.LiquidityHeadroom{headroom})
+		is.Equal(reported[0].Free, float64(900))
+	})
+
+	t.Run("fails when the headroom can't be reported", func(t *testing.T) {
+		is := is.New(t)
+		reportLiquidity := &mocks.ReportLiquidityMock{HeadroomFunc: func(ctx context.Context, currencies []models.CurrencyCode) ([]models.LiquidityHeadroom, error) {
+			return nil, errors.New("oops")
+		}}
+
+		res := httptest.NewRecorder()
+		handlers.NewLiquidityHandler(reportLiquidity).GetLiquidity(res, httptest.NewRequest(http.MethodGet, "/liquidity", nil))
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type PaymentBatchHandler struct {
+	submitPaymentBatch ports.SubmitPaymentBatch
+}
+
+func NewPaymentBatchHandler(submitPaymentBatch ports.SubmitPaymentBatch) *PaymentBatchHandler {
+	return &PaymentBatchHandler{
+		submitPaymentBatch: submitPaymentBatch,
+	}
+}
+
+// PostPaymentBatch accepts the incoming instructions of the request as a batch and responds with it while its
+// payments are made, their outcomes can be followed with GetPaymentBatch.
+func (p *PaymentBatchHandler) PostPaymentBatch(w http.ResponseWriter, r *http.Request) {
+	var instructions models.IncomingInstructions
+	if err := json.NewDecoder(r.Body).Decode(&instructions); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	batch, err := p.submitPaymentBatch.Submit(r.Context(), instructions, r.Header.Get(idempotencyKeyHeader))
+	if err != nil {
+		if rejectedError, isRejectedErr := err.(models.PaymentBatchRejectedError); isRejectedErr {
+			http.Error(w, rejectedError.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to submit payment batch: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	setJSON(w)
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(batch)
+}
+
+// GetPaymentBatch reports the outcome of every item of a batch, items still being processed are PENDING.
+func (p *PaymentBatchHandler) GetPaymentBatch(w http.ResponseWriter, r *http.Request) {
+	id := models.PaymentBatchID(mux.Vars(r)["id"])
+
+	batch, err := p.submitPaymentBatch.Get(r.Context(), id)
+	if err != nil {
+		if missingError, isMissingErr := err.(postgresql.PaymentBatchMissingError); isMissingErr {
+			http.Error(w, missingError.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to get payment batch: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(batch)
+}
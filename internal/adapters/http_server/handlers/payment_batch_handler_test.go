@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestPaymentBatchHandler_PostPaymentBatch(t *testing.T) {
+	t.Run("submits the incoming instructions with the idempotency key and accepts the batch", func(t *testing.T) {
+		is := is.New(t)
+		submitPaymentBatch := &mocks.SubmitPaymentBatchMock{
+			SubmitFunc: func(ctx context.Context, instructions models.IncomingInstructions, idempotencyKey string) (models.PaymentBatch, error) {
+				return models.NewPaymentBatch(instructions), nil
+			},
+		}
+
+		body, err := json.Marshal(models.IncomingInstructions{
+			testhelpers.NewIncomingInstructionBuilder().Build(),
+			testhelpers.NewIncomingInstructionBuilder().Build(),
+		})
+		is.NoErr(err)
+		req := httptest.NewRequest(http.MethodPost, "/payments/batches", strings.NewReader(string(body)))
+		req.Header.Set("Idempotency-Key", "some-idempotency-key")
+		res := httptest.NewRecorder()
+		handlers.NewPaymentBatchHandler(submitPaymentBatch).PostPaymentBatch(res, req)
+
+		is.Equal(res.Code, http.StatusAccepted)
+		is.Equal(len(submitPaymentBatch.SubmitCalls()[0].Instructions), 2)
+		is.Equal(submitPaymentBatch.SubmitCalls()[0].IdempotencyKey, "some-idempotency-key")
+
+		var batch models.PaymentBatch
+		is.NoErr(json.NewDecoder(res.Body).Decode(&batch))
+		is.True(batch.ID != "")
+		is.Equal(batch.State, models.PaymentBatchProcessing)
+		is.Equal(batch.Items[0].Status, models.PaymentBatchItemPending)
+		is.Equal(batch.Items[1].Status, models.PaymentBatchItemPending)
+	})
+
+	t.Run("returns a bad request when the batch is rejected", func(t *testing.T) {
+		is := is.New(t)
+		submitPaymentBatch := &mocks.SubmitPaymentBatchMock{
+			SubmitFunc: func(ctx context.Context, instructions models.IncomingInstructions, idempotencyKey string) (models.PaymentBatch, error) {
+				return models.PaymentBatch{}, models.PaymentBatchRejectedError{Reason: "mixed high risk"}
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/payments/batches", strings.NewReader("[]"))
+		res := httptest.NewRecorder()
+		handlers.NewPaymentBatchHandler(submitPaymentBatch).PostPaymentBatch(res, req)
+
+		is.Equal(res.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns a bad request, when bad JSON is sent, and doesnt submit anything", func(t *testing.T) {
+		is := is.New(t)
+		submitPaymentBatch := &mocks.SubmitPaymentBatchMock{}
+
+		req := httptest.NewRequest(http.MethodPost, "/payments/batches", strings.NewReader("garbage"))
+		res := httptest.NewRecorder()
+		handlers.NewPaymentBatchHandler(submitPaymentBatch).PostPaymentBatch(res, req)
+
+		is.Equal(res.Code, http.StatusBadRequest)
+		is.Equal(len(submitPaymentBatch.SubmitCalls()), 0)
+	})
+}
+
+func TestPaymentBatchHandler_GetPaymentBatch(t *testing.T) {
+	t.Run("returns 404 if the batch doesn't exist", func(t *testing.T) {
+		is := is.New(t)
+		submitPaymentBatch := &mocks.SubmitPaymentBatchMock{
+			GetFunc: func(ctx context.Context, id models.PaymentBatchID) (models.PaymentBatch, error) {
+				return models.PaymentBatch{}, postgresql.PaymentBatchMissingError{ID: id}
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/payments/batches/unknown", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "unknown"})
+		res := httptest.NewRecorder()
+		handlers.NewPaymentBatchHandler(submitPaymentBatch).GetPaymentBatch(res, req)
+
+		is.Equal(res.Code, http.StatusNotFound)
+		is.Equal(submitPaymentBatch.GetCalls()[0].ID, models.PaymentBatchID("unknown"))
+	})
+}
@@ -22,6 +22,9 @@ type PaymentHandler struct {
 	getBCRejectionReport  ports2.GetBankingCircleRejectionReport
 }
 
+// idempotencyKeyHeader lets a client retry a payment request without making the payment twice.
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type paymentResponse struct {
 	ID string `json:"id"`
 }
@@ -46,9 +49,14 @@ func (p *PaymentHandler) PostPaymentInstructions(w http.ResponseWriter, r *http.
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	incomingInstruction.IdempotencyKey = r.Header.Get(idempotencyKeyHeader)
 
 	id, err := p.makePayment.Execute(r.Context(), incomingInstruction)
 	if err != nil {
+		if conflictError, isConflictErr := err.(models.IdempotencyKeyConflictError); isConflictErr {
+			http.Error(w, conflictError.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
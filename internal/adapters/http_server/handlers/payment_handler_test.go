@@ -72,6 +72,42 @@ func TestPaymentHandler_PostPaymentInstructions(t *testing.T) {
 		is.Equal(res.Code, http.StatusInternalServerError)
 	})
 
+	t.Run("passes the idempotency key header on to the payment port", func(t *testing.T) {
+		is := is.New(t)
+
+		spyMakePaymentUseCase := mocks.MakePaymentMock{ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+			return "someID", nil
+		}}
+		handler := handlers.NewPaymentHandler(&spyMakePaymentUseCase, &mocks.GetPaymentInstructionMock{}, nil, nil)
+
+		req := createPaymentRequest(testhelpers.NewIncomingInstructionBuilder().Build())
+		req.Header.Set("Idempotency-Key", "some-idempotency-key")
+		res := httptest.NewRecorder()
+
+		handler.PostPaymentInstructions(res, req)
+
+		is.Equal(res.Code, http.StatusCreated)
+		is.Equal(len(spyMakePaymentUseCase.ExecuteCalls()), 1)
+		is.Equal(spyMakePaymentUseCase.ExecuteCalls()[0].IncomingInstruction.IdempotencyKey, "some-idempotency-key")
+	})
+
+	t.Run("returns a conflict when the idempotency key was used for a different payment", func(t *testing.T) {
+		is := is.New(t)
+
+		spyMakePaymentUseCase := mocks.MakePaymentMock{ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+			return "", models.IdempotencyKeyConflictError{Key: "some-idempotency-key", ID: "someID"}
+		}}
+		handler := handlers.NewPaymentHandler(&spyMakePaymentUseCase, &mocks.GetPaymentInstructionMock{}, nil, nil)
+
+		req := createPaymentRequest(testhelpers.NewIncomingInstructionBuilder().Build())
+		req.Header.Set("Idempotency-Key", "some-idempotency-key")
+		res := httptest.NewRecorder()
+
+		handler.PostPaymentInstructions(res, req)
+
+		is.Equal(res.Code, http.StatusConflict)
+	})
+
 	t.Run("returns a bad request, when bad JSON is sent, and doesnt call the port", func(t *testing.T) {
 		is := is.New(t)
 
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type PendingFundingHandler struct {
+	managePendingFunding ports.ManagePendingFunding
+}
+
+func NewPendingFundingHandler(managePendingFunding ports.ManagePendingFunding) *PendingFundingHandler {
+	return &PendingFundingHandler{
+		managePendingFunding: managePendingFunding,
+	}
+}
+
+type CancelPendingFundingRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (p *PendingFundingHandler) ListPendingFundings(w http.ResponseWriter, r *http.Request) {
+	state := models.PendingFundingState(r.URL.Query().Get("state"))
+
+	pendingFundings, err := p.managePendingFunding.List(r.Context(), state)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list pending fundings: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if pendingFundings == nil {
+		pendingFundings = []models.PendingFunding{}
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(pendingFundings)
+}
+
+func (p *PendingFundingHandler) GetPendingFunding(w http.ResponseWriter, r *http.Request) {
+	id := models.PendingFundingID(mux.Vars(r)["id"])
+
+	pendingFunding, err := p.managePendingFunding.Get(r.Context(), id)
+	if err != nil {
+		writePendingFundingError(w, "failed to get pending funding", err)
+		return
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(pendingFunding)
+}
+
+// ReleasePendingFunding pays a held back batch straight away, without waiting for its funds to be available.
+func (p *PendingFundingHandler) ReleasePendingFunding(w http.ResponseWriter, r *http.Request) {
+	id := models.PendingFundingID(mux.Vars(r)["id"])
+
+	pendingFunding, err := p.managePendingFunding.Release(r.Context(), id)
+	if err != nil {
+		writePendingFundingError(w, "failed to release pending funding", err)
+		return
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(pendingFunding)
+}
+
+func (p *PendingFundingHandler) CancelPendingFunding(w http.ResponseWriter, r *http.Request) {
+	id := models.PendingFundingID(mux.Vars(r)["id"])
+
+	var request CancelPendingFundingRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode request body: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if request.Reason == "" {
+		request.Reason = r.URL.Query().Get("reason")
+	}
+	if request.Reason == "" {
+		http.Error(w, "a reason is required to cancel a pending funding", http.StatusBadRequest)
+		return
+	}
+
+	pendingFunding, err := p.managePendingFunding.Cancel(r.Context(), id, request.Reason)
+	if err != nil {
+		writePendingFundingError(w, "failed to cancel pending funding", err)
+		return
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(pendingFunding)
+}
+
+func writePendingFundingError(w http.ResponseWriter, msg string, err error) {
+	switch err.(type) {
+	case postgresql.PendingFundingMissingError:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case postgresql.PendingFundingStateConflictError:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", msg, err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,137 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestPendingFundingHandler_ListPendingFundings(t *testing.T) {
+	t.Run("returns the pending fundings in the requested state", func(t *testing.T) {
+		is := is.New(t)
+		managePendingFunding := &mocks.ManagePendingFundingMock{
+			ListFunc: func(ctx context.Context, state models.PendingFundingState) ([]models.PendingFunding, error) {
+				return []models.PendingFunding{{ID: "some-id", Currency: models.EUR, State: state}}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/pending-funding?state=PENDING_FUNDING", nil)
+		res := httptest.NewRecorder()
+		handlers.NewPendingFundingHandler(managePendingFunding).ListPendingFundings(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(managePendingFunding.ListCalls()[0].State, models.PendingFundingPending)
+
+		var pendingFundings []models.PendingFunding
+		is.NoErr(json.NewDecoder(res.Body).Decode(&pendingFundings))
+		is.Equal(len(pendingFundings), 1)
+		is.Equal(pendingFundings[0].Currency, models.EUR)
+	})
+}
+
+func TestPendingFundingHandler_GetPendingFunding(t *testing.T) {
+	t.Run("returns 404 if the pending funding doesn't exist", func(t *testing.T) {
+		is := is.New(t)
+		managePendingFunding := &mocks.ManagePendingFundingMock{
+			GetFunc: func(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error) {
+				return models.PendingFunding{}, postgresql.PendingFundingMissingError{ID: id}
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/pending-funding/unknown", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "unknown"})
+		res := httptest.NewRecorder()
+		handlers.NewPendingFundingHandler(managePendingFunding).GetPendingFunding(res, req)
+
+		is.Equal(res.Code, http.StatusNotFound)
+	})
+}
+
+func TestPendingFundingHandler_ReleasePendingFunding(t *testing.T) {
+	t.Run("releases the pending funding", func(t *testing.T) {
+		is := is.New(t)
+		managePendingFunding := &mocks.ManagePendingFundingMock{
+			ReleaseFunc: func(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error) {
+				return models.PendingFunding{ID: id, State: models.PendingFundingReleased}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/pending-funding/some-id/release", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "some-id"})
+		res := httptest.NewRecorder()
+		handlers.NewPendingFundingHandler(managePendingFunding).ReleasePendingFunding(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(managePendingFunding.ReleaseCalls()[0].ID, models.PendingFundingID("some-id"))
+
+		var pendingFunding models.PendingFunding
+		is.NoErr(json.NewDecoder(res.Body).Decode(&pendingFunding))
+		is.Equal(pendingFunding.State, models.PendingFundingReleased)
+	})
+
+	t.Run("returns 409 if the pending funding was already released or cancelled", func(t *testing.T) {
+		is := is.New(t)
+		managePendingFunding := &mocks.ManagePendingFundingMock{
+			ReleaseFunc: func(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error) {
+				return models.PendingFunding{}, postgresql.PendingFundingStateConflictError{
+					ID:       id,
+					Expected: models.PendingFundingPending,
+					Actual:   models.PendingFundingCancelled,
+				}
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/pending-funding/some-id/release", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "some-id"})
+		res := httptest.NewRecorder()
+		handlers.NewPendingFundingHandler(managePendingFunding).ReleasePendingFunding(res, req)
+
+		is.Equal(res.Code, http.StatusConflict)
+	})
+}
+
+func TestPendingFundingHandler_CancelPendingFunding(t *testing.T) {
+	t.Run("cancels the pending funding with the given reason", func(t *testing.T) {
+		is := is.New(t)
+		managePendingFunding := &mocks.ManagePendingFundingMock{
+			CancelFunc: func(ctx context.Context, id models.PendingFundingID, reason string) (models.PendingFunding, error) {
+				return models.PendingFunding{ID: id, State: models.PendingFundingCancelled, StateReason: reason}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/pending-funding/some-id/cancel", strings.NewReader(`{"reason":"paid manually"}`))
+		req = mux.SetURLVars(req, map[string]string{"id": "some-id"})
+		res := httptest.NewRecorder()
+		handlers.NewPendingFundingHandler(managePendingFunding).CancelPendingFunding(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(managePendingFunding.CancelCalls()[0].Reason, "paid manually")
+	})
+
+	t.Run("returns 400 without a reason", func(t *testing.T) {
+		is := is.New(t)
+		managePendingFunding := &mocks.ManagePendingFundingMock{}
+
+		req := httptest.NewRequest(http.MethodPost, "/pending-funding/some-id/cancel", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "some-id"})
+		res := httptest.NewRecorder()
+		handlers.NewPendingFundingHandler(managePendingFunding).CancelPendingFunding(res, req)
+
+		is.Equal(res.Code, http.StatusBadRequest)
+		is.Equal(len(managePendingFunding.CancelCalls()), 0)
+	})
+}
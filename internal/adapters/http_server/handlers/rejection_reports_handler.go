@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type RejectionReportsHandler struct {
+	ingestRejectionReports ports.IngestRejectionReports
+}
+
+func NewRejectionReportsHandler(ingestRejectionReports ports.IngestRejectionReports) *RejectionReportsHandler {
+	return &RejectionReportsHandler{
+		ingestRejectionReports: ingestRejectionReports,
+	}
+}
+
+// ListUnmatchedRejections reports the rejections of the payment providers that couldn't be tracked on a payment
+// instruction, from the reports of the date or of yesterday's reports, the ones the daily ingestion reads.
+func (h *RejectionReportsHandler) ListUnmatchedRejections(w http.ResponseWriter, r *http.Request) {
+	reportDate, err := reportDateOf(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid report date: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	h.writeUnmatched(w, r, reportDate)
+}
+
+// IngestRejectionReports ingests the reports of the date again, e.g. once a payment instruction missing from them
+// has been found, and reports the rejections that are still unmatched.
+func (h *RejectionReportsHandler) IngestRejectionReports(w http.ResponseWriter, r *http.Request) {
+	reportDate, err := reportDateOf(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid report date: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	h.ingestRejectionReports.Ingest(r.Context(), reportDate)
+	h.writeUnmatched(w, r, reportDate)
+}
+
+func (h *RejectionReportsHandler) writeUnmatched(w http.ResponseWriter, r *http.Request, reportDate time.Time) {
+	unmatched, err := h.ingestRejectionReports.ListUnmatched(r.Context(), reportDate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list unmatched rejections: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if unmatched == nil {
+		unmatched = []models.UnmatchedRejection{}
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(unmatched)
+}
+
+func reportDateOf(r *http.Request) (time.Time, error) {
+	date, found := mux.Vars(r)["date"]
+	if !found {
+		return time.Now().UTC().AddDate(0, 0, -1).Truncate(24 * time.Hour), nil
+	}
+	return time.Parse("2006-01-02", date)
+}
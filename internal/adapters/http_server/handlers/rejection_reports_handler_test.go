@@ -0,0 +1,106 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestRejectionReportsHandler(t *testing.T) {
+	unmatched := models.UnmatchedRejection{
+		PaymentProvider: models.BankingCircle,
+		Rejection:       models.ProviderRejection{PaymentReference: "bc-reference", Amount: "1250.5", Currency: models.EUR, StatusReason: "Beneficiary account closed"},
+		Reason:          models.NoPaymentInstruction,
+	}
+	newIngestRejectionReports := func(unmatched ...models.UnmatchedRejection) *mocks.IngestRejectionReportsMock {
+		return &mocks.IngestRejectionReportsMock{
+			IngestFunc: func(ctx context.Context, reportDate time.Time) {},
+			ListUnmatchedFunc: func(ctx context.Context, reportDate time.Time) ([]models.UnmatchedRejection, error) {
+				return unmatched, nil
+			},
+		}
+	}
+
+	t.Run("lists the unmatched rejections of the reports of the date", func(t *testing.T) {
+		is := is.New(t)
+		ingestRejectionReports := newIngestRejectionReports(unmatched)
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/rejection-reports/2022-09-21/unmatched", nil), map[string]string{"date": "2022-09-21"})
+		res := httptest.NewRecorder()
+		handlers.NewRejectionReportsHandler(ingestRejectionReports).ListUnmatchedRejections(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(ingestRejectionReports.ListUnmatchedCalls()[0].ReportDate, time.Date(2022, 9, 21, 0, 0, 0, 0, time.UTC))
+		is.Equal(len(ingestRejectionReports.IngestCalls()), 0)
+		var listed []models.UnmatchedRejection
+		is.NoErr(json.NewDecoder(res.Body).Decode(&listed))
+		is.Equal(listed, []models.UnmatchedRejection{unmatched})
+	})
+
+	t.Run("lists the unmatched rejections of yesterday's reports without a date", func(t *testing.T) {
+		is := is.New(t)
+		ingestRejectionReports := newIngestRejectionReports()
+
+		req := httptest.NewRequest(http.MethodGet, "/rejection-reports/unmatched", nil)
+		res := httptest.NewRecorder()
+		handlers.NewRejectionReportsHandler(ingestRejectionReports).ListUnmatchedRejections(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(res.Body.String(), "[]\n")
+		is.Equal(ingestRejectionReports.ListUnmatchedCalls()[0].ReportDate.Format("2006-01-02"), time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"))
+	})
+
+	t.Run("ingests the reports of the date again before listing what is still unmatched", func(t *testing.T) {
+		is := is.New(t)
+		ingestRejectionReports := newIngestRejectionReports(unmatched)
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/rejection-reports/2022-09-21/ingest", nil), map[string]string{"date": "2022-09-21"})
+		res := httptest.NewRecorder()
+		handlers.NewRejectionReportsHandler(ingestRejectionReports).IngestRejectionReports(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(len(ingestRejectionReports.IngestCalls()), 1)
+		is.Equal(ingestRejectionReports.IngestCalls()[0].ReportDate, time.Date(2022, 9, 21, 0, 0, 0, 0, time.UTC))
+	})
+
+	t.Run("returns a bad request for a date that can't be read", func(t *testing.T) {
+		is := is.New(t)
+		ingestRejectionReports := newIngestRejectionReports()
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/rejection-reports/yesterday/ingest", nil), map[string]string{"date": "yesterday"})
+		res := httptest.NewRecorder()
+		handlers.NewRejectionReportsHandler(ingestRejectionReports).IngestRejectionReports(res, req)
+
+		is.Equal(res.Code, http.StatusBadRequest)
+		is.Equal(len(ingestRejectionReports.IngestCalls()), 0)
+	})
+
+	t.Run("returns an internal server error when the unmatched rejections can't be read", func(t *testing.T) {
+		is := is.New(t)
+		ingestRejectionReports := &mocks.IngestRejectionReportsMock{
+			ListUnmatchedFunc: func(ctx context.Context, reportDate time.Time) ([]models.UnmatchedRejection, error) {
+				return nil, errors.New("db is down")
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/rejection-reports/unmatched", nil)
+		res := httptest.NewRecorder()
+		handlers.NewRejectionReportsHandler(ingestRejectionReports).ListUnmatchedRejections(res, req)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+	})
+}
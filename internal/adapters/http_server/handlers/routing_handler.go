@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type RoutingHandler struct {
+	routePaymentInstruction ports.RoutePaymentInstruction
+}
+
+func NewRoutingHandler(routePaymentInstruction ports.RoutePaymentInstruction) *RoutingHandler {
+	return &RoutingHandler{
+		routePaymentInstruction: routePaymentInstruction,
+	}
+}
+
+// DryRun reports the routing rule an incoming instruction would hit, and where it would be routed, without making
+// the payment.
+func (h *RoutingHandler) DryRun(w http.ResponseWriter, r *http.Request) {
+	var incomingInstruction models.IncomingInstruction
+	if err := json.NewDecoder(r.Body).Decode(&incomingInstruction); err != nil {
+		http.Error(w, fmt.Sprintf("invalid incoming instruction: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(h.routePaymentInstruction.Route(incomingInstruction))
+}
@@ -0,0 +1,45 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/routing"
+)
+
+func TestRoutingHandler(t *testing.T) {
+	t.Run("reports the rule an incoming instruction hits", func(t *testing.T) {
+		is := is.New(t)
+		body, err := json.Marshal(testhelpers.NewIncomingInstructionBuilder().WithMetadataSender("ISB").Build())
+		is.NoErr(err)
+
+		res := httptest.NewRecorder()
+		handlers.NewRoutingHandler(routing.DefaultRules()).DryRun(res, httptest.NewRequest(http.MethodPost, "/routing/dry-run", bytes.NewReader(body)))
+
+		is.Equal(res.Code, http.StatusOK)
+		var decision models.RoutingDecision
+		is.NoErr(json.NewDecoder(res.Body).Decode(&decision))
+		is.Equal(decision, models.RoutingDecision{Rule: "islandsbanki-senders", PaymentProvider: models.Islandsbanki})
+	})
+
+	t.Run("turns down a body that isn't an incoming instruction", func(t *testing.T) {
+		is := is.New(t)
+
+		res := httptest.NewRecorder()
+		handlers.NewRoutingHandler(routing.DefaultRules()).DryRun(res, httptest.NewRequest(http.MethodPost, "/routing/dry-run", strings.NewReader("{")))
+
+		is.Equal(res.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type StuckPaymentsHandler struct {
+	sweepStuckPayments ports.SweepStuckPayments
+}
+
+func NewStuckPaymentsHandler(sweepStuckPayments ports.SweepStuckPayments) *StuckPaymentsHandler {
+	return &StuckPaymentsHandler{
+		sweepStuckPayments: sweepStuckPayments,
+	}
+}
+
+// ListStuckPayments reports the payment instructions still waiting for an outcome from their payment provider, the longest stuck first.
+func (s *StuckPaymentsHandler) ListStuckPayments(w http.ResponseWriter, r *http.Request) {
+	stuck, err := s.sweepStuckPayments.List(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list stuck payment instructions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]StuckPaymentInstructionResponse, 0, len(stuck))
+	for _, instruction := range stuck {
+		response = append(response, NewStuckPaymentInstructionResponse(instruction))
+	}
+	setJSON(w)
+	_ = json.NewEncoder(w).Encode(response)
+}
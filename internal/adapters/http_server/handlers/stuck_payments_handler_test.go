@@ -0,0 +1,86 @@
+//go:build unit
+// +build unit
+
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestStuckPaymentsHandler_ListStuckPayments(t *testing.T) {
+	t.Run("returns the stuck payment instructions and flags the ones the payment provider can't be asked about", func(t *testing.T) {
+		is := is.New(t)
+		var (
+			accepted   = testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+			unaccepted = testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+			stuckSince = time.Date(2022, 9, 19, 10, 0, 0, 0, time.UTC)
+		)
+		sweepStuckPayments := &mocks.SweepStuckPaymentsMock{
+			ListFunc: func(ctx context.Context) ([]models.StuckPaymentInstruction, error) {
+				return []models.StuckPaymentInstruction{
+					{PaymentInstruction: unaccepted, StuckSince: stuckSince},
+					{PaymentInstruction: accepted, ProviderPaymentID: "bc-payment-id", StuckSince: stuckSince.Add(time.Hour)},
+				}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/payments/stuck", nil)
+		res := httptest.NewRecorder()
+		handlers.NewStuckPaymentsHandler(sweepStuckPayments).ListStuckPayments(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		var stuck []handlers.StuckPaymentInstructionResponse
+		is.NoErr(json.NewDecoder(res.Body).Decode(&stuck))
+		is.Equal(len(stuck), 2)
+		is.Equal(stuck[0].ID, unaccepted.ID())
+		is.True(stuck[0].NeedsAttention)
+		is.Equal(stuck[0].StuckSince, stuckSince)
+		is.Equal(stuck[1].ID, accepted.ID())
+		is.Equal(stuck[1].ProviderPaymentID, models.ProviderPaymentID("bc-payment-id"))
+		is.True(!stuck[1].NeedsAttention)
+	})
+
+	t.Run("returns an empty list when nothing is stuck", func(t *testing.T) {
+		is := is.New(t)
+		sweepStuckPayments := &mocks.SweepStuckPaymentsMock{
+			ListFunc: func(ctx context.Context) ([]models.StuckPaymentInstruction, error) {
+				return nil, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/payments/stuck", nil)
+		res := httptest.NewRecorder()
+		handlers.NewStuckPaymentsHandler(sweepStuckPayments).ListStuckPayments(res, req)
+
+		is.Equal(res.Code, http.StatusOK)
+		is.Equal(res.Body.String(), "[]\n")
+	})
+
+	t.Run("returns an internal server error when the payment instructions can't be read", func(t *testing.T) {
+		is := is.New(t)
+		sweepStuckPayments := &mocks.SweepStuckPaymentsMock{
+			ListFunc: func(ctx context.Context) ([]models.StuckPaymentInstruction, error) {
+				return nil, errors.New("db is down")
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/payments/stuck", nil)
+		res := httptest.NewRecorder()
+		handlers.NewStuckPaymentsHandler(sweepStuckPayments).ListStuckPayments(res, req)
+
+		is.Equal(res.Code, http.StatusInternalServerError)
+	})
+}
@@ -10,16 +10,17 @@ import (
 )
 
 func nonLocalAuthMiddleware(handler http.Handler, authorisedUsers AuthorisedUsers, enablePostPaymentsEndpoint bool, permittedTestUsers []string) http.Handler {
-	postPaymentsAPI := regexp.MustCompile(`^/payments$`)
+	postPaymentsAPI := regexp.MustCompile(`^/payments(/batches)?$`)
 	healthCheck := regexp.MustCompile(`^/health_check$`)
 	metrics := regexp.MustCompile(`^/metrics$`)
 	members := regexp.MustCompile(`^/internal/team$`)
 	testAPI := regexp.MustCompile(`^/test`)
+	bankingCircleWebhook := regexp.MustCompile(`^/banking-circle/payment-status$`)
 	handlerWithBearerAuth := withBearerAuth(handler, authorisedUsers)
 	handlerWithTestAuthorisation := withTestAuthorisation(handler, authorisedUsers, permittedTestUsers)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// disable the POST /payments endpoint if configured so
+		// disable the POST /payments and /payments/batches endpoints if configured so
 		if postPaymentsAPI.MatchString(r.URL.Path) && !enablePostPaymentsEndpoint {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
@@ -29,6 +30,12 @@ func nonLocalAuthMiddleware(handler http.Handler, authorisedUsers AuthorisedUser
 			return
 		}
 
+		// Banking Circle signs its notifications instead, the handler checks the signature
+		if bankingCircleWebhook.MatchString(r.URL.Path) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
 		if testAPI.MatchString(r.URL.Path) {
 			handlerWithTestAuthorisation.ServeHTTP(w, r)
 			return
@@ -63,7 +70,7 @@ func withBearerAuth(handler http.Handler, authorisedUsers AuthorisedUsers) http.
 		}
 		zapctx.Info(r.Context(), "Request authenticated", zap.String("Path", r.URL.Path), zap.String("User", username), zap.String("Method", r.Method))
 
-		handler.ServeHTTP(w, r)
+		handler.ServeHTTP(w, r.WithContext(WithUser(r.Context(), username)))
 	})
 }
 
@@ -88,7 +95,7 @@ func withTestAuthorisation(handler http.Handler, authorisedUsers AuthorisedUsers
 		for _, permittedUser := range permittedTestUsers {
 			if username == permittedUser {
 				zapctx.Info(r.Context(), "Request authenticated", zap.String("Path", r.URL.Path), zap.String("User", username), zap.String("Method", r.Method))
-				handler.ServeHTTP(w, r)
+				handler.ServeHTTP(w, r.WithContext(WithUser(r.Context(), username)))
 				return
 			}
 		}
@@ -4,6 +4,7 @@
 package auth_test
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -194,6 +195,12 @@ func TestNewAuthMiddleWare(t *testing.T) {
 			assertWeHadAccess(t, res)
 		})
 
+		t.Run("banking circle reaches its webhook without a bearer token, the webhook checks its signature", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			productionAuthMiddleware.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/banking-circle/payment-status", nil))
+			assertWeHadAccess(t, res)
+		})
+
 		t.Run("unauthorised users cannot create payments", func(t *testing.T) {
 			is := is2.New(t)
 			res := httptest.NewRecorder()
@@ -337,6 +344,26 @@ func TestNewAuthMiddleWare(t *testing.T) {
 		}
 	})
 
+	t.Run("payment batches are only accepted when the payments endpoint is enabled", func(t *testing.T) {
+		for _, isEndpointEnabled := range []bool{true, false} {
+			t.Run(fmt.Sprintf("endpoint enabled: %v", isEndpointEnabled), func(t *testing.T) {
+				is := is2.New(t)
+
+				middleWare, _ := auth.NewAuthMiddleWare(env.ProductionGlobalPlatform, isEndpointEnabled, authorisedUsers, nil)
+				authMiddleware := middleWare(stubHandler)
+
+				res := httptest.NewRecorder()
+				authMiddleware.ServeHTTP(res, reqWithBearer(httptest.NewRequest(http.MethodPost, "/payments/batches", nil), bearerToken))
+
+				if isEndpointEnabled {
+					assertWeHadAccess(t, res)
+				} else {
+					is.Equal(res.Code, http.StatusUnauthorized)
+				}
+			})
+		}
+	})
+
 	t.Run("authorised user is allowed to access test endpoints", func(t *testing.T) {
 		// Given a user with a token
 		username := "testUser"
@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type userKey struct{}
+
+// WithUser keeps the authenticated user in the context of the request, for the handlers that audit who did what.
+func WithUser(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, userKey{}, username)
+}
+
+// User is the user who made the request, empty when it wasn't authenticated, as in local environments.
+func User(ctx context.Context) string {
+	username, _ := ctx.Value(userKey{}).(string)
+	return username
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/saltpay/settlements-payments-system/internal/adapters/aws/ufx_downloader"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/handlers"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/http_server/middleware"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/dead_letters"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
@@ -36,10 +37,31 @@ func NewSettlementsPaymentsServer(
 	allowSqsPurge bool,
 	ufxDownloader ufx_downloader.UfxDownloader,
 	ufxUploader aws.UfxFileUploader,
+	ufxFileLedger ports.UfxFileLedger,
+	managePendingFunding ports.ManagePendingFunding,
+	sweepStuckPayments ports.SweepStuckPayments,
+	submitPaymentBatch ports.SubmitPaymentBatch,
+	receiveBCPaymentStatus ports2.ReceiveBankingCirclePaymentStatus,
+	bankingCircleWebhookSecret string,
+	ingestRejectionReports ports.IngestRejectionReports,
+	routePaymentInstruction ports.RoutePaymentInstruction,
+	reportLiquidity ports.ReportLiquidity,
+	dlqRedriveAudit ports.DlqRedriveAudit,
+	deadLetterTopics dead_letters.TopicMapping,
 ) (server *http.Server) {
 	paymentHandler := handlers.NewPaymentHandler(makePayment, getPaymentInstruction, getPaymentReport, getBCRejectionReport)
 	replayPaymentHandler := handlers.NewReplayPaymentHandler(replayPayment)
+	fileHandler := handlers.NewFileHandler(ufxFileLedger)
+	pendingFundingHandler := handlers.NewPendingFundingHandler(managePendingFunding)
+	stuckPaymentsHandler := handlers.NewStuckPaymentsHandler(sweepStuckPayments)
+	paymentBatchHandler := handlers.NewPaymentBatchHandler(submitPaymentBatch)
+	bankingCircleWebhookHandler := handlers.NewBankingCircleWebhookHandler(receiveBCPaymentStatus, bankingCircleWebhookSecret)
+	rejectionReportsHandler := handlers.NewRejectionReportsHandler(ingestRejectionReports)
+	routingHandler := handlers.NewRoutingHandler(routePaymentInstruction)
+	liquidityHandler := handlers.NewLiquidityHandler(reportLiquidity)
 	internalHandler := handlers.NewInternalHandler(queues, allowSqsPurge, ufxDownloader)
+	internalHandler.AuditRedrivesWith(dlqRedriveAudit)
+	deadLetterTopicHandler := handlers.NewDeadLetterTopicHandler(deadLetterTopics, dlqRedriveAudit)
 	testHandler := tests.NewHandler(ufxUploader)
 
 	tracing.Enable(r, mainConfig.TKI())
@@ -50,17 +72,39 @@ func NewSettlementsPaymentsServer(
 	r.Handle("/payments/report/{date}", http.HandlerFunc(paymentHandler.GetReport)).Methods(http.MethodGet)
 	r.Handle("/payments/currencies-report", http.HandlerFunc(paymentHandler.GetCurrencyReport)).Methods(http.MethodGet)
 	r.Handle("/payments/currencies-report/{date}", http.HandlerFunc(paymentHandler.GetCurrencyReport)).Methods(http.MethodGet)
+	r.Handle("/payments/stuck", http.HandlerFunc(stuckPaymentsHandler.ListStuckPayments)).Methods(http.MethodGet)
+	r.Handle("/payments/batches", http.HandlerFunc(paymentBatchHandler.PostPaymentBatch)).Methods(http.MethodPost)
+	r.Handle("/payments/batches/{id}", http.HandlerFunc(paymentBatchHandler.GetPaymentBatch)).Methods(http.MethodGet)
 	r.Handle("/payments/{id}", http.HandlerFunc(paymentHandler.GetPaymentInstruction)).Methods(http.MethodGet)
 	r.Handle("/payments/correlationId/{correlationId}", http.HandlerFunc(paymentHandler.GetPaymentInstructionByCorrelationID)).Methods(http.MethodGet)
 	r.Handle("/mid/{mid}/{date}", http.HandlerFunc(paymentHandler.GetInstructionByMid)).Methods(http.MethodGet)
 
 	r.Handle("/bc-report", http.HandlerFunc(paymentHandler.GetBCReport)).Methods(http.MethodGet)
 	r.Handle("/bc-report/{date}", http.HandlerFunc(paymentHandler.GetBCReport)).Methods(http.MethodGet)
+	r.Handle("/rejection-reports/unmatched", http.HandlerFunc(rejectionReportsHandler.ListUnmatchedRejections)).Methods(http.MethodGet)
+	r.Handle("/rejection-reports/{date}/unmatched", http.HandlerFunc(rejectionReportsHandler.ListUnmatchedRejections)).Methods(http.MethodGet)
+	r.Handle("/rejection-reports/{date}/ingest", http.HandlerFunc(rejectionReportsHandler.IngestRejectionReports)).Methods(http.MethodPost)
+
+	r.Handle("/liquidity", http.HandlerFunc(liquidityHandler.GetLiquidity)).Methods(http.MethodGet)
+	r.Handle("/routing/dry-run", http.HandlerFunc(routingHandler.DryRun)).Methods(http.MethodPost)
+
+	r.Handle("/banking-circle/payment-status", http.HandlerFunc(bankingCircleWebhookHandler.PostPaymentStatus)).Methods(http.MethodPost)
+
+	r.Handle("/files", http.HandlerFunc(fileHandler.ListFiles)).Methods(http.MethodGet)
+	r.Handle("/files/{name}", http.HandlerFunc(fileHandler.GetFile)).Methods(http.MethodGet)
+
+	r.Handle("/pending-funding", http.HandlerFunc(pendingFundingHandler.ListPendingFundings)).Methods(http.MethodGet)
+	r.Handle("/pending-funding/{id}", http.HandlerFunc(pendingFundingHandler.GetPendingFunding)).Methods(http.MethodGet)
+	r.Handle("/pending-funding/{id}/release", http.HandlerFunc(pendingFundingHandler.ReleasePendingFunding)).Methods(http.MethodPost)
+	r.Handle("/pending-funding/{id}/cancel", http.HandlerFunc(pendingFundingHandler.CancelPendingFunding)).Methods(http.MethodPost)
 
 	r.Handle("/replay-payment", http.HandlerFunc(replayPaymentHandler.ReplayMissingFundsPayments)).Queries("action", "{action}", "currency", "{currency}", "file", "{file}").Methods(http.MethodPost)
 
 	r.Handle("/internal/dead-letter-queues/{name}", http.HandlerFunc(internalHandler.GetDlqInformation)).Methods(http.MethodGet)
+	r.Handle("/internal/dead-letter-queues/{name}/redrive", http.HandlerFunc(internalHandler.RedriveDlq)).Methods(http.MethodPost)
 	r.Handle("/internal/dead-letter-queues", http.HandlerFunc(internalHandler.GetDlqUrls)).Methods(http.MethodGet)
+	r.Handle("/internal/dead-letter-topics/{name}", http.HandlerFunc(deadLetterTopicHandler.GetDltInformation)).Methods(http.MethodGet)
+	r.Handle("/internal/dead-letter-topics/{name}/redrive", http.HandlerFunc(deadLetterTopicHandler.RedriveDlt)).Methods(http.MethodPost)
 	r.Handle("/internal/queues/{name}", http.HandlerFunc(internalHandler.PurgeQueue)).Queries("action", "{action}").Methods(http.MethodPost)
 	r.Handle("/internal/queues/{name}/attributes", http.HandlerFunc(internalHandler.GetQueueAttributes)).Methods(http.MethodGet)
 	r.Handle("/internal/ufx-file/{filetype}", http.HandlerFunc(internalHandler.DownloadUfxFile)).Methods(http.MethodGet)
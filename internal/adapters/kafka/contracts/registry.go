@@ -0,0 +1,161 @@
+package contracts
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/saltpay/go-kafka-driver"
+)
+
+// VersionHeader is the header with the schema version of a message, a message without it is of the first version.
+const VersionHeader = "x-schema-version"
+
+// Subject is a kind of message, each version of its schema lives in schemas/<subject>/v<version>.json.
+type Subject string
+
+const (
+	IncomingInstruction  Subject = "incoming-instruction"
+	PaymentStateUpdate   Subject = "payment-state-update"
+	PaymentProviderEvent Subject = "payment-provider-event"
+	PaymentAck           Subject = "payment-ack"
+)
+
+//go:embed schemas/*/*.json
+var schemaFiles embed.FS
+
+// Schemas are the schemas of the messages of the topics this service reads and writes.
+var Schemas = mustLoadRegistry(schemaFiles)
+
+// Registry is the local stand-in of a schema registry, a schema version is never changed once it is released, a
+// change that breaks the consumers of a subject is a new version of it.
+type Registry struct {
+	schemas map[Subject]map[int]*Schema
+}
+
+// LoadRegistry reads the schemas of the subjects, from the files at schemas/<subject>/v<version>.json.
+func LoadRegistry(files fs.FS) (*Registry, error) {
+	paths, err := fs.Glob(files, "schemas/*/v*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &Registry{schemas: map[Subject]map[int]*Schema{}}
+	for _, file := range paths {
+		subject := Subject(path.Base(path.Dir(file)))
+		version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path.Base(file), "v"), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the version of %s, err: %w", file, err)
+		}
+
+		content, err := fs.ReadFile(files, file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s, err: %w", file, err)
+		}
+		var schema Schema
+		if err := json.Unmarshal(content, &schema); err != nil {
+			return nil, fmt.Errorf("unable to parse %s, err: %w", file, err)
+		}
+		if err := schema.compile(); err != nil {
+			return nil, fmt.Errorf("unable to compile %s, err: %w", file, err)
+		}
+
+		if registry.schemas[subject] == nil {
+			registry.schemas[subject] = map[int]*Schema{}
+		}
+		registry.schemas[subject][version] = &schema
+	}
+
+	return registry, nil
+}
+
+func mustLoadRegistry(files fs.FS) *Registry {
+	registry, err := LoadRegistry(files)
+	if err != nil {
+		panic(fmt.Sprintf("unable to load the message schemas, err: %s", err))
+	}
+	return registry
+}
+
+func (r *Registry) Schema(subject Subject, version int) (*Schema, error) {
+	schema, exists := r.schemas[subject][version]
+	if !exists {
+		return nil, fmt.Errorf("%s has no schema version %d", subject, version)
+	}
+	return schema, nil
+}
+
+// Versions are the schema versions of the subject, oldest first.
+func (r *Registry) Versions(subject Subject) []int {
+	versions := make([]int, 0, len(r.schemas[subject]))
+	for version := range r.schemas[subject] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Latest is the schema version the messages of the subject are written in.
+func (r *Registry) Latest(subject Subject) int {
+	versions := r.Versions(subject)
+	if len(versions) == 0 {
+		return 0
+	}
+	return versions[len(versions)-1]
+}
+
+// Validate checks the message against the schema of its version, and returns the version to read it as.
+func (r *Registry) Validate(subject Subject, message kafka.Message) (int, error) {
+	version, err := Version(message)
+	if err != nil {
+		return 0, err
+	}
+	schema, err := r.Schema(subject, version)
+	if err != nil {
+		return version, err
+	}
+	return version, schema.Validate(message.Value)
+}
+
+// Message writes the value as a message of the latest version of the subject, it fails when the value doesn't match
+// its schema so that no consumer is sent a message it can't read.
+func (r *Registry) Message(subject Subject, value interface{}) (kafka.Message, error) {
+	version := r.Latest(subject)
+	schema, err := r.Schema(subject, version)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("unable to marshal the %s message, err: %w", subject, err)
+	}
+	if err := schema.Validate(body); err != nil {
+		return kafka.Message{}, fmt.Errorf("invalid %s message, err: %w", subject, err)
+	}
+
+	return kafka.Message{
+		Value:   body,
+		Headers: []kafka.Header{{Key: VersionHeader, Value: []byte(strconv.Itoa(version))}},
+	}, nil
+}
+
+// Version is the schema version of the message.
+func Version(message kafka.Message) (int, error) {
+	for _, h := range message.Headers {
+		if h.Key != VersionHeader {
+			continue
+		}
+		version, err := strconv.Atoi(string(h.Value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid schema version %q, err: %w", h.Value, err)
+		}
+		return version, nil
+	}
+	return 1, nil
+}
@@ -0,0 +1,102 @@
+//go:build unit
+// +build unit
+
+package contracts_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/saltpay/go-kafka-driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
+)
+
+// releasedSchemas are the schema versions consumers may be reading, a change to one of them breaks its consumers: it
+// has to be a new version of the schema instead. A new version is added here once it is released.
+var releasedSchemas = map[string]string{
+	"incoming-instruction/v1.json":   "b706e992fd8d7f78cb6be11a80523a575e1d88f09eae6286f5f2f9f1cb49f32b",
+	"incoming-instruction/v2.json":   "acc65cd9e35aa696d2fd2927f82d35e574d50a4d4decdb5debff7f3a7d0780b4",
+	"payment-state-update/v1.json":   "94f8294f41b9bb2b455883ce4f2d852ff3dd890b581df6a2ec296a3dabac3fd9",
+	"payment-state-update/v2.json":   "21ac8b999f62b9d51498f9018cb1f40f27feebe6bf5a2c381e02dc2a46ab5cbd",
+	"payment-provider-event/v1.json": "fa32a983551c099d172af8c23cf04b8d257d34314f554bfc4504ceb22b391241",
+	"payment-ack/v1.json":            "8cb001e60f11eadbc3cc57589775f885f3d84d1f485ffa4a5b3511b2d245fd24",
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("has every version of the subjects", func(t *testing.T) {
+		assert.Equal(t, []int{1, 2}, contracts.Schemas.Versions(contracts.IncomingInstruction))
+		assert.Equal(t, []int{1, 2}, contracts.Schemas.Versions(contracts.PaymentStateUpdate))
+		assert.Equal(t, []int{1}, contracts.Schemas.Versions(contracts.PaymentProviderEvent))
+		assert.Equal(t, []int{1}, contracts.Schemas.Versions(contracts.PaymentAck))
+		assert.Equal(t, 2, contracts.Schemas.Latest(contracts.IncomingInstruction))
+	})
+
+	t.Run("doesn't change a released schema version", func(t *testing.T) {
+		files, err := filepath.Glob("schemas/*/v*.json")
+		require.NoError(t, err)
+		require.Len(t, files, len(releasedSchemas), "every schema version should be in releasedSchemas")
+
+		for _, file := range files {
+			name, err := filepath.Rel("schemas", file)
+			require.NoError(t, err)
+			content, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			released, exists := releasedSchemas[filepath.ToSlash(name)]
+			require.True(t, exists, "%s isn't released, add it to releasedSchemas", name)
+			assert.Equal(t, released, fmt.Sprintf("%x", sha256.Sum256(content)), "%s was released, make the change a new version instead", name)
+		}
+	})
+
+	t.Run("validates a message against the schema of its version", func(t *testing.T) {
+		v1 := kafka.Message{Value: []byte(`{"updated_state": "PROCESSED"}`)}
+		version, err := contracts.Schemas.Validate(contracts.PaymentStateUpdate, v1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, version, "a message without a version is of the first version")
+
+		v2 := kafka.Message{
+			Value:   []byte(`{"updated_state": "PROCESSED"}`),
+			Headers: []kafka.Header{{Key: contracts.VersionHeader, Value: []byte("2")}},
+		}
+		version, err = contracts.Schemas.Validate(contracts.PaymentStateUpdate, v2)
+		assert.Equal(t, 2, version)
+		assert.ErrorContains(t, err, "$.paymentInstructionId: is required")
+	})
+
+	t.Run("doesn't validate a message of an unknown version", func(t *testing.T) {
+		message := kafka.Message{Value: []byte(`{}`), Headers: []kafka.Header{{Key: contracts.VersionHeader, Value: []byte("9")}}}
+
+		_, err := contracts.Schemas.Validate(contracts.PaymentStateUpdate, message)
+
+		assert.ErrorContains(t, err, "payment-state-update has no schema version 9")
+	})
+
+	t.Run("writes a message in the latest version of its subject", func(t *testing.T) {
+		message, err := contracts.Schemas.Message(contracts.PaymentAck, map[string]interface{}{
+			"correlationId":  "some-correlation-id",
+			"outcome":        "ACCEPTED",
+			"acknowledgedAt": "2022-09-30T10:00:00Z",
+		})
+
+		require.NoError(t, err)
+		version, err := contracts.Version(message)
+		require.NoError(t, err)
+		assert.Equal(t, 1, version)
+	})
+
+	t.Run("doesn't write a message that doesn't match its schema", func(t *testing.T) {
+		_, err := contracts.Schemas.Message(contracts.PaymentAck, map[string]interface{}{
+			"correlationId": "some-correlation-id",
+			"outcome":       "MAYBE",
+		})
+
+		assert.ErrorContains(t, err, "$.outcome: MAYBE is not one of")
+		assert.ErrorContains(t, err, "$.acknowledgedAt: is required")
+	})
+}
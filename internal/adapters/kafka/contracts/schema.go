@@ -0,0 +1,222 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is the part of JSON Schema the contracts are written in: types, required and nested properties, items,
+// enums, string formats and lengths. A schema with a keyword it doesn't know fails to load, rather than let through
+// the messages the keyword would reject.
+type Schema struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Type        schemaTypes        `json:"type"`
+	Required    []string           `json:"required"`
+	Properties  map[string]*Schema `json:"properties"`
+	Items       *Schema            `json:"items"`
+	Enum        []interface{}      `json:"enum"`
+	Format      string             `json:"format"`
+	Pattern     string             `json:"pattern"`
+	MinLength   *int               `json:"minLength"`
+
+	pattern     *regexp.Regexp
+	unsupported []string
+}
+
+// supportedKeywords are the keywords of the fields of Schema, and the ones that only describe the schema.
+var supportedKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "examples": true,
+	"type": true, "required": true, "properties": true, "items": true, "enum": true, "format": true, "pattern": true,
+	"minLength": true,
+}
+
+// supportedFormats are the string formats validateString checks.
+var supportedFormats = map[string]bool{"": true, "date-time": true, "date": true}
+
+func (s *Schema) UnmarshalJSON(in []byte) error {
+	type schema Schema
+	if err := json.Unmarshal(in, (*schema)(s)); err != nil {
+		return err
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(in, &keywords); err != nil {
+		return err
+	}
+	for keyword := range keywords {
+		if !supportedKeywords[keyword] {
+			s.unsupported = append(s.unsupported, keyword)
+		}
+	}
+	sort.Strings(s.unsupported)
+	return nil
+}
+
+// schemaTypes is the type of a schema, one type or any of a list of types as in `["string", "null"]`.
+type schemaTypes []string
+
+func (s *schemaTypes) UnmarshalJSON(in []byte) error {
+	var one string
+	if err := json.Unmarshal(in, &one); err == nil {
+		*s = schemaTypes{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(in, &many); err != nil {
+		return fmt.Errorf("type should be a string or a list of strings, err: %w", err)
+	}
+	*s = many
+	return nil
+}
+
+// ValidationError lists every way a message doesn't match its schema, with the path of the value at fault.
+type ValidationError struct {
+	Errors []string
+}
+
+func (v ValidationError) Error() string {
+	return fmt.Sprintf("message doesn't match its schema: %s", strings.Join(v.Errors, "; "))
+}
+
+func (s *Schema) compile() error {
+	if len(s.unsupported) > 0 {
+		return fmt.Errorf("unsupported keywords %s", strings.Join(s.unsupported, ", "))
+	}
+	if !supportedFormats[s.Format] {
+		return fmt.Errorf("unsupported format %q", s.Format)
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("unable to compile pattern %q, err: %w", s.Pattern, err)
+		}
+		s.pattern = pattern
+	}
+	for name, property := range s.Properties {
+		if err := property.compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// Validate checks the JSON document against the schema.
+func (s *Schema) Validate(document []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(document, &value); err != nil {
+		return ValidationError{Errors: []string{fmt.Sprintf("$: is not JSON: %s", err)}}
+	}
+
+	errs := s.validate("$", value, nil)
+	if len(errs) > 0 {
+		return ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, value interface{}, errs []string) []string {
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		return append(errs, fmt.Sprintf("%s: should be %s, is %s", path, strings.Join(s.Type, " or "), typeOf(value)))
+	}
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		errs = append(errs, fmt.Sprintf("%s: %v is not one of %v", path, value, s.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, exists := v[name]; !exists {
+				errs = append(errs, fmt.Sprintf("%s.%s: is required", path, name))
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, described := s.Properties[name]; described {
+				errs = property.validate(path+"."+name, v[name], errs)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				errs = s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case string:
+		errs = s.validateString(path, v, errs)
+	}
+
+	return errs
+}
+
+func (s *Schema) validateString(path, value string, errs []string) []string {
+	if s.MinLength != nil && utf8.RuneCountInString(value) < *s.MinLength {
+		errs = append(errs, fmt.Sprintf("%s: should be at least %d characters", path, *s.MinLength))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		errs = append(errs, fmt.Sprintf("%s: %q doesn't match %s", path, value, s.Pattern))
+	}
+
+	var err error
+	switch s.Format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339Nano, value)
+	case "date":
+		_, err = time.Parse("2006-01-02", value)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("%s: %q is not a %s", path, value, s.Format))
+	}
+	return errs
+}
+
+func (s *Schema) inEnum(value interface{}) bool {
+	for _, allowed := range s.Enum {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (t schemaTypes) matches(value interface{}) bool {
+	for _, schemaType := range t {
+		if schemaType == typeOf(value) || schemaType == "number" && typeOf(value) == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,136 @@
+//go:build unit
+// +build unit
+
+package contracts_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	schema := mustParseSchema(t, `{
+		"type": "object",
+		"required": ["id", "payment"],
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"note": {"type": ["string", "null"]},
+			"count": {"type": "integer"},
+			"payment": {
+				"type": "object",
+				"required": ["amount"],
+				"properties": {
+					"amount": {"type": "string", "pattern": "^[0-9]+(\\.[0-9]+)?$"},
+					"state": {"type": "string", "enum": ["PROCESSED", "FAILURE"]},
+					"executionDate": {"type": "string", "format": "date"},
+					"createdOn": {"type": "string", "format": "date-time"}
+				}
+			},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		}
+	}`)
+
+	t.Run("accepts a document that matches the schema", func(t *testing.T) {
+		err := schema.Validate([]byte(`{
+			"id": "some-id",
+			"note": null,
+			"count": 2,
+			"payment": {"amount": "12.30", "state": "PROCESSED", "executionDate": "2022-09-30", "createdOn": "2022-09-30T10:00:00.123Z"},
+			"tags": ["a", "b"],
+			"undescribed": {"anything": true}
+		}`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("lists every way a document doesn't match the schema with where", func(t *testing.T) {
+		err := schema.Validate([]byte(`{
+			"id": "",
+			"count": 2.5,
+			"payment": {"amount": "12,30", "state": "PENDING", "executionDate": "2022-09-30T10:00:00Z", "createdOn": "yesterday"},
+			"tags": ["a", 1]
+		}`))
+
+		var validationErr contracts.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ElementsMatch(t, []string{
+			"$.id: should be at least 1 characters",
+			"$.count: should be integer, is number",
+			`$.payment.amount: "12,30" doesn't match ^[0-9]+(\.[0-9]+)?$`,
+			"$.payment.state: PENDING is not one of [PROCESSED FAILURE]",
+			`$.payment.executionDate: "2022-09-30T10:00:00Z" is not a date`,
+			`$.payment.createdOn: "yesterday" is not a date-time`,
+			"$.tags[1]: should be string, is integer",
+		}, validationErr.Errors)
+	})
+
+	t.Run("counts the characters of a string, not its bytes", func(t *testing.T) {
+		schema := mustParseSchema(t, `{"type": "string", "minLength": 3}`)
+
+		assert.NoError(t, schema.Validate([]byte(`"€€€"`)))
+		err := schema.Validate([]byte(`"€€"`))
+
+		var validationErr contracts.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []string{"$: should be at least 3 characters"}, validationErr.Errors)
+	})
+
+	t.Run("reports the required properties that are missing", func(t *testing.T) {
+		err := schema.Validate([]byte(`{"payment": {}}`))
+
+		var validationErr contracts.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ElementsMatch(t, []string{"$.id: is required", "$.payment.amount: is required"}, validationErr.Errors)
+	})
+
+	t.Run("rejects a document that isn't JSON", func(t *testing.T) {
+		err := schema.Validate([]byte("invalidJSON"))
+
+		assert.ErrorContains(t, err, "is not JSON")
+	})
+
+	t.Run("rejects a document of another type", func(t *testing.T) {
+		err := schema.Validate([]byte(`["some-id"]`))
+
+		assert.ErrorContains(t, err, "$: should be object, is array")
+	})
+}
+
+func TestLoadRegistry(t *testing.T) {
+	for _, document := range []string{
+		`{"type": "object", "additionalProperties": false}`,
+		`{"type": "object", "properties": {"payment": {"type": "object", "properties": {"amount": {"type": "number", "maximum": 10}}}}}`,
+		`{"type": "array", "items": {"oneOf": [{"type": "string"}, {"type": "integer"}]}}`,
+		`{"type": "object", "properties": {"merchant": {"$ref": "#/definitions/merchant"}}}`,
+		`{"type": "string", "format": "email"}`,
+	} {
+		t.Run("fails on a schema it can't validate: "+document, func(t *testing.T) {
+			_, err := contracts.LoadRegistry(fstest.MapFS{"schemas/test/v1.json": {Data: []byte(document)}})
+
+			assert.ErrorContains(t, err, "unable to compile schemas/test/v1.json")
+			assert.ErrorContains(t, err, "unsupported")
+		})
+	}
+
+	t.Run("says where the keyword it doesn't know is", func(t *testing.T) {
+		_, err := contracts.LoadRegistry(fstest.MapFS{"schemas/test/v1.json": {Data: []byte(
+			`{"type": "object", "properties": {"payment": {"type": "object", "properties": {"amount": {"type": "number", "maximum": 10}}}}}`,
+		)}})
+
+		assert.ErrorContains(t, err, "payment: amount: unsupported keywords maximum")
+	})
+}
+
+func mustParseSchema(t *testing.T, document string) *contracts.Schema {
+	t.Helper()
+	registry, err := contracts.LoadRegistry(fstest.MapFS{"schemas/test/v1.json": {Data: []byte(document)}})
+	require.NoError(t, err)
+	schema, err := registry.Schema("test", 1)
+	require.NoError(t, err)
+	return schema
+}
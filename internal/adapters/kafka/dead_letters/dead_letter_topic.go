@@ -0,0 +1,225 @@
+package dead_letters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/saltpay/go-kafka-driver"
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+// DeadLetterTopic is the dead letter topic of a listener, and a producer to the topic its messages failed on.
+type DeadLetterTopic struct {
+	Reader      TopicReader
+	SourceTopic string
+	Source      producers.Producer
+}
+
+type TopicMapping map[string]DeadLetterTopic
+
+type Message struct {
+	// ID is the partition and offset of the message, as in `0/42`.
+	ID            string            `json:"id"`
+	Key           string            `json:"key,omitempty"`
+	Value         string            `json:"value"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	OriginalTopic string            `json:"originalTopic,omitempty"`
+	Listener      string            `json:"listener,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	Attempts      int               `json:"attempts,omitempty"`
+	FirstFailedAt string            `json:"firstFailedAt,omitempty"`
+}
+
+type DLTInformation struct {
+	Count    int
+	Messages []Message
+}
+
+type RedriveResult struct {
+	Topic       string                      `json:"topic"`
+	SourceTopic string                      `json:"sourceTopic"`
+	DryRun      bool                        `json:"dryRun"`
+	Count       int                         `json:"count"`
+	Messages    []models.RedrivenDlqMessage `json:"messages"`
+	// AlreadyRedriven are the IDs of the selected messages an earlier redrive moved, they are not redriven again.
+	AlreadyRedriven []string `json:"alreadyRedriven,omitempty"`
+}
+
+// Peek reads the messages of the dead letter topic, with why and where they failed.
+func Peek(ctx context.Context, topic DeadLetterTopic) (DLTInformation, error) {
+	read, err := topic.Reader.ReadAll(ctx)
+	if err != nil {
+		return DLTInformation{}, err
+	}
+
+	information := DLTInformation{Messages: make([]Message, 0, len(read))}
+	for _, message := range read {
+		information.Messages = append(information.Messages, messageOf(message))
+	}
+	information.Count = len(information.Messages)
+	return information, nil
+}
+
+// Redrive writes the selected messages of the dead letter topic back to the topic they failed on, where they are
+// retried from the start. Messages can't be deleted from a topic, the redriven ones stay on the dead letter topic, so
+// each message is recorded in the audit before it is written back, and the ones an earlier or concurrent redrive
+// recorded are skipped. The redrive stops when a message can't be recorded, there is nothing to skip without an audit.
+func Redrive(ctx context.Context, name string, topic DeadLetterTopic, request models.DlqRedriveRequest, audit ports.DlqRedriveAudit) (RedriveResult, error) {
+	result := RedriveResult{Topic: name, SourceTopic: topic.SourceTopic, DryRun: request.DryRun, Messages: []models.RedrivenDlqMessage{}}
+	if err := request.ValidateSelection(); err != nil {
+		return result, err
+	}
+
+	read, err := topic.Reader.ReadAll(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	skipped := make(map[string]bool)
+	if request.DryRun && audit != nil {
+		alreadyRedriven, err := audit.RedrivenDlqMessageIDs(ctx, name)
+		if err != nil {
+			return result, fmt.Errorf("unable to read the messages redriven before, err: %w", err)
+		}
+		for _, id := range alreadyRedriven {
+			skipped[id] = true
+		}
+	}
+
+	for _, message := range read {
+		redriven := redrivenMessageOf(message)
+		if !request.Selects(redriven) {
+			continue
+		}
+		if skipped[redriven.MessageID] {
+			result.AlreadyRedriven = append(result.AlreadyRedriven, redriven.MessageID)
+			continue
+		}
+		if request.DryRun {
+			redriven.Body = string(message.Value)
+			result.Messages = append(result.Messages, redriven)
+			continue
+		}
+		if audit != nil {
+			recorded, err := audit.RecordRedrivenDlqMessage(ctx, name, redriven.MessageID)
+			if err != nil {
+				result.Count = len(result.Messages)
+				return result, fmt.Errorf("unable to record the redrive of message %s, err: %w", redriven.MessageID, err)
+			}
+			if !recorded {
+				result.AlreadyRedriven = append(result.AlreadyRedriven, redriven.MessageID)
+				continue
+			}
+		}
+
+		value := message.Value
+		if request.Body != "" {
+			value, redriven.Edited = []byte(request.Body), true
+		}
+		err := topic.Source.WriteMessage(ctx, kafka.Message{
+			Key:     message.Key,
+			Value:   value,
+			Headers: redrivenHeaders(message.Headers),
+		})
+		if err != nil {
+			forget(ctx, audit, name, redriven.MessageID)
+			result.Count = len(result.Messages)
+			return result, fmt.Errorf("unable to redrive message %s to %s, err: %w", redriven.MessageID, topic.SourceTopic, err)
+		}
+		zapctx.Debug(ctx, "[DeadLetterTopic] (Redrive) message redriven",
+			zap.String("topic", name),
+			zap.String("message_id", redriven.MessageID),
+		)
+		result.Messages = append(result.Messages, redriven)
+	}
+
+	result.Count = len(result.Messages)
+	return result, nil
+}
+
+// forget only logs a failure, the message that couldn't be written back is then skipped by the next redrives.
+func forget(ctx context.Context, audit ports.DlqRedriveAudit, name string, messageID string) {
+	if audit == nil {
+		return
+	}
+	if err := audit.ForgetRedrivenDlqMessage(ctx, name, messageID); err != nil {
+		zapctx.Error(ctx, "[DeadLetterTopic] (Redrive) unable to forget the message that couldn't be redriven",
+			zap.String("topic", name),
+			zap.String("message_id", messageID),
+			zap.Error(err),
+		)
+	}
+}
+
+// redrivenHeaders are the headers the message had before it failed, and when it first failed, so that it is retried
+// from its first attempt again.
+func redrivenHeaders(headers []kafka.Header) []kafka.Header {
+	redriven := make([]kafka.Header, 0, len(headers))
+	for _, h := range headers {
+		switch h.Key {
+		case listeners.OriginalTopicHeader, listeners.ListenerHeader, listeners.ErrorHeader, listeners.AttemptsHeader, listeners.RetryAtHeader:
+			continue
+		}
+		redriven = append(redriven, h)
+	}
+	return redriven
+}
+
+func messageID(message kafka.Message) string {
+	return fmt.Sprintf("%d/%d", message.Partition, message.Offset)
+}
+
+func messageOf(message kafka.Message) Message {
+	headers := make(map[string]string, len(message.Headers))
+	for _, h := range message.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	attempts, _ := strconv.Atoi(headers[listeners.AttemptsHeader])
+
+	return Message{
+		ID:            messageID(message),
+		Key:           string(message.Key),
+		Value:         string(message.Value),
+		Headers:       headers,
+		OriginalTopic: headers[listeners.OriginalTopicHeader],
+		Listener:      headers[listeners.ListenerHeader],
+		Error:         headers[listeners.ErrorHeader],
+		Attempts:      attempts,
+		FirstFailedAt: headers[listeners.FirstFailedAtHeader],
+	}
+}
+
+// messageBody reads the payment instruction of the messages of the payments topic, that have the contract number of
+// the merchant, and of the state updates topic, that have the ID of the payment instruction in snake case in the first
+// version of their schema and in camel case from the second on. The body is read as is, a dead letter may not match
+// its schema.
+type messageBody struct {
+	PaymentInstructionID   models.PaymentInstructionID `json:"payment_instruction_id"`
+	PaymentInstructionIDV2 models.PaymentInstructionID `json:"paymentInstructionId"`
+	Merchant               struct {
+		ContractNumber string `json:"contractNumber"`
+	} `json:"merchant"`
+}
+
+func redrivenMessageOf(message kafka.Message) models.RedrivenDlqMessage {
+	redriven := models.RedrivenDlqMessage{MessageID: messageID(message)}
+
+	var body messageBody
+	if json.Unmarshal(message.Value, &body) != nil {
+		return redriven
+	}
+	redriven.PaymentInstructionID = body.PaymentInstructionID
+	if redriven.PaymentInstructionID == "" {
+		redriven.PaymentInstructionID = body.PaymentInstructionIDV2
+	}
+	redriven.ContractNumber = body.Merchant.ContractNumber
+	return redriven
+}
@@ -0,0 +1,110 @@
+//go:generate moq -out mocks/topic_reader_moq.go -pkg=mocks . TopicReader
+
+package dead_letters
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/saltpay/go-kafka-driver"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+const (
+	// maxPeekMessages bounds how many messages are read off each partition of a topic.
+	maxPeekMessages = 1000
+	readTimeout     = 10 * time.Second
+)
+
+// TopicReader reads a topic from its first offset, without a consumer group so that reading it commits nothing.
+type TopicReader interface {
+	ReadAll(ctx context.Context) ([]kafka.Message, error)
+}
+
+type KafkaTopicReader struct {
+	brokers []string
+	topic   string
+	dialer  *kafkago.Dialer
+}
+
+// NewTopicReader reads the topic from the brokers, authenticating with SASL over TLS when a username is given.
+func NewTopicReader(brokers []string, topic, username, password string) KafkaTopicReader {
+	dialer := &kafkago.Dialer{Timeout: readTimeout, DualStack: true}
+	if username != "" {
+		dialer.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+		dialer.SASLMechanism = plain.Mechanism{Username: username, Password: password}
+	}
+
+	return KafkaTopicReader{
+		brokers: brokers,
+		topic:   topic,
+		dialer:  dialer,
+	}
+}
+
+func (t KafkaTopicReader) ReadAll(ctx context.Context) ([]kafka.Message, error) {
+	conn, err := t.dialer.DialContext(ctx, "tcp", t.brokers[0])
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to kafka, err: %w", err)
+	}
+	partitions, err := conn.ReadPartitions(t.topic)
+	_ = conn.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the partitions of %s, err: %w", t.topic, err)
+	}
+
+	var messages []kafka.Message
+	for _, partition := range partitions {
+		read, err := t.readPartition(ctx, partition.ID)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, read...)
+	}
+
+	return messages, nil
+}
+
+func (t KafkaTopicReader) readPartition(ctx context.Context, partition int) ([]kafka.Message, error) {
+	leader, err := t.dialer.DialLeader(ctx, "tcp", t.brokers[0], t.topic, partition)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the leader of %s partition %d, err: %w", t.topic, partition, err)
+	}
+	first, last, err := leader.ReadOffsets()
+	_ = leader.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the offsets of %s partition %d, err: %w", t.topic, partition, err)
+	}
+	if first >= last {
+		return nil, nil
+	}
+
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:   t.brokers,
+		Topic:     t.topic,
+		Partition: partition,
+		Dialer:    t.dialer,
+	})
+	defer reader.Close()
+	if err := reader.SetOffset(first); err != nil {
+		return nil, fmt.Errorf("unable to read %s partition %d from its first offset, err: %w", t.topic, partition, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	var messages []kafka.Message
+	for offset := first; offset < last && len(messages) < maxPeekMessages; {
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s partition %d at offset %d, err: %w", t.topic, partition, offset, err)
+		}
+		messages = append(messages, message)
+		offset = message.Offset + 1
+	}
+
+	return messages, nil
+}
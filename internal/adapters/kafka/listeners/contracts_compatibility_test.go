@@ -0,0 +1,145 @@
+//go:build unit
+// +build unit
+
+package listeners_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/saltpay/go-kafka-driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners/internal/dto"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// The messages below are what the producers send in each version of the schemas, a version of a schema the listeners
+// consume without a message here, or a change of the DTOs that reads one of them differently, fails the tests.
+var (
+	incomingInstructionMessages = map[int]string{
+		1: `{
+			"correlationId": "some-correlation-id",
+			"idempotencyKey": "some-idempotency-key",
+			"merchant": {
+				"contractNumber": "1234567",
+				"name": "Some Merchant",
+				"email": "merchant@example.com",
+				"highRisk": true,
+				"address": {"country": "GB", "city": "London", "addressLine1": "1 Some Street", "addressLine2": "Flat 2"},
+				"account": {"accountNumber": "GB33BUKB20201555555555", "swift": "BUKBGB22", "country": "GB", "swiftReferenceNumber": "ref"}
+			},
+			"metadata": {"source": "Way4", "filename": "some-file.xml", "fileType": "UFX"},
+			"payment": {
+				"sender": {"name": "Saltpay", "accountNumber": "GB94BARC10201530093459", "branchCode": "0001"},
+				"amount": "12.30",
+				"currency": {"isoCode": "GBP", "isoNumber": "826"},
+				"executionDate": "2022-09-30T00:00:00Z"
+			}
+		}`,
+		2: `{
+			"correlationId": "some-correlation-id",
+			"idempotencyKey": "some-idempotency-key",
+			"merchant": {
+				"contractNumber": "1234567",
+				"name": "Some Merchant",
+				"email": "merchant@example.com",
+				"highRisk": true,
+				"address": {"country": "GB", "city": "London", "addressLine1": "1 Some Street", "addressLine2": "Flat 2"},
+				"account": {"accountNumber": "GB33BUKB20201555555555", "swift": "BUKBGB22", "country": "GB", "swiftReferenceNumber": "ref"}
+			},
+			"metadata": {"source": "Way4", "filename": "some-file.xml", "fileType": "UFX"},
+			"payment": {
+				"sender": {"name": "Saltpay", "accountNumber": "GB94BARC10201530093459", "branchCode": "0001"},
+				"amount": "12.30",
+				"currency": {"isoCode": "GBP", "isoNumber": "826"},
+				"executionDate": "2022-09-30"
+			}
+		}`,
+	}
+
+	paymentStateUpdateMessages = map[int]string{
+		1: `{
+			"payment_instruction_id": "some-payment-instruction-id",
+			"updated_state": "FAILURE",
+			"failure_reason": {"code": "MISSING_FUNDING", "message": "not enough funds"}
+		}`,
+		2: `{
+			"paymentInstructionId": "some-payment-instruction-id",
+			"updatedState": "FAILURE",
+			"updatedAt": "2022-09-30T10:00:00Z",
+			"failureReason": {"code": "MISSING_FUNDING", "message": "not enough funds"}
+		}`,
+	}
+)
+
+func TestContractCompatibility(t *testing.T) {
+	t.Run("reads every version of the incoming instruction as the same payment", func(t *testing.T) {
+		expected := models.IncomingInstruction{
+			Merchant: models.Merchant{
+				ContractNumber: "1234567",
+				Name:           "Some Merchant",
+				Email:          "merchant@example.com",
+				Address:        models.Address{Country: "GB", City: "London", AddressLine1: "1 Some Street", AddressLine2: "Flat 2"},
+				Account:        models.Account{AccountNumber: "GB33BUKB20201555555555", Swift: "BUKBGB22", Country: "GB", SwiftReferenceNumber: "ref"},
+				HighRisk:       true,
+			},
+			Metadata: models.Metadata{Source: "Way4", Filename: "some-file.xml", FileType: "UFX"},
+			Payment: models.Payment{
+				Sender:        models.Sender{Name: "Saltpay", AccountNumber: "GB94BARC10201530093459", BranchCode: "0001"},
+				Amount:        "12.30",
+				Currency:      models.Currency{IsoCode: models.GBP, IsoNumber: "826"},
+				ExecutionDate: time.Date(2022, 9, 30, 0, 0, 0, 0, time.UTC),
+			},
+			PaymentCorrelationId: "some-correlation-id",
+			IdempotencyKey:       "some-idempotency-key",
+		}
+
+		for _, version := range contracts.Schemas.Versions(contracts.IncomingInstruction) {
+			payload, exists := incomingInstructionMessages[version]
+			require.True(t, exists, "incoming instruction version %d has no message to check it is read", version)
+
+			incomingInstruction, err := dto.NewIncomingInstructionKafkaDTOFromMessage(versioned(payload, version))
+			require.NoError(t, err, "incoming instruction version %d", version)
+			assert.Equal(t, expected, incomingInstruction.MapFromIncomingInstructionKafkaDTO(), "incoming instruction version %d", version)
+		}
+	})
+
+	t.Run("reads every version of the payment state update as the same update", func(t *testing.T) {
+		for _, version := range contracts.Schemas.Versions(contracts.PaymentStateUpdate) {
+			payload, exists := paymentStateUpdateMessages[version]
+			require.True(t, exists, "payment state update version %d has no message to check it is read", version)
+
+			stateUpdate, err := dto.NewPaymentStateUpdateFromMessage(versioned(payload, version))
+			require.NoError(t, err, "payment state update version %d", version)
+			assert.Equal(t, "some-payment-instruction-id", stateUpdate.PaymentInstructionID, "payment state update version %d", version)
+			assert.Equal(t, models.Failed, stateUpdate.PaymentInstructionStatus(), "payment state update version %d", version)
+			assert.Equal(t, models.DomainRejected, stateUpdate.Event().Type, "payment state update version %d", version)
+			assert.Equal(t, dto.FailureReason{Code: dto.FailureCodeMissingFunding, Message: "not enough funds"}, stateUpdate.Event().Details, "payment state update version %d", version)
+		}
+	})
+
+	t.Run("records a state update when it happened from the second version on", func(t *testing.T) {
+		stateUpdate, err := dto.NewPaymentStateUpdateFromMessage(versioned(paymentStateUpdateMessages[2], 2))
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2022, 9, 30, 10, 0, 0, 0, time.UTC), stateUpdate.Event().CreatedOn)
+	})
+
+	t.Run("doesn't read a message that doesn't match the schema of its version", func(t *testing.T) {
+		// the execution date of the second version is a calendar date
+		_, err := dto.NewIncomingInstructionKafkaDTOFromMessage(versioned(incomingInstructionMessages[1], 2))
+
+		assert.ErrorContains(t, err, `$.payment.executionDate: "2022-09-30T00:00:00Z" is not a date`)
+	})
+}
+
+func versioned(payload string, version int) kafka.Message {
+	return kafka.Message{
+		Value:   []byte(payload),
+		Headers: []kafka.Header{{Key: contracts.VersionHeader, Value: []byte(strconv.Itoa(version))}},
+	}
+}
@@ -2,8 +2,12 @@ package dto
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/saltpay/go-kafka-driver"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 )
 
@@ -13,6 +17,8 @@ type (
 		Metadata      Metadata `json:"metadata"`
 		Payment       Payment  `json:"payment"`
 		CorrelationID string   `json:"correlationId"`
+		// IdempotencyKey is the same on every redelivery of a payment, it is optional
+		IdempotencyKey string `json:"idempotencyKey"`
 	}
 
 	Address struct {
@@ -61,14 +67,48 @@ type (
 		AccountNumber string `json:"accountNumber"`
 		BranchCode    string `json:"branchCode"`
 	}
+
+	// IncomingInstructionV2 is the second version of the incoming instruction, its execution date is a calendar date.
+	IncomingInstructionV2 struct {
+		IncomingInstruction
+		Payment PaymentV2 `json:"payment"`
+	}
+
+	PaymentV2 struct {
+		Sender        Sender   `json:"sender"`
+		Amount        string   `json:"amount"`
+		Currency      Currency `json:"currency"`
+		ExecutionDate Date     `json:"executionDate"`
+	}
+
+	// Date is a calendar date, as in `2022-09-30`.
+	Date time.Time
 )
 
+func (d *Date) UnmarshalJSON(in []byte) error {
+	var date string
+	if err := json.Unmarshal(in, &date); err != nil {
+		return err
+	}
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	*d = Date(parsed)
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Time(d).Format("2006-01-02"))
+}
+
 func (i *IncomingInstruction) MapFromIncomingInstructionKafkaDTO() models.IncomingInstruction {
 	return models.IncomingInstruction{
 		Merchant:             i.Merchant.mapFromMerchantKafkaDTO(),
 		Metadata:             i.Metadata.mapFromMetadataKafkaDTO(),
 		Payment:              i.Payment.mapFromPaymentKafkaDTO(),
 		PaymentCorrelationId: i.CorrelationID,
+		IdempotencyKey:       i.IdempotencyKey,
 	}
 }
 
@@ -122,3 +162,36 @@ func NewIncomingInstructionKafkaDTOFromBytes(in []byte) (IncomingInstruction, er
 	err := json.Unmarshal(in, &incomingInstruction)
 	return incomingInstruction, err
 }
+
+// NewIncomingInstructionKafkaDTOFromMessage reads the incoming instruction of the message as the schema version it was
+// written in, once the message is checked against the schema.
+func NewIncomingInstructionKafkaDTOFromMessage(message kafka.Message) (IncomingInstruction, error) {
+	version, err := contracts.Schemas.Validate(contracts.IncomingInstruction, message)
+	if err != nil {
+		return IncomingInstruction{}, err
+	}
+
+	switch version {
+	case 1:
+		return NewIncomingInstructionKafkaDTOFromBytes(message.Value)
+	case 2:
+		var incomingInstruction IncomingInstructionV2
+		if err := json.Unmarshal(message.Value, &incomingInstruction); err != nil {
+			return IncomingInstruction{}, err
+		}
+		return incomingInstruction.toV1(), nil
+	default:
+		return IncomingInstruction{}, fmt.Errorf("incoming instruction version %d can't be read", version)
+	}
+}
+
+func (i IncomingInstructionV2) toV1() IncomingInstruction {
+	incomingInstruction := i.IncomingInstruction
+	incomingInstruction.Payment = Payment{
+		Sender:        i.Payment.Sender,
+		Amount:        i.Payment.Amount,
+		Currency:      i.Payment.Currency,
+		ExecutionDate: time.Time(i.Payment.ExecutionDate),
+	}
+	return incomingInstruction
+}
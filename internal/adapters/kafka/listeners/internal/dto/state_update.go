@@ -1,8 +1,13 @@
 package dto
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/saltpay/go-kafka-driver"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 )
 
@@ -33,6 +38,45 @@ type PaymentStateUpdate struct {
 	PaymentInstructionID string        `json:"payment_instruction_id"`
 	UpdatedState         State         `json:"updated_state"`
 	FailureReason        FailureReason `json:"failure_reason,omitempty"`
+	// UpdatedAt is only sent from the second version on, the update is recorded when it is received without it
+	UpdatedAt time.Time `json:"-"`
+}
+
+// PaymentStateUpdateV2 is the second version of the payment state update, in camel case and with when it happened.
+type PaymentStateUpdateV2 struct {
+	PaymentInstructionID string        `json:"paymentInstructionId"`
+	UpdatedState         State         `json:"updatedState"`
+	UpdatedAt            time.Time     `json:"updatedAt"`
+	FailureReason        FailureReason `json:"failureReason,omitempty"`
+}
+
+// NewPaymentStateUpdateFromMessage reads the state update of the message as the schema version it was written in,
+// once the message is checked against the schema.
+func NewPaymentStateUpdateFromMessage(message kafka.Message) (PaymentStateUpdate, error) {
+	version, err := contracts.Schemas.Validate(contracts.PaymentStateUpdate, message)
+	if err != nil {
+		return PaymentStateUpdate{}, err
+	}
+
+	switch version {
+	case 1:
+		var stateUpdate PaymentStateUpdate
+		err := json.Unmarshal(message.Value, &stateUpdate)
+		return stateUpdate, err
+	case 2:
+		var stateUpdate PaymentStateUpdateV2
+		if err := json.Unmarshal(message.Value, &stateUpdate); err != nil {
+			return PaymentStateUpdate{}, err
+		}
+		return PaymentStateUpdate{
+			PaymentInstructionID: stateUpdate.PaymentInstructionID,
+			UpdatedState:         stateUpdate.UpdatedState,
+			FailureReason:        stateUpdate.FailureReason,
+			UpdatedAt:            stateUpdate.UpdatedAt,
+		}, nil
+	default:
+		return PaymentStateUpdate{}, fmt.Errorf("payment state update version %d can't be read", version)
+	}
 }
 
 func (psu PaymentStateUpdate) PaymentInstructionStatus() models.PaymentInstructionStatus {
@@ -50,9 +94,12 @@ func (psu PaymentStateUpdate) PaymentInstructionStatus() models.PaymentInstructi
 
 func (psu PaymentStateUpdate) Event() models.PaymentInstructionEvent {
 	event := models.PaymentInstructionEvent{
-		CreatedOn: time.Now(),
+		CreatedOn: psu.UpdatedAt,
 		Details:   psu.FailureReason,
 	}
+	if event.CreatedOn.IsZero() {
+		event.CreatedOn = time.Now()
+	}
 
 	switch psu.UpdatedState {
 	case StateProcessed:
@@ -4,13 +4,20 @@ package listeners
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/saltpay/go-kafka-driver"
 	zapctx "github.com/saltpay/go-zap-ctx"
 	"go.uber.org/zap"
 
 	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners/internal/dto"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/validation"
 )
 
 type Consumer interface {
@@ -25,6 +32,8 @@ type PaymentsListener struct {
 	consumer        Consumer
 	makePayment     ports.MakePayment
 	featureFlagServ ports.FeatureFlagService
+	retries         *Retries
+	acknowledger    ports.AcknowledgePayment
 }
 
 // NewPaymentsListener creates a new kafka payment listener.
@@ -40,35 +49,75 @@ func NewPaymentsListener(
 	}
 }
 
+// RetryWith retries the payments that failed on the retry topics and keeps the ones that still fail, or can't be read,
+// on the dead letter topic, instead of only logging them.
+func (p *PaymentsListener) RetryWith(retries *Retries) {
+	retries.listener = "PaymentsListener"
+	p.retries = retries
+}
+
+// AcknowledgeWith sends the outcome of every payment consumed back to the caller that sent it.
+func (p *PaymentsListener) AcknowledgeWith(acknowledger ports.AcknowledgePayment) {
+	p.acknowledger = acknowledger
+}
+
 // Listen start listening for new incoming messages for the specified consumer. Since its a blocking long-lived task,
 // it should live in a separate goroutine.
 func (p *PaymentsListener) Listen(ctx context.Context) {
-	p.consumer.Listen(ctx, p.processor, kafka.AlwaysCommitWithoutError, p.pauseProcessing)
+	if p.retries == nil {
+		p.consumer.Listen(ctx, p.dropFailures, kafka.AlwaysCommitWithoutError, p.pauseProcessing)
+		return
+	}
+
+	p.retries.listen(ctx, p.processor, p.pauseProcessing)
+	p.consumer.Listen(ctx, p.retries.processor(p.processor), kafka.AlwaysCommitWithoutError, p.pauseProcessing)
+}
+
+// dropFailures commits the payments that failed all the same, the processor logged why they failed.
+func (p *PaymentsListener) dropFailures(ctx context.Context, message kafka.Message) error {
+	_ = p.processor(ctx, message)
+	return nil
 }
 
 func (p *PaymentsListener) processor(ctx context.Context, message kafka.Message) error {
 	zapctx.Debug(ctx, "[PaymentsKafkaListener] processing new message")
 
-	incInstDTO, err := dto.NewIncomingInstructionKafkaDTOFromBytes(message.Value)
+	incInstDTO, err := dto.NewIncomingInstructionKafkaDTOFromMessage(message)
 	if err != nil {
 		zapctx.Error(ctx, "error converting kafka message", zap.Error(err))
-		return nil
+		p.acknowledge(ctx, models.PaymentAck{
+			CorrelationID: correlationIDOf(message),
+			Outcome:       models.PaymentAckRejected,
+			Reason:        fmt.Sprintf("message can't be read: %s", err),
+			MessageKey:    string(message.Key),
+		})
+		return permanent(fmt.Errorf("error converting kafka message: %w", err))
 	}
 
 	// todo map it to service models
 	// todo: add a metrics client and count the number payments received with solar source
 	incomingInstruction := incInstDTO.MapFromIncomingInstructionKafkaDTO()
 
-	_, err = p.makePayment.Execute(ctx, incomingInstruction)
+	id, err := p.makePayment.Execute(ctx, incomingInstruction)
+	ack := paymentAckOf(incomingInstruction.PaymentCorrelationId, id, err)
+	ack.MessageKey = string(message.Key)
+	p.acknowledge(ctx, ack)
 
-	// todo: Error report should be sent back to caller
+	if err != nil && handledFailure(err) {
+		zapctx.Warn(ctx, "payment not made, the caller was told why",
+			zap.String("id", incomingInstruction.PaymentCorrelationId),
+			zap.String("outcome", string(ack.Outcome)),
+			zap.Error(err),
+		)
+		return nil
+	}
 	if err != nil {
 		zapctx.Error(ctx, "error making payment",
 			zap.String("id", incomingInstruction.PaymentCorrelationId),
 			zap.String("account_number", incomingInstruction.AccountNumber()),
 			zap.Error(err),
 		)
-		return nil
+		return fmt.Errorf("error making payment %s: %w", incomingInstruction.PaymentCorrelationId, err)
 	}
 
 	zapctx.Debug(ctx, "[PaymentsKafkaListener] Successfully executed make payment")
@@ -76,6 +125,69 @@ func (p *PaymentsListener) processor(ctx context.Context, message kafka.Message)
 	return nil
 }
 
+// correlationIDOf reads the correlation ID of a message that doesn't match its schema, if it has one, so that the
+// caller hears which of its payments was rejected.
+func correlationIDOf(message kafka.Message) string {
+	var body struct {
+		CorrelationID string `json:"correlationId"`
+	}
+	_ = json.Unmarshal(message.Value, &body)
+	return body.CorrelationID
+}
+
+// handledFailure tells the failures making the payment again can't fix: the payment instruction was rejected, it is a
+// duplicate, or its idempotency key was used for another payment. The message was processed and its caller
+// acknowledged, it is committed like a payment made, the retry and dead letter topics are kept for the messages that
+// couldn't be processed.
+func handledFailure(err error) bool {
+	var (
+		rejected validation.IncomingInstructionValidationResult
+		conflict models.IdempotencyKeyConflictError
+	)
+	return errors.As(err, &rejected) || errors.As(err, &conflict) || errors.Is(err, postgresql.ErrDuplicate)
+}
+
+// acknowledge doesn't fail the message, the payment was made or rejected whether the caller hears of it or not.
+func (p *PaymentsListener) acknowledge(ctx context.Context, ack models.PaymentAck) {
+	if p.acknowledger == nil {
+		return
+	}
+
+	ack.AcknowledgedAt = time.Now().UTC()
+	if err := p.acknowledger.Acknowledge(ctx, ack); err != nil {
+		zapctx.Error(ctx, "[PaymentsKafkaListener] (acknowledge) unable to acknowledge the payment",
+			zap.String("id", ack.CorrelationID),
+			zap.String("outcome", string(ack.Outcome)),
+			zap.Error(err),
+		)
+	}
+}
+
+func paymentAckOf(correlationID string, id models.PaymentInstructionID, err error) models.PaymentAck {
+	ack := models.PaymentAck{
+		CorrelationID:        correlationID,
+		PaymentInstructionID: id,
+		Outcome:              models.PaymentAckAccepted,
+	}
+
+	var (
+		rejected validation.IncomingInstructionValidationResult
+		conflict models.IdempotencyKeyConflictError
+	)
+	switch {
+	case err == nil:
+	case errors.As(err, &rejected):
+		ack.Outcome, ack.ValidationErrors = models.PaymentAckRejected, rejected.GetErrors()
+	case errors.Is(err, postgresql.ErrDuplicate):
+		ack.Outcome = models.PaymentAckDuplicate
+	case errors.As(err, &conflict):
+		ack.Outcome, ack.Reason = models.PaymentAckRejected, conflict.Error()
+	default:
+		ack.Outcome, ack.Reason = models.PaymentAckFailed, err.Error()
+	}
+	return ack
+}
+
 func (p *PaymentsListener) pauseProcessing(ctx context.Context) bool {
 	if !p.featureFlagServ.IsKafkaIngestionEnabledForPaymentTransactions() {
 		zapctx.Info(ctx, "[PaymentsKafkaListener] kafka ingestion for payment transactions is disabled")
@@ -7,16 +7,22 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/saltpay/go-kafka-driver"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners/internal/mocks"
+	producermocks "github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
 	portMocks "github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/validation"
 )
 
 func TestListen(t *testing.T) {
@@ -44,6 +50,28 @@ func TestListen(t *testing.T) {
 
 		assert.Equal(t, len(makePaymentMock.ExecuteCalls()), 1)
 	})
+	t.Run("should pass the idempotency key of the message on to make payment", func(t *testing.T) {
+		var (
+			ctx             = context.Background()
+			consumerMock    = &mocks.ConsumerMock{}
+			makePaymentMock = &portMocks.MakePaymentMock{}
+		)
+
+		consumerMock.ListenFunc = func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+			err := processor(ctx, kafka.Message{Value: []byte(`{"correlationId":"some-correlation-id","idempotencyKey":"some-idempotency-key"}`)})
+			assert.NoError(t, err)
+		}
+
+		makePaymentMock.ExecuteFunc = func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+			return "random payment instruction id", nil
+		}
+
+		paymentsListener := listeners.NewPaymentsListener(consumerMock, makePaymentMock, testdoubles.FeatureFlagService{})
+		paymentsListener.Listen(ctx)
+
+		assert.Equal(t, 1, len(makePaymentMock.ExecuteCalls()))
+		assert.Equal(t, "some-idempotency-key", makePaymentMock.ExecuteCalls()[0].IncomingInstruction.IdempotencyKey)
+	})
 	t.Run("should handle unmarshal error gracefully", func(t *testing.T) {
 		var (
 			ctx             = context.Background()
@@ -91,3 +119,194 @@ func TestListen(t *testing.T) {
 		assert.Equal(t, len(makePaymentMock.ExecuteCalls()), 1)
 	})
 }
+
+func TestListen_Acknowledge(t *testing.T) {
+	acknowledge := func(t *testing.T, payload string, id models.PaymentInstructionID, makePaymentErr error) models.PaymentAck {
+		t.Helper()
+		var (
+			ctx          = context.Background()
+			consumerMock = &mocks.ConsumerMock{
+				ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+					assert.NoError(t, processor(ctx, kafka.Message{Key: []byte("some-message-key"), Value: []byte(payload)}))
+				},
+			}
+			makePaymentMock = &portMocks.MakePaymentMock{
+				ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+					return id, makePaymentErr
+				},
+			}
+			acknowledgerMock = &portMocks.AcknowledgePaymentMock{
+				AcknowledgeFunc: func(ctx context.Context, ack models.PaymentAck) error {
+					return nil
+				},
+			}
+		)
+
+		paymentsListener := listeners.NewPaymentsListener(consumerMock, makePaymentMock, testdoubles.FeatureFlagService{})
+		paymentsListener.AcknowledgeWith(acknowledgerMock)
+		paymentsListener.Listen(ctx)
+
+		require.Len(t, acknowledgerMock.AcknowledgeCalls(), 1)
+		ack := acknowledgerMock.AcknowledgeCalls()[0].Ack
+		assert.False(t, ack.AcknowledgedAt.IsZero())
+		return ack
+	}
+	const payload = `{"correlationId":"some-correlation-id"}`
+
+	t.Run("acknowledges an accepted payment with its payment instruction ID", func(t *testing.T) {
+		ack := acknowledge(t, payload, "some-payment-instruction-id", nil)
+
+		assert.Equal(t, "some-correlation-id", ack.CorrelationID)
+		assert.Equal(t, models.PaymentInstructionID("some-payment-instruction-id"), ack.PaymentInstructionID)
+		assert.Equal(t, models.PaymentAckAccepted, ack.Outcome)
+		assert.Empty(t, ack.ValidationErrors)
+	})
+
+	t.Run("acknowledges a rejected payment with its validation errors", func(t *testing.T) {
+		rejection := validation.Invalid("ContractCode should not be empty", "AccountNumber should not be empty")
+
+		ack := acknowledge(t, payload, "some-payment-instruction-id", rejection)
+
+		assert.Equal(t, models.PaymentAckRejected, ack.Outcome)
+		assert.Equal(t, models.PaymentInstructionID("some-payment-instruction-id"), ack.PaymentInstructionID)
+		assert.Equal(t, []string{"ContractCode should not be empty", "AccountNumber should not be empty"}, ack.ValidationErrors)
+	})
+
+	t.Run("acknowledges a duplicated payment", func(t *testing.T) {
+		ack := acknowledge(t, payload, "some-payment-instruction-id", postgresql.ErrDuplicate)
+
+		assert.Equal(t, models.PaymentAckDuplicate, ack.Outcome)
+	})
+
+	t.Run("acknowledges a payment that reuses the idempotency key of another as rejected", func(t *testing.T) {
+		ack := acknowledge(t, payload, "", models.IdempotencyKeyConflictError{Key: "some-key", ID: "another-payment-instruction-id"})
+
+		assert.Equal(t, models.PaymentAckRejected, ack.Outcome)
+		assert.Empty(t, ack.PaymentInstructionID)
+		assert.Contains(t, ack.Reason, "some-key")
+	})
+
+	t.Run("acknowledges a payment that couldn't be made as failed", func(t *testing.T) {
+		ack := acknowledge(t, payload, "", fmt.Errorf("database is down"))
+
+		assert.Equal(t, models.PaymentAckFailed, ack.Outcome)
+		assert.Equal(t, "database is down", ack.Reason)
+	})
+
+	t.Run("acknowledges a message that can't be read as rejected", func(t *testing.T) {
+		ack := acknowledge(t, "invalid payload", "", nil)
+
+		assert.Equal(t, models.PaymentAckRejected, ack.Outcome)
+		assert.Empty(t, ack.CorrelationID)
+		assert.Equal(t, "some-message-key", ack.MessageKey)
+		assert.Contains(t, ack.Reason, "message can't be read")
+	})
+
+	t.Run("acknowledges a message that doesn't match its schema with the correlation ID it has", func(t *testing.T) {
+		ack := acknowledge(t, `{"correlationId":"some-correlation-id","payment":"not a payment"}`, "", nil)
+
+		assert.Equal(t, models.PaymentAckRejected, ack.Outcome)
+		assert.Equal(t, "some-correlation-id", ack.CorrelationID)
+		assert.Contains(t, ack.Reason, "message can't be read")
+	})
+
+	t.Run("acknowledges a message that doesn't match the schema of its version as rejected without making it", func(t *testing.T) {
+		var (
+			ctx          = context.Background()
+			consumerMock = &mocks.ConsumerMock{
+				ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+					// the second version requires an idempotency key
+					assert.NoError(t, processor(ctx, kafka.Message{
+						Value:   []byte(payload),
+						Headers: []kafka.Header{{Key: contracts.VersionHeader, Value: []byte("2")}},
+					}))
+				},
+			}
+			makePaymentMock  = &portMocks.MakePaymentMock{}
+			acknowledgerMock = &portMocks.AcknowledgePaymentMock{
+				AcknowledgeFunc: func(ctx context.Context, ack models.PaymentAck) error {
+					return nil
+				},
+			}
+		)
+
+		paymentsListener := listeners.NewPaymentsListener(consumerMock, makePaymentMock, testdoubles.FeatureFlagService{})
+		paymentsListener.AcknowledgeWith(acknowledgerMock)
+		paymentsListener.Listen(ctx)
+
+		assert.Empty(t, makePaymentMock.ExecuteCalls())
+		require.Len(t, acknowledgerMock.AcknowledgeCalls(), 1)
+		ack := acknowledgerMock.AcknowledgeCalls()[0].Ack
+		assert.Equal(t, models.PaymentAckRejected, ack.Outcome)
+		assert.Contains(t, ack.Reason, "$.idempotencyKey: is required")
+	})
+
+	t.Run("commits the payment when it can't be acknowledged", func(t *testing.T) {
+		var (
+			ctx          = context.Background()
+			consumerMock = &mocks.ConsumerMock{
+				ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+					assert.NoError(t, processor(ctx, kafka.Message{Value: []byte(payload)}))
+				},
+			}
+			makePaymentMock = &portMocks.MakePaymentMock{
+				ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+					return "some-payment-instruction-id", nil
+				},
+			}
+			acknowledgerMock = &portMocks.AcknowledgePaymentMock{
+				AcknowledgeFunc: func(ctx context.Context, ack models.PaymentAck) error {
+					return fmt.Errorf("kafka is down")
+				},
+			}
+		)
+
+		paymentsListener := listeners.NewPaymentsListener(consumerMock, makePaymentMock, testdoubles.FeatureFlagService{})
+		paymentsListener.AcknowledgeWith(acknowledgerMock)
+		paymentsListener.RetryWith(listeners.NewRetries("transactions", nil, nil))
+		paymentsListener.Listen(ctx)
+
+		assert.Len(t, makePaymentMock.ExecuteCalls(), 1)
+		assert.Len(t, acknowledgerMock.AcknowledgeCalls(), 1)
+	})
+
+	t.Run("commits a payment that was rejected, duplicated or reused an idempotency key without retrying it", func(t *testing.T) {
+		failures := []error{
+			validation.Invalid("ContractCode should not be empty"),
+			postgresql.ErrDuplicate,
+			models.IdempotencyKeyConflictError{Key: "some-key", ID: "another-payment-instruction-id"},
+		}
+		for _, failure := range failures {
+			var (
+				ctx          = context.Background()
+				processed    error
+				consumerMock = &mocks.ConsumerMock{
+					ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+						processed = processor(ctx, kafka.Message{Value: []byte(payload)})
+					},
+				}
+				makePaymentMock = &portMocks.MakePaymentMock{
+					ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+						return "some-payment-instruction-id", failure
+					},
+				}
+				retries = &producermocks.ProducerMock{WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+					return nil
+				}}
+				deadLetters = &producermocks.ProducerMock{WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+					return nil
+				}}
+			)
+
+			paymentsListener := listeners.NewPaymentsListener(consumerMock, makePaymentMock, testdoubles.FeatureFlagService{})
+			paymentsListener.RetryWith(listeners.NewRetries("transactions", []listeners.RetryTopic{
+				{Topic: "transactions-retry-1", Delay: time.Minute, Consumer: &mocks.ConsumerMock{ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {}}, Producer: retries},
+			}, deadLetters))
+			paymentsListener.Listen(ctx)
+
+			assert.NoError(t, processed, failure.Error())
+			assert.Empty(t, retries.WriteMessageCalls(), failure.Error())
+			assert.Empty(t, deadLetters.WriteMessageCalls(), failure.Error())
+		}
+	})
+}
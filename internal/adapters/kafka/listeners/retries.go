@@ -0,0 +1,178 @@
+package listeners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/saltpay/go-kafka-driver"
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers"
+)
+
+// The headers added to a message when it is sent to a retry or dead letter topic, next to its own headers.
+const (
+	OriginalTopicHeader = "x-original-topic"
+	ListenerHeader      = "x-listener"
+	ErrorHeader         = "x-error"
+	AttemptsHeader      = "x-attempts"
+	FirstFailedAtHeader = "x-first-failed-at"
+	RetryAtHeader       = "x-retry-at"
+)
+
+// RetryTopic is a topic the failed messages wait on for Delay before they are processed again.
+type RetryTopic struct {
+	Topic    string
+	Delay    time.Duration
+	Consumer Consumer
+	Producer producers.Producer
+}
+
+// Retries sends a message that failed to the next of the retry topics, and to the dead letter topic once it failed on
+// all of them, or straight away when retrying it can't succeed, as a message that can't be read.
+type Retries struct {
+	listener      string
+	originalTopic string
+	retryTopics   []RetryTopic
+	deadLetters   producers.Producer
+}
+
+func NewRetries(originalTopic string, retryTopics []RetryTopic, deadLetters producers.Producer) *Retries {
+	return &Retries{
+		originalTopic: originalTopic,
+		retryTopics:   retryTopics,
+		deadLetters:   deadLetters,
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (p permanentError) Error() string {
+	return p.err.Error()
+}
+
+func (p permanentError) Unwrap() error {
+	return p.err
+}
+
+// permanent marks an error retrying the message won't fix, the message goes straight to the dead letter topic.
+func permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// listen consumes the retry topics, each in its own goroutine, until the context is done.
+func (r *Retries) listen(ctx context.Context, process kafka.Processor, pause kafka.PauseStrategy) {
+	for _, retryTopic := range r.retryTopics {
+		go retryTopic.Consumer.Listen(ctx, r.delayed(process), kafka.AlwaysCommitWithoutError, pause)
+	}
+}
+
+// processor retries the messages the processor fails to process, the message is only left uncommitted when it couldn't
+// be sent to a retry or dead letter topic.
+func (r *Retries) processor(process kafka.Processor) kafka.Processor {
+	return func(ctx context.Context, message kafka.Message) error {
+		err := process(ctx, message)
+		if err == nil {
+			return nil
+		}
+		return r.fail(ctx, message, err)
+	}
+}
+
+// delayed waits for the retry time of the messages of a retry topic, as the messages of a topic share its delay the
+// messages after it are due later.
+func (r *Retries) delayed(process kafka.Processor) kafka.Processor {
+	retry := r.processor(process)
+	return func(ctx context.Context, message kafka.Message) error {
+		if retryAt, err := time.Parse(time.RFC3339Nano, header(message, RetryAtHeader)); err == nil {
+			select {
+			case <-time.After(time.Until(retryAt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return retry(ctx, message)
+	}
+}
+
+func (r *Retries) fail(ctx context.Context, message kafka.Message, failure error) error {
+	attempts, _ := strconv.Atoi(header(message, AttemptsHeader))
+	attempts++
+
+	firstFailedAt := header(message, FirstFailedAtHeader)
+	if firstFailedAt == "" {
+		firstFailedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+
+	failed := kafka.Message{
+		Key:   message.Key,
+		Value: message.Value,
+		Headers: withHeaders(message.Headers, map[string]string{
+			OriginalTopicHeader: r.originalTopic,
+			ListenerHeader:      r.listener,
+			ErrorHeader:         failure.Error(),
+			AttemptsHeader:      strconv.Itoa(attempts),
+			FirstFailedAtHeader: firstFailedAt,
+		}),
+	}
+
+	var permanentErr permanentError
+	if attempts <= len(r.retryTopics) && !errors.As(failure, &permanentErr) {
+		retryTopic := r.retryTopics[attempts-1]
+		failed.Headers = withHeaders(failed.Headers, map[string]string{
+			RetryAtHeader: time.Now().Add(retryTopic.Delay).UTC().Format(time.RFC3339Nano),
+		})
+		if err := retryTopic.Producer.WriteMessage(ctx, failed); err != nil {
+			return fmt.Errorf("unable to send the message to retry topic %s, err: %w", retryTopic.Topic, err)
+		}
+		zapctx.Warn(ctx, "[Retries] (fail) message sent to a retry topic",
+			zap.String("listener", r.listener),
+			zap.String("topic", retryTopic.Topic),
+			zap.Int("attempts", attempts),
+			zap.Error(failure),
+		)
+		return nil
+	}
+
+	failed.Headers = withHeaders(failed.Headers, map[string]string{RetryAtHeader: ""})
+	if err := r.deadLetters.WriteMessage(ctx, failed); err != nil {
+		return fmt.Errorf("unable to send the message to the dead letter topic of %s, err: %w", r.originalTopic, err)
+	}
+	zapctx.Error(ctx, "[Retries] (fail) message sent to the dead letter topic",
+		zap.String("listener", r.listener),
+		zap.Int("attempts", attempts),
+		zap.Error(failure),
+	)
+	return nil
+}
+
+func header(message kafka.Message, key string) string {
+	for _, h := range message.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// withHeaders sets the headers on a copy of the headers, replacing the ones with the same key, an empty value removes the header.
+func withHeaders(headers []kafka.Header, values map[string]string) []kafka.Header {
+	set := make([]kafka.Header, 0, len(headers)+len(values))
+	for _, h := range headers {
+		if _, replaced := values[h.Key]; !replaced {
+			set = append(set, h)
+		}
+	}
+	for _, key := range []string{OriginalTopicHeader, ListenerHeader, ErrorHeader, AttemptsHeader, FirstFailedAtHeader, RetryAtHeader} {
+		if value, exists := values[key]; exists && value != "" {
+			set = append(set, kafka.Header{Key: key, Value: []byte(value)})
+		}
+	}
+	return set
+}
@@ -0,0 +1,270 @@
+//go:build unit
+// +build unit
+
+package listeners_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saltpay/go-kafka-driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners/internal/dto"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners/internal/mocks"
+	producermocks "github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+const (
+	stateUpdatesTopic = "payment-state-updates"
+	firstRetryTopic   = "payment-state-updates-retry-1"
+	secondRetryTopic  = "payment-state-updates-retry-2"
+)
+
+func TestRetries(t *testing.T) {
+	stateJson, err := json.Marshal(dto.PaymentStateUpdate{
+		PaymentInstructionID: "testPaymentID",
+		UpdatedState:         dto.StateProcessed,
+	})
+	require.NoError(t, err)
+
+	failingTracker := func() *mocks.UpdatePaymentStateMock {
+		return &mocks.UpdatePaymentStateMock{
+			ExecuteFunc: func(ctx context.Context, paymentInstructionID string, state models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				return errors.New("database is down")
+			},
+		}
+	}
+	idleConsumer := func() *mocks.ConsumerMock {
+		return &mocks.ConsumerMock{
+			ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+			},
+		}
+	}
+	writingProducer := func() *producermocks.ProducerMock {
+		return &producermocks.ProducerMock{
+			WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+				return nil
+			},
+		}
+	}
+	consuming := func(message kafka.Message, processed *error) *mocks.ConsumerMock {
+		return &mocks.ConsumerMock{
+			ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+				*processed = processor(ctx, message)
+			},
+		}
+	}
+
+	t.Run("sends a state update that failed to the first retry topic with why it failed", func(t *testing.T) {
+		var (
+			ctx       = context.Background()
+			processed error
+			message   = kafka.Message{
+				Key:     []byte("testPaymentID"),
+				Value:   stateJson,
+				Headers: []kafka.Header{{Key: "trace-id", Value: []byte("abc")}},
+			}
+			firstRetries  = writingProducer()
+			secondRetries = writingProducer()
+			deadLetters   = writingProducer()
+		)
+
+		listener := listeners.NewStateUpdatesListener(consuming(message, &processed), failingTracker())
+		listener.RetryWith(listeners.NewRetries(stateUpdatesTopic, []listeners.RetryTopic{
+			{Topic: firstRetryTopic, Delay: time.Minute, Consumer: idleConsumer(), Producer: firstRetries},
+			{Topic: secondRetryTopic, Delay: 10 * time.Minute, Consumer: idleConsumer(), Producer: secondRetries},
+		}, deadLetters))
+
+		listener.Listen(ctx)
+
+		require.NoError(t, processed, "the message is committed once it is on the retry topic")
+		require.Len(t, firstRetries.WriteMessageCalls(), 1)
+		assert.Empty(t, secondRetries.WriteMessageCalls())
+		assert.Empty(t, deadLetters.WriteMessageCalls())
+
+		retried := firstRetries.WriteMessageCalls()[0].Message
+		assert.Equal(t, message.Key, retried.Key)
+		assert.Equal(t, message.Value, retried.Value)
+
+		headers := headersOf(retried)
+		assert.Equal(t, "abc", headers["trace-id"])
+		assert.Equal(t, stateUpdatesTopic, headers[listeners.OriginalTopicHeader])
+		assert.Equal(t, "StateUpdatesListener", headers[listeners.ListenerHeader])
+		assert.Contains(t, headers[listeners.ErrorHeader], "database is down")
+		assert.Equal(t, "1", headers[listeners.AttemptsHeader])
+		assert.NotEmpty(t, headers[listeners.FirstFailedAtHeader])
+
+		retryAt, err := time.Parse(time.RFC3339Nano, headers[listeners.RetryAtHeader])
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), retryAt, 5*time.Second)
+	})
+
+	t.Run("sends a state update that failed on every retry topic to the dead letter topic", func(t *testing.T) {
+		var (
+			ctx       = context.Background()
+			processed error
+			message   = kafka.Message{
+				Value: stateJson,
+				Headers: []kafka.Header{
+					{Key: listeners.AttemptsHeader, Value: []byte("2")},
+					{Key: listeners.FirstFailedAtHeader, Value: []byte("2022-09-01T10:00:00Z")},
+					{Key: listeners.RetryAtHeader, Value: []byte("2022-09-01T10:10:00Z")},
+				},
+			}
+			retries     = writingProducer()
+			deadLetters = writingProducer()
+		)
+
+		listener := listeners.NewStateUpdatesListener(consuming(message, &processed), failingTracker())
+		listener.RetryWith(listeners.NewRetries(stateUpdatesTopic, []listeners.RetryTopic{
+			{Topic: firstRetryTopic, Delay: time.Minute, Consumer: idleConsumer(), Producer: retries},
+			{Topic: secondRetryTopic, Delay: 10 * time.Minute, Consumer: idleConsumer(), Producer: retries},
+		}, deadLetters))
+
+		listener.Listen(ctx)
+
+		require.NoError(t, processed)
+		assert.Empty(t, retries.WriteMessageCalls())
+		require.Len(t, deadLetters.WriteMessageCalls(), 1)
+
+		headers := headersOf(deadLetters.WriteMessageCalls()[0].Message)
+		assert.Equal(t, "3", headers[listeners.AttemptsHeader])
+		assert.Equal(t, "2022-09-01T10:00:00Z", headers[listeners.FirstFailedAtHeader], "it keeps when it first failed")
+		assert.NotContains(t, headers, listeners.RetryAtHeader)
+	})
+
+	t.Run("sends a state update that can't be read straight to the dead letter topic", func(t *testing.T) {
+		var (
+			ctx         = context.Background()
+			processed   error
+			retries     = writingProducer()
+			deadLetters = writingProducer()
+		)
+
+		listener := listeners.NewStateUpdatesListener(consuming(kafka.Message{Value: []byte("invalidJSON")}, &processed), failingTracker())
+		listener.RetryWith(listeners.NewRetries(stateUpdatesTopic, []listeners.RetryTopic{
+			{Topic: firstRetryTopic, Delay: time.Minute, Consumer: idleConsumer(), Producer: retries},
+		}, deadLetters))
+
+		listener.Listen(ctx)
+
+		require.NoError(t, processed)
+		assert.Empty(t, retries.WriteMessageCalls())
+		require.Len(t, deadLetters.WriteMessageCalls(), 1)
+
+		headers := headersOf(deadLetters.WriteMessageCalls()[0].Message)
+		assert.Equal(t, "1", headers[listeners.AttemptsHeader])
+		assert.Contains(t, headers[listeners.ErrorHeader], "error unmarshalling the message")
+	})
+
+	t.Run("leaves the state update uncommitted when it can't be sent to the dead letter topic", func(t *testing.T) {
+		var (
+			ctx         = context.Background()
+			processed   error
+			deadLetters = &producermocks.ProducerMock{
+				WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+					return errors.New("kafka is down")
+				},
+			}
+		)
+
+		listener := listeners.NewStateUpdatesListener(consuming(kafka.Message{Value: []byte("invalidJSON")}, &processed), failingTracker())
+		listener.RetryWith(listeners.NewRetries(stateUpdatesTopic, nil, deadLetters))
+
+		listener.Listen(ctx)
+
+		require.Error(t, processed)
+		assert.Contains(t, processed.Error(), "unable to send the message to the dead letter topic")
+	})
+
+	t.Run("processes the state updates of the retry topics again once they are due", func(t *testing.T) {
+		var (
+			ctx     = context.Background()
+			retried = make(chan error, 1)
+			message = kafka.Message{
+				Value: stateJson,
+				Headers: []kafka.Header{
+					{Key: listeners.AttemptsHeader, Value: []byte("1")},
+					{Key: listeners.RetryAtHeader, Value: []byte(time.Now().Add(-time.Second).UTC().Format(time.RFC3339Nano))},
+				},
+			}
+			retryConsumer = &mocks.ConsumerMock{
+				ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+					retried <- processor(ctx, message)
+				},
+			}
+			tracker = &mocks.UpdatePaymentStateMock{
+				ExecuteFunc: func(ctx context.Context, paymentInstructionID string, state models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+					return nil
+				},
+			}
+		)
+
+		listener := listeners.NewStateUpdatesListener(idleConsumer(), tracker)
+		listener.RetryWith(listeners.NewRetries(stateUpdatesTopic, []listeners.RetryTopic{
+			{Topic: firstRetryTopic, Delay: time.Minute, Consumer: retryConsumer, Producer: writingProducer()},
+		}, writingProducer()))
+
+		listener.Listen(ctx)
+
+		select {
+		case err := <-retried:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("the retry topic wasn't consumed")
+		}
+		require.Len(t, tracker.ExecuteCalls(), 1)
+		assert.Equal(t, "testPaymentID", tracker.ExecuteCalls()[0].PaymentInstructionID)
+	})
+
+	t.Run("doesn't process a state update of a retry topic before it is due", func(t *testing.T) {
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+			retried     = make(chan error, 1)
+			message     = kafka.Message{
+				Value: stateJson,
+				Headers: []kafka.Header{
+					{Key: listeners.AttemptsHeader, Value: []byte("1")},
+					{Key: listeners.RetryAtHeader, Value: []byte(time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano))},
+				},
+			}
+			retryConsumer = &mocks.ConsumerMock{
+				ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+					retried <- processor(ctx, message)
+				},
+			}
+			tracker = failingTracker()
+		)
+		defer cancel()
+
+		listener := listeners.NewStateUpdatesListener(idleConsumer(), tracker)
+		listener.RetryWith(listeners.NewRetries(stateUpdatesTopic, []listeners.RetryTopic{
+			{Topic: firstRetryTopic, Delay: time.Hour, Consumer: retryConsumer, Producer: writingProducer()},
+		}, writingProducer()))
+
+		listener.Listen(ctx)
+
+		select {
+		case err := <-retried:
+			require.ErrorIs(t, err, context.DeadlineExceeded, "the message is left uncommitted")
+		case <-time.After(time.Second):
+			t.Fatal("the retry topic wasn't consumed")
+		}
+		assert.Empty(t, tracker.ExecuteCalls())
+	})
+}
+
+func headersOf(message kafka.Message) map[string]string {
+	headers := make(map[string]string, len(message.Headers))
+	for _, h := range message.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	return headers
+}
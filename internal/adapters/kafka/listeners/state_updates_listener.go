@@ -4,18 +4,26 @@ package listeners
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/saltpay/go-kafka-driver"
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
 
 	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/listeners/internal/dto"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
 
+// maxConflictRetries is how many times a state update is reapplied when the payment instruction
+// keeps being updated concurrently.
+const maxConflictRetries = 3
+
 type StateUpdatesListener struct {
 	consumer           Consumer
 	updatePaymentState ports.UpdatePaymentState
+	retries            *Retries
 }
 
 func NewStateUpdatesListener(consumer Consumer, updatePaymentState ports.UpdatePaymentState) *StateUpdatesListener {
@@ -25,20 +33,43 @@ func NewStateUpdatesListener(consumer Consumer, updatePaymentState ports.UpdateP
 	}
 }
 
+// RetryWith retries the state updates that failed on the retry topics and keeps the ones that still fail, or can't be
+// read, on the dead letter topic, instead of leaving them uncommitted.
+func (l *StateUpdatesListener) RetryWith(retries *Retries) {
+	retries.listener = "StateUpdatesListener"
+	l.retries = retries
+}
+
 func (l *StateUpdatesListener) Listen(ctx context.Context) {
-	l.consumer.Listen(ctx, l.processor, kafka.AlwaysCommitWithoutError, kafka.NeverPause)
+	if l.retries == nil {
+		l.consumer.Listen(ctx, l.processor, kafka.AlwaysCommitWithoutError, kafka.NeverPause)
+		return
+	}
+
+	l.retries.listen(ctx, l.processor, kafka.NeverPause)
+	l.consumer.Listen(ctx, l.retries.processor(l.processor), kafka.AlwaysCommitWithoutError, kafka.NeverPause)
 }
 
 func (l *StateUpdatesListener) processor(ctx context.Context, message kafka.Message) error {
-	var paymentStatus dto.PaymentStateUpdate
-
-	err := json.Unmarshal(message.Value, &paymentStatus)
+	paymentStatus, err := dto.NewPaymentStateUpdateFromMessage(message)
 	if err != nil {
-		return fmt.Errorf("error unmarshalling the message: %w", err)
+		return permanent(fmt.Errorf("error unmarshalling the message: %w", err))
 	}
 
+	// UpdatePaymentState reads the payment instruction again on every attempt, so retrying reapplies the update
+	// on top of whatever was written concurrently
 	err = l.updatePaymentState.Execute(ctx, paymentStatus.PaymentInstructionID, paymentStatus.PaymentInstructionStatus(), paymentStatus.Event())
-	if err != nil {
+	var conflict models.VersionConflictError
+	for attempt := 1; attempt <= maxConflictRetries && errors.As(err, &conflict); attempt++ {
+		zapctx.Warn(ctx, "[StateUpdatesListener] (processor) payment instruction was updated concurrently, reapplying the state update",
+			zap.String("payment_instruction_id", paymentStatus.PaymentInstructionID),
+			zap.Int("attempt", attempt),
+		)
+		err = l.updatePaymentState.Execute(ctx, paymentStatus.PaymentInstructionID, paymentStatus.PaymentInstructionStatus(), paymentStatus.Event())
+	}
+	// a rejected status transition is already recorded on the payment instruction, there is nothing left to retry
+	var illegalTransition models.IllegalTransitionError
+	if err != nil && !errors.As(err, &illegalTransition) {
 		return fmt.Errorf("error updating state in the db: %w", err)
 	}
 
@@ -185,4 +185,122 @@ func TestStateUpdatesListener_Listen(t *testing.T) {
 		// Then Execute should be called once
 		assert.Len(t, trackerMock.ExecuteCalls(), 1, "Execute method should be called once")
 	})
+	t.Run("should reapply the state update when the payment instruction was updated concurrently", func(t *testing.T) {
+		// Given a state update message
+		var (
+			ctx       = context.Background()
+			paymentID = "testPaymentID"
+		)
+
+		stateJson, err := json.Marshal(dto.PaymentStateUpdate{
+			PaymentInstructionID: paymentID,
+			UpdatedState:         dto.StateProcessed,
+		})
+		require.NoError(t, err)
+		message := kafka.Message{Value: stateJson}
+
+		// And a mock kafka consumer
+		consumerMock := &mocks.ConsumerMock{
+			ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+				err := processor(ctx, message)
+				require.NoError(t, err)
+			},
+		}
+
+		// And a mock state tracker that conflicts on the first attempt
+		trackerMock := &mocks.UpdatePaymentStateMock{}
+		trackerMock.ExecuteFunc = func(ctx context.Context, paymentInstructionID string, state models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+			if len(trackerMock.ExecuteCalls()) == 1 {
+				return models.VersionConflictError{ID: models.PaymentInstructionID(paymentInstructionID), ExpectedVersion: 1, ActualVersion: 2}
+			}
+			return nil
+		}
+
+		// And a state update listener
+		stateUpdatesListener := listeners.NewStateUpdatesListener(consumerMock, trackerMock)
+
+		// When Listen is called on the state update listener
+		stateUpdatesListener.Listen(ctx)
+
+		// Then Execute should be called again after the conflict
+		assert.Len(t, trackerMock.ExecuteCalls(), 2, "Execute method should be called twice")
+	})
+	t.Run("should give up when the payment instruction keeps being updated concurrently", func(t *testing.T) {
+		// Given a state update message
+		var (
+			ctx       = context.Background()
+			paymentID = "testPaymentID"
+		)
+
+		stateJson, err := json.Marshal(dto.PaymentStateUpdate{
+			PaymentInstructionID: paymentID,
+			UpdatedState:         dto.StateProcessed,
+		})
+		require.NoError(t, err)
+		message := kafka.Message{Value: stateJson}
+
+		// And a mock kafka consumer
+		consumerMock := &mocks.ConsumerMock{
+			ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+				err := processor(ctx, message)
+				require.Error(t, err, "should return an error")
+				require.Contains(t, err.Error(), "error updating state in the db")
+			},
+		}
+
+		// And a mock state tracker that always conflicts
+		trackerMock := &mocks.UpdatePaymentStateMock{
+			ExecuteFunc: func(ctx context.Context, paymentInstructionID string, state models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				return models.VersionConflictError{ID: models.PaymentInstructionID(paymentInstructionID)}
+			},
+		}
+
+		// And a state update listener
+		stateUpdatesListener := listeners.NewStateUpdatesListener(consumerMock, trackerMock)
+
+		// When Listen is called on the state update listener
+		stateUpdatesListener.Listen(ctx)
+
+		// Then Execute should be called once and then retried
+		assert.Len(t, trackerMock.ExecuteCalls(), 4, "Execute method should be called once and retried three times")
+	})
+	t.Run("should not retry a state update the payment instruction rejected", func(t *testing.T) {
+		// Given a state update message with an unknown state
+		var (
+			ctx       = context.Background()
+			paymentID = "testPaymentID"
+		)
+
+		stateJson, err := json.Marshal(dto.PaymentStateUpdate{
+			PaymentInstructionID: paymentID,
+			UpdatedState:         "UNKNOWN",
+		})
+		require.NoError(t, err)
+		message := kafka.Message{Value: stateJson}
+
+		// And a mock kafka consumer
+		consumerMock := &mocks.ConsumerMock{
+			ListenFunc: func(ctx context.Context, processor kafka.Processor, toggle kafka.CommitStrategy, ps kafka.PauseStrategy) {
+				err := processor(ctx, message)
+				require.NoError(t, err)
+			},
+		}
+
+		// And a mock state tracker rejecting the transition
+		trackerMock := &mocks.UpdatePaymentStateMock{
+			ExecuteFunc: func(ctx context.Context, paymentInstructionID string, state models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				return models.IllegalTransitionError{ID: models.PaymentInstructionID(paymentInstructionID), From: models.SubmittedForProcessing, To: state}
+			},
+		}
+
+		// And a state update listener
+		stateUpdatesListener := listeners.NewStateUpdatesListener(consumerMock, trackerMock)
+
+		// When Listen is called on the state update listener
+		stateUpdatesListener.Listen(ctx)
+
+		// Then Execute should be called once with the empty state
+		require.Len(t, trackerMock.ExecuteCalls(), 1, "Execute method should be called once")
+		assert.Equal(t, models.PaymentInstructionStatus(""), trackerMock.ExecuteCalls()[0].State)
+	})
 }
@@ -0,0 +1,89 @@
+package producers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/saltpay/go-kafka-driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	portMocks "github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+// The messages written have to match the latest schema of their subject, a change of the models that drops or renames
+// a property the consumers read fails the tests.
+func TestContractCompatibility(t *testing.T) {
+	written := func() (*mocks.ProducerMock, func() kafka.Message) {
+		producerMock := &mocks.ProducerMock{
+			WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+				return nil
+			},
+		}
+		return producerMock, func() kafka.Message {
+			require.Len(t, producerMock.WriteMessageCalls(), 1)
+			return producerMock.WriteMessageCalls()[0].Message
+		}
+	}
+	assertLatestVersion := func(t *testing.T, subject contracts.Subject, message kafka.Message) {
+		t.Helper()
+		version, err := contracts.Schemas.Validate(subject, message)
+		require.NoError(t, err)
+		assert.Equal(t, contracts.Schemas.Latest(subject), version)
+	}
+
+	t.Run("writes payment provider events that match their schema", func(t *testing.T) {
+		featureFlagMock := &portMocks.FeatureFlagServiceMock{IsKafkaPublishingEnableForAcquiringHostTransactionsFunc: func() bool {
+			return true
+		}}
+
+		for _, eventType := range []models.PaymentProviderEventType{models.Submitted, models.Processed, models.Failure, models.Reversal} {
+			producerMock, message := written()
+			ppEvent := newPaymentProviderEvent()
+			ppEvent.Type = eventType
+			ppEvent.BankingReference = "some-banking-reference"
+			ppEvent.FailureReason = models.FailureReason{Code: models.MissingFunding, Message: "not enough funds"}
+
+			err := producers.NewPaymentStatusExporter(featureFlagMock, producerMock).ReportPaymentStatus(context.Background(), ppEvent)
+
+			require.NoError(t, err, "payment provider event %s", eventType)
+			assertLatestVersion(t, contracts.PaymentProviderEvent, message())
+		}
+	})
+
+	t.Run("writes payment acks that match their schema", func(t *testing.T) {
+		for _, outcome := range []models.PaymentAckOutcome{models.PaymentAckAccepted, models.PaymentAckRejected, models.PaymentAckDuplicate, models.PaymentAckFailed} {
+			producerMock, message := written()
+
+			err := producers.NewPaymentAcknowledger(producerMock).Acknowledge(context.Background(), models.PaymentAck{
+				CorrelationID:        "some-correlation-id",
+				PaymentInstructionID: "some-payment-instruction-id",
+				Outcome:              outcome,
+				ValidationErrors:     []string{"ContractCode should not be empty"},
+				Reason:               "some reason",
+				AcknowledgedAt:       time.Now().UTC(),
+			})
+
+			require.NoError(t, err, "payment ack %s", outcome)
+			assertLatestVersion(t, contracts.PaymentAck, message())
+		}
+	})
+
+	t.Run("doesn't write a message that doesn't match its schema", func(t *testing.T) {
+		producerMock, _ := written()
+
+		err := producers.NewPaymentAcknowledger(producerMock).Acknowledge(context.Background(), models.PaymentAck{
+			CorrelationID:  "some-correlation-id",
+			Outcome:        "MAYBE",
+			AcknowledgedAt: time.Now().UTC(),
+		})
+
+		assert.ErrorContains(t, err, "$.outcome: MAYBE is not one of")
+		assert.Empty(t, producerMock.WriteMessageCalls())
+	})
+}
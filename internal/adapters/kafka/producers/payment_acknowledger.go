@@ -0,0 +1,42 @@
+package producers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// PaymentAcknowledger writes the acks of the incoming instructions to the acks topic, keyed by their correlation ID so
+// that the acks of a payment keep their order. The ack of a message with no correlation ID to read is keyed by the key
+// of the message.
+type PaymentAcknowledger struct {
+	producer Producer
+}
+
+func NewPaymentAcknowledger(producer Producer) *PaymentAcknowledger {
+	return &PaymentAcknowledger{producer: producer}
+}
+
+func (p *PaymentAcknowledger) Acknowledge(ctx context.Context, ack models.PaymentAck) error {
+	msg, err := contracts.Schemas.Message(contracts.PaymentAck, ack)
+	if err != nil {
+		return err
+	}
+	msg.Key = []byte(ack.CorrelationID)
+	if ack.CorrelationID == "" {
+		msg.Key = []byte(ack.MessageKey)
+	}
+
+	err = p.producer.WriteMessage(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("unable to write the payment ack of %s, err: %w", ack.CorrelationID, err)
+	}
+
+	return nil
+}
+
+func (p *PaymentAcknowledger) Close() {
+	p.producer.Close()
+}
@@ -0,0 +1,75 @@
+package producers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/saltpay/go-kafka-driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/producers/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+func TestAcknowledge(t *testing.T) {
+	ack := models.PaymentAck{
+		CorrelationID:        "some-correlation-id",
+		PaymentInstructionID: "some-payment-instruction-id",
+		Outcome:              models.PaymentAckRejected,
+		ValidationErrors:     []string{"ContractCode should not be empty"},
+		AcknowledgedAt:       time.Now().UTC(),
+	}
+
+	t.Run("writes the ack keyed by its correlation ID", func(t *testing.T) {
+		producerMock := &mocks.ProducerMock{
+			WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+				return nil
+			},
+		}
+
+		err := producers.NewPaymentAcknowledger(producerMock).Acknowledge(context.Background(), ack)
+		require.NoError(t, err)
+
+		require.Len(t, producerMock.WriteMessageCalls(), 1)
+		message := producerMock.WriteMessageCalls()[0].Message
+		assert.Equal(t, "some-correlation-id", string(message.Key))
+
+		var written models.PaymentAck
+		require.NoError(t, json.Unmarshal(message.Value, &written))
+		assert.Equal(t, ack, written)
+	})
+
+	t.Run("writes the ack of a message without a correlation ID keyed by the key of the message", func(t *testing.T) {
+		producerMock := &mocks.ProducerMock{
+			WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+				return nil
+			},
+		}
+		unread := models.PaymentAck{Outcome: models.PaymentAckRejected, AcknowledgedAt: time.Now().UTC(), MessageKey: "some-message-key"}
+
+		err := producers.NewPaymentAcknowledger(producerMock).Acknowledge(context.Background(), unread)
+		require.NoError(t, err)
+
+		require.Len(t, producerMock.WriteMessageCalls(), 1)
+		message := producerMock.WriteMessageCalls()[0].Message
+		assert.Equal(t, "some-message-key", string(message.Key))
+		assert.NotContains(t, string(message.Value), "some-message-key")
+	})
+
+	t.Run("returns an error when the ack can't be written", func(t *testing.T) {
+		producerMock := &mocks.ProducerMock{
+			WriteMessageFunc: func(ctx context.Context, message kafka.Message) error {
+				return errors.New("kafka is down")
+			},
+		}
+
+		err := producers.NewPaymentAcknowledger(producerMock).Acknowledge(context.Background(), ack)
+
+		assert.ErrorContains(t, err, "kafka is down")
+	})
+}
@@ -4,13 +4,13 @@ package producers
 
 import (
 	"context"
-	"encoding/json"
 
 	zapctx "github.com/saltpay/go-zap-ctx"
 	"go.uber.org/zap"
 
 	"github.com/saltpay/go-kafka-driver"
 
+	"github.com/saltpay/settlements-payments-system/internal/adapters/kafka/contracts"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
 )
@@ -40,13 +40,13 @@ func (p *PaymentStatusExporter) ReportPaymentStatus(ctx context.Context, ppEvent
 		return nil
 	}
 
-	msg, err := json.Marshal(ppEvent)
+	msg, err := contracts.Schemas.Message(contracts.PaymentProviderEvent, ppEvent)
 	if err != nil {
-		zapctx.Error(ctx, "error while marshaling payment provider event to bytes", zap.Error(err))
+		zapctx.Error(ctx, "error while writing payment provider event as a message", zap.Error(err))
 		return err
 	}
 
-	err = p.producer.WriteMessage(ctx, kafka.Message{Value: msg})
+	err = p.producer.WriteMessage(ctx, msg)
 	if err != nil {
 		zapctx.Error(ctx, "error writing message to acquiring settlements service kafka", zap.Error(err))
 		return err
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/sync"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	defaultRelayInterval = time.Second
+	relayBatchSize       = 50
+	// relayLease must be longer than sending a batch takes, or another relay sends the same entries again
+	relayLease = 5 * time.Minute
+
+	firstRetryDelay = 5 * time.Second
+	maxRetryDelay   = 10 * time.Minute
+
+	dispatchedMetricName     = "app_payment_outbox_dispatched"
+	dispatchFailedMetricName = "app_payment_outbox_dispatch_failed"
+	dispatchDelayMetricName  = "app_payment_outbox_dispatch_delay_sec"
+)
+
+// Relay hands the payment instructions in the outbox to their payment provider, retrying the ones that fail
+// with an exponential backoff until they are accepted.
+type Relay struct {
+	outbox        ports.PaymentInstructionOutbox
+	sender        ports.PaymentProviderRequestSender
+	metricsClient ports.MetricsClient
+	interval      time.Duration
+	shouldRun     *sync.AtomicBool
+}
+
+func NewRelay(outbox ports.PaymentInstructionOutbox, sender ports.PaymentProviderRequestSender, metricsClient ports.MetricsClient, interval time.Duration) Relay {
+	if interval <= 0 {
+		interval = defaultRelayInterval
+	}
+
+	shouldRun := sync.New()
+	shouldRun.Set()
+	return Relay{
+		outbox:        outbox,
+		sender:        sender,
+		metricsClient: metricsClient,
+		interval:      interval,
+		shouldRun:     shouldRun,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	for r.shouldRun.IsSet() {
+		if _, err := r.RelayPending(ctx); err != nil {
+			zapctx.Error(ctx, "[OutboxRelay] (Run) unable to relay the payment instruction outbox", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+func (r *Relay) Stop() {
+	r.shouldRun.UnSet()
+}
+
+// RelayPending sends every outbox entry that is due and returns how many of them were sent.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	dispatched := 0
+	for {
+		entries, err := r.outbox.ClaimOutboxEntries(ctx, relayBatchSize, relayLease)
+		if err != nil {
+			return dispatched, err
+		}
+
+		for _, entry := range entries {
+			if r.dispatch(ctx, entry) {
+				dispatched++
+			}
+		}
+
+		if len(entries) < relayBatchSize {
+			return dispatched, nil
+		}
+	}
+}
+
+func (r *Relay) dispatch(ctx context.Context, entry models.OutboxEntry) bool {
+	provider := entry.PaymentInstruction.PaymentProvider()
+
+	if err := r.sender.SendPaymentInstruction(ctx, entry.PaymentInstruction); err != nil {
+		retryAt := time.Now().Add(retryDelay(entry.Attempts))
+		zapctx.Error(ctx, "[OutboxRelay] (dispatch) unable to send payment instruction to the payment provider",
+			zap.String("payment_instruction_id", string(entry.PaymentInstruction.ID())),
+			zap.String("payment_provider", string(provider)),
+			zap.Int("attempts", entry.Attempts),
+			zap.Time("retry_at", retryAt),
+			zap.Error(err),
+		)
+		r.metricsClient.Count(ctx, dispatchFailedMetricName, 1, []string{string(provider)})
+		if markErr := r.outbox.MarkOutboxEntryFailed(ctx, entry.ID, retryAt, err.Error()); markErr != nil {
+			zapctx.Error(ctx, "[OutboxRelay] (dispatch) unable to schedule the retry, the entry is retried when its lease runs out",
+				zap.String("payment_instruction_id", string(entry.PaymentInstruction.ID())),
+				zap.Error(markErr),
+			)
+		}
+		return false
+	}
+
+	r.metricsClient.Count(ctx, dispatchedMetricName, 1, []string{string(provider)})
+	r.metricsClient.Histogram(ctx, dispatchDelayMetricName, time.Since(entry.CreatedAt).Seconds(), []string{string(provider)})
+
+	// the payment instruction is with the payment provider already, if this fails it is sent again once the lease runs out
+	if err := r.outbox.MarkOutboxEntryDispatched(ctx, entry.ID); err != nil {
+		zapctx.Error(ctx, "[OutboxRelay] (dispatch) unable to mark the payment instruction as dispatched",
+			zap.String("payment_instruction_id", string(entry.PaymentInstruction.ID())),
+			zap.Error(err),
+		)
+	}
+	return true
+}
+
+// retryDelay doubles with every failed attempt, from firstRetryDelay up to maxRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/outbox"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestRelay_RelayPending(t *testing.T) {
+	newOutbox := func(entries ...models.OutboxEntry) *mocks.PaymentInstructionOutboxMock {
+		claimed := false
+		return &mocks.PaymentInstructionOutboxMock{
+			ClaimOutboxEntriesFunc: func(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEntry, error) {
+				if claimed {
+					return nil, nil
+				}
+				claimed = true
+				return entries, nil
+			},
+			MarkOutboxEntryDispatchedFunc: func(ctx context.Context, id models.OutboxEntryID) error {
+				return nil
+			},
+			MarkOutboxEntryFailedFunc: func(ctx context.Context, id models.OutboxEntryID, retryAt time.Time, reason string) error {
+				return nil
+			},
+		}
+	}
+
+	t.Run("sends the claimed payment instructions and marks them dispatched", func(t *testing.T) {
+		var (
+			ctx         = context.Background()
+			instruction = testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+			spyOutbox   = newOutbox(models.OutboxEntry{ID: 7, PaymentInstruction: instruction, Attempts: 1})
+			sender      = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
+				return nil
+			}}
+		)
+
+		relay := outbox.NewRelay(spyOutbox, sender, testdoubles.DummyMetricsClient{}, 0)
+		dispatched, err := relay.RelayPending(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+		require.Len(t, sender.SendPaymentInstructionCalls(), 1)
+		assert.Equal(t, instruction.ID(), sender.SendPaymentInstructionCalls()[0].PaymentInstruction.ID())
+		require.Len(t, spyOutbox.MarkOutboxEntryDispatchedCalls(), 1)
+		assert.Equal(t, models.OutboxEntryID(7), spyOutbox.MarkOutboxEntryDispatchedCalls()[0].ID)
+		assert.Empty(t, spyOutbox.MarkOutboxEntryFailedCalls())
+	})
+
+	t.Run("schedules a retry when the payment provider can't be reached", func(t *testing.T) {
+		var (
+			ctx         = context.Background()
+			instruction = testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.Islandsbanki).Build()
+			spyOutbox   = newOutbox(models.OutboxEntry{ID: 3, PaymentInstruction: instruction, Attempts: 3})
+			sender      = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
+				return errors.New("kafka is down")
+			}}
+		)
+
+		relay := outbox.NewRelay(spyOutbox, sender, testdoubles.DummyMetricsClient{}, 0)
+		before := time.Now()
+		dispatched, err := relay.RelayPending(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, dispatched)
+		assert.Empty(t, spyOutbox.MarkOutboxEntryDispatchedCalls())
+		require.Len(t, spyOutbox.MarkOutboxEntryFailedCalls(), 1)
+		failed := spyOutbox.MarkOutboxEntryFailedCalls()[0]
+		assert.Equal(t, models.OutboxEntryID(3), failed.ID)
+		assert.Equal(t, "kafka is down", failed.Reason)
+		assert.WithinDuration(t, before.Add(20*time.Second), failed.RetryAt, time.Second)
+	})
+
+	t.Run("returns the error when the outbox can't be read", func(t *testing.T) {
+		failingOutbox := &mocks.PaymentInstructionOutboxMock{
+			ClaimOutboxEntriesFunc: func(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEntry, error) {
+				return nil, errors.New("db is down")
+			},
+		}
+
+		relay := outbox.NewRelay(failingOutbox, &mocks.PaymentProviderRequestSenderMock{}, testdoubles.DummyMetricsClient{}, 0)
+		_, err := relay.RelayPending(context.Background())
+
+		assert.EqualError(t, err, "db is down")
+	})
+}
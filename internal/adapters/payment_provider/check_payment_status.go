@@ -0,0 +1,53 @@
+package payment_provider
+
+import (
+	"context"
+	"time"
+
+	bcports "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	bcusecases "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/use_cases"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+// BankingCircleStatusChecker asks Banking Circle once for the status of a payment, unlike CheckBankingCirclePaymentStatus
+// it doesn't wait for a pending payment to settle.
+type BankingCircleStatusChecker struct {
+	statusChecker bcports.BankingCirclePaymentStatusChecker
+	now           func() time.Time
+}
+
+var _ ports.PaymentProviderStatusChecker = BankingCircleStatusChecker{}
+
+func NewBankingCircleStatusChecker(statusChecker bcports.BankingCirclePaymentStatusChecker) BankingCircleStatusChecker {
+	return BankingCircleStatusChecker{
+		statusChecker: statusChecker,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (c BankingCircleStatusChecker) CheckPaymentStatus(ctx context.Context, stuck models.StuckPaymentInstruction) (models.PaymentProviderEvent, bool, error) {
+	status, err := c.statusChecker.CheckPaymentStatus(stuck.ProviderPaymentID)
+	if err != nil {
+		return models.PaymentProviderEvent{}, false, err
+	}
+
+	switch status.Outcome() {
+	case bcports.OutcomePending, bcports.OutcomeNeedsManualAction:
+		return models.PaymentProviderEvent{}, false, nil
+	case bcports.OutcomeSucceeded:
+		event, err := models.NewPaymentProviderEvent(c.now(), models.Processed, stuck.PaymentInstruction, models.BC, stuck.ProviderPaymentID, stuck.BankingReference, nil)
+		return event, err == nil, err
+	case bcports.OutcomeReversed:
+		event, err := models.NewPaymentProviderEvent(c.now(), models.Reversal, stuck.PaymentInstruction, models.BC, stuck.ProviderPaymentID, stuck.BankingReference, nil)
+		return event, err == nil, err
+	default:
+		event, err := models.NewPaymentProviderEvent(c.now(), models.Failure, stuck.PaymentInstruction, models.BC, stuck.ProviderPaymentID, stuck.BankingReference, &models.FailureReason{
+			Code:    bcusecases.MapStatusToFailureCode(status),
+			Message: bcusecases.BankingCircleError{Status: status}.Error(),
+		})
+		return event, err == nil, err
+	}
+}
@@ -0,0 +1,82 @@
+package payment_provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bcports "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	mocks3 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_provider"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+)
+
+func TestBankingCircleStatusChecker_CheckPaymentStatus(t *testing.T) {
+	stuck := models.StuckPaymentInstruction{
+		PaymentInstruction: testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build(),
+		ProviderPaymentID:  "bc-payment-id",
+		BankingReference:   "bc-reference",
+	}
+	statusCheckerReturning := func(status bcports.PaymentStatus, err error) *mocks3.BankingCircleAPIMock {
+		return &mocks3.BankingCircleAPIMock{CheckPaymentStatusFunc: func(paymentID models.ProviderPaymentID) (bcports.PaymentStatus, error) {
+			return status, err
+		}}
+	}
+
+	t.Run("a processed payment is settled with a processed event", func(t *testing.T) {
+		spyAPI := statusCheckerReturning(bcports.Processed, nil)
+
+		event, settled, err := payment_provider.NewBankingCircleStatusChecker(spyAPI).CheckPaymentStatus(context.Background(), stuck)
+
+		require.NoError(t, err)
+		assert.True(t, settled)
+		assert.Equal(t, models.ProviderPaymentID("bc-payment-id"), spyAPI.CheckPaymentStatusCalls()[0].PaymentID)
+		assert.Equal(t, models.Processed, event.Type)
+		assert.Equal(t, stuck.PaymentInstruction.ID(), event.PaymentInstruction.ID())
+		assert.Equal(t, models.ProviderPaymentID("bc-payment-id"), event.PaymentProviderPaymentID)
+		assert.Equal(t, models.BankingReference("bc-reference"), event.BankingReference)
+	})
+
+	t.Run("a rejected payment is settled with a failure event", func(t *testing.T) {
+		event, settled, err := payment_provider.NewBankingCircleStatusChecker(statusCheckerReturning(bcports.Rejected, nil)).CheckPaymentStatus(context.Background(), stuck)
+
+		require.NoError(t, err)
+		assert.True(t, settled)
+		assert.Equal(t, models.Failure, event.Type)
+		assert.Equal(t, models.RejectedCode, event.FailureReason.Code)
+	})
+
+	t.Run("a pending payment is not settled", func(t *testing.T) {
+		_, settled, err := payment_provider.NewBankingCircleStatusChecker(statusCheckerReturning(bcports.PendingProcessing, nil)).CheckPaymentStatus(context.Background(), stuck)
+
+		require.NoError(t, err)
+		assert.False(t, settled)
+	})
+
+	t.Run("a payment held for someone to approve is not settled", func(t *testing.T) {
+		_, settled, err := payment_provider.NewBankingCircleStatusChecker(statusCheckerReturning(bcports.PendingApproval, nil)).CheckPaymentStatus(context.Background(), stuck)
+
+		require.NoError(t, err)
+		assert.False(t, settled)
+	})
+
+	t.Run("a reversed payment is settled with a reversal event", func(t *testing.T) {
+		event, settled, err := payment_provider.NewBankingCircleStatusChecker(statusCheckerReturning(bcports.Reversed, nil)).CheckPaymentStatus(context.Background(), stuck)
+
+		require.NoError(t, err)
+		assert.True(t, settled)
+		assert.Equal(t, models.Reversal, event.Type)
+		assert.Equal(t, models.Reversed, event.OutcomeStatus())
+	})
+
+	t.Run("returns the error when Banking Circle can't be reached", func(t *testing.T) {
+		_, settled, err := payment_provider.NewBankingCircleStatusChecker(statusCheckerReturning("", errors.New("timeout"))).CheckPaymentStatus(context.Background(), stuck)
+
+		assert.EqualError(t, err, "timeout")
+		assert.False(t, settled)
+	})
+}
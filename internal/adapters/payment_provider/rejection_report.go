@@ -0,0 +1,58 @@
+package payment_provider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	bcports "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	bcusecases "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/use_cases"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+// BankingCircleRejectionReport reads the rejections of Banking Circle's rejection report.
+type BankingCircleRejectionReport struct {
+	rejectionReport bcports.GetBankingCircleRejectionReport
+}
+
+var _ ports.PaymentProviderRejectionReport = BankingCircleRejectionReport{}
+
+func NewBankingCircleRejectionReport(rejectionReport bcports.GetBankingCircleRejectionReport) BankingCircleRejectionReport {
+	return BankingCircleRejectionReport{rejectionReport: rejectionReport}
+}
+
+func (r BankingCircleRejectionReport) GetRejections(_ context.Context, date time.Time) ([]models.ProviderRejection, error) {
+	report, err := r.rejectionReport.Execute(date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	rejections := make([]models.ProviderRejection, 0, len(report.Rejections))
+	for _, rejection := range report.Rejections {
+		rejections = append(rejections, newProviderRejection(rejection))
+	}
+	return rejections, nil
+}
+
+// newProviderRejection takes the execution date from the reference the payment was requested with, and falls back to
+// the value date of the payment for a reference that can't be read.
+func newProviderRejection(rejection bcmodels.Rejection) models.ProviderRejection {
+	contractNumber, executionDate, ok := bcusecases.ParseDebtorReference(rejection.UserReferenceNumber)
+	if !ok {
+		executionDate = rejection.ValueDate
+	}
+
+	return models.ProviderRejection{
+		PaymentReference:   strings.TrimSpace(rejection.PaymentReferenceNumber),
+		ContractNumber:     contractNumber,
+		ExecutionDate:      executionDate,
+		DestinationAccount: strings.ToUpper(strings.ReplaceAll(rejection.DestinationIban, " ", "")),
+		Amount:             strconv.FormatFloat(rejection.PaymentAmount, 'f', -1, 64),
+		Currency:           models.CurrencyCode(rejection.PaymentCurrency),
+		Status:             rejection.Status,
+		StatusReason:       rejection.StatusReason,
+	}
+}
@@ -0,0 +1,66 @@
+package payment_provider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
+	mocks3 "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_provider"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+func TestBankingCircleRejectionReport_GetRejections(t *testing.T) {
+	reportDate := time.Date(2022, 9, 21, 0, 0, 0, 0, time.UTC)
+	valueDate := time.Date(2022, 9, 19, 0, 0, 0, 0, time.UTC)
+
+	reportOf := func(rejections ...bcmodels.Rejection) *mocks3.GetBankingCircleRejectionReportMock {
+		return &mocks3.GetBankingCircleRejectionReportMock{ExecuteFunc: func(date string) (bcmodels.RejectionReport, error) {
+			return bcmodels.RejectionReport{Rejections: rejections}, nil
+		}}
+	}
+
+	t.Run("reads the contract number and execution date from the reference the payment was requested with", func(t *testing.T) {
+		spyReport := reportOf(bcmodels.Rejection{
+			ValueDate:              valueDate,
+			PaymentAmount:          1250.5,
+			PaymentCurrency:        "EUR",
+			DestinationIban:        "gb33 bukb 2020 1555 5555 55",
+			PaymentReferenceNumber: "bc-reference",
+			UserReferenceNumber:    "Settlm 123456 20220920",
+			Status:                 "Rejected",
+			StatusReason:           "Beneficiary account closed",
+		})
+
+		rejections, err := payment_provider.NewBankingCircleRejectionReport(spyReport).GetRejections(context.Background(), reportDate)
+
+		require.NoError(t, err)
+		assert.Equal(t, "2022-09-21", spyReport.ExecuteCalls()[0].Date)
+		assert.Equal(t, []models.ProviderRejection{{
+			PaymentReference:   "bc-reference",
+			ContractNumber:     "123456",
+			ExecutionDate:      time.Date(2022, 9, 20, 0, 0, 0, 0, time.UTC),
+			DestinationAccount: "GB33BUKB20201555555555",
+			Amount:             "1250.5",
+			Currency:           models.EUR,
+			Status:             "Rejected",
+			StatusReason:       "Beneficiary account closed",
+		}}, rejections)
+	})
+
+	t.Run("falls back to the value date of a payment whose reference can't be read", func(t *testing.T) {
+		rejections, err := payment_provider.NewBankingCircleRejectionReport(reportOf(bcmodels.Rejection{
+			ValueDate:           valueDate,
+			UserReferenceNumber: "something else",
+		})).GetRejections(context.Background(), reportDate)
+
+		require.NoError(t, err)
+		require.Len(t, rejections, 1)
+		assert.Empty(t, rejections[0].ContractNumber)
+		assert.Equal(t, valueDate, rejections[0].ExecutionDate)
+	})
+}
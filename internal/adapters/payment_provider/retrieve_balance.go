@@ -15,6 +15,23 @@ func NewPaymentProvider(retrieveBalanceUseCase ports.RetrieveBankingCircleAccoun
 	}
 }
 
-func (p PaymentProvider) RetrieveBalanceForCurrency(currency models.CurrencyCode, highRisk bool) (float64, float64, error) {
-	return p.retrieveBalanceUseCase.Execute(string(currency), highRisk)
+func (p PaymentProvider) RetrieveBalanceForCurrency(currency models.CurrencyCode, highRisk bool, amount float64) (float64, float64, error) {
+	return p.retrieveBalanceUseCase.Execute(string(currency), highRisk, amount)
+}
+
+func (p PaymentProvider) RetrieveAccountsFunds(currency models.CurrencyCode, highRisk bool) ([]models.AccountFunds, error) {
+	accountsFunds, err := p.retrieveBalanceUseCase.AccountsFunds(string(currency), highRisk)
+	if err != nil {
+		return nil, err
+	}
+
+	funds := make([]models.AccountFunds, 0, len(accountsFunds))
+	for _, accountFunds := range accountsFunds {
+		funds = append(funds, models.AccountFunds{
+			Account:         accountFunds.Iban,
+			Balance:         accountFunds.Balance,
+			MaxIntraDayLoan: accountFunds.MaxIntraDayLoan,
+		})
+	}
+	return funds, nil
 }
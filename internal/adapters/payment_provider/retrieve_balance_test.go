@@ -11,11 +11,11 @@ import (
 
 func TestPaymentProvider_RetrieveBalanceForCurrency(t *testing.T) {
 	t.Run("returns the account balances for a currency", func(t *testing.T) {
-		retrieveBalanceUseCaseMock := &mocks3.RetrieveBankingCircleAccountFundsMock{ExecuteFunc: func(currency string, highRisk bool) (float64, float64, error) {
+		retrieveBalanceUseCaseMock := &mocks3.RetrieveBankingCircleAccountFundsMock{ExecuteFunc: func(currency string, highRisk bool, amount float64) (float64, float64, error) {
 			return 1000000000.00, 1000000000.00, nil
 		}}
 		paymentProvider := payment_provider.NewPaymentProvider(retrieveBalanceUseCaseMock)
-		beginOfDayAmount, intraDayAmount, err := paymentProvider.RetrieveBalanceForCurrency("EUR", false)
+		beginOfDayAmount, intraDayAmount, err := paymentProvider.RetrieveBalanceForCurrency("EUR", false, 100)
 		assert.NoError(t, err)
 		assert.Equal(t, 1000000000.00, beginOfDayAmount)
 		assert.Equal(t, 1000000000.00, intraDayAmount)
@@ -0,0 +1,24 @@
+package payment_provider
+
+import (
+	"context"
+
+	bcports "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+// BankingCircleSubmittedPayments finds the payment instructions Banking Circle accepted in the payment instruction store.
+type BankingCircleSubmittedPayments struct {
+	repo ports.ProviderPaymentRepo
+}
+
+var _ bcports.SubmittedPaymentFinder = BankingCircleSubmittedPayments{}
+
+func NewBankingCircleSubmittedPayments(repo ports.ProviderPaymentRepo) BankingCircleSubmittedPayments {
+	return BankingCircleSubmittedPayments{repo: repo}
+}
+
+func (s BankingCircleSubmittedPayments) FindSubmittedPayment(ctx context.Context, paymentID models.ProviderPaymentID) (models.PaymentInstruction, models.BankingReference, error) {
+	return s.repo.GetFromProviderPaymentID(ctx, models.BankingCircle, paymentID)
+}
@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+)
+
+const backfillBatchSize = 500
+
+// a payment instruction stored before the normalised schema only has its body, and is recognisable by its NULL status.
+const backfillColumnsQuery = `UPDATE payment_instructions SET
+		contract_number = body->'incomingInstruction'->'merchant'->>'contractNumber',
+		account_number = body->'incomingInstruction'->'merchant'->'account'->>'accountNumber',
+		amount = CASE WHEN TRIM(body->'incomingInstruction'->'payment'->>'amount') ~ '^-?[0-9]+(\.[0-9]+)?$'
+			THEN TRIM(body->'incomingInstruction'->'payment'->>'amount')::numeric END,
+		currency = body->'incomingInstruction'->'payment'->'currency'->>'isoCode',
+		high_risk = COALESCE((body->'incomingInstruction'->'merchant'->>'highRisk')::boolean, false),
+		execution_date = CASE WHEN body->'incomingInstruction'->'payment'->>'executionDate' ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}'
+			THEN TO_DATE(LEFT(body->'incomingInstruction'->'payment'->>'executionDate', 10), 'YYYY-MM-DD') END,
+		status = COALESCE(body->>'status', ''),
+		payment_provider = COALESCE(body->>'paymentProvider', ''),
+		version = COALESCE((body->>'version')::int, 0),
+		correlation_id = body->'incomingInstruction'->>'paymentCorrelationId',
+		incoming_instruction = COALESCE(body->'incomingInstruction', '{}'::jsonb)
+	WHERE payment_instruction_id = ANY($1) AND status IS NULL`
+
+const backfillEventsQuery = `INSERT INTO payment_instruction_events (payment_instruction_id, sequence, type, failure_reason_code, created_on, event)
+	SELECT pi.payment_instruction_id,
+		e.sequence,
+		COALESCE(e.event->>'type', ''),
+		COALESCE(e.event->'details'->'failureReason'->>'code', '') || COALESCE(e.event->'details'->'rejectionReason'->>'code', ''),
+		COALESCE((e.event->>'createdOn')::timestamptz, now()),
+		e.event
+	FROM payment_instructions pi,
+		jsonb_array_elements(CASE WHEN jsonb_typeof(pi.body->'events') = 'array' THEN pi.body->'events' ELSE '[]'::jsonb END)
+			WITH ORDINALITY AS e(event, sequence)
+	WHERE pi.payment_instruction_id = ANY($1) AND pi.status IS NULL
+	ON CONFLICT DO NOTHING`
+
+// BackfillNormalisedSchema copies the payment instructions stored as a single JSON body into the instruction columns
+// and the payment_instruction_events table, a batch at a time so the table is never locked as a whole.
+// It can run from several instances at once and returns the number of payment instructions it backfilled.
+func (s PostgresStore) BackfillNormalisedSchema(ctx context.Context, batchSize int) (int, error) {
+	backfilled := 0
+	for {
+		n, err := s.backfillBatch(ctx, batchSize)
+		if err != nil {
+			return backfilled, err
+		}
+		if n == 0 {
+			return backfilled, nil
+		}
+		backfilled += n
+	}
+}
+
+func (s PostgresStore) backfillBatch(ctx context.Context, batchSize int) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT payment_instruction_id FROM payment_instructions WHERE status IS NULL LIMIT $1 FOR UPDATE SKIP LOCKED`,
+		batchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := backfillInstructions(ctx, tx, ids); err != nil {
+		return 0, err
+	}
+
+	return len(ids), tx.Commit()
+}
+
+// backfillInstructions must run in the transaction that locked the given payment instructions.
+func backfillInstructions(ctx context.Context, tx *sql.Tx, ids []string) error {
+	// events first, the column backfill clears the NULL status they are selected by
+	if _, err := tx.ExecContext(ctx, backfillEventsQuery, pq.Array(ids)); err != nil {
+		return fmt.Errorf("unable to backfill payment instruction events, err: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, backfillColumnsQuery, pq.Array(ids)); err != nil {
+		return fmt.Errorf("unable to backfill payment instructions, err: %w", err)
+	}
+	return nil
+}
+
+func (s PostgresStore) backfillInBackground(ctx context.Context) {
+	start := time.Now()
+	backfilled, err := s.BackfillNormalisedSchema(ctx, backfillBatchSize)
+	if err != nil {
+		zapctx.Error(ctx, "[PostgresStore] (backfillInBackground) backfill of the normalised schema failed",
+			zap.Int("backfilled", backfilled),
+			zap.Error(err),
+		)
+		return
+	}
+	if backfilled > 0 {
+		zapctx.Info(ctx, "[PostgresStore] (backfillInBackground) backfill of the normalised schema is done",
+			zap.Int("backfilled", backfilled),
+			zap.String("elapsedTime", time.Since(start).String()),
+		)
+	}
+}
@@ -0,0 +1,120 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	testhelpers2 "github.com/saltpay/settlements-payments-system/internal/testhelpers"
+)
+
+func TestBackfillNormalisedSchema(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	// storeLegacy stores a payment instruction the way it was stored before the normalised schema
+	storeLegacy := func(t *testing.T, instruction models.PaymentInstruction) {
+		t.Helper()
+		body, err := instruction.MarshalJSON()
+		require.NoError(t, err)
+		_, err = paymentStore.db.ExecContext(ctx, `INSERT INTO payment_instructions (payment_instruction_id, body) VALUES ($1, $2)`, instruction.ID(), body)
+		require.NoError(t, err)
+	}
+
+	newFailedInstruction := func() models.PaymentInstruction {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(
+			testhelpers.NewIncomingInstructionBuilder().WithPaymentExecutionDate(time.Now()).WithPaymentAmount("12.50").Build(),
+		).Build()
+		instruction.IncomingInstruction.PaymentCorrelationId = testhelpers2.RandomString()
+		instruction.SubmitForProcessing()
+		instruction.TrackPPEvent(models.PaymentProviderEvent{
+			Type:               models.Failure,
+			PaymentInstruction: instruction,
+			FailureReason:      models.FailureReason{Code: models.RejectedCode, Message: "rejected"},
+		})
+		return instruction
+	}
+
+	t.Run("a payment instruction that is not backfilled yet can still be read", func(t *testing.T) {
+		instruction := newFailedInstruction()
+		storeLegacy(t, instruction)
+
+		stored, err := paymentStore.Get(ctx, instruction.ID())
+		require.NoError(t, err)
+		assert.Equal(t, instruction.GetStatus(), stored.GetStatus())
+		assert.Len(t, stored.Events(), len(instruction.Events()))
+	})
+
+	t.Run("backfilled payment instructions are found by the normalised queries", func(t *testing.T) {
+		instruction := newFailedInstruction()
+		storeLegacy(t, instruction)
+
+		_, err := paymentStore.BackfillNormalisedSchema(ctx, 1)
+		require.NoError(t, err)
+
+		stored, err := paymentStore.GetFromCorrelationID(ctx, instruction.IncomingInstruction.PaymentCorrelationId)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, instruction.ID(), stored[0].ID())
+		assert.Equal(t, instruction.Version(), stored[0].Version())
+		assert.Equal(t, instruction.IncomingInstruction.Payment.Amount, stored[0].IncomingInstruction.Payment.Amount)
+		assert.Len(t, stored[0].Events(), len(instruction.Events()))
+
+		var (
+			amount string
+			reason string
+		)
+		err = paymentStore.db.QueryRowContext(ctx, `SELECT amount::text FROM payment_instructions WHERE payment_instruction_id = $1`, instruction.ID()).Scan(&amount)
+		require.NoError(t, err)
+		assert.Equal(t, "12.50", amount)
+
+		err = paymentStore.db.QueryRowContext(ctx,
+			`SELECT failure_reason_code FROM payment_instruction_events WHERE payment_instruction_id = $1 ORDER BY sequence DESC LIMIT 1`,
+			instruction.ID(),
+		).Scan(&reason)
+		require.NoError(t, err)
+		assert.Equal(t, string(models.RejectedPayment), reason)
+	})
+
+	t.Run("updating a payment instruction that is not backfilled yet backfills it first", func(t *testing.T) {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithStatus(models.SubmittedForProcessing).Build()
+		instruction.IncomingInstruction.PaymentCorrelationId = testhelpers2.RandomString()
+		storeLegacy(t, instruction)
+
+		err := paymentStore.UpdatePayment(ctx, instruction.ID(), instruction.Version(), models.Successful, models.PaymentInstructionEvent{
+			Type:      models.DomainProcessingSucceeded,
+			CreatedOn: time.Time{},
+			Details:   "",
+		})
+		require.NoError(t, err)
+
+		stored, err := paymentStore.GetFromCorrelationID(ctx, instruction.IncomingInstruction.PaymentCorrelationId)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, models.Successful, stored[0].GetStatus())
+		assert.Equal(t, instruction.Version()+1, stored[0].Version())
+		assert.Len(t, stored[0].Events(), len(instruction.Events())+1)
+	})
+}
@@ -0,0 +1,133 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	recordDlqRedriveQuery         = "recordDlqRedrive"
+	redrivenDlqMessageIDsQuery    = "redrivenDlqMessageIDs"
+	recordRedrivenDlqMessageQuery = "recordRedrivenDlqMessage"
+	forgetRedrivenDlqMessageQuery = "forgetRedrivenDlqMessage"
+)
+
+var _ ports.DlqRedriveAudit = PostgresStore{}
+
+func (s PostgresStore) RecordDlqRedrive(ctx context.Context, redrive models.DlqRedrive) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, recordDlqRedriveQuery)
+	defer postgresTracing.EndSpan(span)
+
+	messages := make([]models.RedrivenDlqMessage, 0, len(redrive.Messages))
+	for _, message := range redrive.Messages {
+		message.Body = ""
+		messages = append(messages, message)
+	}
+	messagesJSON, err := json.Marshal(messages)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO dlq_redrives (queue, source_queue, redriven_by, message_count, messages, redriven_at)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+		redrive.Queue,
+		redrive.SourceQueue,
+		redrive.RedrivenBy,
+		len(messages),
+		messagesJSON,
+		redrive.RedrivenAt,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to record the redrive of %s by %s, err: %w", redrive.Queue, redrive.RedrivenBy, err)
+	}
+
+	// the messages are recorded one by one before they are redriven already, this covers the redrives that don't
+	for _, message := range messages {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO dlq_redriven_messages (queue, message_id, redriven_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			redrive.Queue,
+			message.MessageID,
+			redrive.RedrivenAt,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to record the redrive of message %s of %s, err: %w", message.MessageID, redrive.Queue, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s PostgresStore) RedrivenDlqMessageIDs(ctx context.Context, queue string) ([]string, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, redrivenDlqMessageIDsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT message_id FROM dlq_redriven_messages WHERE queue = $1`,
+		queue,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the messages redriven from %s, err: %w", queue, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// RecordRedrivenDlqMessage relies on the primary key of the message, of the redrives recording it at the same time
+// only one inserts it.
+func (s PostgresStore) RecordRedrivenDlqMessage(ctx context.Context, queue string, messageID string) (bool, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, recordRedrivenDlqMessageQuery)
+	defer postgresTracing.EndSpan(span)
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO dlq_redriven_messages (queue, message_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		queue,
+		messageID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("unable to record the redrive of message %s of %s, err: %w", messageID, queue, err)
+	}
+
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return recorded == 1, nil
+}
+
+func (s PostgresStore) ForgetRedrivenDlqMessage(ctx context.Context, queue string, messageID string) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, forgetRedrivenDlqMessageQuery)
+	defer postgresTracing.EndSpan(span)
+
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM dlq_redriven_messages WHERE queue = $1 AND message_id = $2`,
+		queue,
+		messageID,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to forget the redrive of message %s of %s, err: %w", messageID, queue, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	testhelpers2 "github.com/saltpay/settlements-payments-system/internal/testhelpers"
+)
+
+func TestDlqRedriveAudit(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	t.Run("records who redrove which messages, without their bodies", func(t *testing.T) {
+		redrivenBy := testhelpers2.RandomString()
+		redrive := models.DlqRedrive{
+			Queue:       "bc-unprocessed-dlq",
+			SourceQueue: "bc-unprocessed",
+			RedrivenBy:  redrivenBy,
+			Messages: []models.RedrivenDlqMessage{
+				{MessageID: "message-1", PaymentInstructionID: "pi-1", ContractNumber: "1234567", Edited: true, Body: `{"id":"pi-1"}`},
+			},
+			RedrivenAt: time.Now(),
+		}
+
+		require.NoError(t, paymentStore.RecordDlqRedrive(ctx, redrive))
+
+		var (
+			count    int
+			messages string
+		)
+		row := paymentStore.db.QueryRowContext(ctx, `SELECT message_count, messages FROM dlq_redrives WHERE redriven_by = $1`, redrivenBy)
+		require.NoError(t, row.Scan(&count, &messages))
+		assert.Equal(t, 1, count)
+		assert.Contains(t, messages, `"paymentInstructionId": "pi-1"`)
+		assert.NotContains(t, messages, `"body"`)
+	})
+
+	t.Run("reads the IDs of the messages the redrives of a dead letter topic moved", func(t *testing.T) {
+		topic := testhelpers2.RandomString()
+		for _, ids := range [][]string{{"0/1", "0/2"}, {"0/2", "1/5"}} {
+			redrive := models.DlqRedrive{Queue: topic, SourceQueue: "source", RedrivenBy: "jane.doe", RedrivenAt: time.Now()}
+			for _, id := range ids {
+				redrive.Messages = append(redrive.Messages, models.RedrivenDlqMessage{MessageID: id})
+			}
+			require.NoError(t, paymentStore.RecordDlqRedrive(ctx, redrive))
+		}
+
+		ids, err := paymentStore.RedrivenDlqMessageIDs(ctx, topic)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"0/1", "0/2", "1/5"}, ids)
+
+		ids, err = paymentStore.RedrivenDlqMessageIDs(ctx, testhelpers2.RandomString())
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("records a message redriven once, and forgets it when asked", func(t *testing.T) {
+		topic := testhelpers2.RandomString()
+
+		recorded, err := paymentStore.RecordRedrivenDlqMessage(ctx, topic, "0/1")
+		require.NoError(t, err)
+		assert.True(t, recorded)
+
+		recorded, err = paymentStore.RecordRedrivenDlqMessage(ctx, topic, "0/1")
+		require.NoError(t, err)
+		assert.False(t, recorded, "a message recorded already is not redriven again")
+
+		ids, err := paymentStore.RedrivenDlqMessageIDs(ctx, topic)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0/1"}, ids)
+
+		require.NoError(t, paymentStore.ForgetRedrivenDlqMessage(ctx, topic, "0/1"))
+		recorded, err = paymentStore.RecordRedrivenDlqMessage(ctx, topic, "0/1")
+		require.NoError(t, err)
+		assert.True(t, recorded)
+	})
+}
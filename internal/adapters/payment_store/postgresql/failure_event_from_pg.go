@@ -1,6 +1,10 @@
 package postgresql
 
-import "github.com/saltpay/settlements-payments-system/internal/domain/models"
+import (
+	"encoding/json"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
 
 type failureEventFromPG struct {
 	Details errorDetails `json:"details"`
@@ -18,3 +22,10 @@ type errorDetails struct {
 	RejectedReason code `json:"rejectionReason"`
 	FailureReason  code `json:"failureReason"`
 }
+
+// failureReasonCodeOf returns the failure or rejection reason of a JSON encoded PaymentInstructionEvent, if it has any.
+func failureReasonCodeOf(event []byte) models.DomainFailureReasonCode {
+	var failureEvent failureEventFromPG
+	_ = json.Unmarshal(event, &failureEvent)
+	return failureEvent.GetCode()
+}
@@ -0,0 +1,195 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	reserveFundsQuery  = "reserveFunds"
+	releaseFundsQuery  = "releaseFunds"
+	holdFundsQuery     = "holdFunds"
+	reservedFundsQuery = "reservedFunds"
+
+	// reservationExpiry is how long a reservation is kept when its payment provider never reports the outcome of its
+	// payment instruction, its funds are either paid or back in the account by then.
+	reservationExpiry = 72 * time.Hour
+	// holdExpiry is how long a hold is kept when the flow of its file or batch stops before releasing it.
+	holdExpiry = 6 * time.Hour
+)
+
+var _ ports.LiquidityLedger = PostgresStore{}
+
+func (s PostgresStore) Reserve(ctx context.Context, reservation models.FundsReservation) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, reserveFundsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := insertReservation(ctx, tx, reservation); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertReservation waits for the holds of the same currency and risk class being checked, so they see the reservation.
+// The amount is taken out of the hold of the reservation in the same step, the funds are counted once by the hold or
+// the reservation. A reservation without an account is counted against all the accounts of its currency and risk class.
+func insertReservation(ctx context.Context, tx *sql.Tx, reservation models.FundsReservation) error {
+	if err := lockReservations(ctx, tx, reservation.Currency, reservation.HighRisk); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO liquidity_reservations (payment_instruction_id, currency, high_risk, account, amount, expires_at)
+				VALUES ($1, $2, $3, $4, $5, now() + $6 * interval '1 second')
+				ON CONFLICT (payment_instruction_id) DO NOTHING`,
+		reservation.PaymentInstructionID,
+		reservation.Currency,
+		reservation.HighRisk,
+		reservation.Account,
+		reservation.Amount,
+		reservationExpiry.Seconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to reserve funds for payment instruction %s, err: %w", reservation.PaymentInstructionID, err)
+	}
+	if reserved, _ := result.RowsAffected(); reserved == 0 || reservation.HoldID == "" {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE liquidity_reservations SET amount = greatest(amount - $2, 0)
+				WHERE payment_instruction_id = $1 AND released_at IS NULL`,
+		reservation.HoldID,
+		reservation.Amount,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to take the reservation of payment instruction %s out of hold %s, err: %w", reservation.PaymentInstructionID, reservation.HoldID, err)
+	}
+
+	return nil
+}
+
+// Hold sums up the outstanding reservations and holds of the account and inserts the hold while holding the lock of the
+// currency and risk class, a hold held again is checked again with its new amount and account. The expired ones and the
+// ones of the other accounts are left out, those without an account are counted against every account.
+func (s PostgresStore) Hold(ctx context.Context, hold models.FundsHold, funds float64) (bool, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, holdFundsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockReservations(ctx, tx, hold.Currency, hold.HighRisk); err != nil {
+		return false, err
+	}
+
+	var reserved float64
+	err = tx.QueryRowContext(ctx,
+		`SELECT coalesce(sum(amount), 0) FROM liquidity_reservations
+				WHERE currency = $1 AND high_risk = $2 AND (account = $3 OR account = '')
+				AND released_at IS NULL AND expires_at > now() AND payment_instruction_id <> $4`,
+		hold.Currency,
+		hold.HighRisk,
+		hold.Account,
+		hold.ID,
+	).Scan(&reserved)
+	if err != nil {
+		return false, fmt.Errorf("unable to sum up the reserved funds, err: %w", err)
+	}
+	if hold.Amount > funds-reserved {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO liquidity_reservations (payment_instruction_id, currency, high_risk, account, amount, expires_at)
+				VALUES ($1, $2, $3, $4, $5, now() + $6 * interval '1 second')
+				ON CONFLICT (payment_instruction_id) DO UPDATE SET account = excluded.account, amount = excluded.amount,
+					reserved_at = now(), expires_at = excluded.expires_at, released_at = NULL`,
+		hold.ID,
+		hold.Currency,
+		hold.HighRisk,
+		hold.Account,
+		hold.Amount,
+		holdExpiry.Seconds(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("unable to hold funds %s, err: %w", hold.ID, err)
+	}
+
+	return true, tx.Commit()
+}
+
+func (s PostgresStore) ReleaseHold(ctx context.Context, id models.FundsHoldID) error {
+	return s.Release(ctx, models.PaymentInstructionID(id))
+}
+
+// lockReservations takes the transaction lock of the reservations of a currency and risk class.
+func lockReservations(ctx context.Context, tx *sql.Tx, currency models.CurrencyCode, highRisk bool) error {
+	_, err := tx.ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext('liquidity_reservations:' || $1 || ':' || $2::text))`,
+		currency,
+		highRisk,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to lock the %s reservations, err: %w", currency, err)
+	}
+	return nil
+}
+
+func (s PostgresStore) Release(ctx context.Context, id models.PaymentInstructionID) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, releaseFundsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE liquidity_reservations SET released_at = now() WHERE payment_instruction_id = $1 AND released_at IS NULL`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to release the funds of payment instruction %s, err: %w", id, err)
+	}
+
+	return nil
+}
+
+func (s PostgresStore) Reserved(ctx context.Context) ([]models.ReservedFunds, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, reservedFundsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT currency, high_risk, sum(amount) FROM liquidity_reservations
+				WHERE released_at IS NULL AND expires_at > now()
+				GROUP BY currency, high_risk
+				ORDER BY currency, high_risk`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sum up the reserved funds, err: %w", err)
+	}
+	defer rows.Close()
+
+	reserved := make([]models.ReservedFunds, 0)
+	for rows.Next() {
+		var funds models.ReservedFunds
+		if err := rows.Scan(&funds.Currency, &funds.HighRisk, &funds.Amount); err != nil {
+			return nil, err
+		}
+		reserved = append(reserved, funds)
+	}
+
+	return reserved, rows.Err()
+}
@@ -0,0 +1,172 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	testhelpers2 "github.com/saltpay/settlements-payments-system/internal/testhelpers"
+)
+
+func TestLiquidityLedger(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	reservedEUR := func() float64 {
+		reserved, err := paymentStore.Reserved(ctx)
+		require.NoError(t, err)
+		for _, funds := range reserved {
+			if funds.Currency == models.EUR && funds.HighRisk {
+				return funds.Amount
+			}
+		}
+		return 0
+	}
+
+	t.Run("the reserved funds are outstanding until they are released", func(t *testing.T) {
+		before := reservedEUR()
+		reservation := models.FundsReservation{
+			PaymentInstructionID: models.PaymentInstructionID(testhelpers2.RandomString()),
+			Currency:             models.EUR,
+			HighRisk:             true,
+			Amount:               125.5,
+		}
+
+		require.NoError(t, paymentStore.Reserve(ctx, reservation))
+		require.NoError(t, paymentStore.Reserve(ctx, reservation))
+		assert.Equal(t, before+125.5, reservedEUR())
+
+		require.NoError(t, paymentStore.Release(ctx, reservation.PaymentInstructionID))
+		require.NoError(t, paymentStore.Release(ctx, reservation.PaymentInstructionID))
+		assert.Equal(t, before, reservedEUR())
+	})
+
+	t.Run("a hold is only taken when the funds outstanding are enough, and is released like a reservation", func(t *testing.T) {
+		before := reservedEUR()
+		hold := models.FundsHold{
+			ID:       models.NewFundsHoldID(),
+			Currency: models.EUR,
+			HighRisk: true,
+			Amount:   300,
+		}
+
+		held, err := paymentStore.Hold(ctx, hold, before+299)
+		require.NoError(t, err)
+		assert.False(t, held)
+		assert.Equal(t, before, reservedEUR())
+
+		held, err = paymentStore.Hold(ctx, hold, before+300)
+		require.NoError(t, err)
+		assert.True(t, held)
+		assert.Equal(t, before+300, reservedEUR())
+
+		held, err = paymentStore.Hold(ctx, hold, before+300)
+		require.NoError(t, err)
+		assert.True(t, held, "a hold held again doesn't count against itself")
+
+		require.NoError(t, paymentStore.ReleaseHold(ctx, hold.ID))
+		assert.Equal(t, before, reservedEUR())
+	})
+
+	t.Run("the reservations of the payment instructions of a hold are taken out of it", func(t *testing.T) {
+		before := reservedEUR()
+		hold := models.FundsHold{ID: models.NewFundsHoldID(), Currency: models.EUR, HighRisk: true, Amount: 300}
+		held, err := paymentStore.Hold(ctx, hold, before+300)
+		require.NoError(t, err)
+		require.True(t, held)
+
+		reservation := models.FundsReservation{
+			PaymentInstructionID: models.PaymentInstructionID(testhelpers2.RandomString()),
+			Currency:             models.EUR,
+			HighRisk:             true,
+			Amount:               100,
+			HoldID:               hold.ID,
+		}
+		require.NoError(t, paymentStore.Reserve(ctx, reservation))
+		require.NoError(t, paymentStore.Reserve(ctx, reservation))
+		assert.Equal(t, before+300, reservedEUR(), "the funds are counted once, by the hold or the reservation")
+
+		require.NoError(t, paymentStore.ReleaseHold(ctx, hold.ID))
+		assert.Equal(t, before+100, reservedEUR())
+
+		require.NoError(t, paymentStore.Release(ctx, reservation.PaymentInstructionID))
+		assert.Equal(t, before, reservedEUR())
+	})
+
+	t.Run("a hold only counts the reservations and holds of its account and those without one", func(t *testing.T) {
+		before := reservedEUR()
+		accountA, accountB := "A-"+testhelpers2.RandomString(), "B-"+testhelpers2.RandomString()
+		reservation := models.FundsReservation{
+			PaymentInstructionID: models.PaymentInstructionID(testhelpers2.RandomString()),
+			Currency:             models.EUR,
+			HighRisk:             true,
+			Amount:               1000,
+			Account:              accountB,
+		}
+		require.NoError(t, paymentStore.Reserve(ctx, reservation))
+
+		holdA := models.FundsHold{ID: models.NewFundsHoldID(), Currency: models.EUR, HighRisk: true, Amount: 300, Account: accountA}
+		held, err := paymentStore.Hold(ctx, holdA, before+300)
+		require.NoError(t, err)
+		assert.True(t, held)
+
+		holdB := models.FundsHold{ID: models.NewFundsHoldID(), Currency: models.EUR, HighRisk: true, Amount: 300, Account: accountB}
+		held, err = paymentStore.Hold(ctx, holdB, before+300)
+		require.NoError(t, err)
+		assert.False(t, held)
+
+		require.NoError(t, paymentStore.ReleaseHold(ctx, holdA.ID))
+		require.NoError(t, paymentStore.Release(ctx, reservation.PaymentInstructionID))
+		assert.Equal(t, before, reservedEUR())
+	})
+
+	t.Run("the reservations and holds nothing released are no longer outstanding once they expire", func(t *testing.T) {
+		before := reservedEUR()
+		reservation := models.FundsReservation{
+			PaymentInstructionID: models.PaymentInstructionID(testhelpers2.RandomString()),
+			Currency:             models.EUR,
+			HighRisk:             true,
+			Amount:               100,
+		}
+		require.NoError(t, paymentStore.Reserve(ctx, reservation))
+		hold := models.FundsHold{ID: models.NewFundsHoldID(), Currency: models.EUR, HighRisk: true, Amount: 300}
+		held, err := paymentStore.Hold(ctx, hold, before+400)
+		require.NoError(t, err)
+		require.True(t, held)
+		assert.Equal(t, before+400, reservedEUR())
+
+		_, err = paymentStore.db.ExecContext(ctx,
+			`UPDATE liquidity_reservations SET expires_at = now() - interval '1 second' WHERE payment_instruction_id IN ($1, $2)`,
+			reservation.PaymentInstructionID,
+			hold.ID,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, before, reservedEUR())
+
+		newHold := models.FundsHold{ID: models.NewFundsHoldID(), Currency: models.EUR, HighRisk: true, Amount: 400}
+		held, err = paymentStore.Hold(ctx, newHold, before+400)
+		require.NoError(t, err)
+		assert.True(t, held, "the expired ones don't count against a new hold")
+		require.NoError(t, paymentStore.ReleaseHold(ctx, newHold.ID))
+	})
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ufx_files_created_at;
+DROP INDEX IF EXISTS ufx_files_state;
+DROP TABLE IF EXISTS ufx_files;
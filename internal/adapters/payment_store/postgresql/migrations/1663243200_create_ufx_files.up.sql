@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS ufx_files (
+    filename varchar(255) primary key,
+    sender varchar(100) not null default '',
+    s3_key text not null default '',
+    recs_count varchar(20) not null default '',
+    hash_total_amount varchar(50) not null default '',
+    record_count integer not null default 0,
+    currency_totals jsonb not null default '[]'::jsonb,
+    held_back_currencies jsonb not null default '[]'::jsonb,
+    state varchar(30) not null,
+    state_reason text not null default '',
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+);
+CREATE INDEX IF NOT EXISTS ufx_files_state ON ufx_files USING btree (state);
+CREATE INDEX IF NOT EXISTS ufx_files_created_at ON ufx_files USING btree (created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pending_fundings_state;
+DROP TABLE IF EXISTS pending_fundings;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS pending_fundings (
+    pending_funding_id varchar(100) primary key,
+    filename varchar(255) not null,
+    currency varchar(3) not null,
+    high_risk boolean not null default false,
+    amount numeric not null,
+    instruction_count integer not null,
+    instructions jsonb not null default '[]'::jsonb,
+    state varchar(30) not null,
+    state_reason text not null default '',
+    checks integer not null default 0,
+    last_checked_at timestamptz,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+);
+CREATE INDEX IF NOT EXISTS pending_fundings_state ON pending_fundings USING btree (state);
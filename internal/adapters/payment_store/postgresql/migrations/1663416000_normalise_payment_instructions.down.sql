@@ -0,0 +1,21 @@
+-- payment instructions stored after the up migration keep their body up to date, so they stay readable after rolling this back.
+DROP INDEX IF EXISTS payment_instructions_not_backfilled;
+DROP INDEX IF EXISTS payment_instructions_correlation_id;
+DROP INDEX IF EXISTS payment_instructions_execution_date_status;
+DROP INDEX IF EXISTS payment_instructions_duplicate_check;
+
+DROP TABLE IF EXISTS payment_instruction_events;
+
+ALTER TABLE payment_instructions
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS incoming_instruction,
+    DROP COLUMN IF EXISTS correlation_id,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS payment_provider,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS execution_date,
+    DROP COLUMN IF EXISTS high_risk,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS amount,
+    DROP COLUMN IF EXISTS account_number,
+    DROP COLUMN IF EXISTS contract_number;
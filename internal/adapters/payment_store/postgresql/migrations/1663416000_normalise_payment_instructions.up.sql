@@ -0,0 +1,28 @@
+ALTER TABLE payment_instructions
+    ADD COLUMN IF NOT EXISTS contract_number varchar(100),
+    ADD COLUMN IF NOT EXISTS account_number varchar(100),
+    ADD COLUMN IF NOT EXISTS amount numeric,
+    ADD COLUMN IF NOT EXISTS currency varchar(100),
+    ADD COLUMN IF NOT EXISTS high_risk boolean not null default false,
+    ADD COLUMN IF NOT EXISTS execution_date date,
+    ADD COLUMN IF NOT EXISTS status varchar(50),
+    ADD COLUMN IF NOT EXISTS payment_provider varchar(50) not null default '',
+    ADD COLUMN IF NOT EXISTS version integer not null default 0,
+    ADD COLUMN IF NOT EXISTS correlation_id varchar(100),
+    ADD COLUMN IF NOT EXISTS incoming_instruction jsonb not null default '{}'::jsonb,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz not null default now();
+
+CREATE TABLE IF NOT EXISTS payment_instruction_events (
+    payment_instruction_id varchar(100) not null references payment_instructions (payment_instruction_id) on delete cascade,
+    sequence integer not null,
+    type varchar(100) not null,
+    failure_reason_code varchar(100) not null default '',
+    created_on timestamptz not null,
+    event jsonb not null,
+    primary key (payment_instruction_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS payment_instructions_duplicate_check ON payment_instructions USING btree (contract_number, execution_date, currency, account_number);
+CREATE INDEX IF NOT EXISTS payment_instructions_execution_date_status ON payment_instructions USING btree (execution_date, status);
+CREATE INDEX IF NOT EXISTS payment_instructions_correlation_id ON payment_instructions USING btree (correlation_id);
+CREATE INDEX IF NOT EXISTS payment_instructions_not_backfilled ON payment_instructions USING btree (payment_instruction_id) WHERE status IS NULL;
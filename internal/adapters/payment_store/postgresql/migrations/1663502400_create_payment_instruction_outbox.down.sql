@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS payment_instruction_outbox_pending;
+DROP TABLE IF EXISTS payment_instruction_outbox;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS payment_instruction_outbox (
+    outbox_entry_id bigserial primary key,
+    payment_instruction_id varchar(100) not null references payment_instructions (payment_instruction_id) on delete cascade,
+    payment_provider varchar(50) not null default '',
+    payload jsonb not null,
+    attempts integer not null default 0,
+    last_error text not null default '',
+    available_at timestamptz not null default now(),
+    dispatched_at timestamptz,
+    created_at timestamptz not null default now()
+);
+CREATE INDEX IF NOT EXISTS payment_instruction_outbox_pending ON payment_instruction_outbox USING btree (available_at) WHERE dispatched_at IS NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS payment_instructions_in_flight;
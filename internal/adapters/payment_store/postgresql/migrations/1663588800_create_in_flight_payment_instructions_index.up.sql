@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS payment_instructions_in_flight ON payment_instructions USING btree (payment_provider, status) WHERE status IN ('SUBMITTED_FOR_PROCESSING', 'SUBMITTED');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS payment_instructions_idempotency_key;
+ALTER TABLE payment_instructions DROP COLUMN IF EXISTS request_fingerprint;
+ALTER TABLE payment_instructions DROP COLUMN IF EXISTS idempotency_key;
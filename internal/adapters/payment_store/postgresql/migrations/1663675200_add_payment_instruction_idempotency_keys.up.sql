@@ -0,0 +1,3 @@
+ALTER TABLE payment_instructions ADD COLUMN IF NOT EXISTS idempotency_key varchar(255);
+ALTER TABLE payment_instructions ADD COLUMN IF NOT EXISTS request_fingerprint varchar(64) not null default '';
+CREATE UNIQUE INDEX IF NOT EXISTS payment_instructions_idempotency_key ON payment_instructions USING btree (idempotency_key) WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS payment_batches (
+    payment_batch_id varchar(100) primary key,
+    state varchar(30) not null,
+    items jsonb not null default '[]'::jsonb,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+);
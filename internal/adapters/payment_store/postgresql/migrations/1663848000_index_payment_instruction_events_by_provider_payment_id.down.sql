@@ -0,0 +1 @@
+DROP INDEX IF EXISTS payment_instruction_events_provider_payment_id;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS payment_instruction_events_provider_payment_id ON payment_instruction_events USING btree ((event->'details'->>'paymentProviderPaymentID')) WHERE type = 'DOMAIN.ACCEPTED_BY_PAYMENT_PROVIDER';
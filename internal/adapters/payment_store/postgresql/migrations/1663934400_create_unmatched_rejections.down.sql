@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS payment_instruction_events_banking_reference;
+DROP TABLE IF EXISTS unmatched_rejections;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS unmatched_rejections (
+    payment_provider varchar(50) not null,
+    report_date date not null,
+    position integer not null,
+    reason varchar(100) not null,
+    payment_instruction_id varchar(100) not null default '',
+    rejection jsonb not null,
+    created_at timestamptz not null default now(),
+    primary key (payment_provider, report_date, position)
+);
+
+CREATE INDEX IF NOT EXISTS payment_instruction_events_banking_reference ON payment_instruction_events USING btree ((event->'details'->>'bankingReference')) WHERE type = 'DOMAIN.ACCEPTED_BY_PAYMENT_PROVIDER';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS liquidity_reservations_outstanding;
+DROP TABLE IF EXISTS liquidity_reservations;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS liquidity_reservations (
+    payment_instruction_id varchar(100) primary key,
+    currency varchar(3) not null,
+    high_risk boolean not null default false,
+    amount numeric not null,
+    reserved_at timestamptz not null default now(),
+    released_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS liquidity_reservations_outstanding ON liquidity_reservations (currency, high_risk) WHERE released_at IS NULL;
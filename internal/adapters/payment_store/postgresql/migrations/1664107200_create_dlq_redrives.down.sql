@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS dlq_redrives_queue;
+DROP TABLE IF EXISTS dlq_redrives;
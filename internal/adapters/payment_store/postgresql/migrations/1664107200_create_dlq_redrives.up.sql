@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS dlq_redrives (
+    id bigserial primary key,
+    queue varchar(100) not null,
+    source_queue varchar(100) not null,
+    redriven_by varchar(100) not null,
+    message_count integer not null,
+    messages jsonb not null default '[]'::jsonb,
+    redriven_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS dlq_redrives_queue ON dlq_redrives (queue, redriven_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS liquidity_reservations_outstanding;
+CREATE INDEX IF NOT EXISTS liquidity_reservations_outstanding ON liquidity_reservations (currency, high_risk) WHERE released_at IS NULL;
+
+ALTER TABLE liquidity_reservations DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE liquidity_reservations DROP COLUMN IF EXISTS account;
@@ -0,0 +1,9 @@
+ALTER TABLE liquidity_reservations ADD COLUMN IF NOT EXISTS account varchar(100) not null default '';
+ALTER TABLE liquidity_reservations ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+
+UPDATE liquidity_reservations SET expires_at = reserved_at + interval '3 days' WHERE expires_at IS NULL;
+ALTER TABLE liquidity_reservations ALTER COLUMN expires_at SET DEFAULT now() + interval '3 days';
+ALTER TABLE liquidity_reservations ALTER COLUMN expires_at SET NOT NULL;
+
+DROP INDEX IF EXISTS liquidity_reservations_outstanding;
+CREATE INDEX IF NOT EXISTS liquidity_reservations_outstanding ON liquidity_reservations (currency, high_risk, account) WHERE released_at IS NULL;
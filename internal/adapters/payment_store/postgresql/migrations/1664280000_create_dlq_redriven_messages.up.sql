@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS dlq_redriven_messages (
+    queue varchar(100) not null,
+    message_id varchar(100) not null,
+    redriven_at timestamptz not null default now(),
+    primary key (queue, message_id)
+);
+
+INSERT INTO dlq_redriven_messages (queue, message_id, redriven_at)
+    SELECT queue, message->>'messageId', min(redriven_at) FROM dlq_redrives, jsonb_array_elements(messages) AS message
+    WHERE message->>'messageId' IS NOT NULL
+    GROUP BY queue, message->>'messageId'
+ON CONFLICT DO NOTHING;
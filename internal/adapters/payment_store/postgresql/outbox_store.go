@@ -0,0 +1,113 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	claimOutboxEntriesQuery        = "claimOutboxEntries"
+	markOutboxEntryDispatchedQuery = "markOutboxEntryDispatched"
+	markOutboxEntryFailedQuery     = "markOutboxEntryFailed"
+)
+
+var _ ports.PaymentInstructionOutbox = PostgresStore{}
+
+// insertOutboxEntry must run in the transaction that stores the payment instruction.
+func insertOutboxEntry(ctx context.Context, tx *sql.Tx, instruction models.PaymentInstruction) error {
+	payload, err := instruction.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO payment_instruction_outbox (payment_instruction_id, payment_provider, payload) VALUES ($1, $2, $3)`,
+		instruction.ID(),
+		instruction.PaymentProvider(),
+		payload,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to insert payment instruction outbox entry, err: %w", err)
+	}
+	return nil
+}
+
+// ClaimOutboxEntries pushes the availability of the claimed entries back by the lease, SKIP LOCKED keeps
+// concurrent relays from claiming the same entries.
+func (s PostgresStore) ClaimOutboxEntries(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEntry, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, claimOutboxEntriesQuery)
+	defer postgresTracing.EndSpan(span)
+
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE payment_instruction_outbox SET attempts = attempts + 1, available_at = now() + make_interval(secs => $2)
+				WHERE outbox_entry_id IN (
+					SELECT outbox_entry_id FROM payment_instruction_outbox
+					WHERE dispatched_at IS NULL AND available_at <= now()
+					ORDER BY outbox_entry_id
+					LIMIT $1
+					FOR UPDATE SKIP LOCKED
+				)
+				RETURNING outbox_entry_id, payload, attempts, created_at`,
+		limit,
+		lease.Seconds(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to claim payment instruction outbox entries, err: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.OutboxEntry
+	for rows.Next() {
+		var (
+			entry   models.OutboxEntry
+			payload []byte
+		)
+		if err := rows.Scan(&entry.ID, &payload, &entry.Attempts, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry.PaymentInstruction, err = models.NewPaymentInstructionFromJSON(payload)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode payment instruction outbox entry %d, err: %w", entry.ID, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (s PostgresStore) MarkOutboxEntryDispatched(ctx context.Context, id models.OutboxEntryID) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, markOutboxEntryDispatchedQuery)
+	defer postgresTracing.EndSpan(span)
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE payment_instruction_outbox SET dispatched_at = now(), last_error = '' WHERE outbox_entry_id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to mark payment instruction outbox entry %d as dispatched, err: %w", id, err)
+	}
+	return nil
+}
+
+func (s PostgresStore) MarkOutboxEntryFailed(ctx context.Context, id models.OutboxEntryID, retryAt time.Time, reason string) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, markOutboxEntryFailedQuery)
+	defer postgresTracing.EndSpan(span)
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE payment_instruction_outbox SET available_at = $2, last_error = $3 WHERE outbox_entry_id = $1 AND dispatched_at IS NULL`,
+		id,
+		retryAt,
+		reason,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to mark payment instruction outbox entry %d as failed, err: %w", id, err)
+	}
+	return nil
+}
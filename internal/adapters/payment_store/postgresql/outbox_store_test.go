@@ -0,0 +1,95 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+)
+
+func TestPaymentInstructionOutbox(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	// claimEntryOf claims every due entry and returns the one of the given payment instruction, other tests may have left entries behind
+	claimEntryOf := func(t *testing.T, id models.PaymentInstructionID) (models.OutboxEntry, bool) {
+		t.Helper()
+		entries, err := paymentStore.ClaimOutboxEntries(ctx, 1000, time.Minute)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if entry.PaymentInstruction.ID() == id {
+				return entry, true
+			}
+		}
+		return models.OutboxEntry{}, false
+	}
+
+	newSubmittedInstruction := func() models.PaymentInstruction {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+		instruction.SubmitForProcessing()
+		return instruction
+	}
+
+	t.Run("a payment instruction stored for dispatch can be claimed once until its lease runs out", func(t *testing.T) {
+		instruction := newSubmittedInstruction()
+		require.NoError(t, paymentStore.StoreForDispatch(ctx, instruction))
+
+		entry, found := claimEntryOf(t, instruction.ID())
+		require.True(t, found)
+		assert.Equal(t, 1, entry.Attempts)
+		assert.Equal(t, models.SubmittedForProcessing, entry.PaymentInstruction.GetStatus())
+		assert.Equal(t, models.BankingCircle, entry.PaymentInstruction.PaymentProvider())
+
+		_, found = claimEntryOf(t, instruction.ID())
+		assert.False(t, found, "a claimed entry should be hidden for its lease")
+	})
+
+	t.Run("a payment instruction stored without dispatch has no outbox entry", func(t *testing.T) {
+		instruction := newSubmittedInstruction()
+		require.NoError(t, paymentStore.Store(ctx, instruction))
+
+		_, found := claimEntryOf(t, instruction.ID())
+		assert.False(t, found)
+	})
+
+	t.Run("a failed dispatch is claimed again once its retry is due, and never again once dispatched", func(t *testing.T) {
+		instruction := newSubmittedInstruction()
+		require.NoError(t, paymentStore.StoreForDispatch(ctx, instruction))
+
+		entry, found := claimEntryOf(t, instruction.ID())
+		require.True(t, found)
+		require.NoError(t, paymentStore.MarkOutboxEntryFailed(ctx, entry.ID, time.Now().Add(-time.Second), "queue unavailable"))
+
+		entry, found = claimEntryOf(t, instruction.ID())
+		require.True(t, found)
+		assert.Equal(t, 2, entry.Attempts)
+
+		require.NoError(t, paymentStore.MarkOutboxEntryDispatched(ctx, entry.ID))
+		require.NoError(t, paymentStore.MarkOutboxEntryFailed(ctx, entry.ID, time.Now().Add(-time.Second), "late failure"))
+
+		_, found = claimEntryOf(t, instruction.ID())
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	savePaymentBatchQuery = "savePaymentBatch"
+	getPaymentBatchQuery  = "getPaymentBatch"
+)
+
+var _ ports.PaymentBatchRepo = PostgresStore{}
+
+// SavePaymentBatch inserts a payment batch, or overwrites its state and items if it already exists.
+func (s PostgresStore) SavePaymentBatch(ctx context.Context, batch models.PaymentBatch) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, savePaymentBatchQuery)
+	defer postgresTracing.EndSpan(span)
+
+	itemsJSON, err := json.Marshal(batch.Items)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO payment_batches (payment_batch_id, state, items, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (payment_batch_id) DO UPDATE SET
+					state = EXCLUDED.state,
+					items = EXCLUDED.items,
+					updated_at = EXCLUDED.updated_at`,
+		batch.ID,
+		batch.State,
+		itemsJSON,
+		batch.CreatedAt,
+		batch.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to save payment batch %s, err: %w", batch.ID, err)
+	}
+
+	return nil
+}
+
+func (s PostgresStore) GetPaymentBatch(ctx context.Context, id models.PaymentBatchID) (models.PaymentBatch, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getPaymentBatchQuery)
+	defer postgresTracing.EndSpan(span)
+
+	var (
+		batch     models.PaymentBatch
+		itemsJSON []byte
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT payment_batch_id, state, items, created_at, updated_at FROM payment_batches WHERE payment_batch_id = $1`,
+		id,
+	).Scan(&batch.ID, &batch.State, &itemsJSON, &batch.CreatedAt, &batch.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.PaymentBatch{}, PaymentBatchMissingError{ID: id}
+		}
+		return models.PaymentBatch{}, err
+	}
+
+	if err := json.Unmarshal(itemsJSON, &batch.Items); err != nil {
+		return models.PaymentBatch{}, err
+	}
+
+	return batch, nil
+}
+
+// PaymentBatchMissingError is returned when the payment batch referenced by PaymentBatchID can't be found.
+type PaymentBatchMissingError struct {
+	ID models.PaymentBatchID
+}
+
+func (p PaymentBatchMissingError) Error() string {
+	return fmt.Sprintf("payment batch %q is not found", p.ID)
+}
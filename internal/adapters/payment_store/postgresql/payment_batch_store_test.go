@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+)
+
+func TestPaymentBatchRepo(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	t.Run("a saved batch can be read back with the outcome of its items", func(t *testing.T) {
+		batch := models.NewPaymentBatch(models.IncomingInstructions{
+			testhelpers.NewIncomingInstructionBuilder().Build(),
+			testhelpers.NewIncomingInstructionBuilder().Build(),
+		})
+		require.NoError(t, paymentStore.SavePaymentBatch(ctx, batch))
+
+		batch.Accept(0, "some-payment-instruction-id")
+		batch.Reject(1, "not enough funds")
+		batch.Complete()
+		require.NoError(t, paymentStore.SavePaymentBatch(ctx, batch))
+
+		actual, err := paymentStore.GetPaymentBatch(ctx, batch.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentBatchCompleted, actual.State)
+		assert.Equal(t, batch.Items, actual.Items)
+	})
+
+	t.Run("a batch that was never saved can't be found", func(t *testing.T) {
+		_, err := paymentStore.GetPaymentBatch(ctx, "unknown-payment-batch")
+		assert.Equal(t, PaymentBatchMissingError{ID: "unknown-payment-batch"}, err)
+	})
+}
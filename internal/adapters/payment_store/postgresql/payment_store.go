@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,6 +30,7 @@ const (
 	storeInstructionQuery              = "storeInstruction"
 	getInstructionQuery                = "getInstruction"
 	getInstructionByCorrelationIDQuery = "getInstructionByCorrelationID"
+	getInstructionByIdempotencyKey     = "getInstructionByIdempotencyKey"
 	getReportQuery                     = "getReport"
 	getCurrencyReportQuery             = "getCurrencyReport"
 	getPaymentByMidQuery               = "getPaymentByMidQuery"
@@ -63,7 +66,10 @@ func NewPaymentStore(ctx context.Context, postgresConnString string, observer pa
 	}
 	zapctx.Debug(ctx, "migration is done", zap.String("elapsedTime", time.Since(migrationStart).String()))
 
-	return PostgresStore{db: db, observer: observer}, nil
+	store := PostgresStore{db: db, observer: observer}
+	go store.backfillInBackground(ctx)
+
+	return store, nil
 }
 
 func (s PostgresStore) CleanDBForTesting() error {
@@ -96,6 +102,22 @@ func (s PostgresStore) Ping(ctx context.Context) error {
 }
 
 func (s PostgresStore) Store(ctx context.Context, instruction models.PaymentInstruction) error {
+	return s.store(ctx, instruction, false, nil)
+}
+
+// StoreForDispatch stores the payment instruction and its payment_instruction_outbox entry in one transaction,
+// the outbox relay hands it to the payment provider from there.
+func (s PostgresStore) StoreForDispatch(ctx context.Context, instruction models.PaymentInstruction) error {
+	return s.store(ctx, instruction, true, nil)
+}
+
+// StoreForDispatchReserving also inserts the liquidity reservation of the payment instruction in the transaction,
+// ahead of its outbox entry, so its outcome can't be tracked before its funds are reserved.
+func (s PostgresStore) StoreForDispatchReserving(ctx context.Context, instruction models.PaymentInstruction, reservation models.FundsReservation) error {
+	return s.store(ctx, instruction, true, &reservation)
+}
+
+func (s PostgresStore) store(ctx context.Context, instruction models.PaymentInstruction, dispatch bool, reservation *models.FundsReservation) error {
 	ctx, span := postgresTracing.SpanWithContext(ctx, storeInstructionQuery)
 	defer postgresTracing.EndSpan(span)
 
@@ -106,88 +128,257 @@ func (s PostgresStore) Store(ctx context.Context, instruction models.PaymentInst
 	}
 	startTime := time.Now()
 
-	if instruction.GetStatus() != models.Failed && instruction.GetStatus() != models.Rejected {
-		query := `SELECT body FROM payment_instructions 
-            WHERE body->'incomingInstruction'->'merchant'->>'contractNumber' = $1 
-            AND body->'incomingInstruction'->'merchant'->'account'->>'accountNumber'= $2 
-            AND body->'incomingInstruction'->'payment'->>'amount' = $3 
-            AND body->'incomingInstruction'->'payment'->'currency' ->> 'isoCode' =  $4 
-			AND TO_DATE(body->'incomingInstruction'->'payment'->>'executionDate', 'YYYY-MM-DD') = TO_DATE($5, 'YYYY-MM-DD')
-            AND body->>'status' = ANY($6) LIMIT 1`
-
-		rows, err := s.db.Query(query,
-			instruction.IncomingInstruction.Merchant.ContractNumber,
-			instruction.IncomingInstruction.Merchant.Account.AccountNumber,
-			instruction.IncomingInstruction.Payment.Amount,
-			instruction.IncomingInstruction.Payment.Currency.IsoCode,
-			instruction.IncomingInstruction.Payment.ExecutionDate,
-			pq.Array([]models.PaymentInstructionStatus{models.SubmittedForProcessing, models.Successful, models.Received}),
-		)
+	// a payment instruction with an idempotency key is a retry only when it has the same key, not when it looks the same
+	if instruction.GetStatus() != models.Failed && instruction.GetStatus() != models.Rejected && !instruction.IdempotencyKey().IsSet() {
+		hasDuplication, err := s.hasDuplicate(ctx, instruction)
 		if err != nil {
 			return fmt.Errorf("unable to execute database query, err %w", err)
 		}
-
-		hasDuplication := rows.Next()
 		if hasDuplication {
 			return ErrDuplicate
 		}
+	}
 
-		err = rows.Close()
-		if err != nil {
+	if err := s.insert(ctx, instruction, dispatch, reservation); err != nil {
+		if err == ErrDuplicateIdempotencyKey {
 			return err
 		}
+		err = fmt.Errorf("unable to insert payment instruction, err: %w", err)
+		s.observer.FailedStore(ctx, instruction.ID(), instruction.ContractNumber(), err)
+		return err
 	}
 
-	paymentInstructionJSON, err := instruction.MarshalJSON()
+	s.observer.StoreSuccessful(ctx, instruction.ID(), time.Since(startTime).Milliseconds())
+
+	return nil
+}
+
+// hasDuplicate reports whether the same payment to the same merchant account is already received, in flight or paid.
+// Amounts are compared as numbers, so "10.0" and "10" are the same payment.
+func (s PostgresStore) hasDuplicate(ctx context.Context, instruction models.PaymentInstruction) (bool, error) {
+	// the second half covers payment instructions the backfill hasn't reached yet,
+	// it can go with the body column once every instance runs on the normalised schema
+	query := `SELECT EXISTS (
+				SELECT 1 FROM payment_instructions
+				WHERE contract_number = $1
+				AND account_number = $2
+				AND amount = $3::numeric
+				AND currency = $4
+				AND execution_date = $5::date
+				AND status = ANY($6)
+			) OR EXISTS (
+				SELECT 1 FROM payment_instructions
+				WHERE status IS NULL
+				AND body->'incomingInstruction'->'merchant'->>'contractNumber' = $1
+				AND body->'incomingInstruction'->'merchant'->'account'->>'accountNumber' = $2
+				AND body->'incomingInstruction'->'payment'->>'amount' = $7
+				AND body->'incomingInstruction'->'payment'->'currency'->>'isoCode' = $4
+				AND TO_DATE(body->'incomingInstruction'->'payment'->>'executionDate', 'YYYY-MM-DD') = $5::date
+				AND body->>'status' = ANY($6)
+			)`
+
+	var hasDuplication bool
+	err := s.db.QueryRowContext(ctx, query,
+		instruction.IncomingInstruction.Merchant.ContractNumber,
+		instruction.IncomingInstruction.Merchant.Account.AccountNumber,
+		numericAmount(instruction.IncomingInstruction.Payment.Amount),
+		instruction.IncomingInstruction.Payment.Currency.IsoCode,
+		executionDate(instruction.IncomingInstruction),
+		pq.Array([]models.PaymentInstructionStatus{models.SubmittedForProcessing, models.Successful, models.Received}),
+		instruction.IncomingInstruction.Payment.Amount,
+	).Scan(&hasDuplication)
+
+	return hasDuplication, err
+}
+
+func (s PostgresStore) insert(ctx context.Context, instruction models.PaymentInstruction, dispatch bool, reservation *models.FundsReservation) error {
+	incomingInstructionJSON, err := instruction.IncomingInstruction.ToJSON()
+	if err != nil {
+		return err
+	}
+	// the body is written alongside the columns until the backfill is done and no instance reads it anymore,
+	// so a rollback or an instance still on the old schema can read the payment instruction
+	bodyJSON, err := instruction.MarshalJSON()
 	if err != nil {
 		return err
 	}
 
-	_, err = s.db.ExecContext(ctx, "INSERT INTO payment_instructions(payment_instruction_id, body) VALUES($1, $2)", instruction.ID(), paymentInstructionJSON)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO payment_instructions (payment_instruction_id, contract_number, account_number, amount, currency, high_risk,
+				execution_date, status, payment_provider, version, correlation_id, incoming_instruction, idempotency_key, request_fingerprint, body)
+				VALUES ($1, $2, $3, $4::numeric, $5, $6, $7::date, $8, $9, $10, $11, $12, $13, $14, $15)
+				ON CONFLICT (idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING`,
+		instruction.ID(),
+		instruction.IncomingInstruction.Merchant.ContractNumber,
+		instruction.IncomingInstruction.Merchant.Account.AccountNumber,
+		numericAmount(instruction.IncomingInstruction.Payment.Amount),
+		instruction.IncomingInstruction.Payment.Currency.IsoCode,
+		instruction.IncomingInstruction.Merchant.HighRisk,
+		executionDate(instruction.IncomingInstruction),
+		instruction.GetStatus(),
+		instruction.PaymentProvider(),
+		instruction.Version(),
+		instruction.IncomingInstruction.PaymentCorrelationId,
+		incomingInstructionJSON,
+		idempotencyKey(instruction),
+		instruction.IdempotencyKey().RequestFingerprint,
+		bodyJSON,
+	)
 	if err != nil {
-		err = fmt.Errorf("unable to insert payment instruction, err: %w", err)
-		s.observer.FailedStore(ctx, instruction.ID(), instruction.ContractNumber(), err)
 		return err
 	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if inserted == 0 {
+		return ErrDuplicateIdempotencyKey
+	}
 
-	s.observer.StoreSuccessful(ctx, instruction.ID(), time.Since(startTime).Milliseconds())
+	if err := insertEvents(ctx, tx, instruction.ID(), 1, instruction.Events()); err != nil {
+		return err
+	}
+
+	if reservation != nil {
+		if err := insertReservation(ctx, tx, *reservation); err != nil {
+			return err
+		}
+	}
+
+	if dispatch {
+		if err := insertOutboxEntry(ctx, tx, instruction); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
 
+// idempotencyKey is NULL for a payment instruction without a key, so any number of them can be stored.
+func idempotencyKey(instruction models.PaymentInstruction) sql.NullString {
+	key := instruction.IdempotencyKey().Key
+	return sql.NullString{String: key, Valid: key != ""}
+}
+
+func insertEvents(ctx context.Context, tx *sql.Tx, id models.PaymentInstructionID, firstSequence int, events []models.PaymentInstructionEvent) error {
+	for i, event := range events {
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO payment_instruction_events (payment_instruction_id, sequence, type, failure_reason_code, created_on, event)
+					VALUES ($1, $2, $3, $4, $5, $6)`,
+			id,
+			firstSequence+i,
+			event.Type,
+			failureReasonCodeOf(eventJSON),
+			event.CreatedOn,
+			eventJSON,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert payment instruction event, err: %w", err)
+		}
+	}
 	return nil
 }
 
-func (s PostgresStore) UpdatePayment(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+// UpdatePayment moves the payment instruction to the given status and appends the event to its events,
+// as long as nobody else updated it since it was read at the expected version.
+func (s PostgresStore) UpdatePayment(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 	ctx, span := postgresTracing.SpanWithContext(ctx, updatePayment)
 	defer postgresTracing.EndSpan(span)
 
-	updatedStatus := struct {
-		Status models.PaymentInstructionStatus `json:"status"`
-	}{Status: status}
-	updatedStatusJson, err := json.Marshal(updatedStatus)
+	startTime := time.Now()
+	if err := s.updatePayment(ctx, id, expectedVersion, status, event); err != nil {
+		switch err.(type) {
+		case PaymentInstructionMissingError, models.VersionConflictError:
+		default:
+			err = fmt.Errorf("unable to update payment instruction, err: %v", err)
+		}
+		s.observer.FailedUpdate(ctx, id, err)
+		return err
+	}
+	s.observer.UpdateSuccessful(ctx, id, time.Since(startTime).Milliseconds())
+
+	return nil
+}
+
+func (s PostgresStore) updatePayment(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// locking the row serialises the updates of a payment instruction, which keeps its version check
+	// and its event sequence free of races
+	var (
+		currentStatus  sql.NullString
+		currentVersion int
+	)
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, CASE WHEN status IS NULL THEN COALESCE((body->>'version')::int, 0) ELSE version END
+				FROM payment_instructions WHERE payment_instruction_id = $1 FOR UPDATE`,
+		id,
+	).Scan(&currentStatus, &currentVersion)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return PaymentInstructionMissingError{ID: id}
+		}
 		return err
 	}
+	if currentVersion != expectedVersion {
+		return models.VersionConflictError{ID: id, ExpectedVersion: expectedVersion, ActualVersion: currentVersion}
+	}
+	if !currentStatus.Valid {
+		if err := backfillInstructions(ctx, tx, []string{string(id)}); err != nil {
+			return err
+		}
+	}
 
-	updatedEventJson, err := json.Marshal(event)
+	eventJSON, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 
-	startTime := time.Now()
-	query := `UPDATE payment_instructions 
-						SET body = body::jsonb 
-							|| jsonb_set(body::jsonb, array['events'], (body->'events')::jsonb || $1::jsonb)
-				    	|| $2::jsonb
-				    	|| CONCAT('{"version":', COALESCE(body->>'version','0')::int + 1, '}')::jsonb
-							WHERE payment_instruction_id = $3`
-	_, err = s.db.ExecContext(ctx, query, updatedEventJson, updatedStatusJson, id)
+	// the body gets the same update, see insert
+	_, err = tx.ExecContext(ctx,
+		`UPDATE payment_instructions SET status = $1, version = version + 1,
+				body = body || jsonb_build_object(
+					'status', $4::text,
+					'version', version + 1,
+					'events', CASE WHEN jsonb_typeof(body->'events') = 'array' THEN body->'events' ELSE '[]'::jsonb END || jsonb_build_array($3::jsonb))
+				WHERE payment_instruction_id = $2`,
+		status,
+		id,
+		eventJSON,
+		string(status),
+	)
 	if err != nil {
-		err := fmt.Errorf("unable to update payment instruction, err: %v", err)
-		s.observer.FailedUpdate(ctx, id, err)
 		return err
 	}
-	s.observer.UpdateSuccessful(ctx, id, time.Since(startTime).Milliseconds())
 
-	return nil
+	var lastSequence int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(sequence), 0) FROM payment_instruction_events WHERE payment_instruction_id = $1`,
+		id,
+	).Scan(&lastSequence)
+	if err != nil {
+		return err
+	}
+	if err := insertEvents(ctx, tx, id, lastSequence+1, []models.PaymentInstructionEvent{event}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (s PostgresStore) Get(ctx context.Context, id models.PaymentInstructionID) (models.PaymentInstruction, error) {
@@ -196,73 +387,183 @@ func (s PostgresStore) Get(ctx context.Context, id models.PaymentInstructionID)
 
 	s.observer.ReceivedGetInstruction(ctx, id)
 	startTime := time.Now()
-	row := s.db.QueryRowContext(ctx, "SELECT body FROM payment_instructions WHERE payment_instruction_id = $1", id)
-	var body []byte
 
-	if err := row.Scan(&body); err != nil {
-		if err == sql.ErrNoRows {
-			s.observer.PaymentInstructionNotFound(ctx, id)
-			return models.PaymentInstruction{}, PaymentInstructionMissingError{ID: id}
-		} else {
-			s.observer.FailedGet(ctx, id, err)
-			return models.PaymentInstruction{}, err
-		}
-	}
-
-	paymentInstructionFromJSON, err := models.NewPaymentInstructionFromJSON(body)
+	instructions, err := s.queryPaymentInstructions(ctx, `WHERE payment_instruction_id = $1`, id)
 	if err != nil {
 		s.observer.FailedGet(ctx, id, err)
 		return models.PaymentInstruction{}, err
 	}
+	if len(instructions) == 0 {
+		s.observer.PaymentInstructionNotFound(ctx, id)
+		return models.PaymentInstruction{}, PaymentInstructionMissingError{ID: id}
+	}
 
 	s.observer.GetSuccessful(ctx, id, time.Since(startTime).Milliseconds())
-	return paymentInstructionFromJSON, nil
+	return instructions[0], nil
 }
 
 func (s PostgresStore) GetFromCorrelationID(ctx context.Context, correlationId string) ([]models.PaymentInstruction, error) {
 	ctx, span := postgresTracing.SpanWithContext(ctx, getInstructionByCorrelationIDQuery)
 	defer postgresTracing.EndSpan(span)
 
-	row, err := s.db.QueryContext(ctx, `SELECT body FROM payment_instructions WHERE body->'incomingInstruction'->>'paymentCorrelationId' = $1;`, correlationId)
+	result, err := s.queryPaymentInstructions(ctx, `WHERE correlation_id = $1`, correlationId)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]models.PaymentInstruction, 0)
-	for row.Next() {
-		var body []byte
-		err = row.Scan(&body)
+	if len(result) == 0 {
+		return nil, PaymentInstructionMissingError{CorrelationID: correlationId}
+	}
+
+	return result, nil
+}
+
+// GetFromIdempotencyKey returns the payment instruction stored with the idempotency key, together with the key.
+func (s PostgresStore) GetFromIdempotencyKey(ctx context.Context, key string) (models.PaymentInstruction, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getInstructionByIdempotencyKey)
+	defer postgresTracing.EndSpan(span)
+
+	var (
+		id          models.PaymentInstructionID
+		fingerprint string
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT payment_instruction_id, request_fingerprint FROM payment_instructions WHERE idempotency_key = $1`,
+		key,
+	).Scan(&id, &fingerprint)
+	if err == sql.ErrNoRows {
+		return models.PaymentInstruction{}, PaymentInstructionMissingError{IdempotencyKey: key}
+	}
+	if err != nil {
+		return models.PaymentInstruction{}, err
+	}
+
+	instructions, err := s.queryPaymentInstructions(ctx, `WHERE payment_instruction_id = $1`, id)
+	if err != nil {
+		return models.PaymentInstruction{}, err
+	}
+	if len(instructions) == 0 {
+		return models.PaymentInstruction{}, PaymentInstructionMissingError{ID: id}
+	}
+
+	instruction := instructions[0]
+	instruction.SetIdempotencyKey(models.IdempotencyKey{Key: key, RequestFingerprint: fingerprint})
+	return instruction, nil
+}
+
+// the body is only read for payment instructions the backfill hasn't reached yet.
+const paymentInstructionColumns = `payment_instruction_id, status, version, payment_provider, incoming_instruction, CASE WHEN status IS NULL THEN body END`
+
+// queryPaymentInstructions reads the payment instructions matching the where clause together with their events.
+func (s PostgresStore) queryPaymentInstructions(ctx context.Context, where string, args ...interface{}) ([]models.PaymentInstruction, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+paymentInstructionColumns+` FROM payment_instructions `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		dtos       []models.PaymentInstructionDTO
+		legacy     = make(map[int]models.PaymentInstruction)
+		normalised []string
+	)
+	for rows.Next() {
+		var (
+			instruction             models.PaymentInstructionDTO
+			status                  sql.NullString
+			incomingInstructionJSON []byte
+			legacyBody              []byte
+		)
+		err = rows.Scan(&instruction.ID, &status, &instruction.Version, &instruction.PaymentProvider, &incomingInstructionJSON, &legacyBody)
 		if err != nil {
 			return nil, err
 		}
 
-		paymentInstructionFromJSON, err := models.NewPaymentInstructionFromJSON(body)
-		if err != nil {
+		if !status.Valid {
+			legacyInstruction, err := models.NewPaymentInstructionFromJSON(legacyBody)
+			if err != nil {
+				return nil, err
+			}
+			legacy[len(dtos)] = legacyInstruction
+			dtos = append(dtos, models.PaymentInstructionDTO{})
+			continue
+		}
+
+		instruction.Status = models.PaymentInstructionStatus(status.String)
+		if err := json.Unmarshal(incomingInstructionJSON, &instruction.IncomingInstruction); err != nil {
 			return nil, err
 		}
-		result = append(result, paymentInstructionFromJSON)
+		dtos = append(dtos, instruction)
+		normalised = append(normalised, string(instruction.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	if len(result) == 0 {
-		return nil, PaymentInstructionMissingError{CorrelationID: correlationId}
+	events, err := s.eventsOf(ctx, normalised)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.PaymentInstruction, 0, len(dtos))
+	for i, dto := range dtos {
+		if legacyInstruction, isLegacy := legacy[i]; isLegacy {
+			result = append(result, legacyInstruction)
+			continue
+		}
+		dto.Events = events[dto.ID]
+		result = append(result, models.NewPaymentInstructionFromDTO(dto))
 	}
 
 	return result, nil
 }
 
+func (s PostgresStore) eventsOf(ctx context.Context, ids []string) (map[models.PaymentInstructionID][]models.PaymentInstructionEvent, error) {
+	events := make(map[models.PaymentInstructionID][]models.PaymentInstructionEvent)
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT payment_instruction_id, event FROM payment_instruction_events WHERE payment_instruction_id = ANY($1) ORDER BY payment_instruction_id, sequence`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        models.PaymentInstructionID
+			eventJSON []byte
+			event     models.PaymentInstructionEvent
+		)
+		if err := rows.Scan(&id, &eventJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(eventJSON, &event); err != nil {
+			return nil, err
+		}
+		events[id] = append(events[id], event)
+	}
+
+	return events, rows.Err()
+}
+
 func (s PostgresStore) GetReport(ctx context.Context, date time.Time) (models.PaymentReport, error) {
 	ctx, span := postgresTracing.SpanWithContext(ctx, getReportQuery)
 	defer postgresTracing.EndSpan(span)
 
 	startTime := time.Now()
-	maxDate := date.AddDate(0, 0, 1)
 	row, err := s.db.QueryContext(ctx, `
-				select body->>'status' as status, count(1) as count from payment_instructions
-				where body->'incomingInstruction'->'payment'->>'executionDate' > $1 and body->'incomingInstruction'->'payment'->>'executionDate' < $2
-				group by body->>'status';`, date.String(), maxDate.String())
+				select status, count(1) as count from payment_instructions
+				where execution_date = $1::date and status is not null
+				group by status;`, date.Format(executionDateLayout))
 	if err != nil {
 		return models.PaymentReport{}, err
 	}
+	defer row.Close()
 
 	stats := make(map[models.PaymentInstructionStatus]uint)
 	for row.Next() {
@@ -276,30 +577,38 @@ func (s PostgresStore) GetReport(ctx context.Context, date time.Time) (models.Pa
 		stats[statusString] = count
 	}
 
-	failedPayments, failureStats, err := s.getFailures(ctx, date, maxDate)
-	report := newPaymentReport(stats, failedPayments, failureStats)
+	failedPayments, failureStats, err := s.getFailures(ctx, date)
 	if err != nil {
 		return models.PaymentReport{}, err
 	}
+	report := newPaymentReport(stats, failedPayments, failureStats)
 
 	s.observer.GotReport(ctx, time.Since(startTime).Milliseconds())
 
 	return report, nil
 }
 
-func (s PostgresStore) getFailures(ctx context.Context, date, maxDate time.Time) ([]models.FailedInstruction, map[models.DomainFailureReasonCode]uint, error) {
+// getFailures returns the failed and rejected payment instructions of the day, with the failure reason of their last event.
+// Rejected status transitions are skipped, they don't change why the payment instruction failed.
+func (s PostgresStore) getFailures(ctx context.Context, date time.Time) ([]models.FailedInstruction, map[models.DomainFailureReasonCode]uint, error) {
 	row, err := s.db.QueryContext(ctx,
-		`select body->>'id' as id, body->'incomingInstruction'->'payment'->'currency'->>'isoCode' as currency, body->'incomingInstruction'->'merchant'->>'contractNumber' as mid, body->'events' as events
-				from payment_instructions 
-				where (body->'incomingInstruction'->'payment'->>'executionDate' > $1 and body->'incomingInstruction'->'payment'->>'executionDate' < $2) and (body->>'status'=$3 or body->>'status'=$4 );`,
-		date.String(),
-		maxDate.String(),
-		models.Rejected,
-		models.Failed,
+		`select pi.payment_instruction_id, pi.currency, pi.contract_number, coalesce(last_event.failure_reason_code, '')
+				from payment_instructions pi
+				left join lateral (
+					select failure_reason_code from payment_instruction_events e
+					where e.payment_instruction_id = pi.payment_instruction_id and e.type <> $3
+					order by e.sequence desc limit 1
+				) last_event on true
+				where pi.execution_date = $1::date and pi.status = any($2)
+				order by pi.created_at;`,
+		date.Format(executionDateLayout),
+		pq.Array([]models.PaymentInstructionStatus{models.Rejected, models.Failed}),
+		models.DomainTransitionRejected,
 	)
 	if err != nil {
 		return []models.FailedInstruction{}, map[models.DomainFailureReasonCode]uint{}, err
 	}
+	defer row.Close()
 
 	var failedPayments []models.FailedInstruction
 	failedStats := make(map[models.DomainFailureReasonCode]uint)
@@ -307,38 +616,20 @@ func (s PostgresStore) getFailures(ctx context.Context, date, maxDate time.Time)
 		var id models.PaymentInstructionID
 		var currency models.CurrencyCode
 		var mid string
-		var rawEvents []byte
-		var events []failureEventFromPG
+		var reason models.DomainFailureReasonCode
 
-		err = row.Scan(&id, &currency, &mid, &rawEvents)
+		err = row.Scan(&id, &currency, &mid, &reason)
 		if err != nil {
 			continue
 		}
 
-		if err := json.Unmarshal(rawEvents, &events); err != nil {
-			return []models.FailedInstruction{}, map[models.DomainFailureReasonCode]uint{}, err
-		}
-
-		var reason models.DomainFailureReasonCode
-		if len(events) < 1 {
-			reason = ""
-		} else {
-			mostRecentEvent := events[len(events)-1]
-			reason = mostRecentEvent.GetCode()
-		}
-
-		if _, ok := failedStats[reason]; !ok {
-			failedStats[reason] = 0
-		}
 		failedStats[reason] = failedStats[reason] + 1
-		failedPayment := models.FailedInstruction{
+		failedPayments = append(failedPayments, models.FailedInstruction{
 			ID:       id,
 			Currency: currency,
 			Mid:      mid,
 			Reason:   reason,
-		}
-
-		failedPayments = append(failedPayments, failedPayment)
+		})
 	}
 
 	return failedPayments, failedStats, nil
@@ -348,17 +639,16 @@ func (s PostgresStore) GetCurrencyReport(ctx context.Context, date time.Time) (m
 	ctx, span := postgresTracing.SpanWithContext(ctx, getCurrencyReportQuery)
 	defer postgresTracing.EndSpan(span)
 
-	maxDate := date.AddDate(0, 0, 1)
 	row, err := s.db.QueryContext(ctx, `select
-        body->'incomingInstruction'->'payment'->'currency'->>'isoCode' as currency
-		,body->'incomingInstruction'->'merchant'->>'highRisk' as highRisk
-        ,body->>'status' as status
+        currency
+		,high_risk
+        ,status
         ,count(1) as count
-		,sum(cast(body->'incomingInstruction'->'payment'->>'amount' as decimal)) as amount
+		,coalesce(sum(amount), 0) as amount
 		from payment_instructions
-		where (body->'incomingInstruction'->'payment'->>'executionDate' > $1 and body->'incomingInstruction'->'payment'->>'executionDate' < $2)
-		group by body->'incomingInstruction'->'payment'->'currency'->>'isoCode', body->'incomingInstruction'->'merchant'->>'highRisk', body->>'status'
-		order by currency, status, count`, date.String(), maxDate.String())
+		where execution_date = $1::date and status is not null
+		group by currency, high_risk, status
+		order by currency, status, count`, date.Format(executionDateLayout))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return models.PaymentCurrencyReport{}, ReportMissingError{Date: date.String()}
@@ -366,6 +656,7 @@ func (s PostgresStore) GetCurrencyReport(ctx context.Context, date time.Time) (m
 			return models.PaymentCurrencyReport{}, err
 		}
 	}
+	defer row.Close()
 
 	report := make(map[string]models.CurrencyStats)
 	for row.Next() {
@@ -407,31 +698,33 @@ func (s PostgresStore) GetPaymentByMid(ctx context.Context, mid string, date tim
 	ctx, span := postgresTracing.SpanWithContext(ctx, getPaymentByMidQuery)
 	defer postgresTracing.EndSpan(span)
 
-	maxDate := date.AddDate(0, 0, 1)
-	row := s.db.QueryRowContext(ctx,
-		`select body from payment_instructions 
-				where body->'incomingInstruction'->'merchant'->>'contractNumber' = $1 and (body->'incomingInstruction'->'payment'->>'executionDate' > $2 and body->'incomingInstruction'->'payment'->>'executionDate' < $3) `,
+	instructions, err := s.queryPaymentInstructions(ctx,
+		`WHERE contract_number = $1 AND execution_date = $2::date ORDER BY created_at LIMIT 1`,
 		mid,
-		date.String(),
-		maxDate.String(),
+		date.Format(executionDateLayout),
 	)
-
-	var body []byte
-
-	if err := row.Scan(&body); err != nil {
-		if err == sql.ErrNoRows {
-			return models.PaymentInstruction{}, MidMissingError{Mid: mid, Date: date.Format("2006-01-02")}
-		} else {
-			return models.PaymentInstruction{}, err
-		}
-	}
-
-	paymentInstructionFromJSON, err := models.NewPaymentInstructionFromJSON(body)
 	if err != nil {
 		return models.PaymentInstruction{}, err
 	}
+	if len(instructions) == 0 {
+		return models.PaymentInstruction{}, MidMissingError{Mid: mid, Date: date.Format("2006-01-02")}
+	}
+
+	return instructions[0], nil
+}
 
-	return paymentInstructionFromJSON, nil
+const executionDateLayout = "2006-01-02"
+
+func executionDate(instruction models.IncomingInstruction) string {
+	return instruction.Payment.ExecutionDate.Format(executionDateLayout)
+}
+
+var numericPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// numericAmount is NULL for amounts Postgres can't store as NUMERIC, which only rejected payment instructions have.
+func numericAmount(amount string) sql.NullString {
+	amount = strings.TrimSpace(amount)
+	return sql.NullString{String: amount, Valid: numericPattern.MatchString(amount)}
 }
 
 func newPaymentReport(stats map[models.PaymentInstructionStatus]uint, failedPayments []models.FailedInstruction, failureStats map[models.DomainFailureReasonCode]uint) models.PaymentReport {
@@ -442,6 +735,7 @@ func newPaymentReport(stats map[models.PaymentInstructionStatus]uint, failedPaym
 		Rejected:               stats[models.Rejected],
 		SubmittedForProcessing: stats[models.SubmittedForProcessing],
 		Received:               stats[models.Received],
+		Reversed:               stats[models.Reversed],
 	}
 	report.FailedPayments = models.FailedPayments{
 		FailedStats: models.FailedStats{
@@ -452,6 +746,8 @@ func newPaymentReport(stats map[models.PaymentInstructionStatus]uint, failedPaym
 			NoSourceAcct:     failureStats[models.NoSourceAcct],
 			FailedValidation: failureStats[models.FailedValidation],
 			MissingFunds:     failureStats[models.MissingFunds],
+			Cancelled:        failureStats[models.CancelledPayment],
+			ManualAction:     failureStats[models.AwaitingManualAction],
 		},
 		FailedInstructions: failedPayments,
 	}
@@ -461,8 +757,10 @@ func newPaymentReport(stats map[models.PaymentInstructionStatus]uint, failedPaym
 
 // PaymentInstructionMissingError is returned when payment instruction referenced by PaymentInstructionID can't be found.
 type PaymentInstructionMissingError struct {
-	ID            models.PaymentInstructionID
-	CorrelationID string
+	ID                models.PaymentInstructionID
+	CorrelationID     string
+	IdempotencyKey    string
+	ProviderPaymentID models.ProviderPaymentID
 }
 
 func (p PaymentInstructionMissingError) Error() string {
@@ -470,6 +768,12 @@ func (p PaymentInstructionMissingError) Error() string {
 	if id == "" {
 		id = p.CorrelationID
 	}
+	if id == "" {
+		id = p.IdempotencyKey
+	}
+	if id == "" {
+		id = string(p.ProviderPaymentID)
+	}
 
 	return fmt.Sprintf("payment instruction %q is not found", id)
 }
@@ -121,6 +121,60 @@ func TestStorePaymentInstruction(t *testing.T) {
 		assert.EqualError(t, err, errMessage)
 	})
 
+	t.Run("the duplication detection compares amounts as numbers", func(t *testing.T) {
+		var (
+			mid      = testhelpers2.RandomString()
+			currency = models.Currency{
+				IsoCode:   models.GBP,
+				IsoNumber: "826",
+			}
+			accountNumber = "GB33BUKB20201555555555"
+
+			firstPayment  = testhelpers.NewPaymentInstructionBuilder().WithMid(mid).WithAmount("100").WithCurrency(currency).WithAccountNumber(accountNumber).WithStatus(models.Successful).Build()
+			secondPayment = testhelpers.NewPaymentInstructionBuilder().WithMid(mid).WithAmount("100.00").WithCurrency(currency).WithAccountNumber(accountNumber).Build()
+		)
+
+		err := paymentStore.Store(ctx, firstPayment)
+		require.NoError(t, err)
+
+		err = paymentStore.Store(ctx, secondPayment)
+		assert.Equal(t, ErrDuplicate, err)
+	})
+
+	t.Run("a payment with an idempotency key is only duplicated by a payment with the same key", func(t *testing.T) {
+		var (
+			mid           = testhelpers2.RandomString()
+			key           = testhelpers2.RandomString()
+			accountNumber = "GB33BUKB20201555555555"
+
+			firstPayment  = testhelpers.NewPaymentInstructionBuilder().WithMid(mid).WithAmount("100").WithAccountNumber(accountNumber).Build()
+			secondPayment = testhelpers.NewPaymentInstructionBuilder().WithMid(mid).WithAmount("100").WithAccountNumber(accountNumber).Build()
+			retry         = testhelpers.NewPaymentInstructionBuilder().WithMid(mid).WithAmount("100").WithAccountNumber(accountNumber).Build()
+		)
+		firstPayment.SetIdempotencyKey(models.IdempotencyKey{Key: key, RequestFingerprint: "some-fingerprint"})
+		secondPayment.SetIdempotencyKey(models.IdempotencyKey{Key: testhelpers2.RandomString(), RequestFingerprint: "some-fingerprint"})
+		retry.SetIdempotencyKey(models.IdempotencyKey{Key: key, RequestFingerprint: "some-fingerprint"})
+
+		require.NoError(t, paymentStore.StoreForDispatch(ctx, firstPayment))
+		require.NoError(t, paymentStore.StoreForDispatch(ctx, secondPayment))
+		assert.Equal(t, ErrDuplicateIdempotencyKey, paymentStore.StoreForDispatch(ctx, retry))
+
+		stored, err := paymentStore.GetFromIdempotencyKey(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, firstPayment.ID(), stored.ID())
+		assert.Equal(t, firstPayment.IdempotencyKey(), stored.IdempotencyKey())
+
+		_, err = paymentStore.Get(ctx, retry.ID())
+		assert.Equal(t, PaymentInstructionMissingError{ID: retry.ID()}, err)
+	})
+
+	t.Run("no payment instruction is found for an idempotency key nobody used", func(t *testing.T) {
+		key := testhelpers2.RandomString()
+
+		_, err := paymentStore.GetFromIdempotencyKey(ctx, key)
+		assert.Equal(t, PaymentInstructionMissingError{IdempotencyKey: key}, err)
+	})
+
 	t.Run("when a payment is in a state other than FAILED or REJECTED, the replay of that payment with a different date should not trigger the duplication detection", func(t *testing.T) {
 		var (
 			mid      = testhelpers2.RandomString()
@@ -191,7 +245,7 @@ func TestUpdatePayment(t *testing.T) {
 		_, err = paymentStore.Get(ctx, submittedPaymentInstruction.ID())
 		require.NoError(t, err)
 
-		err = paymentStore.UpdatePayment(ctx, submittedPaymentInstruction.ID(), expectedStatus, expectedEvents)
+		err = paymentStore.UpdatePayment(ctx, submittedPaymentInstruction.ID(), submittedPaymentInstruction.Version(), expectedStatus, expectedEvents)
 		require.NoError(t, err)
 
 		updatedInstruction, err := paymentStore.Get(ctx, submittedPaymentInstruction.ID())
@@ -201,6 +255,28 @@ func TestUpdatePayment(t *testing.T) {
 		assert.Equal(t, expectedEvents, updatedInstruction.Events()[2])
 		assert.Equal(t, 3, updatedInstruction.Version())
 	})
+
+	t.Run("an update made on a stale version fails with a conflict and changes nothing", func(t *testing.T) {
+		var (
+			ctx                = context.Background()
+			paymentInstruction = testhelpers.NewPaymentInstructionBuilder().WithStatus(models.SubmittedForProcessing).Build()
+			staleVersion       = paymentInstruction.Version()
+			successEvent       = models.PaymentInstructionEvent{Type: models.DomainProcessingSucceeded, Details: ""}
+			failureEvent       = models.PaymentInstructionEvent{Type: models.DomainProcessingFailed, Details: ""}
+		)
+		require.NoError(t, paymentStore.Store(ctx, paymentInstruction))
+
+		err := paymentStore.UpdatePayment(ctx, paymentInstruction.ID(), staleVersion, models.Successful, successEvent)
+		require.NoError(t, err)
+
+		err = paymentStore.UpdatePayment(ctx, paymentInstruction.ID(), staleVersion, models.Failed, failureEvent)
+		assert.Equal(t, models.VersionConflictError{ID: paymentInstruction.ID(), ExpectedVersion: staleVersion, ActualVersion: staleVersion + 1}, err)
+
+		storedInstruction, err := paymentStore.Get(ctx, paymentInstruction.ID())
+		require.NoError(t, err)
+		assert.Equal(t, models.Successful, storedInstruction.GetStatus())
+		assert.Equal(t, staleVersion+1, storedInstruction.Version())
+	})
 }
 
 func TestPostgresStore_GetReport(t *testing.T) {
@@ -231,6 +307,7 @@ func TestPostgresStore_GetReport(t *testing.T) {
 		successPI1 := testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(
 			testhelpers.NewIncomingInstructionBuilder().WithPaymentExecutionDate(time.Now()).Build(),
 		).Build()
+		successPI1.SubmitForProcessing()
 		successPI1.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Submitted,
 			PaymentInstruction: successPI1,
@@ -239,6 +316,7 @@ func TestPostgresStore_GetReport(t *testing.T) {
 		successPI2 := testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(
 			testhelpers.NewIncomingInstructionBuilder().WithPaymentExecutionDate(time.Now()).Build(),
 		).Build()
+		successPI2.SubmitForProcessing()
 		successPI2.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Submitted,
 			PaymentInstruction: successPI2,
@@ -247,6 +325,7 @@ func TestPostgresStore_GetReport(t *testing.T) {
 		successPIYesterday := testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(
 			testhelpers.NewIncomingInstructionBuilder().WithPaymentExecutionDate(time.Now().AddDate(0, 0, -1)).Build(),
 		).Build()
+		successPIYesterday.SubmitForProcessing()
 		successPIYesterday.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Submitted,
 			PaymentInstruction: successPIYesterday,
@@ -261,6 +340,7 @@ func TestPostgresStore_GetReport(t *testing.T) {
 			testhelpers.NewIncomingInstructionBuilder().WithPaymentExecutionDate(time.Now()).Build(),
 		).Build()
 
+		failedPI.SubmitForProcessing()
 		failedPI.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Failure,
 			PaymentInstruction: failedPI,
@@ -342,6 +422,7 @@ func TestPostgresStore_GetCurrencyReport(t *testing.T) {
 		successPI1 := testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(
 			testhelpers.NewIncomingInstructionBuilder().WithPaymentExecutionDate(time.Now()).WithMerchantAccountNumber(successPI1Mid).WithPaymentAmount("50").Build(),
 		).Build()
+		successPI1.SubmitForProcessing()
 		successPI1.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Submitted,
 			PaymentInstruction: successPI1,
@@ -353,6 +434,7 @@ func TestPostgresStore_GetCurrencyReport(t *testing.T) {
 				IsoNumber: "203",
 			}).Build(),
 		).Build()
+		successPI2.SubmitForProcessing()
 		successPI2.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Submitted,
 			PaymentInstruction: successPI2,
@@ -361,6 +443,7 @@ func TestPostgresStore_GetCurrencyReport(t *testing.T) {
 		successPI3 := testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(
 			testhelpers.NewIncomingInstructionBuilder().WithPaymentExecutionDate(time.Now()).WithPaymentAmount("50").WithMerchantAccountNumber(successPI3Mid).WithHighRIsk().Build(),
 		).Build()
+		successPI3.SubmitForProcessing()
 		successPI3.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Submitted,
 			PaymentInstruction: successPI3,
@@ -378,6 +461,7 @@ func TestPostgresStore_GetCurrencyReport(t *testing.T) {
 			}).Build(),
 		).Build()
 
+		failedPI.SubmitForProcessing()
 		failedPI.TrackPPEvent(models.PaymentProviderEvent{
 			Type:               models.Failure,
 			PaymentInstruction: failedPI,
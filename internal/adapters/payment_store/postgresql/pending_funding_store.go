@@ -0,0 +1,193 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	savePendingFundingQuery        = "savePendingFunding"
+	getPendingFundingQuery         = "getPendingFunding"
+	listPendingFundingsQuery       = "listPendingFundings"
+	recordPendingFundingCheckQuery = "recordPendingFundingCheck"
+	updatePendingFundingStateQuery = "updatePendingFundingState"
+)
+
+var _ ports.PendingFundingRepo = PostgresStore{}
+
+const pendingFundingColumns = `pending_funding_id, filename, currency, high_risk, amount, instruction_count, instructions, state, state_reason, checks, last_checked_at, created_at, updated_at`
+
+func (s PostgresStore) SavePendingFunding(ctx context.Context, pendingFunding models.PendingFunding) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, savePendingFundingQuery)
+	defer postgresTracing.EndSpan(span)
+
+	instructionsJSON, err := json.Marshal(pendingFunding.Instructions)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO pending_fundings (pending_funding_id, filename, currency, high_risk, amount, instruction_count, instructions, state, state_reason)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		pendingFunding.ID,
+		pendingFunding.Filename,
+		pendingFunding.Currency,
+		pendingFunding.HighRisk,
+		pendingFunding.Amount,
+		pendingFunding.Count,
+		instructionsJSON,
+		pendingFunding.State,
+		pendingFunding.StateReason,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to save pending funding %s, err: %w", pendingFunding.ID, err)
+	}
+
+	return nil
+}
+
+func (s PostgresStore) GetPendingFunding(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getPendingFundingQuery)
+	defer postgresTracing.EndSpan(span)
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+pendingFundingColumns+` FROM pending_fundings WHERE pending_funding_id = $1`, id)
+	pendingFunding, err := scanPendingFunding(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.PendingFunding{}, PendingFundingMissingError{ID: id}
+		}
+		return models.PendingFunding{}, err
+	}
+
+	return pendingFunding, nil
+}
+
+func (s PostgresStore) ListPendingFundings(ctx context.Context, state models.PendingFundingState) ([]models.PendingFunding, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, listPendingFundingsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+pendingFundingColumns+` FROM pending_fundings WHERE ($1 = '' OR state = $1) ORDER BY created_at`,
+		state,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pendingFundings := make([]models.PendingFunding, 0)
+	for rows.Next() {
+		pendingFunding, err := scanPendingFunding(rows)
+		if err != nil {
+			return nil, err
+		}
+		pendingFundings = append(pendingFundings, pendingFunding)
+	}
+
+	return pendingFundings, rows.Err()
+}
+
+func (s PostgresStore) RecordPendingFundingCheck(ctx context.Context, id models.PendingFundingID) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, recordPendingFundingCheckQuery)
+	defer postgresTracing.EndSpan(span)
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE pending_fundings SET checks = checks + 1, last_checked_at = now(), updated_at = now() WHERE pending_funding_id = $1`,
+		id,
+	)
+	return err
+}
+
+func (s PostgresStore) UpdatePendingFundingState(ctx context.Context, id models.PendingFundingID, from, to models.PendingFundingState, reason string) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, updatePendingFundingStateQuery)
+	defer postgresTracing.EndSpan(span)
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE pending_fundings SET state = $1, state_reason = $2, updated_at = now() WHERE pending_funding_id = $3 AND state = $4`,
+		to,
+		reason,
+		id,
+		from,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to update pending funding %s, err: %w", id, err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		current, err := s.GetPendingFunding(ctx, id)
+		if err != nil {
+			return err
+		}
+		return PendingFundingStateConflictError{ID: id, Expected: from, Actual: current.State}
+	}
+
+	return nil
+}
+
+func scanPendingFunding(row rowScanner) (models.PendingFunding, error) {
+	var (
+		pendingFunding   models.PendingFunding
+		instructionsJSON []byte
+		lastCheckedAt    sql.NullTime
+	)
+
+	err := row.Scan(
+		&pendingFunding.ID,
+		&pendingFunding.Filename,
+		&pendingFunding.Currency,
+		&pendingFunding.HighRisk,
+		&pendingFunding.Amount,
+		&pendingFunding.Count,
+		&instructionsJSON,
+		&pendingFunding.State,
+		&pendingFunding.StateReason,
+		&pendingFunding.Checks,
+		&lastCheckedAt,
+		&pendingFunding.CreatedAt,
+		&pendingFunding.UpdatedAt,
+	)
+	if err != nil {
+		return models.PendingFunding{}, err
+	}
+
+	if lastCheckedAt.Valid {
+		pendingFunding.LastCheckedAt = &lastCheckedAt.Time
+	}
+	if err := json.Unmarshal(instructionsJSON, &pendingFunding.Instructions); err != nil {
+		return models.PendingFunding{}, err
+	}
+
+	return pendingFunding, nil
+}
+
+// PendingFundingMissingError is returned when the pending funding referenced by PendingFundingID can't be found.
+type PendingFundingMissingError struct {
+	ID models.PendingFundingID
+}
+
+func (p PendingFundingMissingError) Error() string {
+	return fmt.Sprintf("pending funding %q is not found", p.ID)
+}
+
+// PendingFundingStateConflictError is returned when a pending funding is not in the state a transition expects,
+// e.g. when it was already released or cancelled.
+type PendingFundingStateConflictError struct {
+	ID       models.PendingFundingID
+	Expected models.PendingFundingState
+	Actual   models.PendingFundingState
+}
+
+func (p PendingFundingStateConflictError) Error() string {
+	return fmt.Sprintf("pending funding %q is %s, expected %s", p.ID, p.Actual, p.Expected)
+}
@@ -0,0 +1,77 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	testhelpers2 "github.com/saltpay/settlements-payments-system/internal/testhelpers"
+)
+
+func TestPendingFundingRepo(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	newPendingFunding := func() models.PendingFunding {
+		return models.NewPendingFunding(
+			testhelpers2.RandomString()+".xml",
+			models.IncomingInstructionsSummary{CurrencyCode: models.EUR, Counter: 1, Amount: 10},
+			models.IncomingInstructions{testhelpers.NewIncomingInstructionBuilder().Build()},
+		)
+	}
+
+	t.Run("a saved pending funding can be read back with its instructions", func(t *testing.T) {
+		pendingFunding := newPendingFunding()
+		require.NoError(t, paymentStore.SavePendingFunding(ctx, pendingFunding))
+
+		got, err := paymentStore.GetPendingFunding(ctx, pendingFunding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, pendingFunding.Filename, got.Filename)
+		assert.Equal(t, models.PendingFundingPending, got.State)
+		assert.Len(t, got.Instructions, 1)
+		assert.Nil(t, got.LastCheckedAt)
+
+		require.NoError(t, paymentStore.RecordPendingFundingCheck(ctx, pendingFunding.ID))
+		got, err = paymentStore.GetPendingFunding(ctx, pendingFunding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Checks)
+		assert.NotNil(t, got.LastCheckedAt)
+	})
+
+	t.Run("a pending funding can only leave the pending state once", func(t *testing.T) {
+		pendingFunding := newPendingFunding()
+		require.NoError(t, paymentStore.SavePendingFunding(ctx, pendingFunding))
+
+		err := paymentStore.UpdatePendingFundingState(ctx, pendingFunding.ID, models.PendingFundingPending, models.PendingFundingReleased, "funds became available")
+		require.NoError(t, err)
+
+		err = paymentStore.UpdatePendingFundingState(ctx, pendingFunding.ID, models.PendingFundingPending, models.PendingFundingCancelled, "too late")
+		assert.Equal(t, PendingFundingStateConflictError{ID: pendingFunding.ID, Expected: models.PendingFundingPending, Actual: models.PendingFundingReleased}, err)
+	})
+
+	t.Run("getting an unknown pending funding returns a missing error", func(t *testing.T) {
+		_, err := paymentStore.GetPendingFunding(ctx, models.PendingFundingID(testhelpers2.RandomString()))
+		assert.IsType(t, PendingFundingMissingError{}, err)
+	})
+}
@@ -3,3 +3,6 @@ package postgresql
 import "errors"
 
 var ErrDuplicate = errors.New("duplicate")
+
+// ErrDuplicateIdempotencyKey is returned when a payment instruction is stored with an idempotency key another one has already.
+var ErrDuplicateIdempotencyKey = errors.New("duplicate idempotency key")
@@ -0,0 +1,56 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const getInstructionByProviderPaymentIDQuery = "getInstructionByProviderPaymentID"
+
+var _ ports.ProviderPaymentRepo = PostgresStore{}
+
+// GetFromProviderPaymentID finds the payment instruction from the last time the payment provider accepted it under the payment ID.
+func (s PostgresStore) GetFromProviderPaymentID(
+	ctx context.Context,
+	provider models.PaymentProviderType,
+	providerPaymentID models.ProviderPaymentID,
+) (models.PaymentInstruction, models.BankingReference, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getInstructionByProviderPaymentIDQuery)
+	defer postgresTracing.EndSpan(span)
+
+	var (
+		id               models.PaymentInstructionID
+		bankingReference models.BankingReference
+	)
+	err := s.db.QueryRowContext(ctx,
+		`select e.payment_instruction_id, coalesce(e.event->'details'->>'bankingReference', '')
+				from payment_instruction_events e
+				join payment_instructions pi on pi.payment_instruction_id = e.payment_instruction_id
+				where e.type = $1 and e.event->'details'->>'paymentProviderPaymentID' = $2 and pi.payment_provider = $3
+				order by e.sequence desc limit 1`,
+		models.DomainAcceptedByPaymentProvider,
+		providerPaymentID,
+		provider,
+	).Scan(&id, &bankingReference)
+	if err == sql.ErrNoRows {
+		return models.PaymentInstruction{}, "", PaymentInstructionMissingError{ProviderPaymentID: providerPaymentID}
+	}
+	if err != nil {
+		return models.PaymentInstruction{}, "", fmt.Errorf("unable to query payment instruction by provider payment ID, err: %w", err)
+	}
+
+	instructions, err := s.queryPaymentInstructions(ctx, `WHERE payment_instruction_id = $1`, id)
+	if err != nil {
+		return models.PaymentInstruction{}, "", err
+	}
+	if len(instructions) == 0 {
+		return models.PaymentInstruction{}, "", PaymentInstructionMissingError{ID: id}
+	}
+	return instructions[0], bankingReference, nil
+}
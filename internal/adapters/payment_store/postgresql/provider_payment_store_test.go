@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+)
+
+func TestGetFromProviderPaymentID(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	t.Run("a payment instruction accepted by the payment provider is found from its payment ID", func(t *testing.T) {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+		instruction.SubmitForProcessing()
+		require.NoError(t, paymentStore.Store(ctx, instruction))
+
+		providerPaymentID := models.ProviderPaymentID("bc-payment-id-" + string(instruction.ID()))
+		event, err := models.NewPaymentProviderEvent(time.Now(), models.Submitted, instruction, models.BC, providerPaymentID, "bc-reference", nil)
+		require.NoError(t, err)
+		expectedVersion := instruction.Version()
+		instruction.TrackPPEvent(event)
+		events := instruction.Events()
+		require.NoError(t, paymentStore.UpdatePayment(ctx, instruction.ID(), expectedVersion, instruction.GetStatus(), events[len(events)-1]))
+
+		found, bankingReference, err := paymentStore.GetFromProviderPaymentID(ctx, models.BankingCircle, providerPaymentID)
+		require.NoError(t, err)
+		assert.Equal(t, instruction.ID(), found.ID())
+		assert.Equal(t, models.StateSubmitted, found.GetStatus())
+		assert.Equal(t, models.BankingReference("bc-reference"), bankingReference)
+	})
+
+	t.Run("a payment ID no payment provider accepted is missing", func(t *testing.T) {
+		_, _, err := paymentStore.GetFromProviderPaymentID(ctx, models.BankingCircle, "unknown-bc-payment-id")
+
+		var missing PaymentInstructionMissingError
+		assert.True(t, errors.As(err, &missing))
+	})
+}
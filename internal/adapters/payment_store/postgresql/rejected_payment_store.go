@@ -0,0 +1,202 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	getRejectedByProviderReferenceQuery = "getRejectedByProviderReference"
+	getRejectedByContractQuery          = "getRejectedByContract"
+	getRejectedByDestinationQuery       = "getRejectedByDestination"
+	saveUnmatchedRejectionsQuery        = "saveUnmatchedRejections"
+	getUnmatchedRejectionsQuery         = "getUnmatchedRejections"
+)
+
+var _ ports.RejectedPaymentRepo = PostgresStore{}
+
+func (s PostgresStore) GetFromProviderReference(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getRejectedByProviderReferenceQuery)
+	defer postgresTracing.EndSpan(span)
+
+	return s.queryRejectedPaymentInstructions(ctx,
+		`pi.payment_instruction_id in (
+					select e.payment_instruction_id from payment_instruction_events e
+					where e.type = $1 and (e.event->'details'->>'paymentProviderPaymentID' = $3 or e.event->'details'->>'bankingReference' = $3)
+				)`,
+		provider,
+		reference,
+	)
+}
+
+func (s PostgresStore) GetFromContract(ctx context.Context, provider models.PaymentProviderType, contractNumber string, executionDate time.Time) ([]models.RejectedPaymentInstruction, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getRejectedByContractQuery)
+	defer postgresTracing.EndSpan(span)
+
+	return s.queryRejectedPaymentInstructions(ctx,
+		`pi.contract_number = $3 and pi.execution_date = $4::date`,
+		provider,
+		contractNumber,
+		executionDate.Format(executionDateLayout),
+	)
+}
+
+// GetFromDestination compares the amounts as numbers, so an amount matches however many decimals it was written with.
+func (s PostgresStore) GetFromDestination(
+	ctx context.Context,
+	provider models.PaymentProviderType,
+	accountNumber string,
+	currency models.CurrencyCode,
+	amount string,
+	executionDate time.Time,
+) ([]models.RejectedPaymentInstruction, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getRejectedByDestinationQuery)
+	defer postgresTracing.EndSpan(span)
+
+	return s.queryRejectedPaymentInstructions(ctx,
+		`pi.account_number = $3 and pi.currency = $4 and pi.amount = $5::numeric and pi.execution_date = $6::date`,
+		provider,
+		accountNumber,
+		currency,
+		numericAmount(amount),
+		executionDate.Format(executionDateLayout),
+	)
+}
+
+// queryRejectedPaymentInstructions takes the payment provider's payment ID and banking reference from the last time it
+// accepted the payment instruction, where can refer to the type of that event as $1 and to the payment provider as $2.
+func (s PostgresStore) queryRejectedPaymentInstructions(ctx context.Context, where string, provider models.PaymentProviderType, args ...interface{}) ([]models.RejectedPaymentInstruction, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`select pi.payment_instruction_id,
+					coalesce(accepted.details->>'paymentProviderPaymentID', ''), coalesce(accepted.details->>'bankingReference', '')
+				from payment_instructions pi
+				left join lateral (
+					select e.event->'details' as details from payment_instruction_events e
+					where e.payment_instruction_id = pi.payment_instruction_id and e.type = $1
+					order by e.sequence desc limit 1
+				) accepted on true
+				where pi.payment_provider = $2 and `+where+`
+				order by pi.created_at`,
+		append([]interface{}{models.DomainAcceptedByPaymentProvider, provider}, args...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query rejected payment instructions, err: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		rejected []models.RejectedPaymentInstruction
+		ids      []string
+	)
+	for rows.Next() {
+		var (
+			id          models.PaymentInstructionID
+			instruction models.RejectedPaymentInstruction
+		)
+		if err := rows.Scan(&id, &instruction.ProviderPaymentID, &instruction.BankingReference); err != nil {
+			return nil, err
+		}
+		rejected = append(rejected, instruction)
+		ids = append(ids, string(id))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(rejected) == 0 {
+		return nil, nil
+	}
+
+	instructions, err := s.queryPaymentInstructions(ctx, `WHERE payment_instruction_id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rejected payment instructions, err: %w", err)
+	}
+	byID := make(map[models.PaymentInstructionID]models.PaymentInstruction, len(instructions))
+	for _, instruction := range instructions {
+		byID[instruction.ID()] = instruction
+	}
+
+	for i, id := range ids {
+		rejected[i].PaymentInstruction = byID[models.PaymentInstructionID(id)]
+	}
+	return rejected, nil
+}
+
+func (s PostgresStore) SaveUnmatchedRejections(ctx context.Context, provider models.PaymentProviderType, reportDate time.Time, rejections []models.UnmatchedRejection) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, saveUnmatchedRejectionsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	date := reportDate.Format(executionDateLayout)
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM unmatched_rejections WHERE payment_provider = $1 AND report_date = $2::date`,
+		provider,
+		date,
+	); err != nil {
+		return fmt.Errorf("unable to clear unmatched rejections of %s on %s, err: %w", provider, date, err)
+	}
+
+	for position, rejection := range rejections {
+		rejectionJSON, err := json.Marshal(rejection.Rejection)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO unmatched_rejections (payment_provider, report_date, position, reason, payment_instruction_id, rejection)
+					VALUES ($1, $2::date, $3, $4, $5, $6)`,
+			provider,
+			date,
+			position,
+			rejection.Reason,
+			rejection.PaymentInstructionID,
+			rejectionJSON,
+		); err != nil {
+			return fmt.Errorf("unable to save unmatched rejection of %s on %s, err: %w", provider, date, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s PostgresStore) GetUnmatchedRejections(ctx context.Context, reportDate time.Time) ([]models.UnmatchedRejection, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getUnmatchedRejectionsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT payment_provider, reason, payment_instruction_id, rejection FROM unmatched_rejections
+				WHERE report_date = $1::date ORDER BY payment_provider, position`,
+		reportDate.Format(executionDateLayout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query unmatched rejections, err: %w", err)
+	}
+	defer rows.Close()
+
+	var rejections []models.UnmatchedRejection
+	for rows.Next() {
+		var (
+			rejection     models.UnmatchedRejection
+			rejectionJSON []byte
+		)
+		if err := rows.Scan(&rejection.PaymentProvider, &rejection.Reason, &rejection.PaymentInstructionID, &rejectionJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(rejectionJSON, &rejection.Rejection); err != nil {
+			return nil, err
+		}
+		rejections = append(rejections, rejection)
+	}
+	return rejections, rows.Err()
+}
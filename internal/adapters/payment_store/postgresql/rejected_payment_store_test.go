@@ -0,0 +1,118 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+)
+
+func TestRejectedPaymentRepo(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	executionDate := time.Date(2022, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	// newAcceptedInstruction stores a payment instruction Banking Circle accepted, paying its own contract and account
+	newAcceptedInstruction := func(t *testing.T) (models.PaymentInstruction, string) {
+		t.Helper()
+		unique := uuid.NewString()[:8]
+		instruction := testhelpers.NewPaymentInstructionBuilder().
+			WithPaymentProvider(models.BankingCircle).
+			WithMid("mid-" + unique).
+			WithAccountNumber("GB33BUKB" + unique).
+			WithAmount("1250.50").
+			WithDate(executionDate).
+			Build()
+		instruction.SubmitForProcessing()
+		require.NoError(t, paymentStore.Store(ctx, instruction))
+
+		providerPaymentID := models.ProviderPaymentID("bc-payment-id-" + unique)
+		event, err := models.NewPaymentProviderEvent(time.Now(), models.Submitted, instruction, models.BC, providerPaymentID, models.BankingReference("bc-reference-"+unique), nil)
+		require.NoError(t, err)
+		expectedVersion := instruction.Version()
+		instruction.TrackPPEvent(event)
+		events := instruction.Events()
+		require.NoError(t, paymentStore.UpdatePayment(ctx, instruction.ID(), expectedVersion, instruction.GetStatus(), events[len(events)-1]))
+		return instruction, unique
+	}
+
+	t.Run("a payment instruction is found from the payment ID or the banking reference it was accepted with", func(t *testing.T) {
+		instruction, unique := newAcceptedInstruction(t)
+
+		for _, reference := range []string{"bc-payment-id-" + unique, "bc-reference-" + unique} {
+			found, err := paymentStore.GetFromProviderReference(ctx, models.BankingCircle, reference)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			assert.Equal(t, instruction.ID(), found[0].PaymentInstruction.ID())
+			assert.Equal(t, models.ProviderPaymentID("bc-payment-id-"+unique), found[0].ProviderPaymentID)
+		}
+	})
+
+	t.Run("a payment instruction is found from its contract and execution date", func(t *testing.T) {
+		instruction, _ := newAcceptedInstruction(t)
+
+		found, err := paymentStore.GetFromContract(ctx, models.BankingCircle, instruction.ContractNumber(), executionDate)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, instruction.ID(), found[0].PaymentInstruction.ID())
+	})
+
+	t.Run("a payment instruction is found from its destination, whatever decimals its amount is written with", func(t *testing.T) {
+		instruction, _ := newAcceptedInstruction(t)
+
+		found, err := paymentStore.GetFromDestination(ctx, models.BankingCircle, instruction.IncomingInstruction.AccountNumber(), instruction.IncomingInstruction.IsoCode(), "1250.5", executionDate)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, instruction.ID(), found[0].PaymentInstruction.ID())
+
+		found, err = paymentStore.GetFromDestination(ctx, models.BankingCircle, instruction.IncomingInstruction.AccountNumber(), instruction.IncomingInstruction.IsoCode(), "1250.51", executionDate)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("saving the unmatched rejections of a report replaces the ones saved before", func(t *testing.T) {
+		reportDate := time.Date(2000+time.Now().Nanosecond()%1000, 1, 1, 0, 0, 0, 0, time.UTC)
+		first := models.UnmatchedRejection{
+			PaymentProvider: models.BankingCircle,
+			Rejection:       models.ProviderRejection{PaymentReference: "first", Amount: "10", Currency: models.EUR, StatusReason: "closed"},
+			Reason:          models.NoPaymentInstruction,
+		}
+		second := models.UnmatchedRejection{
+			PaymentProvider:      models.BankingCircle,
+			Rejection:            models.ProviderRejection{PaymentReference: "second", Amount: "20", Currency: models.EUR},
+			Reason:               models.PaymentInstructionSucceeded,
+			PaymentInstructionID: "payment-instruction-id",
+		}
+
+		require.NoError(t, paymentStore.SaveUnmatchedRejections(ctx, models.BankingCircle, reportDate, []models.UnmatchedRejection{first, second}))
+		require.NoError(t, paymentStore.SaveUnmatchedRejections(ctx, models.BankingCircle, reportDate, []models.UnmatchedRejection{second}))
+
+		unmatched, err := paymentStore.GetUnmatchedRejections(ctx, reportDate)
+		require.NoError(t, err)
+		assert.Equal(t, []models.UnmatchedRejection{second}, unmatched)
+	})
+}
@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const getStuckPaymentInstructionsQuery = "getStuckPaymentInstructions"
+
+var _ ports.StuckPaymentInstructionRepo = PostgresStore{}
+
+// GetStuckPaymentInstructions takes the payment provider's payment ID from the last time it accepted the payment instruction.
+// Rejected status transitions are skipped, they don't mean the payment provider moved the payment instruction on.
+func (s PostgresStore) GetStuckPaymentInstructions(
+	ctx context.Context,
+	provider models.PaymentProviderType,
+	statuses []models.PaymentInstructionStatus,
+	stuckBefore time.Time,
+) ([]models.StuckPaymentInstruction, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getStuckPaymentInstructionsQuery)
+	defer postgresTracing.EndSpan(span)
+
+	rows, err := s.db.QueryContext(ctx,
+		`select pi.payment_instruction_id, last_event.created_on,
+					coalesce(accepted.details->>'paymentProviderPaymentID', ''), coalesce(accepted.details->>'bankingReference', '')
+				from payment_instructions pi
+				join lateral (
+					select e.created_on from payment_instruction_events e
+					where e.payment_instruction_id = pi.payment_instruction_id and e.type <> $4
+					order by e.sequence desc limit 1
+				) last_event on true
+				left join lateral (
+					select e.event->'details' as details from payment_instruction_events e
+					where e.payment_instruction_id = pi.payment_instruction_id and e.type = $5
+					order by e.sequence desc limit 1
+				) accepted on true
+				where pi.payment_provider = $1 and pi.status = any($2) and last_event.created_on < $3
+				order by last_event.created_on`,
+		provider,
+		pq.Array(statuses),
+		stuckBefore,
+		models.DomainTransitionRejected,
+		models.DomainAcceptedByPaymentProvider,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query stuck payment instructions, err: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		stuck []models.StuckPaymentInstruction
+		ids   []string
+	)
+	for rows.Next() {
+		var (
+			id          models.PaymentInstructionID
+			instruction models.StuckPaymentInstruction
+		)
+		if err := rows.Scan(&id, &instruction.StuckSince, &instruction.ProviderPaymentID, &instruction.BankingReference); err != nil {
+			return nil, err
+		}
+		stuck = append(stuck, instruction)
+		ids = append(ids, string(id))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(stuck) == 0 {
+		return nil, nil
+	}
+
+	instructions, err := s.queryPaymentInstructions(ctx, `WHERE payment_instruction_id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read stuck payment instructions, err: %w", err)
+	}
+	byID := make(map[models.PaymentInstructionID]models.PaymentInstruction, len(instructions))
+	for _, instruction := range instructions {
+		byID[instruction.ID()] = instruction
+	}
+
+	for i, id := range ids {
+		stuck[i].PaymentInstruction = byID[models.PaymentInstructionID(id)]
+	}
+	return stuck, nil
+}
@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+)
+
+func TestGetStuckPaymentInstructions(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	// stuckOf returns the stuck payment instruction with the given ID, other tests may have left payment instructions behind
+	stuckOf := func(t *testing.T, id models.PaymentInstructionID, stuckBefore time.Time) (models.StuckPaymentInstruction, bool) {
+		t.Helper()
+		stuck, err := paymentStore.GetStuckPaymentInstructions(ctx, models.BankingCircle, models.InFlightStatuses, stuckBefore)
+		require.NoError(t, err)
+		for _, instruction := range stuck {
+			if instruction.PaymentInstruction.ID() == id {
+				return instruction, true
+			}
+		}
+		return models.StuckPaymentInstruction{}, false
+	}
+
+	trackEvent := func(t *testing.T, instruction *models.PaymentInstruction, eventType models.PaymentProviderEventType) {
+		t.Helper()
+		event, err := models.NewPaymentProviderEvent(time.Now(), eventType, *instruction, models.BC, "bc-payment-id", "bc-reference", nil)
+		require.NoError(t, err)
+		expectedVersion := instruction.Version()
+		instruction.TrackPPEvent(event)
+		events := instruction.Events()
+		require.NoError(t, paymentStore.UpdatePayment(ctx, instruction.ID(), expectedVersion, instruction.GetStatus(), events[len(events)-1]))
+	}
+
+	t.Run("a payment instruction submitted for processing is stuck once its last event is older than the threshold", func(t *testing.T) {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+		instruction.SubmitForProcessing()
+		require.NoError(t, paymentStore.Store(ctx, instruction))
+
+		_, found := stuckOf(t, instruction.ID(), time.Now().Add(-time.Hour))
+		assert.False(t, found)
+
+		stuck, found := stuckOf(t, instruction.ID(), time.Now().Add(time.Minute))
+		require.True(t, found)
+		assert.Equal(t, models.SubmittedForProcessing, stuck.PaymentInstruction.GetStatus())
+		assert.False(t, stuck.CanBeRechecked())
+	})
+
+	t.Run("a payment instruction accepted by the payment provider is stuck with the payment provider's payment ID", func(t *testing.T) {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+		instruction.SubmitForProcessing()
+		require.NoError(t, paymentStore.Store(ctx, instruction))
+		trackEvent(t, &instruction, models.Submitted)
+
+		stuck, found := stuckOf(t, instruction.ID(), time.Now().Add(time.Minute))
+		require.True(t, found)
+		assert.Equal(t, models.StateSubmitted, stuck.PaymentInstruction.GetStatus())
+		assert.Equal(t, models.ProviderPaymentID("bc-payment-id"), stuck.ProviderPaymentID)
+		assert.Equal(t, models.BankingReference("bc-reference"), stuck.BankingReference)
+	})
+
+	t.Run("a payment instruction with an outcome is never stuck", func(t *testing.T) {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(models.BankingCircle).Build()
+		instruction.SubmitForProcessing()
+		require.NoError(t, paymentStore.Store(ctx, instruction))
+		trackEvent(t, &instruction, models.Submitted)
+		trackEvent(t, &instruction, models.Processed)
+
+		_, found := stuckOf(t, instruction.ID(), time.Now().Add(time.Minute))
+		assert.False(t, found)
+	})
+}
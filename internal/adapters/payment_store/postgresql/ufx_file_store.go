@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	postgresTracing "github.com/saltpay/go-postgres-tracing"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	saveUfxFileQuery  = "saveUfxFile"
+	getUfxFileQuery   = "getUfxFile"
+	listUfxFilesQuery = "listUfxFiles"
+
+	listUfxFilesLimit = 200
+)
+
+var _ ports.UfxFileLedger = PostgresStore{}
+
+const ufxFileColumns = `filename, sender, s3_key, recs_count, hash_total_amount, record_count, currency_totals, held_back_currencies, state, state_reason, created_at, updated_at`
+
+// SaveUfxFile inserts the ledger entry of a UFX file, or overwrites everything but its creation date if it already exists.
+func (s PostgresStore) SaveUfxFile(ctx context.Context, file models.UfxFile) error {
+	ctx, span := postgresTracing.SpanWithContext(ctx, saveUfxFileQuery)
+	defer postgresTracing.EndSpan(span)
+
+	currencyTotals := file.CurrencyTotals
+	if currencyTotals == nil {
+		currencyTotals = []models.IncomingInstructionsSummary{}
+	}
+	currencyTotalsJSON, err := json.Marshal(currencyTotals)
+	if err != nil {
+		return err
+	}
+
+	heldBackCurrencies := file.HeldBackCurrencies
+	if heldBackCurrencies == nil {
+		heldBackCurrencies = []models.CurrencyCode{}
+	}
+	heldBackCurrenciesJSON, err := json.Marshal(heldBackCurrencies)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO ufx_files (filename, sender, s3_key, recs_count, hash_total_amount, record_count, currency_totals, held_back_currencies, state, state_reason)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				ON CONFLICT (filename) DO UPDATE SET
+					sender = EXCLUDED.sender,
+					s3_key = EXCLUDED.s3_key,
+					recs_count = EXCLUDED.recs_count,
+					hash_total_amount = EXCLUDED.hash_total_amount,
+					record_count = EXCLUDED.record_count,
+					currency_totals = EXCLUDED.currency_totals,
+					held_back_currencies = EXCLUDED.held_back_currencies,
+					state = EXCLUDED.state,
+					state_reason = EXCLUDED.state_reason,
+					updated_at = now()`
+	_, err = s.db.ExecContext(ctx, query,
+		file.Filename,
+		file.Sender,
+		file.S3Key,
+		file.CheckSum.RecsCount,
+		file.CheckSum.HashTotalAmount,
+		file.RecordCount,
+		currencyTotalsJSON,
+		heldBackCurrenciesJSON,
+		file.State,
+		file.StateReason,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to save ufx file %s, err: %w", file.Filename, err)
+	}
+
+	return nil
+}
+
+func (s PostgresStore) GetUfxFile(ctx context.Context, filename string) (models.UfxFile, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, getUfxFileQuery)
+	defer postgresTracing.EndSpan(span)
+
+	row := s.db.QueryRowContext(ctx, `SELECT `+ufxFileColumns+` FROM ufx_files WHERE filename = $1`, filename)
+	file, err := scanUfxFile(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.UfxFile{}, UfxFileMissingError{Filename: filename}
+		}
+		return models.UfxFile{}, err
+	}
+
+	return file, nil
+}
+
+// ListUfxFiles returns the most recently received UFX files, optionally only the ones in the given state.
+func (s PostgresStore) ListUfxFiles(ctx context.Context, state models.UfxFileState) ([]models.UfxFile, error) {
+	ctx, span := postgresTracing.SpanWithContext(ctx, listUfxFilesQuery)
+	defer postgresTracing.EndSpan(span)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+ufxFileColumns+` FROM ufx_files WHERE ($1 = '' OR state = $1) ORDER BY created_at DESC LIMIT $2`,
+		state,
+		listUfxFilesLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make([]models.UfxFile, 0)
+	for rows.Next() {
+		file, err := scanUfxFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUfxFile(row rowScanner) (models.UfxFile, error) {
+	var (
+		file                   models.UfxFile
+		currencyTotalsJSON     []byte
+		heldBackCurrenciesJSON []byte
+	)
+
+	err := row.Scan(
+		&file.Filename,
+		&file.Sender,
+		&file.S3Key,
+		&file.CheckSum.RecsCount,
+		&file.CheckSum.HashTotalAmount,
+		&file.RecordCount,
+		&currencyTotalsJSON,
+		&heldBackCurrenciesJSON,
+		&file.State,
+		&file.StateReason,
+		&file.CreatedAt,
+		&file.UpdatedAt,
+	)
+	if err != nil {
+		return models.UfxFile{}, err
+	}
+
+	if err := json.Unmarshal(currencyTotalsJSON, &file.CurrencyTotals); err != nil {
+		return models.UfxFile{}, err
+	}
+	if err := json.Unmarshal(heldBackCurrenciesJSON, &file.HeldBackCurrencies); err != nil {
+		return models.UfxFile{}, err
+	}
+
+	return file, nil
+}
+
+// UfxFileMissingError is returned when no UFX file with the requested name was received.
+type UfxFileMissingError struct {
+	Filename string
+}
+
+func (u UfxFileMissingError) Error() string {
+	return fmt.Sprintf("ufx file %q is not found", u.Filename)
+}
@@ -0,0 +1,76 @@
+//go:build integration
+// +build integration
+
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store"
+	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	testhelpers2 "github.com/saltpay/settlements-payments-system/internal/testhelpers"
+)
+
+func TestUfxFileLedger(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		pgString = os.Getenv("POSTGRES_DB_CONNECTION_STRING")
+	)
+	if pgString == "" {
+		t.Fatal("POSTGRES_DB_CONNECTION_STRING environment variable is not set ")
+	}
+	paymentStore, err := NewPaymentStore(
+		context.Background(),
+		pgString,
+		payment_store.NewLoggingAndMetricsPaymentObservabilityForPostgres(testdoubles.DummyMetricsClient{}),
+	)
+	require.NoError(t, err)
+
+	t.Run("a saved file can be read back and its state updated", func(t *testing.T) {
+		file := models.UfxFile{
+			Filename: testhelpers2.RandomString() + ".xml",
+			Sender:   "SAXO",
+			S3Key:    "inbound/file.xml",
+			State:    models.UfxFileReceived,
+		}
+		require.NoError(t, paymentStore.SaveUfxFile(ctx, file))
+
+		file.State = models.UfxFilePartiallyPaid
+		file.RecordCount = 2
+		file.CheckSum = models.UfxCheckSum{RecsCount: "2", HashTotalAmount: "300.00"}
+		file.CurrencyTotals = []models.IncomingInstructionsSummary{{CurrencyCode: models.EUR, Counter: 2, Amount: 300}}
+		file.HeldBackCurrencies = []models.CurrencyCode{models.EUR}
+		require.NoError(t, paymentStore.SaveUfxFile(ctx, file))
+
+		actual, err := paymentStore.GetUfxFile(ctx, file.Filename)
+		require.NoError(t, err)
+		assert.Equal(t, models.UfxFilePartiallyPaid, actual.State)
+		assert.Equal(t, file.CheckSum, actual.CheckSum)
+		assert.Equal(t, file.CurrencyTotals, actual.CurrencyTotals)
+		assert.Equal(t, file.HeldBackCurrencies, actual.HeldBackCurrencies)
+		assert.False(t, actual.CreatedAt.IsZero())
+
+		files, err := paymentStore.ListUfxFiles(ctx, models.UfxFilePartiallyPaid)
+		require.NoError(t, err)
+		assert.Contains(t, filenames(files), file.Filename)
+	})
+
+	t.Run("a file that was never received", func(t *testing.T) {
+		_, err := paymentStore.GetUfxFile(ctx, testhelpers2.RandomString())
+		assert.ErrorAs(t, err, &UfxFileMissingError{})
+	})
+}
+
+func filenames(files []models.UfxFile) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Filename)
+	}
+	return names
+}
@@ -0,0 +1,49 @@
+package pending_funding
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/sync"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const defaultRecheckInterval = 15 * time.Minute
+
+// RecheckScheduler periodically rechecks the funds of every batch held back for lack of funding,
+// so they get paid as soon as the source account is topped up instead of waiting for a manual replay.
+type RecheckScheduler struct {
+	managePendingFunding ports.ManagePendingFunding
+	interval             time.Duration
+	shouldRun            *sync.AtomicBool
+}
+
+func NewRecheckScheduler(managePendingFunding ports.ManagePendingFunding, interval time.Duration) RecheckScheduler {
+	if interval <= 0 {
+		interval = defaultRecheckInterval
+	}
+
+	shouldRun := sync.New()
+	shouldRun.Set()
+	return RecheckScheduler{
+		managePendingFunding: managePendingFunding,
+		interval:             interval,
+		shouldRun:            shouldRun,
+	}
+}
+
+func (r *RecheckScheduler) Run(ctx context.Context) {
+	for r.shouldRun.IsSet() {
+		r.managePendingFunding.RecheckAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+func (r *RecheckScheduler) Stop() {
+	r.shouldRun.UnSet()
+}
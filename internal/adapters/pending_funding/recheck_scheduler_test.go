@@ -0,0 +1,70 @@
+//go:build unit
+// +build unit
+
+package pending_funding_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/pending_funding"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestRecheckScheduler(t *testing.T) {
+	t.Run("rechecks pending fundings on every interval until stopped", func(t *testing.T) {
+		rechecked := make(chan struct{})
+		spyManagePendingFunding := &mocks.ManagePendingFundingMock{
+			RecheckAllFunc: func(ctx context.Context) {
+				rechecked <- struct{}{}
+			},
+		}
+		scheduler := pending_funding.NewRecheckScheduler(spyManagePendingFunding, time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			scheduler.Run(context.Background())
+			close(done)
+		}()
+
+		<-rechecked
+		<-rechecked
+		scheduler.Stop()
+		go func() {
+			for range rechecked {
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+		assert.GreaterOrEqual(t, len(spyManagePendingFunding.RecheckAllCalls()), 2)
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stubManagePendingFunding := &mocks.ManagePendingFundingMock{
+			RecheckAllFunc: func(ctx context.Context) {
+				cancel()
+			},
+		}
+		scheduler := pending_funding.NewRecheckScheduler(stubManagePendingFunding, time.Hour)
+
+		done := make(chan struct{})
+		go func() {
+			scheduler.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+	})
+}
@@ -17,6 +17,7 @@ type MetricsClient struct {
 	globalTags []string
 
 	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
 	histograms map[string]*prometheus.HistogramVec
 }
 
@@ -41,6 +42,18 @@ func New(ctx context.Context, tags []string) (MetricsClient, error) {
 				Name: "app_http_client_request_retry",
 				Help: "Counter for the number retries",
 			}, []string{"callee", "operation"}),
+			"app_http_client_auth_token_refresh": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_http_client_auth_token_refresh",
+				Help: "Counter for the number of times an auth token was refreshed, or failed to be",
+			}, []string{"callee", "result"}),
+			"app_http_client_circuit_transition": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_http_client_circuit_transition",
+				Help: "Counter for the number of times a circuit breaker changed to a state",
+			}, []string{"callee", "state"}),
+			"app_http_client_circuit_rejected": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_http_client_circuit_rejected",
+				Help: "Counter for the number of requests turned down while a circuit breaker isn't closed",
+			}, []string{"callee", "operation"}),
 			"app_settlements_provider_check_payment_status": promauto.NewCounterVec(prometheus.CounterOpts{
 				Name: "app_settlements_provider_check_payment_status",
 				Help: "Counter for the number of requests done to the payment status endpoint",
@@ -53,6 +66,14 @@ func New(ctx context.Context, tags []string) (MetricsClient, error) {
 				Name: "app_settlements_provider_request_payment",
 				Help: "Counter number of payments requested",
 			}, []string{"result", "payment_provider", "currency"}),
+			"app_settlements_provider_bulk_payment_result_missing": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_settlements_provider_bulk_payment_result_missing",
+				Help: "Counter for the number of payments a bulk payment response left out",
+			}, []string{"payment_provider", "currency"}),
+			"app_settlements_provider_payment_status_notification": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_settlements_provider_payment_status_notification",
+				Help: "Counter for the number of payment status notifications received from the payment provider",
+			}, []string{"result", "payment_provider", "status"}),
 			"app_queue_messages_received": promauto.NewCounterVec(prometheus.CounterOpts{
 				Name: "app_queue_messages_received",
 				Help: "Counter for the number of message received",
@@ -101,12 +122,71 @@ func New(ctx context.Context, tags []string) (MetricsClient, error) {
 				Name: "app_payment_instruction_update",
 				Help: "Counter for when we update the state of a payment",
 			}, []string{"currency"}),
+			"app_ufx_checksum_mismatch": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_ufx_checksum_mismatch",
+				Help: "Counter for the number of UFX files quarantined because their trailer checksum doesn't match their records",
+			}, []string{"source"}),
+			"app_ufx_file_redelivery_rejected": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_ufx_file_redelivery_rejected",
+				Help: "Counter for the number of notifications of already paid UFX files that were dropped",
+			}, []string{"source"}),
+			"app_settlements_pending_funding_held_back": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_settlements_pending_funding_held_back",
+				Help: "Counter for the number of currency batches held back until their source account is funded",
+			}, []string{"currency"}),
+			"app_settlements_pending_funding_released": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_settlements_pending_funding_released",
+				Help: "Counter for the number of held back currency batches released for payment",
+			}, []string{"currency"}),
+			"app_payment_instruction_version_conflict": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_payment_instruction_version_conflict",
+				Help: "Counter for the number of updates reapplied because the payment instruction was updated concurrently",
+			}, []string{"listener"}),
+			"app_payment_instruction_transition_rejected": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_payment_instruction_transition_rejected",
+				Help: "Counter for the number of payment instruction status changes rejected by the status transition table",
+			}, []string{"from", "to"}),
+			"app_payment_outbox_dispatched": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_payment_outbox_dispatched",
+				Help: "Counter for the number of payment instructions the outbox relay handed to their payment provider",
+			}, []string{"payment_provider"}),
+			"app_payment_outbox_dispatch_failed": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_payment_outbox_dispatch_failed",
+				Help: "Counter for the number of failed attempts of the outbox relay to hand a payment instruction to its payment provider",
+			}, []string{"payment_provider"}),
+			"app_payment_instruction_stuck": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_payment_instruction_stuck",
+				Help: "Counter for the number of times a sweep found a payment instruction stuck waiting for its payment provider",
+			}, []string{"payment_provider", "reason"}),
+			"app_payment_instruction_stuck_resolved": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_payment_instruction_stuck_resolved",
+				Help: "Counter for the number of stuck payment instructions whose outcome a sweep got from their payment provider",
+			}, []string{"payment_provider", "event_type"}),
+			"app_payment_provider_rejection": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_payment_provider_rejection",
+				Help: "Counter for the number of rejections ingested from the rejection reports of the payment providers, by how they were resolved",
+			}, []string{"payment_provider", "outcome"}),
+			"app_settlements_payment_batch_items": promauto.NewCounterVec(prometheus.CounterOpts{
+				Name: "app_settlements_payment_batch_items",
+				Help: "Counter for the number of payment batch items by the outcome of their payment",
+			}, []string{"currency", "status"}),
+		},
+		gauges: map[string]*prometheus.GaugeVec{
+			"app_http_client_circuit_state": promauto.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "app_http_client_circuit_state",
+				Help: "Gauge for the state of a circuit breaker, 1 for the state it is in and 0 for the others",
+			}, []string{"callee", "state"}),
 		},
 		histograms: map[string]*prometheus.HistogramVec{
 			"app_http_client_resp_time_ms": promauto.NewHistogramVec(prometheus.HistogramOpts{
 				Name: "app_http_client_resp_time_ms",
 				Help: "HTTP request duration in milliseconds",
 			}, []string{"callee", "operation", "status_code"}),
+			"app_http_client_auth_token_age_sec": promauto.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "app_http_client_auth_token_age_sec",
+				Help:    "Age in seconds of the auth tokens requests are sent with",
+				Buckets: []float64{60, 300, 600, 1200, 1800, 2700, 3600},
+			}, []string{"callee"}),
 			"app_payment_processing_time_sec": promauto.NewHistogramVec(prometheus.HistogramOpts{
 				Name: "app_payment_processing_time_sec",
 				Help: "Payment processing duration in seconds",
@@ -123,6 +203,15 @@ func New(ctx context.Context, tags []string) (MetricsClient, error) {
 				Name: "app_payment_instruction_store_duration",
 				Help: "Histogram for the duration of loading from the datastore",
 			}, []string{"callee", "operation", "result"}),
+			"app_payment_outbox_dispatch_delay_sec": promauto.NewHistogramVec(prometheus.HistogramOpts{
+				Name: "app_payment_outbox_dispatch_delay_sec",
+				Help: "Time in seconds between storing a payment instruction and handing it to its payment provider",
+			}, []string{"payment_provider"}),
+			"app_settlements_provider_bulk_payment_size": promauto.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "app_settlements_provider_bulk_payment_size",
+				Help:    "Number of payments requested in a bulk payment",
+				Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+			}, []string{"payment_provider"}),
 		},
 	}
 
@@ -155,6 +244,29 @@ func (m *MetricsClient) Count(ctx context.Context, name string, value int64, tag
 	)
 }
 
+func (m *MetricsClient) Gauge(ctx context.Context, name string, value float64, tags []string) {
+	if gaugeVec, ok := m.gauges[name]; ok {
+		g, err := gaugeVec.GetMetricWithLabelValues(tags...)
+		if err != nil {
+			zapctx.Info(ctx, "metric not found",
+				zap.String("type", "gauge"),
+				zap.String("metric", name),
+				zap.Strings("tags", tags),
+				zap.Error(err),
+			)
+			return
+		}
+		g.Set(value)
+
+		return
+	}
+
+	zapctx.Info(ctx, "metric not found",
+		zap.String("type", "gauge"),
+		zap.String("metric", name),
+	)
+}
+
 func (m *MetricsClient) Histogram(ctx context.Context, name string, value float64, tags []string) {
 	if histogramVec, ok := m.histograms[name]; ok {
 		h, err := histogramVec.GetMetricWithLabelValues(tags...)
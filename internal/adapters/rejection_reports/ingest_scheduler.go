@@ -0,0 +1,53 @@
+package rejection_reports
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/sync"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const defaultIngestInterval = 24 * time.Hour
+
+// IngestScheduler periodically ingests the rejection reports of the day before, once the payment providers
+// have listed all of its rejections.
+type IngestScheduler struct {
+	ingestRejectionReports ports.IngestRejectionReports
+	interval               time.Duration
+	shouldRun              *sync.AtomicBool
+	now                    func() time.Time
+}
+
+func NewIngestScheduler(ingestRejectionReports ports.IngestRejectionReports, interval time.Duration) IngestScheduler {
+	if interval <= 0 {
+		interval = defaultIngestInterval
+	}
+
+	shouldRun := sync.New()
+	shouldRun.Set()
+	return IngestScheduler{
+		ingestRejectionReports: ingestRejectionReports,
+		interval:               interval,
+		shouldRun:              shouldRun,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (s *IngestScheduler) Run(ctx context.Context) {
+	for s.shouldRun.IsSet() {
+		s.ingestRejectionReports.Ingest(ctx, s.now().AddDate(0, 0, -1))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func (s *IngestScheduler) Stop() {
+	s.shouldRun.UnSet()
+}
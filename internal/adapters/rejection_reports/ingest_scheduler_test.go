@@ -0,0 +1,44 @@
+//go:build unit
+// +build unit
+
+package rejection_reports_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/rejection_reports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestIngestScheduler(t *testing.T) {
+	t.Run("ingests the reports of the day before on every interval until the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		spyIngestRejectionReports := &mocks.IngestRejectionReportsMock{}
+		spyIngestRejectionReports.IngestFunc = func(ctx context.Context, reportDate time.Time) {
+			if len(spyIngestRejectionReports.IngestCalls()) == 2 {
+				cancel()
+			}
+		}
+		scheduler := rejection_reports.NewIngestScheduler(spyIngestRejectionReports, time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			scheduler.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+		require.Len(t, spyIngestRejectionReports.IngestCalls(), 2)
+		yesterday := time.Now().UTC().AddDate(0, 0, -1)
+		assert.WithinDuration(t, yesterday, spyIngestRejectionReports.IngestCalls()[0].ReportDate, time.Minute)
+	})
+}
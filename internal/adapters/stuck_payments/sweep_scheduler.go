@@ -0,0 +1,49 @@
+package stuck_payments
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/sync"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const defaultSweepInterval = 10 * time.Minute
+
+// SweepScheduler periodically sweeps the payment instructions stuck waiting for their payment provider,
+// so a lost outcome gets tracked without someone having to notice it first.
+type SweepScheduler struct {
+	sweepStuckPayments ports.SweepStuckPayments
+	interval           time.Duration
+	shouldRun          *sync.AtomicBool
+}
+
+func NewSweepScheduler(sweepStuckPayments ports.SweepStuckPayments, interval time.Duration) SweepScheduler {
+	if interval <= 0 {
+		interval = defaultSweepInterval
+	}
+
+	shouldRun := sync.New()
+	shouldRun.Set()
+	return SweepScheduler{
+		sweepStuckPayments: sweepStuckPayments,
+		interval:           interval,
+		shouldRun:          shouldRun,
+	}
+}
+
+func (s *SweepScheduler) Run(ctx context.Context) {
+	for s.shouldRun.IsSet() {
+		s.sweepStuckPayments.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func (s *SweepScheduler) Stop() {
+	s.shouldRun.UnSet()
+}
@@ -0,0 +1,41 @@
+//go:build unit
+// +build unit
+
+package stuck_payments_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saltpay/settlements-payments-system/internal/adapters/stuck_payments"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+)
+
+func TestSweepScheduler(t *testing.T) {
+	t.Run("sweeps on every interval until the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		spySweepStuckPayments := &mocks.SweepStuckPaymentsMock{}
+		spySweepStuckPayments.SweepFunc = func(ctx context.Context) {
+			if len(spySweepStuckPayments.SweepCalls()) == 2 {
+				cancel()
+			}
+		}
+		scheduler := stuck_payments.NewSweepScheduler(spySweepStuckPayments, time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			scheduler.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+		assert.Len(t, spySweepStuckPayments.SweepCalls(), 2)
+	})
+}
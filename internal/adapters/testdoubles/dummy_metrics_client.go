@@ -9,3 +9,6 @@ func (d DummyMetricsClient) Histogram(context.Context, string, float64, []string
 
 func (d DummyMetricsClient) Count(context.Context, string, int64, []string) {
 }
+
+func (d DummyMetricsClient) Gauge(context.Context, string, float64, []string) {
+}
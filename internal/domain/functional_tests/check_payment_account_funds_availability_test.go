@@ -11,9 +11,12 @@ import (
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	bcmodels "github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/models"
 	"github.com/saltpay/settlements-payments-system/banking_circle_payment_service/domain/ports/mocks"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_provider"
 	"github.com/saltpay/settlements-payments-system/internal/adapters/testdoubles"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	mocks2 "github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
 	"github.com/saltpay/settlements-payments-system/internal/domain/use_cases"
 )
 
@@ -46,7 +49,7 @@ func TestCheckPaymentAccountFundsAvailability_Execute(t *testing.T) {
 		for _, tc := range cases {
 			t.Run(tc.title, func(t *testing.T) {
 				stub := &mocks.RetrieveBankingCircleAccountFundsMock{
-					ExecuteFunc: func(currency string, highRisk bool) (float64, float64, error) {
+					ExecuteFunc: func(currency string, highRisk bool, amount float64) (float64, float64, error) {
 						return tc.available, tc.loan, nil
 					},
 				}
@@ -87,7 +90,7 @@ func TestCheckPaymentAccountFundsAvailability_Execute(t *testing.T) {
 		for _, tc := range cases {
 			t.Run(tc.title, func(t *testing.T) {
 				stub := &mocks.RetrieveBankingCircleAccountFundsMock{
-					ExecuteFunc: func(currency string, highRisk bool) (float64, float64, error) {
+					ExecuteFunc: func(currency string, highRisk bool, amount float64) (float64, float64, error) {
 						return tc.available, tc.loan, nil
 					},
 				}
@@ -101,4 +104,87 @@ func TestCheckPaymentAccountFundsAvailability_Execute(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Given funds are reserved for the account, then we take them off the balance", func(t *testing.T) {
+		ctx := context.Background()
+		is := is.New(t)
+		stub := &mocks.RetrieveBankingCircleAccountFundsMock{
+			ExecuteFunc: func(currency string, highRisk bool, amount float64) (float64, float64, error) {
+				return 1000, 200, nil
+			},
+		}
+		ledger := &mocks2.LiquidityLedgerMock{ReservedFunc: func(ctx context.Context) ([]models.ReservedFunds, error) {
+			return []models.ReservedFunds{
+				{Currency: models.EUR, HighRisk: false, Amount: 700},
+				{Currency: models.EUR, HighRisk: true, Amount: 5000},
+			}, nil
+		}}
+
+		useCase := use_cases.NewCheckPaymentAccountFundsAvailability(dummyMetrics, payment_provider.NewPaymentProvider(stub), message.NewPrinter(language.English))
+		useCase.SubtractReservations(ledger)
+
+		fundsAvailability, err := useCase.Execute(ctx, "EUR", 500, false)
+		is.NoErr(err)
+		is.True(fundsAvailability)
+
+		fundsAvailability, err = useCase.Execute(ctx, "EUR", 501, false)
+		is.NoErr(err)
+		is.True(!fundsAvailability)
+	})
+
+	t.Run("Given a ledger, then the funds are held against the balance and the intra day loan", func(t *testing.T) {
+		ctx := context.Background()
+		is := is.New(t)
+		stub := &mocks.RetrieveBankingCircleAccountFundsMock{
+			AccountsFundsFunc: func(currency string, highRisk bool) ([]bcmodels.AccountFunds, error) {
+				return []bcmodels.AccountFunds{{Iban: "EUR-1", Balance: 1000, MaxIntraDayLoan: 200}}, nil
+			},
+		}
+		ledger := &mocks2.LiquidityLedgerMock{HoldFunc: func(ctx context.Context, hold models.FundsHold, funds float64) (bool, error) {
+			return hold.Amount <= funds-700, nil
+		}}
+
+		useCase := use_cases.NewCheckPaymentAccountFundsAvailability(dummyMetrics, payment_provider.NewPaymentProvider(stub), message.NewPrinter(language.English))
+		useCase.SubtractReservations(ledger)
+
+		hold, held, err := useCase.Hold(ctx, "hold-1", "EUR", 500, false)
+		is.NoErr(err)
+		is.True(held)
+		is.Equal(hold, models.FundsHold{ID: "hold-1", Currency: models.EUR, Amount: 500, Account: "EUR-1"})
+
+		_, held, err = useCase.Hold(ctx, "hold-2", "EUR", 501, false)
+		is.NoErr(err)
+		is.True(!held)
+
+		is.Equal(len(ledger.HoldCalls()), 2)
+		is.Equal(ledger.HoldCalls()[0].Hold, models.FundsHold{ID: "hold-1", Currency: models.EUR, Amount: 500, Account: "EUR-1"})
+		is.Equal(ledger.HoldCalls()[0].Funds, 1200.0)
+	})
+
+	t.Run("Given an account without enough funds, then the funds are held in the next account", func(t *testing.T) {
+		ctx := context.Background()
+		is := is.New(t)
+		stub := &mocks.RetrieveBankingCircleAccountFundsMock{
+			AccountsFundsFunc: func(currency string, highRisk bool) ([]bcmodels.AccountFunds, error) {
+				return []bcmodels.AccountFunds{
+					{Iban: "EUR-1", Balance: 1000},
+					{Iban: "EUR-2", Balance: 300},
+				}, nil
+			},
+		}
+		reservedByAccount := map[string]float64{"EUR-1": 900}
+		ledger := &mocks2.LiquidityLedgerMock{HoldFunc: func(ctx context.Context, hold models.FundsHold, funds float64) (bool, error) {
+			return hold.Amount <= funds-reservedByAccount[hold.Account], nil
+		}}
+
+		useCase := use_cases.NewCheckPaymentAccountFundsAvailability(dummyMetrics, payment_provider.NewPaymentProvider(stub), message.NewPrinter(language.English))
+		useCase.SubtractReservations(ledger)
+
+		hold, held, err := useCase.Hold(ctx, "hold-1", "EUR", 200, false)
+		is.NoErr(err)
+		is.True(held)
+		is.Equal(hold.Account, "EUR-2")
+		is.Equal(len(ledger.HoldCalls()), 2)
+		is.Equal(ledger.HoldCalls()[1].Funds, 300.0)
+	})
 }
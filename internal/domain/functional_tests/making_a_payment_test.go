@@ -22,7 +22,7 @@ import (
 /*
 	Given a valid payment instruction
 	When we attempt a payment with it
-	Then the payment instruction must be submitted to a Payment Provider
+	Then the payment instruction must be stored for dispatch to a Payment Provider
 	And the submitted payment instruction must have the "created" and "submitted to payment provider" events
 */
 
@@ -30,14 +30,11 @@ func TestMakePayment_Execute(t *testing.T) {
 	t.Run("With a valid incoming instruction that should be handled by Banking Circle", func(t *testing.T) {
 		t.Run("creates a payment instruction and submits it to the Banking Circle payment provider with the relevant events", func(t *testing.T) {
 			var (
-				ctx                          = context.Background()
-				metricCountCounter           = 0
-				metricTotalCounter           = 0
-				incomingInstruction          = testhelpers.NewIncomingInstructionBuilder().WithPaymentAmount("50").Build()
-				paymentProviderRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(context.Context, models.PaymentInstruction) error {
-					return nil
-				}}
-				mockStore = &mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+				ctx                 = context.Background()
+				metricCountCounter  = 0
+				metricTotalCounter  = 0
+				incomingInstruction = testhelpers.NewIncomingInstructionBuilder().WithPaymentAmount("50").Build()
+				mockStore           = &mocks.StorePaymentInstructionToRepoMock{StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				}}
 
@@ -56,7 +53,6 @@ func TestMakePayment_Execute(t *testing.T) {
 
 			makePaymentUseCase := use_cases.NewMakePayment(
 				metricsClient,
-				paymentProviderRequestSender,
 				mockStore,
 				validation.IncomingInstructionValidator{},
 			)
@@ -65,7 +61,7 @@ func TestMakePayment_Execute(t *testing.T) {
 			require.NoError(t, err)
 			assert.NotEmpty(t, id)
 
-			paymentInstFromCall := paymentProviderRequestSender.SendPaymentInstructionCalls()[0].PaymentInstruction
+			paymentInstFromCall := mockStore.StoreForDispatchCalls()[0].Instruction
 			assert.Equal(t, paymentInstFromCall.GetStatus(), models.SubmittedForProcessing)
 			assert.Equal(t, paymentInstFromCall.Version(), 2)
 			assert.Equal(t, len(paymentInstFromCall.Events()), 2)
@@ -81,14 +77,11 @@ func TestMakePayment_Execute(t *testing.T) {
 
 		t.Run("when an account number is in the incorrect format, it increments the metric", func(t *testing.T) {
 			var (
-				ctx                          = context.Background()
-				metricCountCounter           = 0
-				incorrectAccountNumber       = "de 111 11111 gb 11111111"
-				incomingInstruction          = testhelpers.NewIncomingInstructionBuilder().WithMerchantAccountNumber(incorrectAccountNumber).Build()
-				paymentProviderRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(context.Context, models.PaymentInstruction) error {
-					return nil
-				}}
-				mockStore = &mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+				ctx                    = context.Background()
+				metricCountCounter     = 0
+				incorrectAccountNumber = "de 111 11111 gb 11111111"
+				incomingInstruction    = testhelpers.NewIncomingInstructionBuilder().WithMerchantAccountNumber(incorrectAccountNumber).Build()
+				mockStore              = &mocks.StorePaymentInstructionToRepoMock{StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				}}
 
@@ -104,7 +97,6 @@ func TestMakePayment_Execute(t *testing.T) {
 
 			makePaymentUseCase := use_cases.NewMakePayment(
 				metricsClient,
-				paymentProviderRequestSender,
 				mockStore,
 				validation.IncomingInstructionValidator{},
 			)
@@ -113,7 +105,7 @@ func TestMakePayment_Execute(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotEmpty(t, id)
 
-			paymentInstFromCall := paymentProviderRequestSender.SendPaymentInstructionCalls()[0].PaymentInstruction
+			paymentInstFromCall := mockStore.StoreForDispatchCalls()[0].Instruction
 			assert.Equal(t, paymentInstFromCall.GetStatus(), models.SubmittedForProcessing)
 
 			assert.Equal(t, metricCountCounter, 1)
@@ -138,7 +130,7 @@ func TestMakePayment_Execute(t *testing.T) {
 							WithCurrency(iskCurrency).
 							WithPaymentAmount("50").
 							Build()
-				paymentProviderRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(context.Context, models.PaymentInstruction) error {
+				mockStore = &mocks.StorePaymentInstructionToRepoMock{StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				}}
 				metricsClient = &mocks.MetricsClientMock{
@@ -156,10 +148,7 @@ func TestMakePayment_Execute(t *testing.T) {
 
 			makePaymentUseCase := use_cases.NewMakePayment(
 				metricsClient,
-				paymentProviderRequestSender,
-				&mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
-					return nil
-				}},
+				mockStore,
 				validation.IncomingInstructionValidator{},
 			)
 
@@ -167,7 +156,7 @@ func TestMakePayment_Execute(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotEmpty(t, len(id) > 0)
 
-			paymentInstFromCall := paymentProviderRequestSender.SendPaymentInstructionCalls()[0].PaymentInstruction
+			paymentInstFromCall := mockStore.StoreForDispatchCalls()[0].Instruction
 			assert.Equal(t, paymentInstFromCall.GetStatus(), models.SubmittedForProcessing)
 			assert.Equal(t, paymentInstFromCall.Version(), 2)
 			assert.Equal(t, len(paymentInstFromCall.Events()), 2)
@@ -198,8 +187,7 @@ func TestMakePayment_Execute(t *testing.T) {
 							WithCurrency(nonIskCurrency).
 							WithPaymentAmount("50").
 							Build()
-
-				paymentProviderRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(context.Context, models.PaymentInstruction) error {
+				mockStore = &mocks.StorePaymentInstructionToRepoMock{StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				}}
 				metricsClient = &mocks.MetricsClientMock{
@@ -217,10 +205,7 @@ func TestMakePayment_Execute(t *testing.T) {
 
 			makePaymentUseCase := use_cases.NewMakePayment(
 				metricsClient,
-				paymentProviderRequestSender,
-				&mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
-					return nil
-				}},
+				mockStore,
 				validation.IncomingInstructionValidator{},
 			)
 
@@ -228,7 +213,7 @@ func TestMakePayment_Execute(t *testing.T) {
 			require.NoError(t, err)
 			assert.NotEmpty(t, id)
 
-			paymentInstFromCall := paymentProviderRequestSender.SendPaymentInstructionCalls()[0].PaymentInstruction
+			paymentInstFromCall := mockStore.StoreForDispatchCalls()[0].Instruction
 			assert.Equal(t, paymentInstFromCall.GetStatus(), models.SubmittedForProcessing)
 			assert.Equal(t, paymentInstFromCall.Version(), 2)
 
@@ -247,11 +232,8 @@ func TestMakePayment_Execute(t *testing.T) {
 	t.Run("With a payment instruction that is invalid", func(t *testing.T) {
 		t.Run("it does not submit the instruction to the Banking Circle payment provider", func(t *testing.T) {
 			var (
-				ctx                               = context.Background()
-				incomingInstruction               = testhelpers.NewIncomingInstructionBuilder().WithMerchantAccountNumber("").Build()
-				bankingCirclePaymentRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(context.Context, models.PaymentInstruction) error {
-					return nil
-				}}
+				ctx                        = context.Background()
+				incomingInstruction        = testhelpers.NewIncomingInstructionBuilder().WithMerchantAccountNumber("").Build()
 				mockPaymentInstructionRepo = &mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				}}
@@ -259,14 +241,13 @@ func TestMakePayment_Execute(t *testing.T) {
 
 			makePaymentUseCase := use_cases.NewMakePayment(
 				&mocks.MetricsClientMock{},
-				bankingCirclePaymentRequestSender,
 				mockPaymentInstructionRepo,
 				validation.IncomingInstructionValidator{},
 			)
 
 			id, err := makePaymentUseCase.Execute(ctx, incomingInstruction)
-			assert.Empty(t, id)
-			assert.Equal(t, len(bankingCirclePaymentRequestSender.SendPaymentInstructionCalls()), 0)
+			assert.Equal(t, mockPaymentInstructionRepo.StoreCalls()[0].Instruction.ID(), id, "the rejected payment instruction is stored")
+			assert.Equal(t, len(mockPaymentInstructionRepo.StoreForDispatchCalls()), 0)
 
 			validationResult, isValidationResult := err.(validation.IncomingInstructionValidationResult)
 			assert.True(t, isValidationResult)
@@ -285,16 +266,15 @@ func TestMakePayment_Execute(t *testing.T) {
 
 			makePaymentUseCase := use_cases.NewMakePayment(
 				&mocks.MetricsClientMock{},
-				&mocks.PaymentProviderRequestSenderMock{},
 				mockPaymentInstructionRepo,
 				validation.IncomingInstructionValidator{},
 			)
 
 			id, err := makePaymentUseCase.Execute(ctx, invalidIncomingInstruction)
-			assert.Empty(t, id)
 
 			instruction := mockPaymentInstructionRepo.StoreCalls()[0].Instruction
 			assert.Equal(t, len(mockPaymentInstructionRepo.StoreCalls()), 1) // payment is stored
+			assert.Equal(t, instruction.ID(), id)
 
 			assert.True(t, err != nil)
 			assert.Equal(t, instruction.GetStatus(), models.Rejected)
@@ -319,16 +299,15 @@ func TestMakePayment_Execute(t *testing.T) {
 			ctx                 = context.Background()
 			incomingInstruction = testhelpers.NewIncomingInstructionBuilder().Build()
 			errorMessage        = "unable to store payment"
-			mockStore           = &mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+			mockStore           = &mocks.StorePaymentInstructionToRepoMock{StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 				return fmt.Errorf(errorMessage)
 			}}
 			mockMetricsClient = &mocks.MetricsClientMock{
 				CountFunc:     nil,
 				HistogramFunc: nil,
 			}
-			mockPaymentProviderRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: nil}
 		)
-		makePayment := use_cases.NewMakePayment(mockMetricsClient, mockPaymentProviderRequestSender, mockStore, validation.IncomingInstructionValidator{})
+		makePayment := use_cases.NewMakePayment(mockMetricsClient, mockStore, validation.IncomingInstructionValidator{})
 
 		_, err := makePayment.Execute(ctx, incomingInstruction)
 		assert.EqualError(t, err, errorMessage)
@@ -340,16 +319,14 @@ func TestMakePayment_Execute(t *testing.T) {
 			incomingInstructionOne = testhelpers.NewIncomingInstructionBuilder().Build()
 			incomingInstructionTwo = testhelpers.NewIncomingInstructionBuilder().Build()
 			errMessage             = "duplicate"
-			mockStoreOne           = &mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+			mockStoreOne           = &mocks.StorePaymentInstructionToRepoMock{StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 				return nil
 			}}
-			counter      = 0
 			mockStoreTwo = &mocks.StorePaymentInstructionToRepoMock{
+				StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+					return postgresql.ErrDuplicate
+				},
 				StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
-					counter++
-					if counter == 1 {
-						return postgresql.ErrDuplicate
-					}
 					return nil
 				},
 			}
@@ -359,16 +336,13 @@ func TestMakePayment_Execute(t *testing.T) {
 				HistogramFunc: func(ctx context.Context, name string, value float64, tags []string) {
 				},
 			}
-			mockPaymentProviderRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
-				return nil
-			}}
 		)
 
-		makePaymentOne := use_cases.NewMakePayment(mockMetricsClient, mockPaymentProviderRequestSender, mockStoreOne, validation.IncomingInstructionValidator{})
+		makePaymentOne := use_cases.NewMakePayment(mockMetricsClient, mockStoreOne, validation.IncomingInstructionValidator{})
 		_, err := makePaymentOne.Execute(ctx, incomingInstructionOne)
 		require.NoError(t, err)
 
-		makePaymentTwo := use_cases.NewMakePayment(mockMetricsClient, mockPaymentProviderRequestSender, mockStoreTwo, validation.IncomingInstructionValidator{})
+		makePaymentTwo := use_cases.NewMakePayment(mockMetricsClient, mockStoreTwo, validation.IncomingInstructionValidator{})
 		_, err = makePaymentTwo.Execute(ctx, incomingInstructionTwo)
 		assert.EqualError(t, err, errMessage)
 	})
@@ -383,22 +357,161 @@ func TestMakePayment_Execute(t *testing.T) {
 				HistogramFunc: func(ctx context.Context, name string, value float64, tags []string) {
 				},
 			}
-			mockPaymentProviderRequestSender = &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
-				return nil
-			}}
-			mockPaymentStore = &mocks.StorePaymentInstructionToRepoMock{StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
-				return postgresql.ErrDuplicate
-			}}
+			mockPaymentStore = &mocks.StorePaymentInstructionToRepoMock{
+				StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+					return postgresql.ErrDuplicate
+				},
+				StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+					return postgresql.ErrDuplicate
+				},
+			}
 		)
 
-		makePayment := use_cases.NewMakePayment(mockMetricsClient, mockPaymentProviderRequestSender, mockPaymentStore, validation.IncomingInstructionValidator{})
+		makePayment := use_cases.NewMakePayment(mockMetricsClient, mockPaymentStore, validation.IncomingInstructionValidator{})
 		_, err := makePayment.Execute(ctx, incomingInstruction)
 		require.Error(t, err)
 
-		assert.Len(t, mockPaymentStore.StoreCalls(), 2)
-		assert.Equal(t, models.Failed, mockPaymentStore.StoreCalls()[1].Instruction.GetStatus())
-		failedPaymentEvents := mockPaymentStore.StoreCalls()[1].Instruction.Events()
+		// the failed duplicate is stored without an outbox entry, so it is never dispatched
+		assert.Len(t, mockPaymentStore.StoreForDispatchCalls(), 1)
+		assert.Len(t, mockPaymentStore.StoreCalls(), 1)
+		assert.Equal(t, models.Failed, mockPaymentStore.StoreCalls()[0].Instruction.GetStatus())
+		failedPaymentEvents := mockPaymentStore.StoreCalls()[0].Instruction.Events()
 		assert.NotEmpty(t, failedPaymentEvents, "payment events should not be empty")
 		assert.Equal(t, models.DomainProcessingFailed, failedPaymentEvents[len(failedPaymentEvents)-1].Type)
 	})
+
+	t.Run("With an idempotency key", func(t *testing.T) {
+		var (
+			mockMetricsClient = &mocks.MetricsClientMock{
+				CountFunc:     func(ctx context.Context, name string, value int64, tags []string) {},
+				HistogramFunc: func(ctx context.Context, name string, value float64, tags []string) {},
+			}
+			incomingInstruction = testhelpers.NewIncomingInstructionBuilder().WithPaymentAmount("50").Build()
+			withKey             = func(key string, amount string) models.IncomingInstruction {
+				keyed := incomingInstruction
+				keyed.IdempotencyKey = key
+				keyed.Payment.Amount = amount
+				return keyed
+			}
+		)
+
+		// newKeyedStore stores payment instructions by their idempotency key, as the payment store does
+		newKeyedStore := func() *mocks.StorePaymentInstructionToRepoMock {
+			stored := make(map[string]models.PaymentInstruction)
+			store := func(ctx context.Context, instruction models.PaymentInstruction) error {
+				if _, found := stored[instruction.IdempotencyKey().Key]; found {
+					return postgresql.ErrDuplicateIdempotencyKey
+				}
+				stored[instruction.IdempotencyKey().Key] = instruction
+				return nil
+			}
+			return &mocks.StorePaymentInstructionToRepoMock{
+				StoreFunc:            store,
+				StoreForDispatchFunc: store,
+				GetFromIdempotencyKeyFunc: func(ctx context.Context, key string) (models.PaymentInstruction, error) {
+					instruction, found := stored[key]
+					if !found {
+						return models.PaymentInstruction{}, postgresql.PaymentInstructionMissingError{IdempotencyKey: key}
+					}
+					return instruction, nil
+				},
+			}
+		}
+
+		t.Run("a retry of the request returns the original payment instruction without making the payment again", func(t *testing.T) {
+			var (
+				ctx         = context.Background()
+				mockStore   = newKeyedStore()
+				makePayment = use_cases.NewMakePayment(mockMetricsClient, mockStore, validation.IncomingInstructionValidator{})
+			)
+
+			id, err := makePayment.Execute(ctx, withKey("some-key", "50"))
+			require.NoError(t, err)
+
+			retriedID, err := makePayment.Execute(ctx, withKey("some-key", "50"))
+			require.NoError(t, err)
+
+			assert.Equal(t, id, retriedID)
+			assert.Len(t, mockStore.StoreForDispatchCalls(), 1)
+			assert.Equal(t, "some-key", mockStore.StoreForDispatchCalls()[0].Instruction.IdempotencyKey().Key)
+		})
+
+		t.Run("a different request with the same key is a conflict", func(t *testing.T) {
+			var (
+				ctx         = context.Background()
+				mockStore   = newKeyedStore()
+				makePayment = use_cases.NewMakePayment(mockMetricsClient, mockStore, validation.IncomingInstructionValidator{})
+			)
+
+			id, err := makePayment.Execute(ctx, withKey("some-key", "50"))
+			require.NoError(t, err)
+
+			_, err = makePayment.Execute(ctx, withKey("some-key", "60"))
+
+			assert.Equal(t, models.IdempotencyKeyConflictError{Key: "some-key", ID: id}, err)
+			assert.Len(t, mockStore.StoreForDispatchCalls(), 1)
+		})
+
+		t.Run("the same payment with another key is a second payment", func(t *testing.T) {
+			var (
+				ctx         = context.Background()
+				mockStore   = newKeyedStore()
+				makePayment = use_cases.NewMakePayment(mockMetricsClient, mockStore, validation.IncomingInstructionValidator{})
+			)
+
+			id, err := makePayment.Execute(ctx, withKey("some-key", "50"))
+			require.NoError(t, err)
+
+			secondID, err := makePayment.Execute(ctx, withKey("another-key", "50"))
+			require.NoError(t, err)
+
+			assert.NotEqual(t, id, secondID)
+			assert.Len(t, mockStore.StoreForDispatchCalls(), 2)
+		})
+
+		t.Run("a retry of a rejected request returns its validation errors again", func(t *testing.T) {
+			var (
+				ctx                 = context.Background()
+				mockStore           = newKeyedStore()
+				makePayment         = use_cases.NewMakePayment(mockMetricsClient, mockStore, validation.IncomingInstructionValidator{})
+				incomingInstruction = testhelpers.NewIncomingInstructionBuilder().WithMerchantAccountNumber("").Build()
+			)
+			incomingInstruction.IdempotencyKey = "some-key"
+
+			_, err := makePayment.Execute(ctx, incomingInstruction)
+			require.Error(t, err)
+
+			_, retriedErr := makePayment.Execute(ctx, incomingInstruction)
+
+			assert.EqualError(t, retriedErr, err.Error())
+			assert.Len(t, mockStore.StoreCalls(), 1)
+		})
+
+		t.Run("a retry of a request rejected by its routing rule returns the rejection again", func(t *testing.T) {
+			var (
+				ctx         = context.Background()
+				mockStore   = newKeyedStore()
+				makePayment = use_cases.NewMakePayment(mockMetricsClient, mockStore, validation.IncomingInstructionValidator{})
+				decision    = models.RoutingDecision{Rule: "saxo-isk", Rejection: "SAXO files should not contain ISK currencies"}
+			)
+			makePayment.RouteWith(&mocks.RoutePaymentInstructionMock{RouteFunc: func(instruction models.IncomingInstruction) models.RoutingDecision {
+				return decision
+			}})
+
+			id, err := makePayment.Execute(ctx, withKey("some-key", "50"))
+			require.Error(t, err)
+
+			retriedID, retriedErr := makePayment.Execute(ctx, withKey("some-key", "50"))
+			assert.Equal(t, id, retriedID)
+			assert.EqualError(t, retriedErr, err.Error())
+
+			// the routing rules no longer reject it, the retry is still answered with the stored rejection
+			decision = models.RoutingDecision{Rule: "default", PaymentProvider: models.BankingCircle}
+			_, retriedErr = makePayment.Execute(ctx, withKey("some-key", "50"))
+			assert.ErrorContains(t, retriedErr, "SAXO files should not contain ISK currencies")
+
+			assert.Len(t, mockStore.StoreCalls(), 1)
+			assert.Len(t, mockStore.StoreForDispatchCalls(), 0)
+		})
+	})
 }
@@ -24,6 +24,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 	dummyEventValidator := &validationMocks.PPEventValidatorMock{ValidateFunc: func(ppEvent models.PaymentProviderEvent) error {
 		return nil
 	}}
+	dummyMetricsClient := &mocks.MetricsClientMock{CountFunc: func(ctx context.Context, name string, value int64, tags []string) {}}
 
 	t.Run("given a successful payment, we update the status, events and version of the payment instruction", func(t *testing.T) {
 		var (
@@ -33,7 +34,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 				StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				},
-				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 					return nil
 				},
 			}
@@ -44,7 +45,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 			return nil
 		}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
 
 		err := useCase.Execute(ctx, ppEvent)
 		require.NoError(t, err)
@@ -54,6 +55,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		updatedEvents := mockPaymentInstructionRepo.UpdatePaymentCalls()[0].Event
 		assert.Equal(t, updatedStatus, models.Successful)
 		assert.Equal(t, updatedEvents.Type, models.DomainProcessingSucceeded)
+		assert.Equal(t, ppEvent.PaymentInstruction.Version(), mockPaymentInstructionRepo.UpdatePaymentCalls()[0].ExpectedVersion, "the update should expect the version the event was raised for")
 	})
 
 	t.Run(fmt.Sprintf("Given a failed ppEvent with a faliure code of %s, it updates the status of the payment instruction, adds the relevant event and tracks it in the payment repo", models.StuckInPending), func(t *testing.T) {
@@ -67,14 +69,14 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		mockPaymentInstructionRepo.StoreFunc = func(ctx context.Context, instruction models.PaymentInstruction) error {
 			return nil
 		}
-		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 			return nil
 		}
 		paymentExporterProducer.ReportPaymentStatusFunc = func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
 			return nil
 		}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
 		err := useCase.Execute(ctx, ppEvent)
 		require.NoError(t, err)
 
@@ -104,7 +106,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		mockPaymentInstructionRepo.StoreFunc = func(ctx context.Context, instruction models.PaymentInstruction) error {
 			return nil
 		}
-		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 			return nil
 		}
 
@@ -112,7 +114,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 			return nil
 		}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
 		err := useCase.Execute(ctx, ppEvent)
 		require.NoError(t, err)
 
@@ -142,7 +144,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		mockPaymentInstructionRepo.StoreFunc = func(ctx context.Context, instruction models.PaymentInstruction) error {
 			return nil
 		}
-		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 			return nil
 		}
 
@@ -150,7 +152,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 			return nil
 		}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
 		err := useCase.Execute(ctx, ppEvent)
 		require.NoError(t, err)
 
@@ -180,7 +182,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		mockPaymentInstructionRepo.StoreFunc = func(ctx context.Context, instruction models.PaymentInstruction) error {
 			return nil
 		}
-		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 			return nil
 		}
 
@@ -188,7 +190,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 			return nil
 		}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
 		err := useCase.Execute(ctx, ppEvent)
 		require.NoError(t, err)
 
@@ -218,7 +220,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		mockPaymentInstructionRepo.StoreFunc = func(ctx context.Context, instruction models.PaymentInstruction) error {
 			return nil
 		}
-		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 			return nil
 		}
 
@@ -226,7 +228,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 			return nil
 		}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
 		err := useCase.Execute(ctx, ppEvent)
 		require.NoError(t, err)
 
@@ -255,7 +257,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		mockPaymentInstructionRepo.StoreFunc = func(ctx context.Context, instruction models.PaymentInstruction) error {
 			return nil
 		}
-		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 			return nil
 		}
 
@@ -263,7 +265,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 			return nil
 		}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
 		err := useCase.Execute(ctx, ppEvent)
 		require.NoError(t, err)
 
@@ -292,7 +294,7 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 		mockPaymentInstructionRepo.StoreFunc = func(ctx context.Context, instruction models.PaymentInstruction) error {
 			return nil
 		}
-		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+		mockPaymentInstructionRepo.UpdatePaymentFunc = func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 			return nil
 		}
 
@@ -305,11 +307,116 @@ func TestTrackPaymentOutcome_Execute(t *testing.T) {
 			return validationError
 		}}
 
-		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, spyEventValidator, paymentExporterProducer)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, spyEventValidator, paymentExporterProducer, dummyMetricsClient)
 		err := useCase.Execute(ctx, ppEvent)
 		assert.Equal(t, err, validationError)
 		assert.Equal(t, spyEventValidator.ValidateCalls()[0].PaymentProviderEvent, ppEvent)
 	})
+
+	t.Run("a failure for a payment instruction that already succeeded is recorded as a rejected transition and not reported", func(t *testing.T) {
+		var (
+			ctx                        = context.Background()
+			ppEvent                    = randomFailedPaymentProviderEvent(models.RejectedCode)
+			mockPaymentInstructionRepo = &mocks.StorePaymentInstructionToRepoMock{
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+					return nil
+				},
+			}
+			paymentExporterProducer = &mocks.PaymentExporterProducerMock{}
+			spyMetricsClient        = &mocks.MetricsClientMock{CountFunc: func(ctx context.Context, name string, value int64, tags []string) {}}
+		)
+		ppEvent.PaymentInstruction.TrackPPEvent(randomSuccessfulPaymentProviderEvent())
+		succeededVersion := ppEvent.PaymentInstruction.Version()
+
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, spyMetricsClient)
+		err := useCase.Execute(ctx, ppEvent)
+
+		assert.Equal(t, models.IllegalTransitionError{ID: ppEvent.PaymentInstruction.ID(), From: models.Successful, To: models.Failed}, err)
+		require.Len(t, mockPaymentInstructionRepo.UpdatePaymentCalls(), 1)
+		update := mockPaymentInstructionRepo.UpdatePaymentCalls()[0]
+		assert.Equal(t, models.Successful, update.Status)
+		assert.Equal(t, succeededVersion, update.ExpectedVersion)
+		assert.Equal(t, models.DomainTransitionRejected, update.Event.Type)
+		assert.Equal(t, models.DomainTransitionRejectedEventDetails{From: models.Successful, To: models.Failed}, update.Event.Details)
+		assert.Empty(t, paymentExporterProducer.ReportPaymentStatusCalls())
+		require.Len(t, spyMetricsClient.CountCalls(), 1)
+		assert.Equal(t, []string{string(models.Successful), string(models.Failed)}, spyMetricsClient.CountCalls()[0].Tags)
+	})
+
+	t.Run("an acceptance by the payment provider keeps its payment ID on the payment instruction without reporting it", func(t *testing.T) {
+		var (
+			ctx                        = context.Background()
+			ppEvent                    = randomSuccessfulPaymentProviderEvent()
+			mockPaymentInstructionRepo = &mocks.StorePaymentInstructionToRepoMock{
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+					return nil
+				},
+			}
+			paymentExporterProducer = &mocks.PaymentExporterProducerMock{}
+		)
+		ppEvent.Type = models.Submitted
+		ppEvent.BankingReference = "banking-reference"
+
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
+
+		require.NoError(t, useCase.Execute(ctx, ppEvent))
+
+		require.Len(t, mockPaymentInstructionRepo.UpdatePaymentCalls(), 1)
+		update := mockPaymentInstructionRepo.UpdatePaymentCalls()[0]
+		assert.Equal(t, models.StateSubmitted, update.Status)
+		assert.Equal(t, models.DomainAcceptedByPaymentProvider, update.Event.Type)
+		assert.Equal(t, models.DomainAcceptedByPaymentProviderEventDetails{
+			PaymentProviderPaymentID: ppEvent.PaymentProviderPaymentID,
+			BankingReference:         "banking-reference",
+		}, update.Event.Details)
+		assert.Empty(t, paymentExporterProducer.ReportPaymentStatusCalls())
+	})
+
+	t.Run("an acceptance arriving after the outcome of the payment is ignored", func(t *testing.T) {
+		var (
+			ctx                        = context.Background()
+			ppEvent                    = randomSuccessfulPaymentProviderEvent()
+			mockPaymentInstructionRepo = &mocks.StorePaymentInstructionToRepoMock{}
+			paymentExporterProducer    = &mocks.PaymentExporterProducerMock{}
+		)
+		ppEvent.PaymentInstruction.TrackPPEvent(ppEvent)
+		ppEvent.Type = models.Submitted
+
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
+
+		require.NoError(t, useCase.Execute(ctx, ppEvent))
+		assert.Empty(t, mockPaymentInstructionRepo.UpdatePaymentCalls())
+		assert.Empty(t, paymentExporterProducer.ReportPaymentStatusCalls())
+	})
+
+	t.Run("the funds reserved by the payment instruction are released once its outcome is tracked, not on its acceptance", func(t *testing.T) {
+		var (
+			ctx                        = context.Background()
+			mockPaymentInstructionRepo = &mocks.StorePaymentInstructionToRepoMock{
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+					return nil
+				},
+			}
+			paymentExporterProducer = &mocks.PaymentExporterProducerMock{ReportPaymentStatusFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+				return nil
+			}}
+			ledger = &mocks.LiquidityLedgerMock{ReleaseFunc: func(ctx context.Context, id models.PaymentInstructionID) error {
+				return errors.New("oops")
+			}}
+		)
+		useCase := use_cases.NewTrackPaymentOutcome(mockPaymentInstructionRepo, dummyEventValidator, paymentExporterProducer, dummyMetricsClient)
+		useCase.ReleaseFundsWith(ledger)
+
+		accepted := randomSuccessfulPaymentProviderEvent()
+		accepted.Type = models.Submitted
+		require.NoError(t, useCase.Execute(ctx, accepted))
+		assert.Empty(t, ledger.ReleaseCalls())
+
+		failed := randomFailedPaymentProviderEvent(models.MissingFunding)
+		require.NoError(t, useCase.Execute(ctx, failed), "the outcome is tracked even when the funds can't be released")
+		require.Len(t, ledger.ReleaseCalls(), 1)
+		assert.Equal(t, failed.PaymentInstruction.ID(), ledger.ReleaseCalls()[0].ID)
+	})
 }
 
 func randomSuccessfulPaymentProviderEvent() models.PaymentProviderEvent {
@@ -31,7 +31,7 @@ func TestTrackPaymentState(t *testing.T) {
 				StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				},
-				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 					return nil
 				},
 			}
@@ -39,7 +39,7 @@ func TestTrackPaymentState(t *testing.T) {
 			}}
 		)
 		// when we execute the update payment method
-		useCase := use_cases.NewUpdatePaymentState(mockStore, mockMetricsClient)
+		useCase := use_cases.NewUpdatePaymentState(mockStore, stubPaymentRepoAtVersion(1), mockMetricsClient)
 		err := useCase.Execute(ctx, paymentInstructionID, successfulState, event)
 		require.NoError(t, err)
 
@@ -48,6 +48,7 @@ func TestTrackPaymentState(t *testing.T) {
 		assert.Equal(t, models.PaymentInstructionID(paymentInstructionID), mockStore.UpdatePaymentCalls()[0].ID)
 		assert.Equal(t, successfulState, mockStore.UpdatePaymentCalls()[0].Status)
 		assert.Equal(t, event, mockStore.UpdatePaymentCalls()[0].Event)
+		assert.Equal(t, 1, mockStore.UpdatePaymentCalls()[0].ExpectedVersion)
 		assert.Len(t, mockMetricsClient.CountCalls(), 1)
 	})
 	t.Run("update the state of a failed payment sent by isb", func(t *testing.T) {
@@ -67,7 +68,7 @@ func TestTrackPaymentState(t *testing.T) {
 				StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				},
-				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 					return nil
 				},
 			}
@@ -75,7 +76,7 @@ func TestTrackPaymentState(t *testing.T) {
 			}}
 		)
 		// when we execute the update payment method
-		useCase := use_cases.NewUpdatePaymentState(mockStore, mockMetricsClient)
+		useCase := use_cases.NewUpdatePaymentState(mockStore, stubPaymentRepoAtVersion(1), mockMetricsClient)
 		err := useCase.Execute(ctx, paymentInstructionID, failedState, event)
 		require.NoError(t, err)
 
@@ -102,7 +103,7 @@ func TestTrackPaymentState(t *testing.T) {
 				StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 					return nil
 				},
-				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 					return updatePaymentError
 				},
 			}
@@ -110,11 +111,81 @@ func TestTrackPaymentState(t *testing.T) {
 			}}
 		)
 
-		useCase := use_cases.NewUpdatePaymentState(mockStore, mockMetricsClient)
+		useCase := use_cases.NewUpdatePaymentState(mockStore, stubPaymentRepoAtVersion(1), mockMetricsClient)
 		err := useCase.Execute(ctx, paymentInstructionID, state, event)
 		assert.Error(t, err)
 		assert.Equal(t, err, updatePaymentError)
 
 		assert.Len(t, mockStore.UpdatePaymentCalls(), 1)
 	})
+
+	t.Run("returns the version conflict when the payment changes between reading and updating it", func(t *testing.T) {
+		var (
+			ctx                  = context.Background()
+			paymentInstructionID = testhelpers.RandomString()
+			conflict             = models.VersionConflictError{ID: models.PaymentInstructionID(paymentInstructionID), ExpectedVersion: 3, ActualVersion: 4}
+			mockStore            = &mocks.StorePaymentInstructionToRepoMock{
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+					return conflict
+				},
+			}
+			mockMetricsClient = &mocks.MetricsClientMock{}
+		)
+
+		useCase := use_cases.NewUpdatePaymentState(mockStore, stubPaymentRepoAtVersion(3), mockMetricsClient)
+		err := useCase.Execute(ctx, paymentInstructionID, models.Successful, models.PaymentInstructionEvent{Type: models.DomainProcessingSucceeded})
+
+		assert.Equal(t, conflict, err)
+		assert.Equal(t, 3, mockStore.UpdatePaymentCalls()[0].ExpectedVersion)
+		assert.Empty(t, mockMetricsClient.CountCalls())
+	})
+
+	t.Run("records an unknown state as a rejected transition instead of storing it", func(t *testing.T) {
+		var (
+			ctx                  = context.Background()
+			paymentInstructionID = testhelpers.RandomString()
+			mockStore            = &mocks.StorePaymentInstructionToRepoMock{
+				UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+					return nil
+				},
+			}
+			mockMetricsClient = &mocks.MetricsClientMock{CountFunc: func(ctx context.Context, name string, value int64, tags []string) {
+			}}
+		)
+
+		useCase := use_cases.NewUpdatePaymentState(mockStore, stubPaymentRepoAtVersion(2), mockMetricsClient)
+		err := useCase.Execute(ctx, paymentInstructionID, "", models.PaymentInstructionEvent{CreatedOn: time.Now()})
+
+		assert.Equal(t, models.IllegalTransitionError{ID: models.PaymentInstructionID(paymentInstructionID), From: models.SubmittedForProcessing, To: ""}, err)
+		require.Len(t, mockStore.UpdatePaymentCalls(), 1)
+		assert.Equal(t, models.SubmittedForProcessing, mockStore.UpdatePaymentCalls()[0].Status)
+		assert.Equal(t, 2, mockStore.UpdatePaymentCalls()[0].ExpectedVersion)
+		assert.Equal(t, models.DomainTransitionRejected, mockStore.UpdatePaymentCalls()[0].Event.Type)
+		require.Len(t, mockMetricsClient.CountCalls(), 1)
+		assert.Equal(t, "app_payment_instruction_transition_rejected", mockMetricsClient.CountCalls()[0].Name)
+	})
+
+	t.Run("ignores a redelivered state update for the state the payment instruction has already", func(t *testing.T) {
+		var (
+			ctx                  = context.Background()
+			paymentInstructionID = testhelpers.RandomString()
+			mockStore            = &mocks.StorePaymentInstructionToRepoMock{}
+			mockMetricsClient    = &mocks.MetricsClientMock{}
+		)
+
+		useCase := use_cases.NewUpdatePaymentState(mockStore, stubPaymentRepoAtVersion(2), mockMetricsClient)
+		err := useCase.Execute(ctx, paymentInstructionID, models.SubmittedForProcessing, models.PaymentInstructionEvent{CreatedOn: time.Now()})
+
+		require.NoError(t, err)
+		assert.Empty(t, mockStore.UpdatePaymentCalls())
+		assert.Empty(t, mockMetricsClient.CountCalls())
+	})
+}
+
+func stubPaymentRepoAtVersion(version int) *mocks.GetPaymentInstructionFromRepoMock {
+	return &mocks.GetPaymentInstructionFromRepoMock{
+		GetFunc: func(ctx context.Context, id models.PaymentInstructionID) (models.PaymentInstruction, error) {
+			return models.NewPaymentInstructionFromDTO(models.PaymentInstructionDTO{ID: id, Version: version, Status: models.SubmittedForProcessing}), nil
+		},
+	}
 }
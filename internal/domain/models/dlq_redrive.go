@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDlqEditNeedsOneMessage   = errors.New("an edited body can only replace the body of a single message selected by its ID")
+	ErrDlqRedriveNeedsSelection = errors.New("select the messages to redrive, or redrive all of them with all")
+)
+
+// DlqRedriveSelection selects the messages of a dead letter queue or topic by any of their message ID, or the ID or
+// contract number of the payment instruction in their body, it selects all of them when empty or when All is set.
+type DlqRedriveSelection struct {
+	MessageIDs            []string               `json:"messageIds,omitempty"`
+	PaymentInstructionIDs []PaymentInstructionID `json:"paymentInstructionIds,omitempty"`
+	ContractNumbers       []string               `json:"contractNumbers,omitempty"`
+	All                   bool                   `json:"all,omitempty"`
+}
+
+func (s DlqRedriveSelection) empty() bool {
+	return len(s.MessageIDs) == 0 && len(s.PaymentInstructionIDs) == 0 && len(s.ContractNumbers) == 0
+}
+
+func (s DlqRedriveSelection) all() bool {
+	return s.All || s.empty()
+}
+
+func (s DlqRedriveSelection) Selects(message RedrivenDlqMessage) bool {
+	if s.all() {
+		return true
+	}
+	for _, id := range s.MessageIDs {
+		if id == message.MessageID {
+			return true
+		}
+	}
+	for _, id := range s.PaymentInstructionIDs {
+		if id != "" && id == message.PaymentInstructionID {
+			return true
+		}
+	}
+	for _, contractNumber := range s.ContractNumbers {
+		if contractNumber != "" && contractNumber == message.ContractNumber {
+			return true
+		}
+	}
+	return false
+}
+
+type DlqRedriveRequest struct {
+	DlqRedriveSelection
+	// DryRun reports the messages that would be redriven, with their bodies, and leaves them where they are.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Body replaces the body of the message when it is redriven, e.g. to fix the field it failed on.
+	Body string `json:"body,omitempty"`
+}
+
+func (r DlqRedriveRequest) Validate() error {
+	if r.Body != "" && (r.All || len(r.MessageIDs) != 1 || len(r.PaymentInstructionIDs) > 0 || len(r.ContractNumbers) > 0) {
+		return ErrDlqEditNeedsOneMessage
+	}
+	return nil
+}
+
+// ValidateSelection fails a request that selects no message without All, so that an empty request doesn't redrive a
+// whole dead letter queue or topic by accident. The messages of a dead letter topic even stay on it once redriven.
+func (r DlqRedriveRequest) ValidateSelection() error {
+	if r.empty() && !r.All {
+		return ErrDlqRedriveNeedsSelection
+	}
+	return r.Validate()
+}
+
+type RedrivenDlqMessage struct {
+	MessageID            string               `json:"messageId"`
+	PaymentInstructionID PaymentInstructionID `json:"paymentInstructionId,omitempty"`
+	ContractNumber       string               `json:"contractNumber,omitempty"`
+	Edited               bool                 `json:"edited,omitempty"`
+	// Body is only reported by dry runs, it is not kept in the audit of the redrive.
+	Body string `json:"body,omitempty"`
+}
+
+// DlqRedrive records who moved which messages of a dead letter queue or topic back to its source.
+type DlqRedrive struct {
+	Queue       string               `json:"queue"`
+	SourceQueue string               `json:"sourceQueue"`
+	RedrivenBy  string               `json:"redrivenBy"`
+	Messages    []RedrivenDlqMessage `json:"messages"`
+	RedrivenAt  time.Time            `json:"redrivenAt"`
+}
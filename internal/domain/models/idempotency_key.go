@@ -0,0 +1,46 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// IdempotencyKey is the key a client sent a payment request with, together with the fingerprint of the request,
+// so a retry of the request can be told apart from a different request reusing the key.
+type IdempotencyKey struct {
+	Key                string
+	RequestFingerprint string
+}
+
+// NewIdempotencyKey returns an empty IdempotencyKey when the IncomingInstruction came without a key.
+func NewIdempotencyKey(instruction IncomingInstruction) (IdempotencyKey, error) {
+	if instruction.IdempotencyKey == "" {
+		return IdempotencyKey{}, nil
+	}
+
+	body, err := instruction.ToJSON()
+	if err != nil {
+		return IdempotencyKey{}, err
+	}
+	fingerprint := sha256.Sum256(body)
+
+	return IdempotencyKey{
+		Key:                instruction.IdempotencyKey,
+		RequestFingerprint: hex.EncodeToString(fingerprint[:]),
+	}, nil
+}
+
+func (k IdempotencyKey) IsSet() bool {
+	return k.Key != ""
+}
+
+// IdempotencyKeyConflictError is returned when an idempotency key is sent again with a different request.
+type IdempotencyKeyConflictError struct {
+	Key string
+	ID  PaymentInstructionID
+}
+
+func (i IdempotencyKeyConflictError) Error() string {
+	return fmt.Sprintf("idempotency key %q was used for payment instruction %s with a different request", i.Key, i.ID)
+}
@@ -17,6 +17,10 @@ type IncomingInstruction struct {
 	Metadata             Metadata `json:"metadata"`
 	Payment              Payment  `json:"payment"`
 	PaymentCorrelationId string   `json:"paymentCorrelationId"`
+	// IdempotencyKey comes with the request rather than in the instruction, so a retry has the same body as the first attempt.
+	IdempotencyKey string `json:"-"`
+	// FundsHold is the hold of the file or batch the instruction is paid in, its reservation is taken out of the hold.
+	FundsHold FundsHold `json:"-"`
 }
 
 type Address struct {
@@ -141,6 +145,9 @@ func (a IncomingInstructions) SumByCurrency() ([]IncomingInstructionsSummary, er
 		amount float64
 		count  int
 	}
+	if len(a) == 0 {
+		return nil, nil
+	}
 	summary := make(map[CurrencyCode]*currencySummary)
 	highRisk := a[0].Merchant.HighRisk
 
@@ -185,6 +192,16 @@ func (a IncomingInstructions) FilterOutCurrency(currency CurrencyCode) IncomingI
 	return filteredSlice
 }
 
+// WithFundsHold sets the hold of the funds of a currency on the instructions of that currency.
+func (a IncomingInstructions) WithFundsHold(hold FundsHold) IncomingInstructions {
+	for i := range a {
+		if a[i].IsoCode() == hold.Currency {
+			a[i].FundsHold = hold
+		}
+	}
+	return a
+}
+
 func (a IncomingInstructions) ReturnCurrency(currency CurrencyCode) IncomingInstructions {
 	filteredSlice := IncomingInstructions{}
 	for _, instruction := range a {
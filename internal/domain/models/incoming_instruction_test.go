@@ -145,6 +145,15 @@ func TestIncomingInstruction_CurrencyTotals(t *testing.T) {
 		is.Equal(len(summary), 2)
 	})
 
+	t.Run("Returns an empty summary for no incoming instructions", func(t *testing.T) {
+		is := is.New(t)
+
+		summary, err := models.IncomingInstructions{}.SumByCurrency()
+
+		is.NoErr(err)
+		is.Equal(len(summary), 0)
+	})
+
 	t.Run("Return total sum to 4 decimal places", func(t *testing.T) {
 		is := is.New(t)
 
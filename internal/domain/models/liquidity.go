@@ -0,0 +1,70 @@
+package models
+
+import "github.com/google/uuid"
+
+// FundsReservation holds back the amount of a payment instruction from the funds of the source account of its currency
+// and risk class, from the time it is accepted until its payment provider reports its outcome or it expires.
+type FundsReservation struct {
+	PaymentInstructionID PaymentInstructionID `json:"paymentInstructionId"`
+	Currency             CurrencyCode         `json:"currency"`
+	HighRisk             bool                 `json:"highRisk"`
+	Amount               float64              `json:"amount"`
+	// Account is the source account the payment instruction is paid from, it is only held against that account.
+	Account string `json:"account,omitempty"`
+	// HoldID is the hold of the file or batch of the payment instruction, the reservation is taken out of it.
+	HoldID FundsHoldID `json:"holdId,omitempty"`
+}
+
+// FundsHold holds back the total of a currency of a file or batch while its payment instructions are made, each of them
+// reserves its own amount out of the hold and what is left of it is released once they all did. A hold left over by a
+// flow that stopped half way expires on its own.
+type FundsHold struct {
+	ID       FundsHoldID  `json:"id"`
+	Currency CurrencyCode `json:"currency"`
+	HighRisk bool         `json:"highRisk"`
+	Amount   float64      `json:"amount"`
+	// Account is the source account whose funds cover the hold, the payment instructions of the hold are paid from it.
+	Account string `json:"account,omitempty"`
+}
+
+type FundsHoldID string
+
+func NewFundsHoldID() FundsHoldID {
+	return FundsHoldID("hold-" + uuid.NewString())
+}
+
+// AccountFunds is the balance and intraday loan of one of the source accounts of a currency and risk class.
+type AccountFunds struct {
+	Account         string  `json:"account"`
+	Balance         float64 `json:"balance"`
+	MaxIntraDayLoan float64 `json:"maxIntraDayLoan"`
+}
+
+// ReservedFunds is the amount of the outstanding reservations and holds of a currency and risk class.
+type ReservedFunds struct {
+	Currency CurrencyCode `json:"currency"`
+	HighRisk bool         `json:"highRisk"`
+	Amount   float64      `json:"amount"`
+}
+
+// LiquidityHeadroom is how much of the balance and intraday loan of a source account is left for new payments,
+// once the outstanding reservations are taken off.
+type LiquidityHeadroom struct {
+	Currency        CurrencyCode `json:"currency"`
+	HighRisk        bool         `json:"highRisk"`
+	Balance         float64      `json:"balance"`
+	MaxIntraDayLoan float64      `json:"maxIntraDayLoan"`
+	Reserved        float64      `json:"reserved"`
+	Free            float64      `json:"free"`
+}
+
+func NewLiquidityHeadroom(currency CurrencyCode, highRisk bool, balance, maxIntraDayLoan, reserved float64) LiquidityHeadroom {
+	return LiquidityHeadroom{
+		Currency:        currency,
+		HighRisk:        highRisk,
+		Balance:         balance,
+		MaxIntraDayLoan: maxIntraDayLoan,
+		Reserved:        reserved,
+		Free:            balance + maxIntraDayLoan - reserved,
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type OutboxEntryID int64
+
+// OutboxEntry is a PaymentInstruction stored as submitted for processing that still has to be handed to its payment provider.
+type OutboxEntry struct {
+	ID                 OutboxEntryID
+	PaymentInstruction PaymentInstruction
+	// Attempts counts the dispatches started for the entry, including the current one.
+	Attempts  int
+	CreatedAt time.Time
+}
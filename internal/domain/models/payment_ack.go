@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type PaymentAckOutcome string
+
+const (
+	PaymentAckAccepted  PaymentAckOutcome = "ACCEPTED"
+	PaymentAckRejected  PaymentAckOutcome = "REJECTED"
+	PaymentAckDuplicate PaymentAckOutcome = "DUPLICATE"
+	// PaymentAckFailed is a payment that couldn't be made for now, it is acknowledged again when it is retried.
+	PaymentAckFailed PaymentAckOutcome = "FAILED"
+)
+
+// PaymentAck tells the caller that sent an incoming instruction what became of it, matched by its correlation ID.
+type PaymentAck struct {
+	CorrelationID        string               `json:"correlationId"`
+	PaymentInstructionID PaymentInstructionID `json:"paymentInstructionId,omitempty"`
+	Outcome              PaymentAckOutcome    `json:"outcome"`
+	ValidationErrors     []string             `json:"validationErrors,omitempty"`
+	Reason               string               `json:"reason,omitempty"`
+	AcknowledgedAt       time.Time            `json:"acknowledgedAt"`
+	// MessageKey is the key of the message the incoming instruction came in, the ack is keyed by it when it has no
+	// correlation ID.
+	MessageKey string `json:"-"`
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentBatchID string
+
+type PaymentBatchState string
+
+const (
+	PaymentBatchProcessing PaymentBatchState = "PROCESSING"
+	PaymentBatchCompleted  PaymentBatchState = "COMPLETED"
+)
+
+type PaymentBatchItemStatus string
+
+const (
+	PaymentBatchItemPending  PaymentBatchItemStatus = "PENDING"
+	PaymentBatchItemAccepted PaymentBatchItemStatus = "ACCEPTED"
+	PaymentBatchItemRejected PaymentBatchItemStatus = "REJECTED"
+)
+
+// PaymentBatch is a set of incoming instructions submitted in one request, each item is made into a payment
+// of its own and keeps its own outcome.
+type PaymentBatch struct {
+	ID        PaymentBatchID     `json:"id"`
+	State     PaymentBatchState  `json:"state"`
+	Items     []PaymentBatchItem `json:"items"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// PaymentBatchItem is the outcome of one incoming instruction of a PaymentBatch, in the order it was submitted.
+type PaymentBatchItem struct {
+	Index                int                    `json:"index"`
+	ContractNumber       string                 `json:"contractNumber"`
+	Currency             CurrencyCode           `json:"currency"`
+	Amount               string                 `json:"amount"`
+	Status               PaymentBatchItemStatus `json:"status"`
+	PaymentInstructionID PaymentInstructionID   `json:"paymentInstructionId,omitempty"`
+	Reason               string                 `json:"reason,omitempty"`
+}
+
+func NewPaymentBatch(instructions IncomingInstructions) PaymentBatch {
+	items := make([]PaymentBatchItem, len(instructions))
+	for i, instruction := range instructions {
+		items[i] = PaymentBatchItem{
+			Index:          i,
+			ContractNumber: instruction.Merchant.ContractNumber,
+			Currency:       instruction.IsoCode(),
+			Amount:         instruction.Payment.Amount,
+			Status:         PaymentBatchItemPending,
+		}
+	}
+
+	now := time.Now()
+	return PaymentBatch{
+		ID:        PaymentBatchID(uuid.NewString()),
+		State:     PaymentBatchProcessing,
+		Items:     items,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (b *PaymentBatch) Accept(index int, id PaymentInstructionID) {
+	b.Items[index].Status = PaymentBatchItemAccepted
+	b.Items[index].PaymentInstructionID = id
+	b.UpdatedAt = time.Now()
+}
+
+func (b *PaymentBatch) Reject(index int, reason string) {
+	b.Items[index].Status = PaymentBatchItemRejected
+	b.Items[index].Reason = reason
+	b.UpdatedAt = time.Now()
+}
+
+func (b *PaymentBatch) Complete() {
+	b.State = PaymentBatchCompleted
+	b.UpdatedAt = time.Now()
+}
+
+// Count returns how many items of the batch are in the given status.
+func (b PaymentBatch) Count(status PaymentBatchItemStatus) int {
+	count := 0
+	for _, item := range b.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// PaymentBatchRejectedError is returned when a batch can't be processed at all, none of its items is paid.
+type PaymentBatchRejectedError struct {
+	Reason string
+}
+
+func (p PaymentBatchRejectedError) Error() string {
+	return fmt.Sprintf("payment batch rejected: %s", p.Reason)
+}
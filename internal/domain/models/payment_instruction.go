@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +17,7 @@ type PaymentInstruction struct {
 	paymentProvider     PaymentProviderType
 	status              PaymentInstructionStatus
 	events              []PaymentInstructionEvent
+	idempotencyKey      IdempotencyKey
 }
 
 type (
@@ -32,6 +32,8 @@ const (
 	Successful             PaymentInstructionStatus = "PROCESSING_SUCCEEDED"
 	Failed                 PaymentInstructionStatus = "PROCESSING_FAILED"
 	StateSubmitted         PaymentInstructionStatus = "SUBMITTED"
+	// Reversed is a payment the payment provider processed and then reversed, the merchant wasn't paid after all.
+	Reversed PaymentInstructionStatus = "PROCESSING_REVERSED"
 )
 
 func NewPaymentInstruction(instruction IncomingInstruction) PaymentInstruction {
@@ -75,6 +77,10 @@ func NewPaymentInstructionFromJSON(in []byte) (PaymentInstruction, error) {
 	if err != nil {
 		return PaymentInstruction{}, err
 	}
+	return NewPaymentInstructionFromDTO(instruction), nil
+}
+
+func NewPaymentInstructionFromDTO(instruction PaymentInstructionDTO) PaymentInstruction {
 	return PaymentInstruction{
 		IncomingInstruction: instruction.IncomingInstruction,
 		id:                  instruction.ID,
@@ -82,7 +88,7 @@ func NewPaymentInstructionFromJSON(in []byte) (PaymentInstruction, error) {
 		paymentProvider:     instruction.PaymentProvider,
 		status:              instruction.Status,
 		events:              instruction.Events,
-	}, nil
+	}
 }
 
 var _ json.Marshaler = PaymentInstruction{}
@@ -99,8 +105,10 @@ func (p PaymentInstruction) MustToJSON() ([]byte, error) {
 	return serialised, nil
 }
 
-func (p *PaymentInstruction) Rejected(instruction IncomingInstruction, err string) {
-	p.updateStatus(Rejected)
+func (p *PaymentInstruction) Rejected(instruction IncomingInstruction, err string) error {
+	if changed, transitionErr := p.updateStatus(Rejected); !changed {
+		return transitionErr
+	}
 	instructionJSON, _ := instruction.ToJSON()
 	p.events = append(p.events, PaymentInstructionEvent{
 		Type:      DomainRejected,
@@ -113,40 +121,29 @@ func (p *PaymentInstruction) Rejected(instruction IncomingInstruction, err strin
 			}.Error(),
 		}},
 	})
+	return nil
 }
 
-func (p *PaymentInstruction) SubmitForProcessing() {
-	p.updateStatus(SubmittedForProcessing)
+func (p *PaymentInstruction) SubmitForProcessing() error {
+	if changed, err := p.updateStatus(SubmittedForProcessing); !changed {
+		return err
+	}
 	p.events = append(p.events, PaymentInstructionEvent{
 		Type:      DomainSubmittedToPaymentProvider,
 		CreatedOn: time.Now(),
 		Details:   DomainSubmittedToPaymentProviderEventDetails{PaymentProviderType: p.paymentProvider},
 	})
-}
-
-func (p *PaymentInstruction) RouteToPaymentProvider() {
-	switch {
-	case strings.HasPrefix(p.IncomingInstruction.Metadata.Sender, "ISB"):
-		p.SetPaymentProvider(Islandsbanki)
-	case strings.HasPrefix(p.IncomingInstruction.Metadata.Sender, "RB"):
-		p.SetPaymentProvider(Islandsbanki)
-	case strings.HasPrefix(p.IncomingInstruction.Metadata.Sender, "SAXO"):
-		p.SetPaymentProvider(BankingCircle)
-	case p.IncomingInstruction.Metadata.Sender == "":
-		if p.IncomingInstruction.Metadata.Source == "Solanteq" && p.IncomingInstruction.Payment.Currency.IsoCode == "ISK" {
-			p.SetPaymentProvider(Islandsbanki)
-		} else {
-			p.SetPaymentProvider(BankingCircle)
-		}
-	}
+	return nil
 }
 
 func (p PaymentInstruction) GetStatus() PaymentInstructionStatus {
 	return p.status
 }
 
-func (p *PaymentInstruction) SetStatus(status PaymentInstructionStatus) {
-	p.updateStatus(status)
+// SetStatus moves the PaymentInstruction to the status, it returns an IllegalTransitionError if it can't move there.
+func (p *PaymentInstruction) SetStatus(status PaymentInstructionStatus) error {
+	_, err := p.updateStatus(status)
+	return err
 }
 
 func (p PaymentInstruction) Version() int {
@@ -161,10 +158,38 @@ func (p PaymentInstruction) Events() []PaymentInstructionEvent {
 	return p.events
 }
 
+// RejectionReason is the message of the last DOMAIN.REJECTED event, the details of the events of a stored payment
+// instruction are read back as JSON objects.
+func (p PaymentInstruction) RejectionReason() (string, bool) {
+	for i := len(p.events) - 1; i >= 0; i-- {
+		if p.events[i].Type != DomainRejected {
+			continue
+		}
+		if details, ok := p.events[i].Details.(DomainRejectedEventDetails); ok {
+			return details.FailureReason.Message, true
+		}
+		var details DomainRejectedEventDetails
+		detailsJSON, err := json.Marshal(p.events[i].Details)
+		if err != nil || json.Unmarshal(detailsJSON, &details) != nil {
+			return "", false
+		}
+		return details.FailureReason.Message, true
+	}
+	return "", false
+}
+
 func (p PaymentInstruction) ID() PaymentInstructionID {
 	return p.id
 }
 
+func (p PaymentInstruction) IdempotencyKey() IdempotencyKey {
+	return p.idempotencyKey
+}
+
+func (p *PaymentInstruction) SetIdempotencyKey(key IdempotencyKey) {
+	p.idempotencyKey = key
+}
+
 func (p PaymentInstruction) ContractNumber() string {
 	return p.IncomingInstruction.Merchant.ContractNumber
 }
@@ -186,21 +211,39 @@ func (p PaymentInstruction) BusinessID() string {
 	)
 }
 
-func (p *PaymentInstruction) TrackPPEvent(event PaymentProviderEvent) {
-	if event.Type == Failure {
-		p.failedToProcessPayment(event)
-	} else {
-		p.successfullyProcessed(event.PaymentProviderPaymentID)
+// TrackPPEvent applies the outcome of the payment provider, it returns an IllegalTransitionError if the
+// PaymentInstruction can't move to the status of the outcome and leaves it unchanged if it has that status already.
+func (p *PaymentInstruction) TrackPPEvent(event PaymentProviderEvent) error {
+	switch event.OutcomeStatus() {
+	case Failed:
+		return p.failedToProcessPayment(event)
+	case StateSubmitted:
+		return p.acceptedByPaymentProvider(event)
+	case Reversed:
+		return p.reversedByPaymentProvider(event)
+	default:
+		return p.successfullyProcessed(event.PaymentProviderPaymentID)
 	}
 }
 
-func (p *PaymentInstruction) updateStatus(newStatus PaymentInstructionStatus) {
+// updateStatus moves the PaymentInstruction to the new status if the status transitions allow it. Moving to the status
+// it already has, e.g. for a redelivered event, changes nothing and reports false without an error.
+func (p *PaymentInstruction) updateStatus(newStatus PaymentInstructionStatus) (bool, error) {
+	if newStatus == p.status {
+		return false, nil
+	}
+	if err := p.ValidateTransition(newStatus); err != nil {
+		return false, err
+	}
 	p.status = newStatus
 	p.version += 1
+	return true, nil
 }
 
-func (p *PaymentInstruction) failedToProcessPayment(event PaymentProviderEvent) {
-	p.updateStatus(Failed)
+func (p *PaymentInstruction) failedToProcessPayment(event PaymentProviderEvent) error {
+	if changed, err := p.updateStatus(Failed); !changed {
+		return err
+	}
 
 	var instructionEvent PaymentInstructionEvent
 	id := event.PaymentProviderPaymentID
@@ -263,6 +306,30 @@ func (p *PaymentInstruction) failedToProcessPayment(event PaymentProviderEvent)
 				},
 			},
 		}
+	case CancelledCode:
+		instructionEvent = PaymentInstructionEvent{
+			Type:      DomainProcessingFailed,
+			CreatedOn: time.Now(),
+			Details: DomainProcessingFailedEventDetails{
+				FailureReason: PIFailureReason{
+					Code:    CancelledPayment,
+					Message: event.FailureReason.Message,
+				},
+				PaymentProviderID: id,
+			},
+		}
+	case NeedsManualAction:
+		instructionEvent = PaymentInstructionEvent{
+			Type:      DomainProcessingFailed,
+			CreatedOn: time.Now(),
+			Details: DomainProcessingFailedEventDetails{
+				FailureReason: PIFailureReason{
+					Code:    AwaitingManualAction,
+					Message: event.FailureReason.Message,
+				},
+				PaymentProviderID: id,
+			},
+		}
 	default:
 		instructionEvent = PaymentInstructionEvent{
 			Type:      DomainProcessingFailed,
@@ -278,15 +345,51 @@ func (p *PaymentInstruction) failedToProcessPayment(event PaymentProviderEvent)
 	}
 
 	p.events = append(p.events, instructionEvent)
+	return nil
+}
+
+// acceptedByPaymentProvider keeps the payment provider's ID of the payment, so it can be checked again if its outcome never arrives.
+func (p *PaymentInstruction) acceptedByPaymentProvider(event PaymentProviderEvent) error {
+	if changed, err := p.updateStatus(StateSubmitted); !changed {
+		return err
+	}
+	p.events = append(p.events, PaymentInstructionEvent{
+		Type:      DomainAcceptedByPaymentProvider,
+		CreatedOn: time.Now(),
+		Details: DomainAcceptedByPaymentProviderEventDetails{
+			PaymentProviderPaymentID: event.PaymentProviderPaymentID,
+			BankingReference:         event.BankingReference,
+		},
+	})
+	return nil
 }
 
-func (p *PaymentInstruction) successfullyProcessed(paymentProviderPaymentID ProviderPaymentID) {
-	p.updateStatus(Successful)
+func (p *PaymentInstruction) successfullyProcessed(paymentProviderPaymentID ProviderPaymentID) error {
+	if changed, err := p.updateStatus(Successful); !changed {
+		return err
+	}
 	p.events = append(p.events, PaymentInstructionEvent{
 		Type:      DomainProcessingSucceeded,
 		CreatedOn: time.Now(),
 		Details:   DomainProcessingSucceededEventDetails{PaymentProviderPaymentID: paymentProviderPaymentID},
 	})
+	return nil
+}
+
+// reversedByPaymentProvider records that the payment provider took back a payment, whether it told us it processed it or not.
+func (p *PaymentInstruction) reversedByPaymentProvider(event PaymentProviderEvent) error {
+	if changed, err := p.updateStatus(Reversed); !changed {
+		return err
+	}
+	p.events = append(p.events, PaymentInstructionEvent{
+		Type:      DomainProcessingReversed,
+		CreatedOn: time.Now(),
+		Details: DomainProcessingReversedEventDetails{
+			PaymentProviderPaymentID: event.PaymentProviderPaymentID,
+			BankingReference:         event.BankingReference,
+		},
+	})
+	return nil
 }
 
 func (p *PaymentInstruction) SetEvents(events []PaymentInstructionEvent) {
@@ -305,3 +408,15 @@ type InvalidPaymentInstructionError struct {
 func (i InvalidPaymentInstructionError) Error() string {
 	return fmt.Sprintf("Payment Instruction Invalid, err: %v for instruction JSON: %s", i.UnderlyingError, i.InstructionJSON)
 }
+
+// VersionConflictError is returned when a PaymentInstruction was updated by someone else since it was read.
+// The update should be reapplied to a freshly read PaymentInstruction.
+type VersionConflictError struct {
+	ID              PaymentInstructionID
+	ExpectedVersion int
+	ActualVersion   int
+}
+
+func (v VersionConflictError) Error() string {
+	return fmt.Sprintf("payment instruction %s is at version %d instead of the expected version %d", v.ID, v.ActualVersion, v.ExpectedVersion)
+}
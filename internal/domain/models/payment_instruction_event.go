@@ -19,6 +19,9 @@ const (
 	DomainProcessingSucceeded        PaymentInstructionEventType = "DOMAIN.PROCESSING_SUCCEEDED"
 	DomainProcessingFailed           PaymentInstructionEventType = "DOMAIN.PROCESSING_FAILED"
 	DomainRejected                   PaymentInstructionEventType = "DOMAIN.REJECTED"
+	DomainTransitionRejected         PaymentInstructionEventType = "DOMAIN.TRANSITION_REJECTED"
+	DomainAcceptedByPaymentProvider  PaymentInstructionEventType = "DOMAIN.ACCEPTED_BY_PAYMENT_PROVIDER"
+	DomainProcessingReversed         PaymentInstructionEventType = "DOMAIN.PROCESSING_REVERSED"
 )
 
 type PaymentInstructionEvent struct {
@@ -31,11 +34,21 @@ type DomainSubmittedToPaymentProviderEventDetails struct {
 	PaymentProviderType PaymentProviderType `json:"paymentProviderType"`
 }
 
+type DomainAcceptedByPaymentProviderEventDetails struct {
+	PaymentProviderPaymentID ProviderPaymentID `json:"paymentProviderPaymentID"`
+	BankingReference         BankingReference  `json:"bankingReference"`
+}
+
 type DomainProcessingSucceededEventDetails struct {
 	PaymentProviderPaymentID ProviderPaymentID `json:"paymentProviderPaymentID"`
 	// inconsistent naming convention? everything else is paymentproviderID
 }
 
+type DomainProcessingReversedEventDetails struct {
+	PaymentProviderPaymentID ProviderPaymentID `json:"paymentProviderPaymentID"`
+	BankingReference         BankingReference  `json:"bankingReference"`
+}
+
 type DomainProcessingFailedEventDetails struct {
 	FailureReason     PIFailureReason   `json:"failureReason"`
 	PaymentProviderID ProviderPaymentID `json:"paymentProviderPaymentID"`
@@ -60,6 +73,11 @@ type DomainRejectedEventDetails struct {
 	FailureReason PIFailureReason `json:"rejectionReason"`
 }
 
+type DomainTransitionRejectedEventDetails struct {
+	From PaymentInstructionStatus `json:"from"`
+	To   PaymentInstructionStatus `json:"to"`
+}
+
 type DomainFailureReasonCode string
 
 const (
@@ -70,6 +88,9 @@ const (
 	NoSourceAcct     DomainFailureReasonCode = "NO_SOURCE_ACCOUNT"
 	FailedValidation DomainFailureReasonCode = "FAILED_VALIDATION"
 	MissingFunds     DomainFailureReasonCode = "MISSING_FUNDS"
+	CancelledPayment DomainFailureReasonCode = "CANCELLED_PAYMENT"
+	// AwaitingManualAction is a payment the payment provider held for someone to act on until we stopped waiting.
+	AwaitingManualAction DomainFailureReasonCode = "AWAITING_MANUAL_ACTION"
 )
 
 type PIFailureReason struct {
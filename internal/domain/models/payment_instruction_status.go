@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// statusTransitions lists the statuses a PaymentInstruction can move to from each of its statuses.
+// REJECTED, PROCESSING_FAILED and PROCESSING_REVERSED are final, PROCESSING_SUCCEEDED can only be reversed afterwards,
+// a status missing from the table can't be moved to.
+var statusTransitions = map[PaymentInstructionStatus][]PaymentInstructionStatus{
+	Received:               {Rejected, SubmittedForProcessing, Failed},
+	SubmittedForProcessing: {StateSubmitted, Successful, Failed, Reversed},
+	StateSubmitted:         {Successful, Failed, Reversed},
+	Successful:             {Reversed},
+}
+
+// CanTransitionTo tells whether a PaymentInstruction can move from this status to the next one.
+func (s PaymentInstructionStatus) CanTransitionTo(next PaymentInstructionStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IllegalTransitionError is returned when a PaymentInstruction is asked to move to a status it can't move to
+// from its current one, e.g. a successful payment being failed afterwards.
+type IllegalTransitionError struct {
+	ID   PaymentInstructionID
+	From PaymentInstructionStatus
+	To   PaymentInstructionStatus
+}
+
+func (i IllegalTransitionError) Error() string {
+	return fmt.Sprintf("payment instruction %s can't move from status %q to status %q", i.ID, i.From, i.To)
+}
+
+// ValidateTransition returns an IllegalTransitionError if the PaymentInstruction can't move to the given status.
+func (p PaymentInstruction) ValidateTransition(next PaymentInstructionStatus) error {
+	if !p.status.CanTransitionTo(next) {
+		return IllegalTransitionError{ID: p.id, From: p.status, To: next}
+	}
+	return nil
+}
+
+// RejectTransition records that the PaymentInstruction refused to move to another status, its status stays the same.
+func (p *PaymentInstruction) RejectTransition(illegalTransition IllegalTransitionError) PaymentInstructionEvent {
+	event := PaymentInstructionEvent{
+		Type:      DomainTransitionRejected,
+		CreatedOn: time.Now(),
+		Details:   DomainTransitionRejectedEventDetails{From: illegalTransition.From, To: illegalTransition.To},
+	}
+	p.version += 1
+	p.events = append(p.events, event)
+	return event
+}
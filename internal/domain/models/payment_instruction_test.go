@@ -44,35 +44,6 @@ func TestPaymentInstruction(t *testing.T) {
 		assert.Equal(t, uniqueID, "800900#2021-08-12#234.56")
 	})
 
-	t.Run("routes the PI to the correct banking provider", func(t *testing.T) {
-		testData := []struct {
-			sender           string
-			source           string
-			currency         models.Currency
-			expectedProvider models.PaymentProviderType
-			description      string
-		}{
-			{"ISB", "Way4", models.Currency{IsoCode: "USD", IsoNumber: "840"}, models.Islandsbanki, "ISB from Way4 USD"},
-			{"ISB", "Way4", models.Currency{IsoCode: "GBP", IsoNumber: "826"}, models.Islandsbanki, "ISB from Way4 GBP"},
-			{"ISB", "", models.Currency{IsoCode: "EUR", IsoNumber: "978"}, models.Islandsbanki, "ISB from Way4 EUR"},
-			{"RB", "Way4", models.Currency{IsoCode: "ISK", IsoNumber: "352"}, models.Islandsbanki, "RB from Way4 ISK"},
-			{"RB_12", "Way4", models.Currency{IsoCode: "ISK", IsoNumber: "352"}, models.Islandsbanki, "RB from Way4 ISK"},
-			{"SAXO", "Way4", models.Currency{IsoCode: "EUR", IsoNumber: "978"}, models.BankingCircle, "SAXO from Way4 EUR"},
-			{"SAXO", "", models.Currency{IsoCode: "HUF", IsoNumber: "348"}, models.BankingCircle, "SAXO HUF"},
-			{"", "Solanteq", models.Currency{IsoCode: "ISK", IsoNumber: "352"}, models.Islandsbanki, "from Solanteq ISK"},
-			{"", "Solanteq", models.Currency{IsoCode: "EUR", IsoNumber: "978"}, models.BankingCircle, "from Solanteq EUR"},
-		}
-
-		for _, datum := range testData {
-			var (
-				incomingInstruction = testhelpers.NewIncomingInstructionBuilder().WithMetadataSender(datum.sender).WithMetadataSource(datum.source).WithCurrency(datum.currency).Build()
-				paymentInstruction  = testhelpers.NewPaymentInstructionBuilder().WithIncomingInstruction(incomingInstruction).Build()
-			)
-			paymentInstruction.RouteToPaymentProvider()
-			assert.Equal(t, datum.expectedProvider, paymentInstruction.PaymentProvider(), datum.description)
-		}
-	})
-
 	t.Run("fixes a payment instruction with IBAN in the incorrect format", func(t *testing.T) {
 		var (
 			incorrectAccountNumber = "gb 33BUKb202 0155555 5555"
@@ -83,3 +54,93 @@ func TestPaymentInstruction(t *testing.T) {
 		assert.Equal(t, "GB33BUKB20201555555555", paymentInstruction.IncomingInstruction.Merchant.Account.AccountNumber)
 	})
 }
+
+func TestPaymentInstructionStatus_CanTransitionTo(t *testing.T) {
+	testData := []struct {
+		from     models.PaymentInstructionStatus
+		to       models.PaymentInstructionStatus
+		expected bool
+	}{
+		{models.Received, models.SubmittedForProcessing, true},
+		{models.Received, models.Rejected, true},
+		{models.SubmittedForProcessing, models.StateSubmitted, true},
+		{models.SubmittedForProcessing, models.Successful, true},
+		{models.SubmittedForProcessing, models.Failed, true},
+		{models.StateSubmitted, models.Successful, true},
+		{models.StateSubmitted, models.SubmittedForProcessing, false},
+		{models.Successful, models.Failed, false},
+		{models.Successful, models.Successful, false},
+		{models.Failed, models.Successful, false},
+		{models.Rejected, models.SubmittedForProcessing, false},
+		{models.SubmittedForProcessing, "", false},
+		{models.StateSubmitted, models.Reversed, true},
+		{models.Successful, models.Reversed, true},
+		{models.Failed, models.Reversed, false},
+		{models.Reversed, models.Successful, false},
+	}
+
+	for _, data := range testData {
+		assert.Equal(t, data.expected, data.from.CanTransitionTo(data.to), "%s to %q", data.from, data.to)
+	}
+}
+
+func TestPaymentInstruction_RejectTransition(t *testing.T) {
+	paymentInstruction := testhelpers.NewPaymentInstructionBuilder().WithStatus(models.Successful).Build()
+	version := paymentInstruction.Version()
+
+	err := paymentInstruction.ValidateTransition(models.Failed)
+	require.Equal(t, models.IllegalTransitionError{ID: paymentInstruction.ID(), From: models.Successful, To: models.Failed}, err)
+
+	event := paymentInstruction.RejectTransition(err.(models.IllegalTransitionError))
+	assert.Equal(t, models.DomainTransitionRejected, event.Type)
+	assert.Equal(t, models.Successful, paymentInstruction.GetStatus())
+	assert.Equal(t, version+1, paymentInstruction.Version())
+	assert.Equal(t, event, paymentInstruction.Events()[len(paymentInstruction.Events())-1])
+}
+
+func TestPaymentInstruction_TrackPPEvent_Reversal(t *testing.T) {
+	paymentInstruction := testhelpers.NewPaymentInstructionBuilder().WithStatus(models.Successful).Build()
+	version := paymentInstruction.Version()
+
+	event, err := models.NewPaymentProviderEvent(time.Now(), models.Reversal, paymentInstruction, models.BC, "bc-payment-id", "bc-reference", nil)
+	require.NoError(t, err)
+	require.NoError(t, paymentInstruction.ValidateTransition(event.OutcomeStatus()))
+	require.NoError(t, paymentInstruction.TrackPPEvent(event))
+
+	assert.Equal(t, models.Reversed, paymentInstruction.GetStatus())
+	assert.Equal(t, version+1, paymentInstruction.Version())
+	lastEvent := paymentInstruction.Events()[len(paymentInstruction.Events())-1]
+	assert.Equal(t, models.DomainProcessingReversed, lastEvent.Type)
+	assert.Equal(t, models.DomainProcessingReversedEventDetails{PaymentProviderPaymentID: "bc-payment-id", BankingReference: "bc-reference"}, lastEvent.Details)
+}
+
+func TestPaymentInstruction_EnforcesStatusTransitions(t *testing.T) {
+	t.Run("a status the payment instruction can't move to leaves it unchanged", func(t *testing.T) {
+		paymentInstruction := testhelpers.NewPaymentInstructionBuilder().WithStatus(models.Successful).Build()
+		version := paymentInstruction.Version()
+		events := len(paymentInstruction.Events())
+
+		event, err := models.NewPaymentProviderEvent(time.Now(), models.Failure, paymentInstruction, models.BC, "bc-payment-id", "bc-reference", &models.FailureReason{Code: models.RejectedCode})
+		require.NoError(t, err)
+
+		assert.Equal(t, models.IllegalTransitionError{ID: paymentInstruction.ID(), From: models.Successful, To: models.Failed}, paymentInstruction.TrackPPEvent(event))
+		assert.Error(t, paymentInstruction.SetStatus(models.Received))
+		assert.Equal(t, models.Successful, paymentInstruction.GetStatus())
+		assert.Equal(t, version, paymentInstruction.Version())
+		assert.Len(t, paymentInstruction.Events(), events)
+	})
+
+	t.Run("moving to the status the payment instruction already has changes nothing", func(t *testing.T) {
+		paymentInstruction := testhelpers.NewPaymentInstructionBuilder().Build()
+		require.NoError(t, paymentInstruction.SubmitForProcessing())
+		version := paymentInstruction.Version()
+		events := len(paymentInstruction.Events())
+
+		require.NoError(t, paymentInstruction.SubmitForProcessing())
+		require.NoError(t, paymentInstruction.SetStatus(models.SubmittedForProcessing))
+
+		assert.Equal(t, models.SubmittedForProcessing, paymentInstruction.GetStatus())
+		assert.Equal(t, version, paymentInstruction.Version())
+		assert.Len(t, paymentInstruction.Events(), events)
+	})
+}
@@ -45,6 +45,9 @@ const (
 	TransportFailure               PPEventFailureCode = "TRANSPORT_FAILURE"
 	NoSourceAccount                PPEventFailureCode = "NO_SOURCE_ACCOUNT"
 	MissingFunding                 PPEventFailureCode = "MISSING_FUNDING"
+	CancelledCode                  PPEventFailureCode = "CANCELLED"
+	// NeedsManualAction is a payment the payment provider holds until someone acts on it, e.g. approves it.
+	NeedsManualAction PPEventFailureCode = "NEEDS_MANUAL_ACTION"
 )
 
 type PaymentProviderEventType string
@@ -53,6 +56,8 @@ const (
 	Submitted PaymentProviderEventType = "SUBMITTED"
 	Processed PaymentProviderEventType = "PROCESSED"
 	Failure   PaymentProviderEventType = "FAILURE"
+	// Reversal is a payment the payment provider took back, usually after having processed it.
+	Reversal PaymentProviderEventType = "REVERSED"
 )
 
 type (
@@ -107,3 +112,17 @@ func NewPaymentProviderEvent(
 
 	return event, nil
 }
+
+// OutcomeStatus is the status a PaymentInstruction moves to when the event is tracked.
+func (e PaymentProviderEvent) OutcomeStatus() PaymentInstructionStatus {
+	switch e.Type {
+	case Failure:
+		return Failed
+	case Submitted:
+		return StateSubmitted
+	case Reversal:
+		return Reversed
+	default:
+		return Successful
+	}
+}
@@ -16,6 +16,7 @@ type PaymentStats struct {
 	SubmittedForProcessing uint `json:"submitted_for_processing"`
 	Rejected               uint `json:"rejected"`
 	Received               uint `json:"received"`
+	Reversed               uint `json:"reversed"`
 }
 
 type FailedPayments struct {
@@ -31,6 +32,8 @@ type FailedStats struct {
 	NoSourceAcct     uint `json:"no_source_acct"`
 	FailedValidation uint `json:"failed_validation"`
 	MissingFunds     uint `json:"missing_funds"`
+	Cancelled        uint `json:"cancelled"`
+	ManualAction     uint `json:"awaiting_manual_action"`
 }
 
 type FailedInstruction struct {
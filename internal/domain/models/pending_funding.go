@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PendingFundingID string
+
+type PendingFundingState string
+
+const (
+	PendingFundingPending   PendingFundingState = "PENDING_FUNDING"
+	PendingFundingReleased  PendingFundingState = "RELEASED"
+	PendingFundingCancelled PendingFundingState = "CANCELLED"
+)
+
+// PendingFunding is a batch of instructions of one currency from a UFX file that was held back because the source
+// account did not have enough funds to pay it. It is released once the balance plus intraday loan covers Amount.
+type PendingFunding struct {
+	ID            PendingFundingID     `json:"id"`
+	Filename      string               `json:"filename"`
+	Currency      CurrencyCode         `json:"currency"`
+	HighRisk      bool                 `json:"highRisk"`
+	Amount        float64              `json:"amount"`
+	Count         int                  `json:"count"`
+	Instructions  IncomingInstructions `json:"instructions,omitempty"`
+	State         PendingFundingState  `json:"state"`
+	StateReason   string               `json:"stateReason,omitempty"`
+	Checks        int                  `json:"checks"`
+	LastCheckedAt *time.Time           `json:"lastCheckedAt,omitempty"`
+	CreatedAt     time.Time            `json:"createdAt"`
+	UpdatedAt     time.Time            `json:"updatedAt"`
+}
+
+func NewPendingFunding(filename string, summary IncomingInstructionsSummary, instructions IncomingInstructions) PendingFunding {
+	return PendingFunding{
+		ID:           PendingFundingID(uuid.NewString()),
+		Filename:     filename,
+		Currency:     summary.CurrencyCode,
+		HighRisk:     summary.HighRisk,
+		Amount:       summary.Amount,
+		Count:        summary.Counter,
+		Instructions: instructions,
+		State:        PendingFundingPending,
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// ProviderRejection is a payment its payment provider rejected after taking it, as listed in the provider's rejection report.
+type ProviderRejection struct {
+	// PaymentReference is how the payment provider refers to the payment, either its payment ID or banking reference.
+	PaymentReference string `json:"paymentReference"`
+	// ContractNumber and ExecutionDate are read from the reference the payment was requested with, they are empty when it can't be parsed.
+	ContractNumber     string       `json:"contractNumber,omitempty"`
+	ExecutionDate      time.Time    `json:"executionDate"`
+	DestinationAccount string       `json:"destinationAccount"`
+	Amount             string       `json:"amount"`
+	Currency           CurrencyCode `json:"currency"`
+	Status             string       `json:"status"`
+	StatusReason       string       `json:"statusReason"`
+}
+
+// RejectedPaymentInstruction is a payment instruction a ProviderRejection may be about, together with the payment ID
+// and banking reference from the last time the payment provider accepted it, empty when it never did.
+type RejectedPaymentInstruction struct {
+	PaymentInstruction PaymentInstruction
+	ProviderPaymentID  ProviderPaymentID
+	BankingReference   BankingReference
+}
+
+type UnmatchedRejectionReason string
+
+const (
+	// NoPaymentInstruction is a rejection none of the payment instructions sent to the payment provider fit.
+	NoPaymentInstruction UnmatchedRejectionReason = "NO_PAYMENT_INSTRUCTION"
+	// ManyPaymentInstructions is a rejection more than one payment instruction fits, it can't tell which one was rejected.
+	ManyPaymentInstructions UnmatchedRejectionReason = "MANY_PAYMENT_INSTRUCTIONS"
+	// PaymentInstructionSucceeded is a rejection of a payment instruction the payment provider reported as paid, listed by
+	// the ingestions made before such a payment instruction was reversed instead.
+	PaymentInstructionSucceeded UnmatchedRejectionReason = "PAYMENT_INSTRUCTION_SUCCEEDED"
+	// RejectionNotTracked is a rejection whose payment instruction couldn't be looked up or failed.
+	RejectionNotTracked UnmatchedRejectionReason = "NOT_TRACKED"
+)
+
+// UnmatchedRejection is a ProviderRejection that couldn't be tracked on a payment instruction, left for someone to look into.
+type UnmatchedRejection struct {
+	PaymentProvider PaymentProviderType      `json:"paymentProvider"`
+	Rejection       ProviderRejection        `json:"rejection"`
+	Reason          UnmatchedRejectionReason `json:"reason"`
+	// PaymentInstructionID is set when the rejection matched a payment instruction whose outcome contradicts it.
+	PaymentInstructionID PaymentInstructionID `json:"paymentInstructionId,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// RoutingDecision is where a routing rule sends an incoming instruction, Rule is empty when no rule matched it.
+type RoutingDecision struct {
+	Rule            string              `json:"rule,omitempty"`
+	PaymentProvider PaymentProviderType `json:"paymentProvider,omitempty"`
+	// SourceAccount is the account the payment is made from, the payment provider picks it when empty.
+	SourceAccount string `json:"sourceAccount,omitempty"`
+	// Rejection is why the incoming instruction is rejected, when the rule rejects rather than routes it.
+	Rejection string `json:"rejection,omitempty"`
+}
+
+func (d RoutingDecision) Matched() bool {
+	return d.Rule != ""
+}
+
+func (d RoutingDecision) Rejected() bool {
+	return d.Rejection != ""
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// InFlightStatuses are the statuses of a PaymentInstruction waiting for the outcome of its payment provider.
+var InFlightStatuses = []PaymentInstructionStatus{SubmittedForProcessing, StateSubmitted}
+
+// IsInFlight tells whether the payment instruction is still waiting for the outcome of its payment provider.
+func (p PaymentInstruction) IsInFlight() bool {
+	for _, status := range InFlightStatuses {
+		if p.GetStatus() == status {
+			return true
+		}
+	}
+	return false
+}
+
+// StuckPaymentInstruction is a PaymentInstruction that has been waiting on its payment provider for longer than expected.
+type StuckPaymentInstruction struct {
+	PaymentInstruction PaymentInstruction
+	// ProviderPaymentID is empty when the payment provider never confirmed it accepted the payment.
+	ProviderPaymentID ProviderPaymentID
+	BankingReference  BankingReference
+	// StuckSince is when the last event of the payment instruction was recorded.
+	StuckSince time.Time
+}
+
+// CanBeRechecked tells whether the payment provider can be asked for the outcome of the payment.
+func (s StuckPaymentInstruction) CanBeRechecked() bool {
+	return s.ProviderPaymentID != ""
+}
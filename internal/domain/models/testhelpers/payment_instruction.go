@@ -32,7 +32,7 @@ func getValidPaymentInstructionsJSON() []string {
 		`{
 		  	"id": "339aec00-771c-467e-a8c0-9056c6d2580a",
 			"version": 1,
-			"status": "CREATED",
+			"status": "RECEIVED",
 			"paymentProvider": "banking_circle",
 			"incomingInstruction": {
 				"merchant": {
@@ -78,7 +78,7 @@ func getValidPaymentInstructionsJSON() []string {
 		`{
 		  	"id": "339aec00-771c-467e-a8c0-9056c6d245645",
 		  	"version": 1, 
-			"status": "CREATED",
+			"status": "RECEIVED",
 			"paymentProvider": "islandsbanki",
 			"incomingInstruction": {
 				"merchant": {
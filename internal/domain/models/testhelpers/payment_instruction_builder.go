@@ -24,8 +24,12 @@ func (p *PaymentInstructionBuilder) WithPaymentProvider(paymentProvider models.P
 	return p
 }
 
+// WithStatus puts the payment instruction in the status directly, whether it could move there or not.
 func (p *PaymentInstructionBuilder) WithStatus(status models.PaymentInstructionStatus) *PaymentInstructionBuilder {
-	p.PaymentInstruction.SetStatus(status)
+	dto := models.NewPaymentInstructionDTO(p.PaymentInstruction)
+	dto.Status = status
+	dto.Version++
+	p.PaymentInstruction = models.NewPaymentInstructionFromDTO(dto)
 	return p
 }
 
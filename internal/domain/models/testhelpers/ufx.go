@@ -318,7 +318,7 @@ func testUfx() string {
 	<FileTrailer>
 		<CheckSum>
 			<RecsCount>1</RecsCount>
-			<HashTotalAmount>9668</HashTotalAmount>
+			<HashTotalAmount>10</HashTotalAmount>
 		</CheckSum>
 	</FileTrailer>
 </DocFile>`
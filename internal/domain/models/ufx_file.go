@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type UfxFileState string
+
+const (
+	UfxFileReceived      UfxFileState = "RECEIVED"
+	UfxFileConverted     UfxFileState = "CONVERTED"
+	UfxFilePartiallyPaid UfxFileState = "PARTIALLY_PAID"
+	UfxFileFullyPaid     UfxFileState = "FULLY_PAID"
+	UfxFileQuarantined   UfxFileState = "QUARANTINED"
+)
+
+// UfxCheckSum is the FileTrailer CheckSum of a Way4 UFX file.
+type UfxCheckSum struct {
+	RecsCount       string `json:"recsCount"`
+	HashTotalAmount string `json:"hashTotalAmount"`
+}
+
+// UfxFile is the ingestion ledger entry of a UFX file, tracking what happened to it from the moment its S3 event
+// was received until all of its payment instructions were handed to the make payment use case.
+type UfxFile struct {
+	Filename           string                        `json:"filename"`
+	Sender             string                        `json:"sender"`
+	S3Key              string                        `json:"s3Key"`
+	CheckSum           UfxCheckSum                   `json:"checkSum"`
+	RecordCount        int                           `json:"recordCount"`
+	CurrencyTotals     []IncomingInstructionsSummary `json:"currencyTotals"`
+	HeldBackCurrencies []CurrencyCode                `json:"heldBackCurrencies"`
+	State              UfxFileState                  `json:"state"`
+	StateReason        string                        `json:"stateReason,omitempty"`
+	CreatedAt          time.Time                     `json:"createdAt"`
+	UpdatedAt          time.Time                     `json:"updatedAt"`
+}
+
+// IsFullyPaid reports whether every instruction of the file was already handed over for payment,
+// in which case a re-delivery of the same file must not be processed again.
+func (f UfxFile) IsFullyPaid() bool {
+	return f.State == UfxFileFullyPaid
+}
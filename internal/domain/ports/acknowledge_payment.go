@@ -0,0 +1,14 @@
+//go:generate moq -out mocks/acknowledge_payment_moq.go -pkg=mocks . AcknowledgePayment
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// AcknowledgePayment sends the outcome of an incoming instruction back to the caller that sent it.
+type AcknowledgePayment interface {
+	Acknowledge(ctx context.Context, ack models.PaymentAck) error
+}
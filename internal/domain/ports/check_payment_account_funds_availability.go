@@ -13,4 +13,11 @@ import (
 
 type CheckPaymentAccountFundsAvailability interface {
 	Execute(ctx context.Context, code models.CurrencyCode, amount float64, highRisk bool) (bool, error)
+	// Hold checks the funds like Execute and holds the amount back in the same step when they cover it, so that files
+	// and batches checked at the same time can't both count on the same funds. The source accounts of the currency are
+	// tried in the order they are paid from, the hold returned has the account that covers it. The payment instructions
+	// of the amount reserve their own funds out of the hold, what is left of it is released with ReleaseHold once they
+	// are made.
+	Hold(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error)
+	ReleaseHold(ctx context.Context, id models.FundsHoldID)
 }
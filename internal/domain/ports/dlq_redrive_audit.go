@@ -0,0 +1,21 @@
+//go:generate moq -out mocks/dlq_redrive_audit_moq.go -pkg=mocks . DlqRedriveAudit
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type DlqRedriveAudit interface {
+	RecordDlqRedrive(ctx context.Context, redrive models.DlqRedrive) error
+	// RedrivenDlqMessageIDs are the IDs of the messages of the dead letter queue or topic the recorded redrives moved.
+	RedrivenDlqMessageIDs(ctx context.Context, queue string) ([]string, error)
+	// RecordRedrivenDlqMessage records the message as redriven before it is written back to its source, it reports false
+	// when a redrive recorded it already. A message is only recorded once, so redrives made at the same time can't both
+	// write it back.
+	RecordRedrivenDlqMessage(ctx context.Context, queue string, messageID string) (bool, error)
+	// ForgetRedrivenDlqMessage removes the record of a message that couldn't be written back, a later redrive moves it.
+	ForgetRedrivenDlqMessage(ctx context.Context, queue string, messageID string) error
+}
@@ -0,0 +1,17 @@
+//go:generate moq -out mocks/ingest_rejection_reports_moq.go -pkg=mocks . IngestRejectionReports
+
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// IngestRejectionReports tracks the payments the payment providers rejected after taking them on their payment
+// instructions, and keeps the rejections it can't match to a payment instruction for someone to look into.
+type IngestRejectionReports interface {
+	Ingest(ctx context.Context, reportDate time.Time)
+	ListUnmatched(ctx context.Context, reportDate time.Time) ([]models.UnmatchedRejection, error)
+}
@@ -0,0 +1,27 @@
+//go:generate moq -out mocks/liquidity_ledger_moq.go -pkg=mocks . LiquidityLedger
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// LiquidityLedger keeps the funds reserved by the payment instructions accepted but not paid yet, so that the files,
+// batches, Kafka payments and replays checking the funds of the same account at the same time don't count them twice.
+type LiquidityLedger interface {
+	// Reserve reserves the amount of the payment instruction, reserving it again changes nothing. The amount is taken
+	// out of the hold of the reservation, if it has one, so that the funds are not counted twice.
+	Reserve(ctx context.Context, reservation models.FundsReservation) error
+	// Release releases the reservation of the payment instruction, if it still has one.
+	Release(ctx context.Context, id models.PaymentInstructionID) error
+	// Hold holds the amount back only when the funds of its account cover it along with the outstanding reservations and
+	// holds of the account. Both are done under the lock of its currency and risk class, so holds and reservations made
+	// at the same time can't count on the same funds. Holds and reservations expire when nothing releases them.
+	Hold(ctx context.Context, hold models.FundsHold, funds float64) (bool, error)
+	// ReleaseHold releases the hold, if it is still held.
+	ReleaseHold(ctx context.Context, id models.FundsHoldID) error
+	// Reserved sums up the outstanding reservations and holds that haven't expired per currency and risk class.
+	Reserved(ctx context.Context) ([]models.ReservedFunds, error)
+}
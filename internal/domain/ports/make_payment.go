@@ -10,6 +10,7 @@ import (
 
 // MakePayment is a use case that will try to make a payment as specified in the supplied request.
 // If no error is returned, then the request to make payment is accepted.
+// A rejected or duplicated payment instruction is stored all the same, its ID is returned along with the error.
 type MakePayment interface {
 	Execute(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error)
 }
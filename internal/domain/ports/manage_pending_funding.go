@@ -0,0 +1,20 @@
+//go:generate moq -out mocks/manage_pending_funding_moq.go -pkg=mocks . ManagePendingFunding
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// ManagePendingFunding is a use case that keeps track of currency batches held back for missing funds,
+// and pays them once the funds are available or an operator decides to force them through.
+type ManagePendingFunding interface {
+	HoldBack(ctx context.Context, filename string, summary models.IncomingInstructionsSummary, instructions models.IncomingInstructions) (models.PendingFunding, error)
+	List(ctx context.Context, state models.PendingFundingState) ([]models.PendingFunding, error)
+	Get(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error)
+	RecheckAll(ctx context.Context)
+	Release(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error)
+	Cancel(ctx context.Context, id models.PendingFundingID, reason string) (models.PendingFunding, error)
+}
@@ -8,6 +8,7 @@ import "context"
 type MetricsClient interface {
 	Histogram(ctx context.Context, name string, value float64, tags []string)
 	Count(ctx context.Context, name string, value int64, tags []string)
+	Gauge(ctx context.Context, name string, value float64, tags []string)
 }
 
 const (
@@ -23,6 +23,12 @@ var _ ports.CheckPaymentAccountFundsAvailability = &CheckPaymentAccountFundsAvai
 // 			ExecuteFunc: func(ctx context.Context, code models.CurrencyCode, amount float64, highRisk bool) (bool, error) {
 // 				panic("mock out the Execute method")
 // 			},
+// 			HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+// 				panic("mock out the Hold method")
+// 			},
+// 			ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {
+// 				panic("mock out the ReleaseHold method")
+// 			},
 // 		}
 //
 // 		// use mockedCheckPaymentAccountFundsAvailability in code that requires ports.CheckPaymentAccountFundsAvailability
@@ -33,6 +39,12 @@ type CheckPaymentAccountFundsAvailabilityMock struct {
 	// ExecuteFunc mocks the Execute method.
 	ExecuteFunc func(ctx context.Context, code models.CurrencyCode, amount float64, highRisk bool) (bool, error)
 
+	// HoldFunc mocks the Hold method.
+	HoldFunc func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error)
+
+	// ReleaseHoldFunc mocks the ReleaseHold method.
+	ReleaseHoldFunc func(ctx context.Context, id models.FundsHoldID)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Execute holds details about calls to the Execute method.
@@ -46,8 +58,30 @@ type CheckPaymentAccountFundsAvailabilityMock struct {
 			// HighRisk is the highRisk argument value.
 			HighRisk bool
 		}
+		// Hold holds details about calls to the Hold method.
+		Hold []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID models.FundsHoldID
+			// Code is the code argument value.
+			Code models.CurrencyCode
+			// Amount is the amount argument value.
+			Amount float64
+			// HighRisk is the highRisk argument value.
+			HighRisk bool
+		}
+		// ReleaseHold holds details about calls to the ReleaseHold method.
+		ReleaseHold []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID models.FundsHoldID
+		}
 	}
-	lockExecute sync.RWMutex
+	lockExecute     sync.RWMutex
+	lockHold        sync.RWMutex
+	lockReleaseHold sync.RWMutex
 }
 
 // Execute calls ExecuteFunc.
@@ -92,3 +126,85 @@ func (mock *CheckPaymentAccountFundsAvailabilityMock) ExecuteCalls() []struct {
 	mock.lockExecute.RUnlock()
 	return calls
 }
+
+// Hold calls HoldFunc.
+func (mock *CheckPaymentAccountFundsAvailabilityMock) Hold(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+	if mock.HoldFunc == nil {
+		panic("CheckPaymentAccountFundsAvailabilityMock.HoldFunc: method is nil but CheckPaymentAccountFundsAvailability.Hold was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		ID       models.FundsHoldID
+		Code     models.CurrencyCode
+		Amount   float64
+		HighRisk bool
+	}{
+		Ctx:      ctx,
+		ID:       id,
+		Code:     code,
+		Amount:   amount,
+		HighRisk: highRisk,
+	}
+	mock.lockHold.Lock()
+	mock.calls.Hold = append(mock.calls.Hold, callInfo)
+	mock.lockHold.Unlock()
+	return mock.HoldFunc(ctx, id, code, amount, highRisk)
+}
+
+// HoldCalls gets all the calls that were made to Hold.
+// Check the length with:
+//     len(mockedCheckPaymentAccountFundsAvailability.HoldCalls())
+func (mock *CheckPaymentAccountFundsAvailabilityMock) HoldCalls() []struct {
+	Ctx      context.Context
+	ID       models.FundsHoldID
+	Code     models.CurrencyCode
+	Amount   float64
+	HighRisk bool
+} {
+	var calls []struct {
+		Ctx      context.Context
+		ID       models.FundsHoldID
+		Code     models.CurrencyCode
+		Amount   float64
+		HighRisk bool
+	}
+	mock.lockHold.RLock()
+	calls = mock.calls.Hold
+	mock.lockHold.RUnlock()
+	return calls
+}
+
+// ReleaseHold calls ReleaseHoldFunc.
+func (mock *CheckPaymentAccountFundsAvailabilityMock) ReleaseHold(ctx context.Context, id models.FundsHoldID) {
+	if mock.ReleaseHoldFunc == nil {
+		panic("CheckPaymentAccountFundsAvailabilityMock.ReleaseHoldFunc: method is nil but CheckPaymentAccountFundsAvailability.ReleaseHold was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  models.FundsHoldID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockReleaseHold.Lock()
+	mock.calls.ReleaseHold = append(mock.calls.ReleaseHold, callInfo)
+	mock.lockReleaseHold.Unlock()
+	mock.ReleaseHoldFunc(ctx, id)
+}
+
+// ReleaseHoldCalls gets all the calls that were made to ReleaseHold.
+// Check the length with:
+//     len(mockedCheckPaymentAccountFundsAvailability.ReleaseHoldCalls())
+func (mock *CheckPaymentAccountFundsAvailabilityMock) ReleaseHoldCalls() []struct {
+	Ctx context.Context
+	ID  models.FundsHoldID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  models.FundsHoldID
+	}
+	mock.lockReleaseHold.RLock()
+	calls = mock.calls.ReleaseHold
+	mock.lockReleaseHold.RUnlock()
+	return calls
+}
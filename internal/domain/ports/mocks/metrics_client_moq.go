@@ -22,6 +22,9 @@ var _ ports.MetricsClient = &MetricsClientMock{}
 // 			CountFunc: func(ctx context.Context, name string, value int64, tags []string)  {
 // 				panic("mock out the Count method")
 // 			},
+// 			GaugeFunc: func(ctx context.Context, name string, value float64, tags []string)  {
+// 				panic("mock out the Gauge method")
+// 			},
 // 			HistogramFunc: func(ctx context.Context, name string, value float64, tags []string)  {
 // 				panic("mock out the Histogram method")
 // 			},
@@ -35,6 +38,9 @@ type MetricsClientMock struct {
 	// CountFunc mocks the Count method.
 	CountFunc func(ctx context.Context, name string, value int64, tags []string)
 
+	// GaugeFunc mocks the Gauge method.
+	GaugeFunc func(ctx context.Context, name string, value float64, tags []string)
+
 	// HistogramFunc mocks the Histogram method.
 	HistogramFunc func(ctx context.Context, name string, value float64, tags []string)
 
@@ -51,6 +57,17 @@ type MetricsClientMock struct {
 			// Tags is the tags argument value.
 			Tags []string
 		}
+		// Gauge holds details about calls to the Gauge method.
+		Gauge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value float64
+			// Tags is the tags argument value.
+			Tags []string
+		}
 		// Histogram holds details about calls to the Histogram method.
 		Histogram []struct {
 			// Ctx is the ctx argument value.
@@ -64,6 +81,7 @@ type MetricsClientMock struct {
 		}
 	}
 	lockCount     sync.RWMutex
+	lockGauge     sync.RWMutex
 	lockHistogram sync.RWMutex
 }
 
@@ -110,6 +128,49 @@ func (mock *MetricsClientMock) CountCalls() []struct {
 	return calls
 }
 
+// Gauge calls GaugeFunc.
+func (mock *MetricsClientMock) Gauge(ctx context.Context, name string, value float64, tags []string) {
+	if mock.GaugeFunc == nil {
+		panic("MetricsClientMock.GaugeFunc: method is nil but MetricsClient.Gauge was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Name  string
+		Value float64
+		Tags  []string
+	}{
+		Ctx:   ctx,
+		Name:  name,
+		Value: value,
+		Tags:  tags,
+	}
+	mock.lockGauge.Lock()
+	mock.calls.Gauge = append(mock.calls.Gauge, callInfo)
+	mock.lockGauge.Unlock()
+	mock.GaugeFunc(ctx, name, value, tags)
+}
+
+// GaugeCalls gets all the calls that were made to Gauge.
+// Check the length with:
+//     len(mockedMetricsClient.GaugeCalls())
+func (mock *MetricsClientMock) GaugeCalls() []struct {
+	Ctx   context.Context
+	Name  string
+	Value float64
+	Tags  []string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Name  string
+		Value float64
+		Tags  []string
+	}
+	mock.lockGauge.RLock()
+	calls = mock.calls.Gauge
+	mock.lockGauge.RUnlock()
+	return calls
+}
+
 // Histogram calls HistogramFunc.
 func (mock *MetricsClientMock) Histogram(ctx context.Context, name string, value float64, tags []string) {
 	if mock.HistogramFunc == nil {
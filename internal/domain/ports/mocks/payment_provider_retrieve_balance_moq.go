@@ -19,9 +19,12 @@ var _ ports.PaymentProviderRetrieveBalance = &PaymentProviderRetrieveBalanceMock
 //
 // 		// make and configure a mocked ports.PaymentProviderRetrieveBalance
 // 		mockedPaymentProviderRetrieveBalance := &PaymentProviderRetrieveBalanceMock{
-// 			RetrieveBalanceForCurrencyFunc: func(currency models.CurrencyCode, highRisk bool) (float64, float64, error) {
+// 			RetrieveBalanceForCurrencyFunc: func(currency models.CurrencyCode, highRisk bool, amount float64) (float64, float64, error) {
 // 				panic("mock out the RetrieveBalanceForCurrency method")
 // 			},
+// 			RetrieveAccountsFundsFunc: func(currency models.CurrencyCode, highRisk bool) ([]models.AccountFunds, error) {
+// 				panic("mock out the RetrieveAccountsFunds method")
+// 			},
 // 		}
 //
 // 		// use mockedPaymentProviderRetrieveBalance in code that requires ports.PaymentProviderRetrieveBalance
@@ -30,7 +33,10 @@ var _ ports.PaymentProviderRetrieveBalance = &PaymentProviderRetrieveBalanceMock
 // 	}
 type PaymentProviderRetrieveBalanceMock struct {
 	// RetrieveBalanceForCurrencyFunc mocks the RetrieveBalanceForCurrency method.
-	RetrieveBalanceForCurrencyFunc func(currency models.CurrencyCode, highRisk bool) (float64, float64, error)
+	RetrieveBalanceForCurrencyFunc func(currency models.CurrencyCode, highRisk bool, amount float64) (float64, float64, error)
+
+	// RetrieveAccountsFundsFunc mocks the RetrieveAccountsFunds method.
+	RetrieveAccountsFundsFunc func(currency models.CurrencyCode, highRisk bool) ([]models.AccountFunds, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -40,42 +46,93 @@ type PaymentProviderRetrieveBalanceMock struct {
 			Currency models.CurrencyCode
 			// HighRisk is the highRisk argument value.
 			HighRisk bool
+			// Amount is the amount argument value.
+			Amount float64
+		}
+		// RetrieveAccountsFunds holds details about calls to the RetrieveAccountsFunds method.
+		RetrieveAccountsFunds []struct {
+			// Currency is the currency argument value.
+			Currency models.CurrencyCode
+			// HighRisk is the highRisk argument value.
+			HighRisk bool
 		}
 	}
 	lockRetrieveBalanceForCurrency sync.RWMutex
+	lockRetrieveAccountsFunds      sync.RWMutex
 }
 
 // RetrieveBalanceForCurrency calls RetrieveBalanceForCurrencyFunc.
-func (mock *PaymentProviderRetrieveBalanceMock) RetrieveBalanceForCurrency(currency models.CurrencyCode, highRisk bool) (float64, float64, error) {
+func (mock *PaymentProviderRetrieveBalanceMock) RetrieveBalanceForCurrency(currency models.CurrencyCode, highRisk bool, amount float64) (float64, float64, error) {
 	if mock.RetrieveBalanceForCurrencyFunc == nil {
 		panic("PaymentProviderRetrieveBalanceMock.RetrieveBalanceForCurrencyFunc: method is nil but PaymentProviderRetrieveBalance.RetrieveBalanceForCurrency was just called")
 	}
 	callInfo := struct {
 		Currency models.CurrencyCode
 		HighRisk bool
+		Amount   float64
 	}{
 		Currency: currency,
 		HighRisk: highRisk,
+		Amount:   amount,
 	}
 	mock.lockRetrieveBalanceForCurrency.Lock()
 	mock.calls.RetrieveBalanceForCurrency = append(mock.calls.RetrieveBalanceForCurrency, callInfo)
 	mock.lockRetrieveBalanceForCurrency.Unlock()
-	return mock.RetrieveBalanceForCurrencyFunc(currency, highRisk)
+	return mock.RetrieveBalanceForCurrencyFunc(currency, highRisk, amount)
 }
 
 // RetrieveBalanceForCurrencyCalls gets all the calls that were made to RetrieveBalanceForCurrency.
 // Check the length with:
-//     len(mockedPaymentProviderRetrieveBalance.RetrieveBalanceForCurrencyCalls())
+//
+// 	len(mockedPaymentProviderRetrieveBalance.RetrieveBalanceForCurrencyCalls())
 func (mock *PaymentProviderRetrieveBalanceMock) RetrieveBalanceForCurrencyCalls() []struct {
 	Currency models.CurrencyCode
 	HighRisk bool
+	Amount   float64
 } {
 	var calls []struct {
 		Currency models.CurrencyCode
 		HighRisk bool
+		Amount   float64
 	}
 	mock.lockRetrieveBalanceForCurrency.RLock()
 	calls = mock.calls.RetrieveBalanceForCurrency
 	mock.lockRetrieveBalanceForCurrency.RUnlock()
 	return calls
 }
+
+// RetrieveAccountsFunds calls RetrieveAccountsFundsFunc.
+func (mock *PaymentProviderRetrieveBalanceMock) RetrieveAccountsFunds(currency models.CurrencyCode, highRisk bool) ([]models.AccountFunds, error) {
+	if mock.RetrieveAccountsFundsFunc == nil {
+		panic("PaymentProviderRetrieveBalanceMock.RetrieveAccountsFundsFunc: method is nil but PaymentProviderRetrieveBalance.RetrieveAccountsFunds was just called")
+	}
+	callInfo := struct {
+		Currency models.CurrencyCode
+		HighRisk bool
+	}{
+		Currency: currency,
+		HighRisk: highRisk,
+	}
+	mock.lockRetrieveAccountsFunds.Lock()
+	mock.calls.RetrieveAccountsFunds = append(mock.calls.RetrieveAccountsFunds, callInfo)
+	mock.lockRetrieveAccountsFunds.Unlock()
+	return mock.RetrieveAccountsFundsFunc(currency, highRisk)
+}
+
+// RetrieveAccountsFundsCalls gets all the calls that were made to RetrieveAccountsFunds.
+// Check the length with:
+//
+// 	len(mockedPaymentProviderRetrieveBalance.RetrieveAccountsFundsCalls())
+func (mock *PaymentProviderRetrieveBalanceMock) RetrieveAccountsFundsCalls() []struct {
+	Currency models.CurrencyCode
+	HighRisk bool
+} {
+	var calls []struct {
+		Currency models.CurrencyCode
+		HighRisk bool
+	}
+	mock.lockRetrieveAccountsFunds.RLock()
+	calls = mock.calls.RetrieveAccountsFunds
+	mock.lockRetrieveAccountsFunds.RUnlock()
+	return calls
+}
@@ -20,12 +20,21 @@ var _ ports.StorePaymentInstructionToRepo = &StorePaymentInstructionToRepoMock{}
 //
 // 		// make and configure a mocked ports.StorePaymentInstructionToRepo
 // 		mockedStorePaymentInstructionToRepo := &StorePaymentInstructionToRepoMock{
+// 			GetFromIdempotencyKeyFunc: func(ctx context.Context, key string) (models.PaymentInstruction, error) {
+// 				panic("mock out the GetFromIdempotencyKey method")
+// 			},
 // 			StoreFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
 // 				panic("mock out the Store method")
 // 			},
-// 			UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+// 			StoreForDispatchFunc: func(ctx context.Context, instruction models.PaymentInstruction) error {
+// 				panic("mock out the StoreForDispatch method")
+// 			},
+// 			UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 // 				panic("mock out the UpdatePayment method")
 // 			},
+// 			StoreForDispatchReservingFunc: func(ctx context.Context, instruction models.PaymentInstruction, reservation models.FundsReservation) error {
+// 				panic("mock out the StoreForDispatchReserving method")
+// 			},
 // 		}
 //
 // 		// use mockedStorePaymentInstructionToRepo in code that requires ports.StorePaymentInstructionToRepo
@@ -33,14 +42,30 @@ var _ ports.StorePaymentInstructionToRepo = &StorePaymentInstructionToRepoMock{}
 //
 // 	}
 type StorePaymentInstructionToRepoMock struct {
+	// GetFromIdempotencyKeyFunc mocks the GetFromIdempotencyKey method.
+	GetFromIdempotencyKeyFunc func(ctx context.Context, key string) (models.PaymentInstruction, error)
+
 	// StoreFunc mocks the Store method.
 	StoreFunc func(ctx context.Context, instruction models.PaymentInstruction) error
 
+	// StoreForDispatchFunc mocks the StoreForDispatch method.
+	StoreForDispatchFunc func(ctx context.Context, instruction models.PaymentInstruction) error
+
 	// UpdatePaymentFunc mocks the UpdatePayment method.
-	UpdatePaymentFunc func(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error
+	UpdatePaymentFunc func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error
+
+	// StoreForDispatchReservingFunc mocks the StoreForDispatchReserving method.
+	StoreForDispatchReservingFunc func(ctx context.Context, instruction models.PaymentInstruction, reservation models.FundsReservation) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// GetFromIdempotencyKey holds details about calls to the GetFromIdempotencyKey method.
+		GetFromIdempotencyKey []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
 		// Store holds details about calls to the Store method.
 		Store []struct {
 			// Ctx is the ctx argument value.
@@ -48,20 +73,77 @@ type StorePaymentInstructionToRepoMock struct {
 			// Instruction is the instruction argument value.
 			Instruction models.PaymentInstruction
 		}
+		// StoreForDispatch holds details about calls to the StoreForDispatch method.
+		StoreForDispatch []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Instruction is the instruction argument value.
+			Instruction models.PaymentInstruction
+		}
 		// UpdatePayment holds details about calls to the UpdatePayment method.
 		UpdatePayment []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 			// ID is the id argument value.
 			ID models.PaymentInstructionID
+			// ExpectedVersion is the expectedVersion argument value.
+			ExpectedVersion int
 			// Status is the status argument value.
 			Status models.PaymentInstructionStatus
 			// Event is the event argument value.
 			Event models.PaymentInstructionEvent
 		}
+		// StoreForDispatchReserving holds details about calls to the StoreForDispatchReserving method.
+		StoreForDispatchReserving []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Instruction is the instruction argument value.
+			Instruction models.PaymentInstruction
+			// Reservation is the reservation argument value.
+			Reservation models.FundsReservation
+		}
+	}
+	lockGetFromIdempotencyKey     sync.RWMutex
+	lockStore                     sync.RWMutex
+	lockStoreForDispatch          sync.RWMutex
+	lockUpdatePayment             sync.RWMutex
+	lockStoreForDispatchReserving sync.RWMutex
+}
+
+// GetFromIdempotencyKey calls GetFromIdempotencyKeyFunc.
+func (mock *StorePaymentInstructionToRepoMock) GetFromIdempotencyKey(ctx context.Context, key string) (models.PaymentInstruction, error) {
+	if mock.GetFromIdempotencyKeyFunc == nil {
+		panic("StorePaymentInstructionToRepoMock.GetFromIdempotencyKeyFunc: method is nil but StorePaymentInstructionToRepo.GetFromIdempotencyKey was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockGetFromIdempotencyKey.Lock()
+	mock.calls.GetFromIdempotencyKey = append(mock.calls.GetFromIdempotencyKey, callInfo)
+	mock.lockGetFromIdempotencyKey.Unlock()
+	return mock.GetFromIdempotencyKeyFunc(ctx, key)
+}
+
+// GetFromIdempotencyKeyCalls gets all the calls that were made to GetFromIdempotencyKey.
+// Check the length with:
+//
+// 	len(mockedStorePaymentInstructionToRepo.GetFromIdempotencyKeyCalls())
+func (mock *StorePaymentInstructionToRepoMock) GetFromIdempotencyKeyCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
 	}
-	lockStore         sync.RWMutex
-	lockUpdatePayment sync.RWMutex
+	mock.lockGetFromIdempotencyKey.RLock()
+	calls = mock.calls.GetFromIdempotencyKey
+	mock.lockGetFromIdempotencyKey.RUnlock()
+	return calls
 }
 
 // Store calls StoreFunc.
@@ -84,7 +166,8 @@ func (mock *StorePaymentInstructionToRepoMock) Store(ctx context.Context, instru
 
 // StoreCalls gets all the calls that were made to Store.
 // Check the length with:
-//     len(mockedStorePaymentInstructionToRepo.StoreCalls())
+//
+// 	len(mockedStorePaymentInstructionToRepo.StoreCalls())
 func (mock *StorePaymentInstructionToRepoMock) StoreCalls() []struct {
 	Ctx         context.Context
 	Instruction models.PaymentInstruction
@@ -99,45 +182,126 @@ func (mock *StorePaymentInstructionToRepoMock) StoreCalls() []struct {
 	return calls
 }
 
+// StoreForDispatch calls StoreForDispatchFunc.
+func (mock *StorePaymentInstructionToRepoMock) StoreForDispatch(ctx context.Context, instruction models.PaymentInstruction) error {
+	if mock.StoreForDispatchFunc == nil {
+		panic("StorePaymentInstructionToRepoMock.StoreForDispatchFunc: method is nil but StorePaymentInstructionToRepo.StoreForDispatch was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Instruction models.PaymentInstruction
+	}{
+		Ctx:         ctx,
+		Instruction: instruction,
+	}
+	mock.lockStoreForDispatch.Lock()
+	mock.calls.StoreForDispatch = append(mock.calls.StoreForDispatch, callInfo)
+	mock.lockStoreForDispatch.Unlock()
+	return mock.StoreForDispatchFunc(ctx, instruction)
+}
+
+// StoreForDispatchCalls gets all the calls that were made to StoreForDispatch.
+// Check the length with:
+//
+// 	len(mockedStorePaymentInstructionToRepo.StoreForDispatchCalls())
+func (mock *StorePaymentInstructionToRepoMock) StoreForDispatchCalls() []struct {
+	Ctx         context.Context
+	Instruction models.PaymentInstruction
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Instruction models.PaymentInstruction
+	}
+	mock.lockStoreForDispatch.RLock()
+	calls = mock.calls.StoreForDispatch
+	mock.lockStoreForDispatch.RUnlock()
+	return calls
+}
+
 // UpdatePayment calls UpdatePaymentFunc.
-func (mock *StorePaymentInstructionToRepoMock) UpdatePayment(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+func (mock *StorePaymentInstructionToRepoMock) UpdatePayment(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
 	if mock.UpdatePaymentFunc == nil {
 		panic("StorePaymentInstructionToRepoMock.UpdatePaymentFunc: method is nil but StorePaymentInstructionToRepo.UpdatePayment was just called")
 	}
 	callInfo := struct {
-		Ctx    context.Context
-		ID     models.PaymentInstructionID
-		Status models.PaymentInstructionStatus
-		Event  models.PaymentInstructionEvent
+		Ctx             context.Context
+		ID              models.PaymentInstructionID
+		ExpectedVersion int
+		Status          models.PaymentInstructionStatus
+		Event           models.PaymentInstructionEvent
 	}{
-		Ctx:    ctx,
-		ID:     id,
-		Status: status,
-		Event:  event,
+		Ctx:             ctx,
+		ID:              id,
+		ExpectedVersion: expectedVersion,
+		Status:          status,
+		Event:           event,
 	}
 	mock.lockUpdatePayment.Lock()
 	mock.calls.UpdatePayment = append(mock.calls.UpdatePayment, callInfo)
 	mock.lockUpdatePayment.Unlock()
-	return mock.UpdatePaymentFunc(ctx, id, status, event)
+	return mock.UpdatePaymentFunc(ctx, id, expectedVersion, status, event)
 }
 
 // UpdatePaymentCalls gets all the calls that were made to UpdatePayment.
 // Check the length with:
-//     len(mockedStorePaymentInstructionToRepo.UpdatePaymentCalls())
+//
+// 	len(mockedStorePaymentInstructionToRepo.UpdatePaymentCalls())
 func (mock *StorePaymentInstructionToRepoMock) UpdatePaymentCalls() []struct {
-	Ctx    context.Context
-	ID     models.PaymentInstructionID
-	Status models.PaymentInstructionStatus
-	Event  models.PaymentInstructionEvent
+	Ctx             context.Context
+	ID              models.PaymentInstructionID
+	ExpectedVersion int
+	Status          models.PaymentInstructionStatus
+	Event           models.PaymentInstructionEvent
 } {
 	var calls []struct {
-		Ctx    context.Context
-		ID     models.PaymentInstructionID
-		Status models.PaymentInstructionStatus
-		Event  models.PaymentInstructionEvent
+		Ctx             context.Context
+		ID              models.PaymentInstructionID
+		ExpectedVersion int
+		Status          models.PaymentInstructionStatus
+		Event           models.PaymentInstructionEvent
 	}
 	mock.lockUpdatePayment.RLock()
 	calls = mock.calls.UpdatePayment
 	mock.lockUpdatePayment.RUnlock()
 	return calls
 }
+
+// StoreForDispatchReserving calls StoreForDispatchReservingFunc.
+func (mock *StorePaymentInstructionToRepoMock) StoreForDispatchReserving(ctx context.Context, instruction models.PaymentInstruction, reservation models.FundsReservation) error {
+	if mock.StoreForDispatchReservingFunc == nil {
+		panic("StorePaymentInstructionToRepoMock.StoreForDispatchReservingFunc: method is nil but StorePaymentInstructionToRepo.StoreForDispatchReserving was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Instruction models.PaymentInstruction
+		Reservation models.FundsReservation
+	}{
+		Ctx:         ctx,
+		Instruction: instruction,
+		Reservation: reservation,
+	}
+	mock.lockStoreForDispatchReserving.Lock()
+	mock.calls.StoreForDispatchReserving = append(mock.calls.StoreForDispatchReserving, callInfo)
+	mock.lockStoreForDispatchReserving.Unlock()
+	return mock.StoreForDispatchReservingFunc(ctx, instruction, reservation)
+}
+
+// StoreForDispatchReservingCalls gets all the calls that were made to StoreForDispatchReserving.
+// Check the length with:
+//
+// 	len(mockedStorePaymentInstructionToRepo.StoreForDispatchReservingCalls())
+func (mock *StorePaymentInstructionToRepoMock) StoreForDispatchReservingCalls() []struct {
+	Ctx         context.Context
+	Instruction models.PaymentInstruction
+	Reservation models.FundsReservation
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Instruction models.PaymentInstruction
+		Reservation models.FundsReservation
+	}
+	mock.lockStoreForDispatchReserving.RLock()
+	calls = mock.calls.StoreForDispatchReserving
+	mock.lockStoreForDispatchReserving.RUnlock()
+	return calls
+}
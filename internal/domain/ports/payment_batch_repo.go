@@ -0,0 +1,15 @@
+//go:generate moq -out mocks/payment_batch_repo_moq.go -pkg=mocks . PaymentBatchRepo
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type PaymentBatchRepo interface {
+	// SavePaymentBatch stores the batch, or replaces the stored one with the same ID.
+	SavePaymentBatch(ctx context.Context, batch models.PaymentBatch) error
+	GetPaymentBatch(ctx context.Context, id models.PaymentBatchID) (models.PaymentBatch, error)
+}
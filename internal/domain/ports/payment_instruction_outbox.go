@@ -0,0 +1,20 @@
+//go:generate moq -out mocks/payment_instruction_outbox_moq.go -pkg=mocks . PaymentInstructionOutbox
+
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// PaymentInstructionOutbox holds the payment instructions stored for dispatch until they are handed to their payment provider.
+type PaymentInstructionOutbox interface {
+	// ClaimOutboxEntries returns up to limit entries due for dispatch and hides them from other claims for the lease,
+	// an entry that is neither marked dispatched nor failed within the lease is claimed again.
+	ClaimOutboxEntries(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEntry, error)
+	MarkOutboxEntryDispatched(ctx context.Context, id models.OutboxEntryID) error
+	// MarkOutboxEntryFailed keeps the entry for another dispatch once retryAt has passed.
+	MarkOutboxEntryFailed(ctx context.Context, id models.OutboxEntryID, retryAt time.Time, reason string) error
+}
@@ -0,0 +1,15 @@
+//go:generate moq -out mocks/payment_provider_rejection_report_moq.go -pkg=mocks . PaymentProviderRejectionReport
+
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type PaymentProviderRejectionReport interface {
+	// GetRejections returns the payments the payment provider rejected on the date.
+	GetRejections(ctx context.Context, date time.Time) ([]models.ProviderRejection, error)
+}
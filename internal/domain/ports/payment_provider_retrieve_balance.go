@@ -6,6 +6,10 @@ import "github.com/saltpay/settlements-payments-system/internal/domain/models"
 
 // PaymentProviderRetrieveBalance.
 type PaymentProviderRetrieveBalance interface {
-	// RetrieveBalanceForCurrency will fetch the balance for a certain currency and high risk value
-	RetrieveBalanceForCurrency(currency models.CurrencyCode, highRisk bool) (float64, float64, error)
+	// RetrieveBalanceForCurrency will fetch the balance for a certain currency and high risk value, of the account
+	// that would pay the amount
+	RetrieveBalanceForCurrency(currency models.CurrencyCode, highRisk bool, amount float64) (float64, float64, error)
+	// RetrieveAccountsFunds fetches the funds of each source account of a currency and high risk value, in the order
+	// the payment provider pays from them
+	RetrieveAccountsFunds(currency models.CurrencyCode, highRisk bool) ([]models.AccountFunds, error)
 }
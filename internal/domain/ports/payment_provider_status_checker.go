@@ -0,0 +1,15 @@
+//go:generate moq -out mocks/payment_provider_status_checker_moq.go -pkg=mocks . PaymentProviderStatusChecker
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// PaymentProviderStatusChecker asks a payment provider for the outcome of a payment it accepted.
+type PaymentProviderStatusChecker interface {
+	// CheckPaymentStatus returns the event of the outcome of the payment, settled is false while the payment provider is still processing it.
+	CheckPaymentStatus(ctx context.Context, stuck models.StuckPaymentInstruction) (event models.PaymentProviderEvent, settled bool, err error)
+}
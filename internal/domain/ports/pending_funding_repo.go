@@ -0,0 +1,18 @@
+//go:generate moq -out mocks/pending_funding_repo_moq.go -pkg=mocks . PendingFundingRepo
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type PendingFundingRepo interface {
+	SavePendingFunding(ctx context.Context, pendingFunding models.PendingFunding) error
+	GetPendingFunding(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error)
+	ListPendingFundings(ctx context.Context, state models.PendingFundingState) ([]models.PendingFunding, error)
+	RecordPendingFundingCheck(ctx context.Context, id models.PendingFundingID) error
+	// UpdatePendingFundingState moves a pending funding from one state to another, failing if it is no longer in the `from` state.
+	UpdatePendingFundingState(ctx context.Context, id models.PendingFundingID, from, to models.PendingFundingState, reason string) error
+}
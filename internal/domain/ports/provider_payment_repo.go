@@ -0,0 +1,15 @@
+//go:generate moq -out mocks/provider_payment_repo_moq.go -pkg=mocks . ProviderPaymentRepo
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type ProviderPaymentRepo interface {
+	// GetFromProviderPaymentID returns the payment instruction the payment provider accepted under its payment ID,
+	// together with the banking reference it was accepted with.
+	GetFromProviderPaymentID(ctx context.Context, provider models.PaymentProviderType, providerPaymentID models.ProviderPaymentID) (models.PaymentInstruction, models.BankingReference, error)
+}
@@ -0,0 +1,23 @@
+//go:generate moq -out mocks/rejected_payment_repo_moq.go -pkg=mocks . RejectedPaymentRepo
+
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type RejectedPaymentRepo interface {
+	// GetFromProviderReference returns the payment instructions the payment provider accepted under the reference,
+	// either as its payment ID or its banking reference.
+	GetFromProviderReference(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error)
+	// GetFromContract returns the payment instructions of the payment provider paying the contract on the execution date.
+	GetFromContract(ctx context.Context, provider models.PaymentProviderType, contractNumber string, executionDate time.Time) ([]models.RejectedPaymentInstruction, error)
+	// GetFromDestination returns the payment instructions of the payment provider paying the amount to the account on the execution date.
+	GetFromDestination(ctx context.Context, provider models.PaymentProviderType, accountNumber string, currency models.CurrencyCode, amount string, executionDate time.Time) ([]models.RejectedPaymentInstruction, error)
+	// SaveUnmatchedRejections replaces the unmatched rejections of the payment provider's report of the date.
+	SaveUnmatchedRejections(ctx context.Context, provider models.PaymentProviderType, reportDate time.Time, rejections []models.UnmatchedRejection) error
+	GetUnmatchedRejections(ctx context.Context, reportDate time.Time) ([]models.UnmatchedRejection, error)
+}
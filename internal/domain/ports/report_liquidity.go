@@ -0,0 +1,15 @@
+//go:generate moq -out mocks/report_liquidity_moq.go -pkg=mocks . ReportLiquidity
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type ReportLiquidity interface {
+	// Headroom reports the headroom of the accounts of the currencies, or of the ones with outstanding reservations
+	// when no currency is given.
+	Headroom(ctx context.Context, currencies []models.CurrencyCode) ([]models.LiquidityHeadroom, error)
+}
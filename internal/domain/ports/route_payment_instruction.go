@@ -0,0 +1,12 @@
+//go:generate moq -out mocks/route_payment_instruction_moq.go -pkg=mocks . RoutePaymentInstruction
+
+package ports
+
+import (
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// RoutePaymentInstruction decides which payment provider makes the payment of an incoming instruction.
+type RoutePaymentInstruction interface {
+	Route(instruction models.IncomingInstruction) models.RoutingDecision
+}
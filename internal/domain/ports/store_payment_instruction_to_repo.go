@@ -10,5 +10,14 @@ import (
 
 type StorePaymentInstructionToRepo interface {
 	Store(ctx context.Context, instruction models.PaymentInstruction) error
-	UpdatePayment(ctx context.Context, id models.PaymentInstructionID, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error
+	// StoreForDispatch stores the PaymentInstruction together with an entry in the PaymentInstructionOutbox, in the same transaction,
+	// so it is handed to its payment provider if and only if it is stored.
+	StoreForDispatch(ctx context.Context, instruction models.PaymentInstruction) error
+	// StoreForDispatchReserving stores the PaymentInstruction like StoreForDispatch and reserves its funds in the same
+	// transaction, so they are reserved before it can be handed to its payment provider and its outcome release them.
+	StoreForDispatchReserving(ctx context.Context, instruction models.PaymentInstruction, reservation models.FundsReservation) error
+	// UpdatePayment fails with a models.VersionConflictError when the stored PaymentInstruction is not at the expected version.
+	UpdatePayment(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error
+	// GetFromIdempotencyKey returns the PaymentInstruction stored with the idempotency key, with its models.IdempotencyKey set.
+	GetFromIdempotencyKey(ctx context.Context, key string) (models.PaymentInstruction, error)
 }
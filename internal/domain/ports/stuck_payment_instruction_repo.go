@@ -0,0 +1,16 @@
+//go:generate moq -out mocks/stuck_payment_instruction_repo_moq.go -pkg=mocks . StuckPaymentInstructionRepo
+
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type StuckPaymentInstructionRepo interface {
+	// GetStuckPaymentInstructions returns the payment instructions of the payment provider in one of the statuses
+	// that haven't recorded any event since stuckBefore, the longest stuck first.
+	GetStuckPaymentInstructions(ctx context.Context, provider models.PaymentProviderType, statuses []models.PaymentInstructionStatus, stuckBefore time.Time) ([]models.StuckPaymentInstruction, error)
+}
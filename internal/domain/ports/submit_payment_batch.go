@@ -0,0 +1,16 @@
+//go:generate moq -out mocks/submit_payment_batch_moq.go -pkg=mocks . SubmitPaymentBatch
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// SubmitPaymentBatch is a use case that makes a payment of every incoming instruction of a batch and records the
+// outcome of each of them, so the batch can be followed while it is processed.
+type SubmitPaymentBatch interface {
+	Submit(ctx context.Context, instructions models.IncomingInstructions, idempotencyKey string) (models.PaymentBatch, error)
+	Get(ctx context.Context, id models.PaymentBatchID) (models.PaymentBatch, error)
+}
@@ -0,0 +1,16 @@
+//go:generate moq -out mocks/sweep_stuck_payments_moq.go -pkg=mocks . SweepStuckPayments
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+// SweepStuckPayments looks for the payment instructions that never got an outcome from their payment provider,
+// rechecks the ones the payment provider can be asked about and flags the rest for someone to look into.
+type SweepStuckPayments interface {
+	Sweep(ctx context.Context)
+	List(ctx context.Context) ([]models.StuckPaymentInstruction, error)
+}
@@ -0,0 +1,15 @@
+//go:generate moq -out mocks/ufx_file_ledger_moq.go -pkg=mocks . UfxFileLedger
+
+package ports
+
+import (
+	"context"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+)
+
+type UfxFileLedger interface {
+	SaveUfxFile(ctx context.Context, file models.UfxFile) error
+	GetUfxFile(ctx context.Context, filename string) (models.UfxFile, error)
+	ListUfxFiles(ctx context.Context, state models.UfxFileState) ([]models.UfxFile, error)
+}
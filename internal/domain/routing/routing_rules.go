@@ -0,0 +1,118 @@
+package routing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+// Rule routes the incoming instructions it matches to a payment provider, or rejects them. A rule matches an instruction
+// when every condition it sets holds, a condition left empty holds for any instruction.
+type Rule struct {
+	Name string `json:"name"`
+	// Senders are matched exactly, or as a prefix when they end with `*`. An empty sender matches the instructions
+	// that come without one.
+	Senders           []string              `json:"senders,omitempty"`
+	Sources           []string              `json:"sources,omitempty"`
+	Currencies        []models.CurrencyCode `json:"currencies,omitempty"`
+	HighRisk          *bool                 `json:"highRisk,omitempty"`
+	MerchantCountries []string              `json:"merchantCountries,omitempty"`
+
+	PaymentProvider models.PaymentProviderType `json:"paymentProvider,omitempty"`
+	SourceAccount   string                     `json:"sourceAccount,omitempty"`
+	Reject          string                     `json:"reject,omitempty"`
+}
+
+// Rules are tried in order, the first one matching an instruction decides where it goes.
+type Rules struct {
+	rules []Rule
+}
+
+var _ ports.RoutePaymentInstruction = Rules{}
+
+func NewRules(rules []Rule) (Rules, error) {
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return Rules{}, fmt.Errorf("routing rule %d has no name", i)
+		}
+		if (rule.PaymentProvider == "") == (rule.Reject == "") {
+			return Rules{}, fmt.Errorf("routing rule %s should either route to a payment provider or reject", rule.Name)
+		}
+	}
+	return Rules{rules: rules}, nil
+}
+
+// DefaultRules route the instructions as they were before the rules could be configured.
+func DefaultRules() Rules {
+	return Rules{rules: []Rule{
+		{Name: "saxo-isk", Senders: []string{"SAXO*"}, Currencies: []models.CurrencyCode{models.ISK}, Reject: "SAXO files should not contain ISK currencies"},
+		{Name: "islandsbanki-senders", Senders: []string{"ISB*", "RB*"}, PaymentProvider: models.Islandsbanki},
+		{Name: "saxo", Senders: []string{"SAXO*"}, PaymentProvider: models.BankingCircle},
+		{Name: "solanteq-isk", Senders: []string{""}, Sources: []string{"Solanteq"}, Currencies: []models.CurrencyCode{models.ISK}, PaymentProvider: models.Islandsbanki},
+		{Name: "no-sender", Senders: []string{""}, PaymentProvider: models.BankingCircle},
+	}}
+}
+
+func (r Rules) Route(instruction models.IncomingInstruction) models.RoutingDecision {
+	for _, rule := range r.rules {
+		if rule.matches(instruction) {
+			return models.RoutingDecision{
+				Rule:            rule.Name,
+				PaymentProvider: rule.PaymentProvider,
+				SourceAccount:   rule.SourceAccount,
+				Rejection:       rule.Reject,
+			}
+		}
+	}
+	return models.RoutingDecision{}
+}
+
+func (r Rule) matches(instruction models.IncomingInstruction) bool {
+	return matchesSender(r.Senders, instruction.Metadata.Sender) &&
+		matchesAny(r.Sources, instruction.Metadata.Source) &&
+		matchesCurrency(r.Currencies, instruction.Payment.Currency.IsoCode) &&
+		(r.HighRisk == nil || *r.HighRisk == instruction.Merchant.HighRisk) &&
+		matchesAny(r.MerchantCountries, instruction.Merchant.Address.Country)
+}
+
+func matchesSender(senders []string, sender string) bool {
+	if len(senders) == 0 {
+		return true
+	}
+	for _, s := range senders {
+		if prefix := strings.TrimSuffix(s, "*"); prefix != s {
+			if prefix != "" && strings.HasPrefix(sender, prefix) {
+				return true
+			}
+		} else if s == sender {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesCurrency(currencies []models.CurrencyCode, currency models.CurrencyCode) bool {
+	if len(currencies) == 0 {
+		return true
+	}
+	for _, c := range currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
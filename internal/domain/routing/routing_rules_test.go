@@ -0,0 +1,103 @@
+//go:build unit
+// +build unit
+
+package routing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/routing"
+)
+
+func TestDefaultRules(t *testing.T) {
+	t.Run("routes the instruction to the correct payment provider", func(t *testing.T) {
+		testData := []struct {
+			sender           string
+			source           string
+			currency         models.Currency
+			expectedProvider models.PaymentProviderType
+			description      string
+		}{
+			{"ISB", "Way4", models.Currency{IsoCode: "USD", IsoNumber: "840"}, models.Islandsbanki, "ISB from Way4 USD"},
+			{"ISB", "Way4", models.Currency{IsoCode: "GBP", IsoNumber: "826"}, models.Islandsbanki, "ISB from Way4 GBP"},
+			{"ISB", "", models.Currency{IsoCode: "EUR", IsoNumber: "978"}, models.Islandsbanki, "ISB from Way4 EUR"},
+			{"RB", "Way4", models.Currency{IsoCode: "ISK", IsoNumber: "352"}, models.Islandsbanki, "RB from Way4 ISK"},
+			{"RB_12", "Way4", models.Currency{IsoCode: "ISK", IsoNumber: "352"}, models.Islandsbanki, "RB from Way4 ISK"},
+			{"SAXO", "Way4", models.Currency{IsoCode: "EUR", IsoNumber: "978"}, models.BankingCircle, "SAXO from Way4 EUR"},
+			{"SAXO", "", models.Currency{IsoCode: "HUF", IsoNumber: "348"}, models.BankingCircle, "SAXO HUF"},
+			{"", "Solanteq", models.Currency{IsoCode: "ISK", IsoNumber: "352"}, models.Islandsbanki, "from Solanteq ISK"},
+			{"", "Solanteq", models.Currency{IsoCode: "EUR", IsoNumber: "978"}, models.BankingCircle, "from Solanteq EUR"},
+			{"UNKNOWN", "Way4", models.Currency{IsoCode: "EUR", IsoNumber: "978"}, "", "unknown sender"},
+		}
+
+		for _, datum := range testData {
+			incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithMetadataSender(datum.sender).WithMetadataSource(datum.source).WithCurrency(datum.currency).Build()
+			decision := routing.DefaultRules().Route(incomingInstruction)
+			assert.Equal(t, datum.expectedProvider, decision.PaymentProvider, datum.description)
+			assert.False(t, decision.Rejected(), datum.description)
+		}
+	})
+
+	t.Run("rejects ISK payments in SAXO files", func(t *testing.T) {
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithMetadataSender("SAXO").WithCurrency(models.Currency{IsoCode: "ISK", IsoNumber: "352"}).Build()
+
+		decision := routing.DefaultRules().Route(incomingInstruction)
+
+		assert.True(t, decision.Rejected())
+		assert.Equal(t, "saxo-isk", decision.Rule)
+		assert.Equal(t, "SAXO files should not contain ISK currencies", decision.Rejection)
+	})
+}
+
+func TestRules(t *testing.T) {
+	highRisk := true
+	rules, err := routing.NewRules([]routing.Rule{
+		{Name: "high-risk-gb", HighRisk: &highRisk, MerchantCountries: []string{"GB"}, PaymentProvider: models.BankingCircle, SourceAccount: "GB-HIGH-RISK"},
+		{Name: "way4-eur", Sources: []string{"Way4"}, Currencies: []models.CurrencyCode{models.EUR}, PaymentProvider: models.BankingCircle},
+	})
+	require.NoError(t, err)
+
+	t.Run("routes to the provider and source account of the first matching rule", func(t *testing.T) {
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithHighRIsk().WithMetadataSource("Way4").Build()
+		incomingInstruction.Merchant.Address.Country = "GB"
+
+		decision := rules.Route(incomingInstruction)
+
+		assert.Equal(t, models.RoutingDecision{Rule: "high-risk-gb", PaymentProvider: models.BankingCircle, SourceAccount: "GB-HIGH-RISK"}, decision)
+	})
+
+	t.Run("skips the rules whose conditions don't all hold", func(t *testing.T) {
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithMetadataSource("Way4").Build()
+		incomingInstruction.Merchant.Address.Country = "GB"
+
+		decision := rules.Route(incomingInstruction)
+
+		assert.Equal(t, "way4-eur", decision.Rule)
+		assert.Empty(t, decision.SourceAccount)
+	})
+
+	t.Run("matches no rule", func(t *testing.T) {
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithMetadataSource("Solanteq").Build()
+
+		decision := rules.Route(incomingInstruction)
+
+		assert.False(t, decision.Matched())
+		assert.False(t, decision.Rejected())
+	})
+
+	t.Run("a rule has a name, and either routes or rejects", func(t *testing.T) {
+		_, err := routing.NewRules([]routing.Rule{{PaymentProvider: models.BankingCircle}})
+		assert.Error(t, err)
+
+		_, err = routing.NewRules([]routing.Rule{{Name: "both", PaymentProvider: models.BankingCircle, Reject: "no"}})
+		assert.Error(t, err)
+
+		_, err = routing.NewRules([]routing.Rule{{Name: "neither"}})
+		assert.Error(t, err)
+	})
+}
@@ -22,6 +22,7 @@ type CheckPaymentAccountFundsAvailability struct {
 	metricsClient                  ports.MetricsClient
 	printer                        *message.Printer
 	paymentProviderRetrieveBalance ports.PaymentProviderRetrieveBalance
+	liquidityLedger                ports.LiquidityLedger
 }
 
 func NewCheckPaymentAccountFundsAvailability(
@@ -36,18 +37,39 @@ func NewCheckPaymentAccountFundsAvailability(
 	}
 }
 
+// SubtractReservations takes the funds reserved by the payment instructions accepted but not paid yet off the balance,
+// and holds the funds of the files and batches it checks, so that payments checked at the same time don't count on the
+// same funds.
+func (c *CheckPaymentAccountFundsAvailability) SubtractReservations(liquidityLedger ports.LiquidityLedger) {
+	c.liquidityLedger = liquidityLedger
+}
+
 func (c CheckPaymentAccountFundsAvailability) Execute(ctx context.Context, currencyCode models.CurrencyCode, amount float64, highRisk bool) (bool, error) {
 	amountFormatted := c.formatCurrencyAmount(currencyCode, amount)
 
-	balance, maxIntraDayLoan, err := c.paymentProviderRetrieveBalance.RetrieveBalanceForCurrency(currencyCode, highRisk)
+	balance, maxIntraDayLoan, err := c.retrieveBalance(ctx, currencyCode, amount, highRisk)
 	if err != nil {
-		zapctx.Error(ctx, "[CheckPaymentAccountFundsAvailability] (Execute) error occurred when retrieving balance for currency",
+		return false, err
+	}
+
+	reserved, err := reservedFunds(ctx, c.liquidityLedger, currencyCode, highRisk)
+	if err != nil {
+		zapctx.Error(ctx, "[CheckPaymentAccountFundsAvailability] (Execute) error occurred when retrieving the reserved funds for currency",
 			zap.String("currency", string(currencyCode)),
 			zap.String("amount_needed", amountFormatted),
+			zap.Error(err),
 		)
 
 		return false, err
 	}
+	if reserved > 0 {
+		zapctx.Info(ctx, "[CheckPaymentAccountFundsAvailability] (Execute) taking the reserved funds off the balance",
+			zap.String("currency", string(currencyCode)),
+			zap.Bool("high_risk", highRisk),
+			zap.String("reserved", c.formatCurrencyAmount(currencyCode, reserved)),
+		)
+		balance -= reserved
+	}
 
 	balanceFormatted := c.formatCurrencyAmount(currencyCode, balance)
 	intraDayLoanFormatted := c.formatCurrencyAmount(currencyCode, maxIntraDayLoan)
@@ -87,6 +109,119 @@ func (c CheckPaymentAccountFundsAvailability) Execute(ctx context.Context, curre
 	return true, nil
 }
 
+// Hold leaves the sum of the outstanding reservations and the hold to the ledger, there is nothing to hold without one.
+// The source accounts are tried in the order they are paid from, the first one whose funds cover the amount along with
+// its own reservations is held.
+func (c CheckPaymentAccountFundsAvailability) Hold(ctx context.Context, id models.FundsHoldID, currencyCode models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+	hold := models.FundsHold{
+		ID:       id,
+		Currency: currencyCode,
+		HighRisk: highRisk,
+		Amount:   amount,
+	}
+	if c.liquidityLedger == nil {
+		hasFunds, err := c.Execute(ctx, currencyCode, amount, highRisk)
+		return hold, hasFunds, err
+	}
+
+	accountsFunds, err := c.paymentProviderRetrieveBalance.RetrieveAccountsFunds(currencyCode, highRisk)
+	if err != nil {
+		zapctx.Error(ctx, "[CheckPaymentAccountFundsAvailability] (Hold) error occurred when retrieving the funds of the accounts for currency",
+			zap.String("currency", string(currencyCode)),
+			zap.String("amount_needed", c.formatCurrencyAmount(currencyCode, amount)),
+			zap.Error(err),
+		)
+
+		return hold, false, err
+	}
+
+	for _, funds := range accountsFunds {
+		hold.Account = funds.Account
+		held, err := c.liquidityLedger.Hold(ctx, hold, funds.Balance+funds.MaxIntraDayLoan)
+		if err != nil {
+			zapctx.Error(ctx, "[CheckPaymentAccountFundsAvailability] (Hold) error occurred when holding the funds for currency",
+				zap.String("currency", string(currencyCode)),
+				zap.String("account", funds.Account),
+				zap.String("amount_needed", c.formatCurrencyAmount(currencyCode, amount)),
+				zap.Error(err),
+			)
+
+			return hold, false, err
+		}
+		if held {
+			zapctx.Info(ctx, "[CheckPaymentAccountFundsAvailability] (Hold) funds held",
+				zap.String("hold_id", string(id)),
+				zap.String("account", funds.Account),
+				zap.String("amount_needed", c.formatCurrencyAmount(currencyCode, amount)),
+				zap.String("currency", string(currencyCode)),
+				zap.Bool("high_risk", highRisk),
+			)
+
+			return hold, true, nil
+		}
+
+		zapctx.Info(ctx, "[CheckPaymentAccountFundsAvailability] (Hold) Not enough funds in the account once the reserved funds are taken off",
+			zap.String("balance", c.formatCurrencyAmount(currencyCode, funds.Balance)),
+			zap.String("account", funds.Account),
+			zap.String("currency", string(currencyCode)),
+			zap.Bool("high_risk", highRisk),
+			zap.String("amount_needed", c.formatCurrencyAmount(currencyCode, amount)),
+		)
+	}
+
+	zapctx.Error(ctx, "[CheckPaymentAccountFundsAvailability] (Hold) Not enough funds in any account once the reserved funds are taken off",
+		zap.String("currency", string(currencyCode)),
+		zap.Bool("high_risk", highRisk),
+		zap.String("amount_needed", c.formatCurrencyAmount(currencyCode, amount)),
+	)
+
+	c.metricsClient.Count(ctx, missingAccountFunds, 1, []string{string(currencyCode)})
+	hold.Account = ""
+	return hold, false, nil
+}
+
+// ReleaseHold leaves a hold it couldn't release outstanding, the payment instructions are made regardless.
+func (c CheckPaymentAccountFundsAvailability) ReleaseHold(ctx context.Context, id models.FundsHoldID) {
+	if c.liquidityLedger == nil {
+		return
+	}
+	if err := c.liquidityLedger.ReleaseHold(ctx, id); err != nil {
+		zapctx.Error(ctx, "[CheckPaymentAccountFundsAvailability] (ReleaseHold) unable to release the held funds",
+			zap.String("hold_id", string(id)),
+			zap.Error(err),
+		)
+	}
+}
+
+func (c CheckPaymentAccountFundsAvailability) retrieveBalance(ctx context.Context, currencyCode models.CurrencyCode, amount float64, highRisk bool) (float64, float64, error) {
+	balance, maxIntraDayLoan, err := c.paymentProviderRetrieveBalance.RetrieveBalanceForCurrency(currencyCode, highRisk, amount)
+	if err != nil {
+		zapctx.Error(ctx, "[CheckPaymentAccountFundsAvailability] (Execute) error occurred when retrieving balance for currency",
+			zap.String("currency", string(currencyCode)),
+			zap.String("amount_needed", c.formatCurrencyAmount(currencyCode, amount)),
+		)
+	}
+	return balance, maxIntraDayLoan, err
+}
+
+// reservedFunds sums up the outstanding reservations of the currency and risk class, there are none without a ledger.
+func reservedFunds(ctx context.Context, liquidityLedger ports.LiquidityLedger, currencyCode models.CurrencyCode, highRisk bool) (float64, error) {
+	if liquidityLedger == nil {
+		return 0, nil
+	}
+
+	reserved, err := liquidityLedger.Reserved(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, funds := range reserved {
+		if funds.Currency == currencyCode && funds.HighRisk == highRisk {
+			return funds.Amount, nil
+		}
+	}
+	return 0, nil
+}
+
 // formatCurrencyAmount formats a currency amount for the locale associated with the currency code.
 // e.g. with input 1000000.0666 it outputs 1,000,000.07 for GBP.
 func (c CheckPaymentAccountFundsAvailability) formatCurrencyAmount(currencyCode models.CurrencyCode, amount float64) string {
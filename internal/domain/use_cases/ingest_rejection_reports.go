@@ -0,0 +1,237 @@
+package use_cases
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	providerRejectionMetricName = "app_payment_provider_rejection"
+
+	// how an ingested rejection was resolved, besides the reasons of the unmatched ones
+	rejectionTracked      = "tracked"
+	rejectionReversed     = "reversed"
+	rejectionAlreadyKnown = "already_known"
+)
+
+type IngestRejectionReports struct {
+	reports             map[models.PaymentProviderType]ports.PaymentProviderRejectionReport
+	repo                ports.RejectedPaymentRepo
+	trackPaymentOutcome ports.TrackPaymentOutcome
+	metricsClient       ports.MetricsClient
+	now                 func() time.Time
+}
+
+var _ ports.IngestRejectionReports = IngestRejectionReports{}
+
+// NewIngestRejectionReports ingests the rejection report of every payment provider in reports.
+func NewIngestRejectionReports(
+	reports map[models.PaymentProviderType]ports.PaymentProviderRejectionReport,
+	repo ports.RejectedPaymentRepo,
+	trackPaymentOutcome ports.TrackPaymentOutcome,
+	metricsClient ports.MetricsClient,
+) IngestRejectionReports {
+	return IngestRejectionReports{
+		reports:             reports,
+		repo:                repo,
+		trackPaymentOutcome: trackPaymentOutcome,
+		metricsClient:       metricsClient,
+		now:                 time.Now,
+	}
+}
+
+// Ingest fails the payment instructions still waiting for the outcome of a payment their payment provider rejected, and
+// reverses the ones it reported as paid before rejecting them, which also reports the outcome to the acquiring host. Ingesting a report again replaces its unmatched rejections,
+// so a rejection matched on a later run stops being listed.
+func (i IngestRejectionReports) Ingest(ctx context.Context, reportDate time.Time) {
+	for _, provider := range i.providers() {
+		rejections, err := i.reports[provider].GetRejections(ctx, reportDate)
+		if err != nil {
+			zapctx.Error(ctx, "[IngestRejectionReports] (Ingest) error getting the rejection report",
+				zap.String("payment_provider", string(provider)),
+				zap.Time("report_date", reportDate),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		unmatched := make([]models.UnmatchedRejection, 0)
+		for _, rejection := range rejections {
+			if reason, id, matched := i.ingest(ctx, provider, rejection); !matched {
+				unmatched = append(unmatched, models.UnmatchedRejection{
+					PaymentProvider:      provider,
+					Rejection:            rejection,
+					Reason:               reason,
+					PaymentInstructionID: id,
+				})
+			}
+		}
+
+		if err := i.repo.SaveUnmatchedRejections(ctx, provider, reportDate, unmatched); err != nil {
+			zapctx.Error(ctx, "[IngestRejectionReports] (Ingest) error saving the unmatched rejections",
+				zap.String("payment_provider", string(provider)),
+				zap.Time("report_date", reportDate),
+				zap.Int("unmatched", len(unmatched)),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (i IngestRejectionReports) ListUnmatched(ctx context.Context, reportDate time.Time) ([]models.UnmatchedRejection, error) {
+	return i.repo.GetUnmatchedRejections(ctx, reportDate)
+}
+
+func (i IngestRejectionReports) providers() []models.PaymentProviderType {
+	providers := make([]models.PaymentProviderType, 0, len(i.reports))
+	for provider := range i.reports {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(a, b int) bool { return providers[a] < providers[b] })
+	return providers
+}
+
+// ingest returns why the rejection couldn't be tracked on a payment instruction, and which one it is about when that is known.
+func (i IngestRejectionReports) ingest(ctx context.Context, provider models.PaymentProviderType, rejection models.ProviderRejection) (models.UnmatchedRejectionReason, models.PaymentInstructionID, bool) {
+	candidates, err := i.match(ctx, provider, rejection)
+	if err != nil {
+		i.unmatched(ctx, provider, rejection, models.RejectionNotTracked, "", err)
+		return models.RejectionNotTracked, "", false
+	}
+	if len(candidates) == 0 {
+		i.unmatched(ctx, provider, rejection, models.NoPaymentInstruction, "", nil)
+		return models.NoPaymentInstruction, "", false
+	}
+	if len(candidates) > 1 {
+		i.unmatched(ctx, provider, rejection, models.ManyPaymentInstructions, "", nil)
+		return models.ManyPaymentInstructions, "", false
+	}
+
+	rejected := candidates[0]
+	instruction := rejected.PaymentInstruction
+	eventType, resolution := models.Failure, rejectionTracked
+	switch {
+	case instruction.GetStatus() == models.Successful:
+		// the payment provider reported the payment as paid before rejecting it, the merchant wasn't paid after all
+		eventType, resolution = models.Reversal, rejectionReversed
+	case !instruction.IsInFlight():
+		// the payment instruction got its outcome already, most likely from the payment provider's notification of the rejection
+		i.metricsClient.Count(ctx, providerRejectionMetricName, 1, []string{string(provider), rejectionAlreadyKnown})
+		return "", instruction.ID(), true
+	}
+
+	message := rejection.StatusReason
+	if message == "" {
+		message = rejection.Status
+	}
+	failure := models.FailureReason{
+		Code:    models.RejectedCode,
+		Message: message,
+	}
+	event, err := models.NewPaymentProviderEvent(i.now(), eventType, instruction, models.PaymentProviderName(provider), rejected.ProviderPaymentID, rejected.BankingReference, &failure)
+	if err == nil {
+		// a reversal carries the rejection too, the acquiring host is told why the payment was taken back
+		event.FailureReason = failure
+		err = i.trackPaymentOutcome.Execute(ctx, event)
+	}
+	if err != nil {
+		i.unmatched(ctx, provider, rejection, models.RejectionNotTracked, instruction.ID(), err)
+		return models.RejectionNotTracked, instruction.ID(), false
+	}
+
+	zapctx.Info(ctx, "[IngestRejectionReports] (ingest) tracked a rejection of the payment provider",
+		zap.String("payment_instruction_id", string(instruction.ID())),
+		zap.String("payment_provider", string(provider)),
+		zap.String("status_reason", rejection.StatusReason),
+		zap.String("event_type", string(eventType)),
+	)
+	i.metricsClient.Count(ctx, providerRejectionMetricName, 1, []string{string(provider), resolution})
+	return "", instruction.ID(), true
+}
+
+// match looks the rejection up by the most specific reference the report has, falling back to the destination of the payment.
+func (i IngestRejectionReports) match(ctx context.Context, provider models.PaymentProviderType, rejection models.ProviderRejection) ([]models.RejectedPaymentInstruction, error) {
+	if rejection.PaymentReference != "" {
+		candidates, err := i.repo.GetFromProviderReference(ctx, provider, rejection.PaymentReference)
+		if err != nil || len(candidates) > 0 {
+			return outstanding(candidates), err
+		}
+	}
+
+	if rejection.ExecutionDate.IsZero() {
+		return nil, nil
+	}
+
+	if rejection.ContractNumber != "" {
+		candidates, err := i.repo.GetFromContract(ctx, provider, rejection.ContractNumber, rejection.ExecutionDate)
+		if err != nil {
+			return nil, err
+		}
+		if candidates = payingTo(candidates, rejection.DestinationAccount); len(candidates) > 0 {
+			return outstanding(candidates), nil
+		}
+	}
+
+	if rejection.DestinationAccount != "" && rejection.Amount != "" {
+		candidates, err := i.repo.GetFromDestination(ctx, provider, rejection.DestinationAccount, rejection.Currency, rejection.Amount, rejection.ExecutionDate)
+		return outstanding(candidates), err
+	}
+	return nil, nil
+}
+
+// payingTo keeps the payment instructions paying the account, all of them when the account isn't known.
+func payingTo(candidates []models.RejectedPaymentInstruction, account string) []models.RejectedPaymentInstruction {
+	if account == "" {
+		return candidates
+	}
+
+	var paying []models.RejectedPaymentInstruction
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate.PaymentInstruction.IncomingInstruction.AccountNumber(), account) {
+			paying = append(paying, candidate)
+		}
+	}
+	return paying
+}
+
+// outstanding narrows many candidates down to the ones the payment provider may still have paid, e.g. a payment
+// instruction replayed after it failed is paid by its replay. When all of them got their outcome already, any one will do.
+func outstanding(candidates []models.RejectedPaymentInstruction) []models.RejectedPaymentInstruction {
+	if len(candidates) <= 1 {
+		return candidates
+	}
+
+	var kept []models.RejectedPaymentInstruction
+	for _, candidate := range candidates {
+		if candidate.PaymentInstruction.IsInFlight() || candidate.PaymentInstruction.GetStatus() == models.Successful {
+			kept = append(kept, candidate)
+		}
+	}
+	if len(kept) == 0 {
+		return candidates[:1]
+	}
+	return kept
+}
+
+func (i IngestRejectionReports) unmatched(ctx context.Context, provider models.PaymentProviderType, rejection models.ProviderRejection, reason models.UnmatchedRejectionReason, id models.PaymentInstructionID, err error) {
+	zapctx.Warn(ctx, "[IngestRejectionReports] (unmatched) rejection of the payment provider needs someone to look into it",
+		zap.String("payment_provider", string(provider)),
+		zap.String("payment_reference", rejection.PaymentReference),
+		zap.String("contract_number", rejection.ContractNumber),
+		zap.String("destination_account", rejection.DestinationAccount),
+		zap.String("amount", rejection.Amount),
+		zap.String("currency", string(rejection.Currency)),
+		zap.String("payment_instruction_id", string(id)),
+		zap.String("reason", string(reason)),
+		zap.Error(err),
+	)
+	i.metricsClient.Count(ctx, providerRejectionMetricName, 1, []string{string(provider), strings.ToLower(string(reason))})
+}
@@ -0,0 +1,234 @@
+//go:build unit
+// +build unit
+
+package use_cases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/use_cases"
+	"github.com/saltpay/settlements-payments-system/internal/domain/validation"
+)
+
+func TestIngestRejectionReports(t *testing.T) {
+	var (
+		ctx           = context.Background()
+		reportDate    = time.Date(2022, 9, 21, 0, 0, 0, 0, time.UTC)
+		executionDate = time.Date(2022, 9, 20, 0, 0, 0, 0, time.UTC)
+	)
+
+	rejection := models.ProviderRejection{
+		PaymentReference:   "bc-reference",
+		ContractNumber:     "123456",
+		ExecutionDate:      executionDate,
+		DestinationAccount: "GB33BUKB20201555555555",
+		Amount:             "1250.5",
+		Currency:           models.EUR,
+		Status:             "Rejected",
+		StatusReason:       "Beneficiary account closed",
+	}
+
+	rejectedWith := func(status models.PaymentInstructionStatus) models.RejectedPaymentInstruction {
+		instruction := testhelpers.NewPaymentInstructionBuilder().
+			WithPaymentProvider(models.BankingCircle).
+			WithAccountNumber(rejection.DestinationAccount).
+			WithStatus(status).
+			Build()
+		return models.RejectedPaymentInstruction{PaymentInstruction: instruction, ProviderPaymentID: "bc-payment-id", BankingReference: "bc-reference"}
+	}
+
+	newReports := func(rejections ...models.ProviderRejection) map[models.PaymentProviderType]ports.PaymentProviderRejectionReport {
+		return map[models.PaymentProviderType]ports.PaymentProviderRejectionReport{
+			models.BankingCircle: &mocks.PaymentProviderRejectionReportMock{GetRejectionsFunc: func(ctx context.Context, date time.Time) ([]models.ProviderRejection, error) {
+				return rejections, nil
+			}},
+		}
+	}
+
+	// newRepo finds nothing unless a lookup is given
+	newRepo := func() *mocks.RejectedPaymentRepoMock {
+		return &mocks.RejectedPaymentRepoMock{
+			GetFromProviderReferenceFunc: func(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error) {
+				return nil, nil
+			},
+			GetFromContractFunc: func(ctx context.Context, provider models.PaymentProviderType, contractNumber string, executionDate time.Time) ([]models.RejectedPaymentInstruction, error) {
+				return nil, nil
+			},
+			GetFromDestinationFunc: func(ctx context.Context, provider models.PaymentProviderType, accountNumber string, currency models.CurrencyCode, amount string, executionDate time.Time) ([]models.RejectedPaymentInstruction, error) {
+				return nil, nil
+			},
+			SaveUnmatchedRejectionsFunc: func(ctx context.Context, provider models.PaymentProviderType, reportDate time.Time, rejections []models.UnmatchedRejection) error {
+				return nil
+			},
+		}
+	}
+
+	newTrackPaymentOutcome := func() *mocks.TrackPaymentOutcomeMock {
+		return &mocks.TrackPaymentOutcomeMock{ExecuteFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+			return nil
+		}}
+	}
+
+	t.Run("fails a payment instruction still waiting for the outcome of a payment the payment provider rejected", func(t *testing.T) {
+		rejected := rejectedWith(models.StateSubmitted)
+		repo := newRepo()
+		repo.GetFromProviderReferenceFunc = func(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error) {
+			return []models.RejectedPaymentInstruction{rejected}, nil
+		}
+		trackPaymentOutcome := newTrackPaymentOutcome()
+
+		use_cases.NewIngestRejectionReports(newReports(rejection), repo, trackPaymentOutcome, newEmptyMetricsClientMock()).Ingest(ctx, reportDate)
+
+		require.Len(t, repo.GetFromProviderReferenceCalls(), 1)
+		assert.Equal(t, "bc-reference", repo.GetFromProviderReferenceCalls()[0].Reference)
+		require.Len(t, trackPaymentOutcome.ExecuteCalls(), 1)
+		event := trackPaymentOutcome.ExecuteCalls()[0].PpEvent
+		assert.Equal(t, models.Failure, event.Type)
+		assert.Equal(t, rejected.PaymentInstruction.ID(), event.PaymentInstruction.ID())
+		assert.Equal(t, models.BC, event.PaymentProviderName)
+		assert.Equal(t, models.ProviderPaymentID("bc-payment-id"), event.PaymentProviderPaymentID)
+		assert.Equal(t, models.FailureReason{Code: models.RejectedCode, Message: "Beneficiary account closed"}, event.FailureReason)
+
+		require.Len(t, repo.SaveUnmatchedRejectionsCalls(), 1)
+		assert.Equal(t, reportDate, repo.SaveUnmatchedRejectionsCalls()[0].ReportDate)
+		assert.Empty(t, repo.SaveUnmatchedRejectionsCalls()[0].Rejections)
+	})
+
+	t.Run("falls back to the contract paying the destination account, then to the destination of the payment", func(t *testing.T) {
+		rejected := rejectedWith(models.StateSubmitted)
+		repo := newRepo()
+		repo.GetFromContractFunc = func(ctx context.Context, provider models.PaymentProviderType, contractNumber string, executionDate time.Time) ([]models.RejectedPaymentInstruction, error) {
+			paysSomeoneElse := testhelpers.NewPaymentInstructionBuilder().WithAccountNumber("GB94BARC10201530093459").WithStatus(models.StateSubmitted).Build()
+			return []models.RejectedPaymentInstruction{{PaymentInstruction: paysSomeoneElse}}, nil
+		}
+		repo.GetFromDestinationFunc = func(ctx context.Context, provider models.PaymentProviderType, accountNumber string, currency models.CurrencyCode, amount string, executionDate time.Time) ([]models.RejectedPaymentInstruction, error) {
+			return []models.RejectedPaymentInstruction{rejected}, nil
+		}
+		trackPaymentOutcome := newTrackPaymentOutcome()
+
+		use_cases.NewIngestRejectionReports(newReports(rejection), repo, trackPaymentOutcome, newEmptyMetricsClientMock()).Ingest(ctx, reportDate)
+
+		require.Len(t, repo.GetFromContractCalls(), 1)
+		assert.Equal(t, "123456", repo.GetFromContractCalls()[0].ContractNumber)
+		assert.Equal(t, executionDate, repo.GetFromContractCalls()[0].ExecutionDate)
+		require.Len(t, repo.GetFromDestinationCalls(), 1)
+		destinationCall := repo.GetFromDestinationCalls()[0]
+		assert.Equal(t, "GB33BUKB20201555555555", destinationCall.AccountNumber)
+		assert.Equal(t, models.EUR, destinationCall.Currency)
+		assert.Equal(t, "1250.5", destinationCall.Amount)
+		require.Len(t, trackPaymentOutcome.ExecuteCalls(), 1)
+		assert.Equal(t, rejected.PaymentInstruction.ID(), trackPaymentOutcome.ExecuteCalls()[0].PpEvent.PaymentInstruction.ID())
+	})
+
+	t.Run("a payment instruction that got its outcome already is left alone", func(t *testing.T) {
+		repo := newRepo()
+		repo.GetFromProviderReferenceFunc = func(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error) {
+			return []models.RejectedPaymentInstruction{rejectedWith(models.Failed)}, nil
+		}
+		trackPaymentOutcome := newTrackPaymentOutcome()
+
+		use_cases.NewIngestRejectionReports(newReports(rejection), repo, trackPaymentOutcome, newEmptyMetricsClientMock()).Ingest(ctx, reportDate)
+
+		assert.Empty(t, trackPaymentOutcome.ExecuteCalls())
+		require.Len(t, repo.SaveUnmatchedRejectionsCalls(), 1)
+		assert.Empty(t, repo.SaveUnmatchedRejectionsCalls()[0].Rejections)
+	})
+
+	t.Run("reverses a payment instruction the payment provider reported as paid before rejecting it", func(t *testing.T) {
+		succeeded := rejectedWith(models.Successful)
+		repo := newRepo()
+		repo.GetFromProviderReferenceFunc = func(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error) {
+			return []models.RejectedPaymentInstruction{succeeded}, nil
+		}
+		paymentInstructionRepo := &mocks.StorePaymentInstructionToRepoMock{
+			UpdatePaymentFunc: func(ctx context.Context, id models.PaymentInstructionID, expectedVersion int, status models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
+				return nil
+			},
+		}
+		paymentExporterProducer := &mocks.PaymentExporterProducerMock{ReportPaymentStatusFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+			return nil
+		}}
+		trackPaymentOutcome := use_cases.NewTrackPaymentOutcome(paymentInstructionRepo, validation.PPEventValidatorFunc(validation.ValidatePaymentProviderEvent), paymentExporterProducer, newEmptyMetricsClientMock())
+
+		use_cases.NewIngestRejectionReports(newReports(rejection), repo, trackPaymentOutcome, newEmptyMetricsClientMock()).Ingest(ctx, reportDate)
+
+		require.Len(t, paymentInstructionRepo.UpdatePaymentCalls(), 1)
+		update := paymentInstructionRepo.UpdatePaymentCalls()[0]
+		assert.Equal(t, succeeded.PaymentInstruction.ID(), update.ID)
+		assert.Equal(t, models.Reversed, update.Status)
+		assert.Equal(t, models.DomainProcessingReversed, update.Event.Type)
+
+		require.Len(t, paymentExporterProducer.ReportPaymentStatusCalls(), 1)
+		exported := paymentExporterProducer.ReportPaymentStatusCalls()[0].PpEvent
+		assert.Equal(t, models.Reversal, exported.Type)
+		assert.Equal(t, models.Reversed, exported.PaymentInstruction.GetStatus())
+		assert.Equal(t, models.ProviderPaymentID("bc-payment-id"), exported.PaymentProviderPaymentID)
+		assert.Equal(t, models.FailureReason{Code: models.RejectedCode, Message: "Beneficiary account closed"}, exported.FailureReason)
+
+		require.Len(t, repo.SaveUnmatchedRejectionsCalls(), 1)
+		assert.Empty(t, repo.SaveUnmatchedRejectionsCalls()[0].Rejections)
+	})
+
+	t.Run("lists the rejections that can't be tracked on a payment instruction for review", func(t *testing.T) {
+		unknown := rejection
+		unknown.PaymentReference = "unknown-reference"
+		repo := newRepo()
+		repo.GetFromProviderReferenceFunc = func(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error) {
+			switch reference {
+			case "failing-reference":
+				return nil, errors.New("database unavailable")
+			default:
+				return nil, nil
+			}
+		}
+		failing := rejection
+		failing.PaymentReference = "failing-reference"
+		trackPaymentOutcome := newTrackPaymentOutcome()
+
+		use_cases.NewIngestRejectionReports(newReports(unknown, failing), repo, trackPaymentOutcome, newEmptyMetricsClientMock()).Ingest(ctx, reportDate)
+
+		assert.Empty(t, trackPaymentOutcome.ExecuteCalls())
+		require.Len(t, repo.SaveUnmatchedRejectionsCalls(), 1)
+		assert.Equal(t, []models.UnmatchedRejection{
+			{PaymentProvider: models.BankingCircle, Rejection: unknown, Reason: models.NoPaymentInstruction},
+			{PaymentProvider: models.BankingCircle, Rejection: failing, Reason: models.RejectionNotTracked},
+		}, repo.SaveUnmatchedRejectionsCalls()[0].Rejections)
+	})
+
+	t.Run("a rejection many payment instructions still waiting for their outcome fit is listed for review", func(t *testing.T) {
+		repo := newRepo()
+		repo.GetFromProviderReferenceFunc = func(ctx context.Context, provider models.PaymentProviderType, reference string) ([]models.RejectedPaymentInstruction, error) {
+			return []models.RejectedPaymentInstruction{rejectedWith(models.StateSubmitted), rejectedWith(models.Failed), rejectedWith(models.StateSubmitted)}, nil
+		}
+		trackPaymentOutcome := newTrackPaymentOutcome()
+
+		use_cases.NewIngestRejectionReports(newReports(rejection), repo, trackPaymentOutcome, newEmptyMetricsClientMock()).Ingest(ctx, reportDate)
+
+		assert.Empty(t, trackPaymentOutcome.ExecuteCalls())
+		require.Len(t, repo.SaveUnmatchedRejectionsCalls()[0].Rejections, 1)
+		assert.Equal(t, models.ManyPaymentInstructions, repo.SaveUnmatchedRejectionsCalls()[0].Rejections[0].Reason)
+	})
+
+	t.Run("keeps the unmatched rejections saved before when the report can't be fetched", func(t *testing.T) {
+		repo := newRepo()
+		reports := map[models.PaymentProviderType]ports.PaymentProviderRejectionReport{
+			models.BankingCircle: &mocks.PaymentProviderRejectionReportMock{GetRejectionsFunc: func(ctx context.Context, date time.Time) ([]models.ProviderRejection, error) {
+				return nil, errors.New("banking circle unavailable")
+			}},
+		}
+
+		use_cases.NewIngestRejectionReports(reports, repo, newTrackPaymentOutcome(), newEmptyMetricsClientMock()).Ingest(ctx, reportDate)
+
+		assert.Empty(t, repo.SaveUnmatchedRejectionsCalls())
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/saltpay/settlements-payments-system/internal/adapters/payment_store/postgresql"
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/routing"
 	"github.com/saltpay/settlements-payments-system/internal/domain/validation"
 )
 
@@ -24,29 +25,65 @@ const (
 )
 
 type MakePayment struct {
-	metricsClient                ports.MetricsClient
-	paymentProviderRequestSender ports.PaymentProviderRequestSender
-	aggregatePaymentStore        ports.StorePaymentInstructionToRepo
-	paymentInstructionValidator  validation.Validator
+	metricsClient               ports.MetricsClient
+	aggregatePaymentStore       ports.StorePaymentInstructionToRepo
+	paymentInstructionValidator validation.Validator
+	router                      ports.RoutePaymentInstruction
+	reserveFunds                bool
 }
 
 func NewMakePayment(
 	metricsClient ports.MetricsClient,
-	paymentProviderRequestSender ports.PaymentProviderRequestSender,
 	paymentInstructionRepo ports.StorePaymentInstructionToRepo,
 	paymentRequestValidator validation.Validator,
 ) *MakePayment {
 	return &MakePayment{
-		metricsClient:                metricsClient,
-		paymentProviderRequestSender: paymentProviderRequestSender,
-		aggregatePaymentStore:        paymentInstructionRepo,
-		paymentInstructionValidator:  paymentRequestValidator,
+		metricsClient:               metricsClient,
+		aggregatePaymentStore:       paymentInstructionRepo,
+		paymentInstructionValidator: paymentRequestValidator,
+		router:                      routing.DefaultRules(),
 	}
 }
 
+// RouteWith replaces the default routing rules, with the rules loaded from configuration.
+func (m *MakePayment) RouteWith(router ports.RoutePaymentInstruction) {
+	m.router = router
+}
+
+// ReserveFunds reserves the amount of the payment instructions paid by Banking Circle along with storing them, until
+// their outcome is tracked.
+func (m *MakePayment) ReserveFunds() {
+	m.reserveFunds = true
+}
+
 func (m MakePayment) Execute(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+	idempotencyKey, err := models.NewIdempotencyKey(incomingInstruction)
+	if err != nil {
+		return "", err
+	}
+
+	// the account the payment is made from is for the routing rules and the payment provider to choose, not the caller
+	if incomingInstruction.Payment.Sender.AccountNumber != "" {
+		zapctx.Warn(ctx, "[MakePayment] (Execute) ignoring the sender account of the incoming instruction",
+			zap.String("merchant_contract_number", incomingInstruction.Merchant.ContractNumber),
+		)
+		incomingInstruction.Payment.Sender.AccountNumber = ""
+	}
+
+	if idempotencyKey.IsSet() {
+		id, replayed, err := m.replay(ctx, incomingInstruction, idempotencyKey)
+		if replayed {
+			return id, err
+		}
+	}
+
 	validationRes := m.paymentInstructionValidator.ValidateIncomingInstruction(incomingInstruction)
+	routingDecision := m.router.Route(incomingInstruction)
+	if routingDecision.Rejected() {
+		validationRes = validationRes.Merge(validation.Invalid(routingDecision.Rejection))
+	}
 	paymentInstruction := models.NewPaymentInstruction(incomingInstruction)
+	paymentInstruction.SetIdempotencyKey(idempotencyKey)
 
 	if incomingInstruction.AccountNumber() != paymentInstruction.IncomingInstruction.AccountNumber() {
 		zapctx.Warn(ctx, "flow_step #6: account number (IBAN) is in an incorrect format - it has spaces and/or lower case letters. But payment is still going ahead. Please contact CR to fix the account number.",
@@ -57,8 +94,13 @@ func (m MakePayment) Execute(ctx context.Context, incomingInstruction models.Inc
 	}
 
 	if !validationRes.IsValid() {
-		paymentInstruction.Rejected(incomingInstruction, validationRes.Error())
+		if err := paymentInstruction.Rejected(incomingInstruction, validationRes.Error()); err != nil {
+			return "", err
+		}
 		err := m.aggregatePaymentStore.Store(ctx, paymentInstruction)
+		if errors.Is(err, postgresql.ErrDuplicateIdempotencyKey) {
+			return m.replayStored(ctx, incomingInstruction, idempotencyKey)
+		}
 		if err != nil {
 			return "", err
 		}
@@ -68,7 +110,7 @@ func (m MakePayment) Execute(ctx context.Context, incomingInstruction models.Inc
 			zap.Error(validationRes),
 		)
 
-		return "", validationRes
+		return paymentInstruction.ID(), validationRes
 	}
 
 	zapctx.Debug(ctx, "flow_step #6: payment instruction is valid",
@@ -86,16 +128,40 @@ func (m MakePayment) Execute(ctx context.Context, incomingInstruction models.Inc
 		paymentInstruction.IncomingInstruction.Payment.Amount = strconv.FormatFloat(roundedAmount, 'f', -1, 64)
 	}
 
-	// TODO: SubmitForProcessing should happen only after payment instruction
-	// Route the PI to the correct payment provider
-	paymentInstruction.RouteToPaymentProvider()
-	paymentInstruction.SubmitForProcessing()
+	if !routingDecision.Matched() {
+		zapctx.Warn(ctx, "flow_step #7: no routing rule matches the payment instruction, it has no payment provider",
+			zap.String("id", string(paymentInstruction.ID())),
+			zap.String("sender", incomingInstruction.Metadata.Sender),
+			zap.String("source", incomingInstruction.Metadata.Source),
+		)
+	}
+	paymentInstruction.SetPaymentProvider(routingDecision.PaymentProvider)
+	switch hold := paymentInstruction.IncomingInstruction.FundsHold; {
+	case routingDecision.SourceAccount != "":
+		paymentInstruction.SetSourceAccount(routingDecision.SourceAccount)
+	case routingDecision.PaymentProvider == models.BankingCircle && hold.Account != "":
+		// paid from the account whose funds the file or batch of the payment instruction held
+		paymentInstruction.SetSourceAccount(hold.Account)
+	}
+	if err := paymentInstruction.SubmitForProcessing(); err != nil {
+		return "", err
+	}
 
-	err := m.aggregatePaymentStore.Store(ctx, paymentInstruction)
+	// the outbox relay hands the payment instruction to its payment provider once it is stored
+	if reservation, reserve := m.fundsReservation(ctx, paymentInstruction); reserve {
+		err = m.aggregatePaymentStore.StoreForDispatchReserving(ctx, paymentInstruction, reservation)
+	} else {
+		err = m.aggregatePaymentStore.StoreForDispatch(ctx, paymentInstruction)
+	}
 	if err != nil {
 		switch {
+		case errors.Is(err, postgresql.ErrDuplicateIdempotencyKey):
+			// the same request is being made concurrently and the other one got stored first
+			return m.replayStored(ctx, incomingInstruction, idempotencyKey)
 		case errors.Is(err, postgresql.ErrDuplicate):
-			paymentInstruction.SetStatus(models.Failed)
+			if err := paymentInstruction.SetStatus(models.Failed); err != nil {
+				return "", err
+			}
 			paymentInstruction.AddEvent(models.PaymentInstructionEvent{
 				Type:      models.DomainProcessingFailed,
 				CreatedOn: time.Now(),
@@ -110,17 +176,12 @@ func (m MakePayment) Execute(ctx context.Context, incomingInstruction models.Inc
 			if errFromDB != nil {
 				return "", fmt.Errorf("unable to store duplicated payment as failed %w, %s", errFromDB, err.Error())
 			}
-			return "", err
+			return paymentInstruction.ID(), err
 		default:
 			return "", err
 		}
 	}
 
-	err = m.paymentProviderRequestSender.SendPaymentInstruction(ctx, paymentInstruction)
-	if err != nil {
-		return "", err
-	}
-
 	zapctx.Debug(ctx, "flow_step #7: payment instruction routed",
 		zap.String("id", string(paymentInstruction.ID())),
 		zap.String("merchant_contract_number", incomingInstruction.Merchant.ContractNumber),
@@ -139,3 +200,77 @@ func (m MakePayment) Execute(ctx context.Context, incomingInstruction models.Inc
 
 	return paymentInstruction.ID(), nil
 }
+
+// fundsReservation doesn't fail the payment instruction, it is made without a reservation when its amount can't be read.
+// The reservation is kept against the source account of the payment instruction, one Banking Circle chooses later is
+// not known yet and the reservation is kept against all the accounts of the currency and risk class.
+func (m MakePayment) fundsReservation(ctx context.Context, paymentInstruction models.PaymentInstruction) (models.FundsReservation, bool) {
+	if !m.reserveFunds || paymentInstruction.PaymentProvider() != models.BankingCircle {
+		return models.FundsReservation{}, false
+	}
+
+	amount, err := strconv.ParseFloat(paymentInstruction.IncomingInstruction.Payment.Amount, 64)
+	if err != nil {
+		zapctx.Error(ctx, "[MakePayment] (fundsReservation) unable to reserve the funds of the payment instruction",
+			zap.String("id", string(paymentInstruction.ID())),
+			zap.Error(err),
+		)
+		return models.FundsReservation{}, false
+	}
+
+	return models.FundsReservation{
+		PaymentInstructionID: paymentInstruction.ID(),
+		Currency:             paymentInstruction.IncomingInstruction.IsoCode(),
+		HighRisk:             paymentInstruction.IncomingInstruction.Merchant.HighRisk,
+		Amount:               amount,
+		Account:              paymentInstruction.IncomingInstruction.Payment.Sender.AccountNumber,
+		HoldID:               paymentInstruction.IncomingInstruction.FundsHold.ID,
+	}, true
+}
+
+// replay returns the outcome of the payment instruction already stored with the idempotency key,
+// it reports false when there is none and the incoming instruction is a new payment.
+func (m MakePayment) replay(ctx context.Context, incomingInstruction models.IncomingInstruction, idempotencyKey models.IdempotencyKey) (models.PaymentInstructionID, bool, error) {
+	stored, err := m.aggregatePaymentStore.GetFromIdempotencyKey(ctx, idempotencyKey.Key)
+	if err != nil {
+		var missing postgresql.PaymentInstructionMissingError
+		if errors.As(err, &missing) {
+			return "", false, nil
+		}
+		return "", true, err
+	}
+
+	if stored.IdempotencyKey().RequestFingerprint != idempotencyKey.RequestFingerprint {
+		return "", true, models.IdempotencyKeyConflictError{Key: idempotencyKey.Key, ID: stored.ID()}
+	}
+
+	zapctx.Info(ctx, "[MakePayment] (replay) payment instruction was already received with the same idempotency key",
+		zap.String("id", string(stored.ID())),
+		zap.String("idempotency_key", idempotencyKey.Key),
+		zap.String("merchant_contract_number", incomingInstruction.Merchant.ContractNumber),
+	)
+
+	// a rejected payment instruction is answered with its validation errors and routing rejection, as the first time
+	if stored.GetStatus() == models.Rejected {
+		validationRes := m.paymentInstructionValidator.ValidateIncomingInstruction(incomingInstruction)
+		if routingDecision := m.router.Route(incomingInstruction); routingDecision.Rejected() {
+			validationRes = validationRes.Merge(validation.Invalid(routingDecision.Rejection))
+		}
+		if validationRes.IsValid() {
+			// the rules changed since, it stays rejected for the reason it was rejected with
+			reason, _ := stored.RejectionReason()
+			validationRes = validation.Invalid(reason)
+		}
+		return stored.ID(), true, validationRes
+	}
+
+	return stored.ID(), true, nil
+}
+
+func (m MakePayment) replayStored(ctx context.Context, incomingInstruction models.IncomingInstruction, idempotencyKey models.IdempotencyKey) (models.PaymentInstructionID, error) {
+	id, replayed, err := m.replay(ctx, incomingInstruction, idempotencyKey)
+	if !replayed && err == nil {
+		return "", postgresql.ErrDuplicateIdempotencyKey
+	}
+	return id, err
+}
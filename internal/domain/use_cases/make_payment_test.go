@@ -26,12 +26,12 @@ func TestMakePayment_RoundHUFPaymentsToNextInteger(t *testing.T) {
 	mockValidator := alwaysValidValidatorMock()
 
 	var sentPaymentInstruction models.PaymentInstruction
-	mockPaymentSender := &mocks.PaymentProviderRequestSenderMock{SendPaymentInstructionFunc: func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
+	mockPaymentInstructionRepo.StoreForDispatchFunc = func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
 		sentPaymentInstruction = paymentInstruction
 		return nil
-	}}
+	}
 
-	makePayment := use_cases.NewMakePayment(mockMetricsClient, mockPaymentSender, mockPaymentInstructionRepo, mockValidator)
+	makePayment := use_cases.NewMakePayment(mockMetricsClient, mockPaymentInstructionRepo, mockValidator)
 
 	hufAmounts := []struct {
 		originalAmount              string
@@ -115,3 +115,142 @@ func createHUFPaymentInstructionWithAmount(amount string) models.PaymentInstruct
 		WithIncomingInstruction(ii).
 		Build()
 }
+
+func TestMakePayment_RoutesWithTheRoutingRules(t *testing.T) {
+	routeInstruction := func(decision models.RoutingDecision, incomingInstruction models.IncomingInstruction) (*mocks.StorePaymentInstructionToRepoMock, error) {
+		repo := newPaymentInstructionRepoMock()
+		repo.StoreForDispatchFunc = func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
+			return nil
+		}
+		makePayment := use_cases.NewMakePayment(newEmptyMetricsClientMock(), repo, alwaysValidValidatorMock())
+		makePayment.RouteWith(&mocks.RoutePaymentInstructionMock{RouteFunc: func(instruction models.IncomingInstruction) models.RoutingDecision {
+			return decision
+		}})
+
+		_, err := makePayment.Execute(context.Background(), incomingInstruction)
+		return repo, err
+	}
+	route := func(decision models.RoutingDecision) (*mocks.StorePaymentInstructionToRepoMock, error) {
+		return routeInstruction(decision, testhelpers.NewIncomingInstructionBuilder().Build())
+	}
+
+	t.Run("sets the payment provider and source account of the rule", func(t *testing.T) {
+		repo, err := route(models.RoutingDecision{Rule: "gb", PaymentProvider: models.BankingCircle, SourceAccount: "GB-SOURCE"})
+
+		assert.NoError(t, err)
+		assert.Len(t, repo.StoreForDispatchCalls(), 1)
+		dispatched := repo.StoreForDispatchCalls()[0].Instruction
+		assert.Equal(t, models.BankingCircle, dispatched.PaymentProvider())
+		assert.Equal(t, "GB-SOURCE", dispatched.IncomingInstruction.Payment.Sender.AccountNumber)
+	})
+
+	t.Run("ignores the sender account sent by the caller", func(t *testing.T) {
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().Build()
+		incomingInstruction.Payment.Sender.AccountNumber = "CALLERS-ACCOUNT"
+
+		repo, err := routeInstruction(models.RoutingDecision{Rule: "bc", PaymentProvider: models.BankingCircle}, incomingInstruction)
+
+		assert.NoError(t, err)
+		assert.Len(t, repo.StoreForDispatchCalls(), 1)
+		assert.Empty(t, repo.StoreForDispatchCalls()[0].Instruction.IncomingInstruction.Payment.Sender.AccountNumber)
+
+		repo, err = routeInstruction(models.RoutingDecision{Rule: "gb", PaymentProvider: models.BankingCircle, SourceAccount: "GB-SOURCE"}, incomingInstruction)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "GB-SOURCE", repo.StoreForDispatchCalls()[0].Instruction.IncomingInstruction.Payment.Sender.AccountNumber)
+	})
+
+	t.Run("rejects the instruction when the rule rejects it", func(t *testing.T) {
+		repo, err := route(models.RoutingDecision{Rule: "saxo-isk", Rejection: "SAXO files should not contain ISK currencies"})
+
+		assert.ErrorContains(t, err, "SAXO files should not contain ISK currencies")
+		assert.Len(t, repo.StoreForDispatchCalls(), 0)
+		assert.Len(t, repo.StoreCalls(), 1)
+		assert.Equal(t, models.Rejected, repo.StoreCalls()[0].Instruction.GetStatus())
+	})
+
+	t.Run("returns the ID of the rejected instruction along with why it was rejected", func(t *testing.T) {
+		repo := newPaymentInstructionRepoMock()
+		makePayment := use_cases.NewMakePayment(newEmptyMetricsClientMock(), repo, alwaysValidValidatorMock())
+		makePayment.RouteWith(&mocks.RoutePaymentInstructionMock{RouteFunc: func(instruction models.IncomingInstruction) models.RoutingDecision {
+			return models.RoutingDecision{Rule: "saxo-isk", Rejection: "SAXO files should not contain ISK currencies"}
+		}})
+
+		id, err := makePayment.Execute(context.Background(), testhelpers.NewIncomingInstructionBuilder().Build())
+
+		assert.Error(t, err)
+		assert.Len(t, repo.StoreCalls(), 1)
+		assert.Equal(t, repo.StoreCalls()[0].Instruction.ID(), id)
+	})
+}
+
+func TestMakePayment_ReservesTheFundsOfBankingCirclePayments(t *testing.T) {
+	newMakePayment := func(provider models.PaymentProviderType) (*use_cases.MakePayment, *mocks.StorePaymentInstructionToRepoMock) {
+		repo := newPaymentInstructionRepoMock()
+		repo.StoreForDispatchFunc = func(ctx context.Context, paymentInstruction models.PaymentInstruction) error {
+			return nil
+		}
+		repo.StoreForDispatchReservingFunc = func(ctx context.Context, paymentInstruction models.PaymentInstruction, reservation models.FundsReservation) error {
+			return nil
+		}
+		makePayment := use_cases.NewMakePayment(newEmptyMetricsClientMock(), repo, alwaysValidValidatorMock())
+		makePayment.RouteWith(&mocks.RoutePaymentInstructionMock{RouteFunc: func(instruction models.IncomingInstruction) models.RoutingDecision {
+			return models.RoutingDecision{Rule: "test", PaymentProvider: provider}
+		}})
+		makePayment.ReserveFunds()
+		return makePayment, repo
+	}
+
+	t.Run("reserves the amount of a Banking Circle payment along with storing it", func(t *testing.T) {
+		makePayment, repo := newMakePayment(models.BankingCircle)
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithHighRIsk().WithPaymentAmount("1250.75").Build()
+
+		id, err := makePayment.Execute(context.Background(), incomingInstruction)
+
+		assert.NoError(t, err)
+		assert.Empty(t, repo.StoreForDispatchCalls())
+		assert.Len(t, repo.StoreForDispatchReservingCalls(), 1)
+		assert.Equal(t, id, repo.StoreForDispatchReservingCalls()[0].Instruction.ID())
+		assert.Equal(t, models.FundsReservation{
+			PaymentInstructionID: id,
+			Currency:             models.EUR,
+			HighRisk:             true,
+			Amount:               1250.75,
+		}, repo.StoreForDispatchReservingCalls()[0].Reservation)
+	})
+
+	t.Run("takes the reservation out of the hold of the file or batch of the payment", func(t *testing.T) {
+		makePayment, repo := newMakePayment(models.BankingCircle)
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithPaymentAmount("10").Build()
+		incomingInstruction.FundsHold = models.FundsHold{ID: "hold-1", Currency: models.EUR, Amount: 100}
+
+		_, err := makePayment.Execute(context.Background(), incomingInstruction)
+
+		assert.NoError(t, err)
+		assert.Len(t, repo.StoreForDispatchReservingCalls(), 1)
+		assert.Equal(t, models.FundsHoldID("hold-1"), repo.StoreForDispatchReservingCalls()[0].Reservation.HoldID)
+	})
+
+	t.Run("pays from and reserves against the account the hold of the file or batch of the payment is held in", func(t *testing.T) {
+		makePayment, repo := newMakePayment(models.BankingCircle)
+		incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithPaymentAmount("10").Build()
+		incomingInstruction.FundsHold = models.FundsHold{ID: "hold-1", Currency: models.EUR, Amount: 100, Account: "EUR-2"}
+
+		_, err := makePayment.Execute(context.Background(), incomingInstruction)
+
+		assert.NoError(t, err)
+		assert.Len(t, repo.StoreForDispatchReservingCalls(), 1)
+		assert.Equal(t, "EUR-2", repo.StoreForDispatchReservingCalls()[0].Instruction.IncomingInstruction.Payment.Sender.AccountNumber)
+		assert.Equal(t, "EUR-2", repo.StoreForDispatchReservingCalls()[0].Reservation.Account)
+	})
+
+	t.Run("doesn't reserve the funds of other payment providers", func(t *testing.T) {
+		makePayment, repo := newMakePayment(models.Islandsbanki)
+
+		_, err := makePayment.Execute(context.Background(), testhelpers.NewIncomingInstructionBuilder().Build())
+
+		assert.NoError(t, err)
+		assert.Len(t, repo.StoreForDispatchCalls(), 1)
+		assert.Empty(t, repo.StoreForDispatchReservingCalls())
+	})
+}
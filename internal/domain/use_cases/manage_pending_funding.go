@@ -0,0 +1,160 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	pendingFundingHeldBack = "app_settlements_pending_funding_held_back"
+	pendingFundingReleased = "app_settlements_pending_funding_released"
+)
+
+type ManagePendingFunding struct {
+	repo                                 ports.PendingFundingRepo
+	makePayment                          ports.MakePayment
+	checkPaymentAccountFundsAvailability ports.CheckPaymentAccountFundsAvailability
+	metricsClient                        ports.MetricsClient
+}
+
+var _ ports.ManagePendingFunding = ManagePendingFunding{}
+
+func NewManagePendingFunding(
+	repo ports.PendingFundingRepo,
+	makePayment ports.MakePayment,
+	checkPaymentAccountFundsAvailability ports.CheckPaymentAccountFundsAvailability,
+	metricsClient ports.MetricsClient,
+) ManagePendingFunding {
+	return ManagePendingFunding{
+		repo:                                 repo,
+		makePayment:                          makePayment,
+		checkPaymentAccountFundsAvailability: checkPaymentAccountFundsAvailability,
+		metricsClient:                        metricsClient,
+	}
+}
+
+func (m ManagePendingFunding) HoldBack(ctx context.Context, filename string, summary models.IncomingInstructionsSummary, instructions models.IncomingInstructions) (models.PendingFunding, error) {
+	pendingFunding := models.NewPendingFunding(filename, summary, instructions)
+	if err := m.repo.SavePendingFunding(ctx, pendingFunding); err != nil {
+		return models.PendingFunding{}, err
+	}
+
+	zapctx.Warn(ctx, "[ManagePendingFunding] (HoldBack) currency held back until the source account is funded",
+		zap.String("pending_funding_id", string(pendingFunding.ID)),
+		zap.String("file_name", filename),
+		zap.String("currency", string(summary.CurrencyCode)),
+		zap.Bool("high_risk", summary.HighRisk),
+		zap.Float64("amount", summary.Amount),
+	)
+	m.metricsClient.Count(ctx, pendingFundingHeldBack, 1, []string{string(summary.CurrencyCode)})
+
+	return pendingFunding, nil
+}
+
+func (m ManagePendingFunding) List(ctx context.Context, state models.PendingFundingState) ([]models.PendingFunding, error) {
+	return m.repo.ListPendingFundings(ctx, state)
+}
+
+func (m ManagePendingFunding) Get(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error) {
+	return m.repo.GetPendingFunding(ctx, id)
+}
+
+// RecheckAll checks the funds of every pending batch and releases the ones that can now be paid.
+// Batches are checked one after the other so each check sees the balance left by the previous release.
+func (m ManagePendingFunding) RecheckAll(ctx context.Context) {
+	pendingFundings, err := m.repo.ListPendingFundings(ctx, models.PendingFundingPending)
+	if err != nil {
+		zapctx.Error(ctx, "[ManagePendingFunding] (RecheckAll) error listing pending fundings", zap.Error(err))
+		return
+	}
+
+	for _, pendingFunding := range pendingFundings {
+		if err := m.repo.RecordPendingFundingCheck(ctx, pendingFunding.ID); err != nil {
+			zapctx.Error(ctx, "[ManagePendingFunding] (RecheckAll) error recording check",
+				zap.String("pending_funding_id", string(pendingFunding.ID)),
+				zap.Error(err),
+			)
+		}
+
+		hold, hasFunds, err := m.checkPaymentAccountFundsAvailability.Hold(ctx, models.NewFundsHoldID(), pendingFunding.Currency, pendingFunding.Amount, pendingFunding.HighRisk)
+		if err != nil || !hasFunds {
+			continue
+		}
+
+		if _, err := m.release(ctx, pendingFunding, "funds became available", hold); err != nil {
+			zapctx.Error(ctx, "[ManagePendingFunding] (RecheckAll) error releasing pending funding",
+				zap.String("pending_funding_id", string(pendingFunding.ID)),
+				zap.Error(err),
+			)
+		}
+		m.checkPaymentAccountFundsAvailability.ReleaseHold(ctx, hold.ID)
+	}
+}
+
+// Release pays a pending batch without checking the funds first.
+func (m ManagePendingFunding) Release(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error) {
+	pendingFunding, err := m.repo.GetPendingFunding(ctx, id)
+	if err != nil {
+		return models.PendingFunding{}, err
+	}
+
+	return m.release(ctx, pendingFunding, "force released", models.FundsHold{})
+}
+
+func (m ManagePendingFunding) Cancel(ctx context.Context, id models.PendingFundingID, reason string) (models.PendingFunding, error) {
+	if err := m.repo.UpdatePendingFundingState(ctx, id, models.PendingFundingPending, models.PendingFundingCancelled, reason); err != nil {
+		return models.PendingFunding{}, err
+	}
+
+	zapctx.Info(ctx, "[ManagePendingFunding] (Cancel) pending funding cancelled",
+		zap.String("pending_funding_id", string(id)),
+		zap.String("reason", reason),
+	)
+
+	return m.repo.GetPendingFunding(ctx, id)
+}
+
+// release reserves the funds of the payment instructions out of the hold of the recheck, a forced release has none.
+func (m ManagePendingFunding) release(ctx context.Context, pendingFunding models.PendingFunding, reason string, hold models.FundsHold) (models.PendingFunding, error) {
+	// claiming the batch before paying guarantees the scheduler and an operator can't both pay it
+	err := m.repo.UpdatePendingFundingState(ctx, pendingFunding.ID, models.PendingFundingPending, models.PendingFundingReleased, reason)
+	if err != nil {
+		return models.PendingFunding{}, err
+	}
+
+	failed := 0
+	for _, instruction := range pendingFunding.Instructions {
+		instruction.FundsHold = hold
+		if _, err := m.makePayment.Execute(ctx, instruction); err != nil {
+			failed++
+			zapctx.Error(ctx, "[ManagePendingFunding] (release) error making payment",
+				zap.String("pending_funding_id", string(pendingFunding.ID)),
+				zap.String("merchant_contract_number", instruction.Merchant.ContractNumber),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if failed > 0 {
+		reason = fmt.Sprintf("%s, %d of %d payment instructions failed", reason, failed, len(pendingFunding.Instructions))
+		err := m.repo.UpdatePendingFundingState(ctx, pendingFunding.ID, models.PendingFundingReleased, models.PendingFundingReleased, reason)
+		if err != nil {
+			zapctx.Error(ctx, "[ManagePendingFunding] (release) error recording failed payments", zap.Error(err))
+		}
+	}
+
+	zapctx.Info(ctx, "[ManagePendingFunding] (release) pending funding released",
+		zap.String("pending_funding_id", string(pendingFunding.ID)),
+		zap.String("currency", string(pendingFunding.Currency)),
+		zap.String("reason", reason),
+	)
+	m.metricsClient.Count(ctx, pendingFundingReleased, 1, []string{string(pendingFunding.Currency)})
+
+	return m.repo.GetPendingFunding(ctx, pendingFunding.ID)
+}
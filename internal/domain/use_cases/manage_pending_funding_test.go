@@ -0,0 +1,180 @@
+//go:build unit
+// +build unit
+
+package use_cases_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/use_cases"
+)
+
+func TestManagePendingFunding(t *testing.T) {
+	t.Run("RecheckAll releases only the batches whose funds became available", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newInMemoryPendingFundingRepo()
+		spyMakePayment := newSucceedingMakePaymentMock()
+		stubFunds := &mocks.CheckPaymentAccountFundsAvailabilityMock{
+			ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {},
+			HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+				return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, code == models.EUR, nil
+			},
+		}
+		useCase := use_cases.NewManagePendingFunding(repo, spyMakePayment, stubFunds, newEmptyMetricsClientMock())
+
+		// Given a EUR and a GBP batch held back
+		eur, err := useCase.HoldBack(ctx, "file.xml", models.IncomingInstructionsSummary{CurrencyCode: models.EUR, Counter: 1, Amount: 10}, instructionsIn(models.EUR))
+		require.NoError(t, err)
+		gbp, err := useCase.HoldBack(ctx, "file.xml", models.IncomingInstructionsSummary{CurrencyCode: models.GBP, Counter: 1, Amount: 10}, instructionsIn(models.GBP))
+		require.NoError(t, err)
+
+		// When only EUR is funded
+		useCase.RecheckAll(ctx)
+
+		// Then only the EUR batch is paid
+		assert.Len(t, spyMakePayment.ExecuteCalls(), 1)
+		assert.Equal(t, models.EUR, spyMakePayment.ExecuteCalls()[0].IncomingInstruction.Payment.Currency.IsoCode)
+		assert.Equal(t, models.PendingFundingReleased, repo.get(eur.ID).State)
+
+		// And its payments reserve their amounts out of the hold of the recheck
+		for _, held := range stubFunds.HoldCalls() {
+			if held.Code == models.EUR {
+				assert.Equal(t, held.ID, spyMakePayment.ExecuteCalls()[0].IncomingInstruction.FundsHold.ID)
+			}
+		}
+		assert.Equal(t, models.PendingFundingPending, repo.get(gbp.ID).State)
+
+		// And both batches were checked once
+		assert.Equal(t, 1, repo.get(eur.ID).Checks)
+		assert.Equal(t, 1, repo.get(gbp.ID).Checks)
+	})
+
+	t.Run("Release pays a batch regardless of funds and can't pay it twice", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newInMemoryPendingFundingRepo()
+		spyMakePayment := newSucceedingMakePaymentMock()
+		spyFunds := &mocks.CheckPaymentAccountFundsAvailabilityMock{}
+		useCase := use_cases.NewManagePendingFunding(repo, spyMakePayment, spyFunds, newEmptyMetricsClientMock())
+
+		pendingFunding, err := useCase.HoldBack(ctx, "file.xml", models.IncomingInstructionsSummary{CurrencyCode: models.EUR, Counter: 1, Amount: 10}, instructionsIn(models.EUR))
+		require.NoError(t, err)
+
+		released, err := useCase.Release(ctx, pendingFunding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PendingFundingReleased, released.State)
+		assert.Len(t, spyMakePayment.ExecuteCalls(), 1)
+		assert.Empty(t, spyFunds.HoldCalls())
+
+		_, err = useCase.Release(ctx, pendingFunding.ID)
+		assert.Error(t, err)
+		assert.Len(t, spyMakePayment.ExecuteCalls(), 1)
+	})
+
+	t.Run("Cancel stops a batch from ever being paid", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newInMemoryPendingFundingRepo()
+		spyMakePayment := newSucceedingMakePaymentMock()
+		stubFunds := &mocks.CheckPaymentAccountFundsAvailabilityMock{
+			ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {},
+			HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+				return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, true, nil
+			},
+		}
+		useCase := use_cases.NewManagePendingFunding(repo, spyMakePayment, stubFunds, newEmptyMetricsClientMock())
+
+		pendingFunding, err := useCase.HoldBack(ctx, "file.xml", models.IncomingInstructionsSummary{CurrencyCode: models.EUR, Counter: 1, Amount: 10}, instructionsIn(models.EUR))
+		require.NoError(t, err)
+
+		cancelled, err := useCase.Cancel(ctx, pendingFunding.ID, "paid manually")
+		require.NoError(t, err)
+		assert.Equal(t, models.PendingFundingCancelled, cancelled.State)
+		assert.Equal(t, "paid manually", cancelled.StateReason)
+
+		useCase.RecheckAll(ctx)
+		assert.Empty(t, spyMakePayment.ExecuteCalls())
+	})
+}
+
+func instructionsIn(currency models.CurrencyCode) models.IncomingInstructions {
+	instruction := testhelpers.NewIncomingInstructionBuilder().Build()
+	instruction.Payment.Currency.IsoCode = currency
+	return models.IncomingInstructions{instruction}
+}
+
+func newSucceedingMakePaymentMock() *mocks.MakePaymentMock {
+	return &mocks.MakePaymentMock{
+		ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+			return "id", nil
+		},
+	}
+}
+
+type inMemoryPendingFundingRepo struct {
+	*mocks.PendingFundingRepoMock
+	mu              sync.Mutex
+	pendingFundings map[models.PendingFundingID]models.PendingFunding
+	order           []models.PendingFundingID
+}
+
+func newInMemoryPendingFundingRepo() *inMemoryPendingFundingRepo {
+	repo := &inMemoryPendingFundingRepo{pendingFundings: map[models.PendingFundingID]models.PendingFunding{}}
+	repo.PendingFundingRepoMock = &mocks.PendingFundingRepoMock{
+		SavePendingFundingFunc: func(ctx context.Context, pendingFunding models.PendingFunding) error {
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			repo.pendingFundings[pendingFunding.ID] = pendingFunding
+			repo.order = append(repo.order, pendingFunding.ID)
+			return nil
+		},
+		GetPendingFundingFunc: func(ctx context.Context, id models.PendingFundingID) (models.PendingFunding, error) {
+			return repo.get(id), nil
+		},
+		ListPendingFundingsFunc: func(ctx context.Context, state models.PendingFundingState) ([]models.PendingFunding, error) {
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			var result []models.PendingFunding
+			for _, id := range repo.order {
+				if state == "" || repo.pendingFundings[id].State == state {
+					result = append(result, repo.pendingFundings[id])
+				}
+			}
+			return result, nil
+		},
+		RecordPendingFundingCheckFunc: func(ctx context.Context, id models.PendingFundingID) error {
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			pendingFunding := repo.pendingFundings[id]
+			pendingFunding.Checks++
+			repo.pendingFundings[id] = pendingFunding
+			return nil
+		},
+		UpdatePendingFundingStateFunc: func(ctx context.Context, id models.PendingFundingID, from models.PendingFundingState, to models.PendingFundingState, reason string) error {
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			pendingFunding := repo.pendingFundings[id]
+			if pendingFunding.State != from {
+				return errors.New("state conflict")
+			}
+			pendingFunding.State = to
+			pendingFunding.StateReason = reason
+			repo.pendingFundings[id] = pendingFunding
+			return nil
+		},
+	}
+	return repo
+}
+
+func (r *inMemoryPendingFundingRepo) get(id models.PendingFundingID) models.PendingFunding {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pendingFundings[id]
+}
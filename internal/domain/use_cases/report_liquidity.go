@@ -0,0 +1,69 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+type ReportLiquidity struct {
+	paymentProviderRetrieveBalance ports.PaymentProviderRetrieveBalance
+	liquidityLedger                ports.LiquidityLedger
+}
+
+var _ ports.ReportLiquidity = ReportLiquidity{}
+
+func NewReportLiquidity(
+	paymentProviderRetrieveBalance ports.PaymentProviderRetrieveBalance,
+	liquidityLedger ports.LiquidityLedger,
+) ReportLiquidity {
+	return ReportLiquidity{
+		paymentProviderRetrieveBalance: paymentProviderRetrieveBalance,
+		liquidityLedger:                liquidityLedger,
+	}
+}
+
+// riskClass is the currency and risk class a source account pays for.
+type riskClass struct {
+	Currency models.CurrencyCode
+	HighRisk bool
+}
+
+// Headroom reports both risk classes of the currencies given, and only the risk classes with outstanding reservations
+// otherwise.
+func (r ReportLiquidity) Headroom(ctx context.Context, currencies []models.CurrencyCode) ([]models.LiquidityHeadroom, error) {
+	reserved, err := r.liquidityLedger.Reserved(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservedByClass := make(map[riskClass]float64)
+	classes := make([]riskClass, 0)
+	for _, funds := range reserved {
+		class := riskClass{Currency: funds.Currency, HighRisk: funds.HighRisk}
+		reservedByClass[class] = funds.Amount
+		if len(currencies) == 0 {
+			classes = append(classes, class)
+		}
+	}
+	for _, currency := range currencies {
+		classes = append(classes, riskClass{Currency: currency}, riskClass{Currency: currency, HighRisk: true})
+	}
+
+	headroom := make([]models.LiquidityHeadroom, 0, len(classes))
+	for _, class := range classes {
+		balance, maxIntraDayLoan, err := r.paymentProviderRetrieveBalance.RetrieveBalanceForCurrency(class.Currency, class.HighRisk, 0)
+		if err != nil {
+			if len(currencies) > 0 && reservedByClass[class] == 0 {
+				// the currency has no account for this risk class
+				continue
+			}
+			return nil, fmt.Errorf("unable to retrieve the balance of the %s account (high risk: %t), err: %w", class.Currency, class.HighRisk, err)
+		}
+		headroom = append(headroom, models.NewLiquidityHeadroom(class.Currency, class.HighRisk, balance, maxIntraDayLoan, reservedByClass[class]))
+	}
+
+	return headroom, nil
+}
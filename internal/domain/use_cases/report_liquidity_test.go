@@ -0,0 +1,45 @@
+//go:build unit
+// +build unit
+
+package use_cases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/use_cases"
+)
+
+func TestReportLiquidity(t *testing.T) {
+	ctx := context.Background()
+	ledger := &mocks.LiquidityLedgerMock{ReservedFunc: func(ctx context.Context) ([]models.ReservedFunds, error) {
+		return []models.ReservedFunds{{Currency: models.EUR, HighRisk: true, Amount: 300}}, nil
+	}}
+	balances := &mocks.PaymentProviderRetrieveBalanceMock{RetrieveBalanceForCurrencyFunc: func(currency models.CurrencyCode, highRisk bool, amount float64) (float64, float64, error) {
+		if currency == models.GBP && highRisk {
+			return 0, 0, errors.New("account not found")
+		}
+		return 1000, 200, nil
+	}}
+	reportLiquidity := use_cases.NewReportLiquidity(balances, ledger)
+
+	t.Run("reports the risk classes with outstanding reservations", func(t *testing.T) {
+		headroom, err := reportLiquidity.Headroom(ctx, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.LiquidityHeadroom{{Currency: models.EUR, HighRisk: true, Balance: 1000, MaxIntraDayLoan: 200, Reserved: 300, Free: 900}}, headroom)
+	})
+
+	t.Run("reports the risk classes of the currencies that have an account", func(t *testing.T) {
+		headroom, err := reportLiquidity.Headroom(ctx, []models.CurrencyCode{models.GBP})
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.LiquidityHeadroom{{Currency: models.GBP, Balance: 1000, MaxIntraDayLoan: 200, Free: 1200}}, headroom)
+	})
+}
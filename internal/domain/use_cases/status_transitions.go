@@ -0,0 +1,38 @@
+package use_cases
+
+import (
+	"context"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const transitionRejectedMetricName = "app_payment_instruction_transition_rejected"
+
+// rejectTransition stores the refused status change as an event of the payment instruction, without changing its status,
+// and returns the models.IllegalTransitionError unless the event couldn't be stored.
+func rejectTransition(
+	ctx context.Context,
+	paymentStore ports.StorePaymentInstructionToRepo,
+	metrics ports.MetricsClient,
+	paymentInstruction models.PaymentInstruction,
+	illegalTransition models.IllegalTransitionError,
+) error {
+	expectedVersion := paymentInstruction.Version()
+	event := paymentInstruction.RejectTransition(illegalTransition)
+	if err := paymentStore.UpdatePayment(ctx, paymentInstruction.ID(), expectedVersion, paymentInstruction.GetStatus(), event); err != nil {
+		return err
+	}
+
+	zapctx.Warn(ctx, "payment instruction status change rejected",
+		zap.String("paymentInstructionID", string(illegalTransition.ID)),
+		zap.String("from", string(illegalTransition.From)),
+		zap.String("to", string(illegalTransition.To)),
+	)
+	metrics.Count(ctx, transitionRejectedMetricName, 1, []string{string(illegalTransition.From), string(illegalTransition.To)})
+
+	return illegalTransition
+}
@@ -0,0 +1,172 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	paymentBatchItemsMetricName = "app_settlements_payment_batch_items"
+	// MaxPaymentBatchItems is the most incoming instructions a batch can have.
+	MaxPaymentBatchItems = 1000
+)
+
+type SubmitPaymentBatch struct {
+	repo                                 ports.PaymentBatchRepo
+	makePayment                          ports.MakePayment
+	checkPaymentAccountFundsAvailability ports.CheckPaymentAccountFundsAvailability
+	metricsClient                        ports.MetricsClient
+}
+
+var _ ports.SubmitPaymentBatch = SubmitPaymentBatch{}
+
+func NewSubmitPaymentBatch(
+	repo ports.PaymentBatchRepo,
+	makePayment ports.MakePayment,
+	checkPaymentAccountFundsAvailability ports.CheckPaymentAccountFundsAvailability,
+	metricsClient ports.MetricsClient,
+) SubmitPaymentBatch {
+	return SubmitPaymentBatch{
+		repo:                                 repo,
+		makePayment:                          makePayment,
+		checkPaymentAccountFundsAvailability: checkPaymentAccountFundsAvailability,
+		metricsClient:                        metricsClient,
+	}
+}
+
+// Submit saves the batch and returns it straight away, its items are processed in the background and their outcomes
+// can be followed with Get. The batch gets the same checks as a UFX file, then a payment is made of every item in the
+// currencies with enough funds, the batch is saved after every item.
+// With an idempotency key every item gets a key of its own, so a retried batch doesn't pay its items twice.
+func (s SubmitPaymentBatch) Submit(ctx context.Context, instructions models.IncomingInstructions, idempotencyKey string) (models.PaymentBatch, error) {
+	if len(instructions) == 0 {
+		return models.PaymentBatch{}, models.PaymentBatchRejectedError{Reason: "the batch has no incoming instructions"}
+	}
+	if len(instructions) > MaxPaymentBatchItems {
+		return models.PaymentBatch{}, models.PaymentBatchRejectedError{Reason: fmt.Sprintf("the batch has %d incoming instructions, at most %d are allowed", len(instructions), MaxPaymentBatchItems)}
+	}
+
+	summary, err := instructions.SumByCurrency()
+	if err != nil {
+		return models.PaymentBatch{}, models.PaymentBatchRejectedError{Reason: err.Error()}
+	}
+
+	batch := models.NewPaymentBatch(instructions)
+	if err := s.repo.SavePaymentBatch(ctx, batch); err != nil {
+		return models.PaymentBatch{}, err
+	}
+
+	// the batch outlives the request, a client going away mustn't stop it halfway
+	go s.process(detached{parent: ctx}, batch, instructions, summary, idempotencyKey)
+
+	return batch, nil
+}
+
+func (s SubmitPaymentBatch) process(ctx context.Context, batch models.PaymentBatch, instructions models.IncomingInstructions, summary []models.IncomingInstructionsSummary, idempotencyKey string) {
+	holds := s.rejectUnfundedCurrencies(ctx, &batch, summary)
+	for _, hold := range holds {
+		instructions = instructions.WithFundsHold(hold)
+	}
+
+	for i, instruction := range instructions {
+		if batch.Items[i].Status != models.PaymentBatchItemPending {
+			continue
+		}
+		if idempotencyKey != "" {
+			instruction.IdempotencyKey = fmt.Sprintf("%s-%d", idempotencyKey, i)
+		}
+
+		id, err := s.makePayment.Execute(ctx, instruction)
+		if err != nil {
+			batch.Reject(i, err.Error())
+		} else {
+			batch.Accept(i, id)
+		}
+		s.save(ctx, batch)
+	}
+
+	// the payment instructions took their reservations out of the holds by now
+	for _, hold := range holds {
+		s.checkPaymentAccountFundsAvailability.ReleaseHold(ctx, hold.ID)
+	}
+
+	batch.Complete()
+	s.save(ctx, batch)
+
+	for _, item := range batch.Items {
+		s.metricsClient.Count(ctx, paymentBatchItemsMetricName, 1, []string{string(item.Currency), string(item.Status)})
+	}
+	zapctx.Info(ctx, "[SubmitPaymentBatch] (process) payment batch processed",
+		zap.String("payment_batch_id", string(batch.ID)),
+		zap.Int("total", len(batch.Items)),
+		zap.Int("accepted", batch.Count(models.PaymentBatchItemAccepted)),
+		zap.Int("rejected", batch.Count(models.PaymentBatchItemRejected)),
+	)
+}
+
+func (s SubmitPaymentBatch) Get(ctx context.Context, id models.PaymentBatchID) (models.PaymentBatch, error) {
+	return s.repo.GetPaymentBatch(ctx, id)
+}
+
+// rejectUnfundedCurrencies rejects the items of every currency the source account can't pay in full and holds the funds
+// of the others, ISK is paid by Islandsbanki and not checked, as in a UFX file.
+func (s SubmitPaymentBatch) rejectUnfundedCurrencies(ctx context.Context, batch *models.PaymentBatch, summary []models.IncomingInstructionsSummary) []models.FundsHold {
+	var holds []models.FundsHold
+	for _, sum := range summary {
+		if sum.CurrencyCode == models.ISK {
+			continue
+		}
+
+		reason := ""
+		hold, hasBalance, err := s.checkPaymentAccountFundsAvailability.Hold(ctx, models.NewFundsHoldID(), sum.CurrencyCode, sum.Amount, sum.HighRisk)
+		switch {
+		case err != nil:
+			zapctx.Error(ctx, "[SubmitPaymentBatch] (rejectUnfundedCurrencies) error checking balance for currency",
+				zap.String("payment_batch_id", string(batch.ID)),
+				zap.String("currency", string(sum.CurrencyCode)),
+				zap.Error(err),
+			)
+			reason = fmt.Sprintf("unable to check the funds of the %s account: %v", sum.CurrencyCode, err)
+		case !hasBalance:
+			reason = fmt.Sprintf("not enough funds in the %s account to pay %v", sum.CurrencyCode, sum.Amount)
+		default:
+			holds = append(holds, hold)
+			continue
+		}
+
+		for i, item := range batch.Items {
+			if item.Currency == sum.CurrencyCode {
+				batch.Reject(i, reason)
+			}
+		}
+	}
+	return holds
+}
+
+// save only logs a failure, the payments are made already and the next save records them.
+func (s SubmitPaymentBatch) save(ctx context.Context, batch models.PaymentBatch) {
+	if err := s.repo.SavePaymentBatch(ctx, batch); err != nil {
+		zapctx.Error(ctx, "[SubmitPaymentBatch] (save) error saving payment batch",
+			zap.String("payment_batch_id", string(batch.ID)),
+			zap.String("state", string(batch.State)),
+			zap.Error(err),
+		)
+	}
+}
+
+// detached keeps the values of its parent context, like its logger, without being cancelled along with it.
+type detached struct {
+	parent context.Context
+}
+
+func (d detached) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (d detached) Done() <-chan struct{}             { return nil }
+func (d detached) Err() error                        { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
@@ -0,0 +1,196 @@
+//go:build unit
+// +build unit
+
+package use_cases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/use_cases"
+)
+
+func TestSubmitPaymentBatch(t *testing.T) {
+	newRepo := func() *mocks.PaymentBatchRepoMock {
+		return &mocks.PaymentBatchRepoMock{
+			SavePaymentBatchFunc: func(ctx context.Context, batch models.PaymentBatch) error {
+				return nil
+			},
+		}
+	}
+	fundedIn := func(currencies ...models.CurrencyCode) *mocks.CheckPaymentAccountFundsAvailabilityMock {
+		return &mocks.CheckPaymentAccountFundsAvailabilityMock{
+			ReleaseHoldFunc: func(ctx context.Context, id models.FundsHoldID) {},
+			HoldFunc: func(ctx context.Context, id models.FundsHoldID, code models.CurrencyCode, amount float64, highRisk bool) (models.FundsHold, bool, error) {
+				for _, currency := range currencies {
+					if currency == code {
+						return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, true, nil
+					}
+				}
+				return models.FundsHold{ID: id, Currency: code, HighRisk: highRisk, Amount: amount}, false, nil
+			},
+		}
+	}
+	// processed waits for the batch to be processed in the background and returns it as it was saved last
+	processed := func(t *testing.T, repo *mocks.PaymentBatchRepoMock) models.PaymentBatch {
+		t.Helper()
+		var batch models.PaymentBatch
+		require.Eventually(t, func() bool {
+			calls := repo.SavePaymentBatchCalls()
+			batch = calls[len(calls)-1].Batch
+			return batch.State == models.PaymentBatchCompleted
+		}, time.Second, time.Millisecond)
+		return batch
+	}
+	batchOf := func(currencies ...models.CurrencyCode) models.IncomingInstructions {
+		var instructions models.IncomingInstructions
+		for _, currency := range currencies {
+			instructions = append(instructions, instructionsIn(currency)...)
+		}
+		return instructions
+	}
+
+	t.Run("pays every item in a funded currency and rejects the items of the others", func(t *testing.T) {
+		var (
+			ctx            = context.Background()
+			repo           = newRepo()
+			spyMakePayment = newSucceedingMakePaymentMock()
+			useCase        = use_cases.NewSubmitPaymentBatch(repo, spyMakePayment, fundedIn(models.EUR), newEmptyMetricsClientMock())
+		)
+
+		submitted, err := useCase.Submit(ctx, batchOf(models.EUR, models.GBP, models.EUR), "")
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentBatchProcessing, submitted.State)
+
+		batch := processed(t, repo)
+		assert.Equal(t, submitted.ID, batch.ID)
+		assert.Equal(t, models.PaymentBatchItemAccepted, batch.Items[0].Status)
+		assert.Equal(t, models.PaymentInstructionID("id"), batch.Items[0].PaymentInstructionID)
+		assert.Equal(t, models.PaymentBatchItemRejected, batch.Items[1].Status)
+		assert.Contains(t, batch.Items[1].Reason, "not enough funds in the GBP account")
+		assert.Equal(t, models.PaymentBatchItemAccepted, batch.Items[2].Status)
+		assert.Len(t, spyMakePayment.ExecuteCalls(), 2)
+
+		// the payments reserve their amounts out of the hold of their currency
+		hold := spyMakePayment.ExecuteCalls()[0].IncomingInstruction.FundsHold
+		assert.NotEmpty(t, hold.ID)
+		assert.Equal(t, models.EUR, hold.Currency)
+		assert.Equal(t, hold, spyMakePayment.ExecuteCalls()[1].IncomingInstruction.FundsHold)
+	})
+
+	t.Run("records the error of a payment that couldn't be made and carries on with the batch", func(t *testing.T) {
+		var (
+			ctx            = context.Background()
+			spyMakePayment = &mocks.MakePaymentMock{
+				ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+					if incomingInstruction.Merchant.ContractNumber == "invalid" {
+						return "", errors.New("account number is missing")
+					}
+					return "id", nil
+				},
+			}
+			repo         = newRepo()
+			useCase      = use_cases.NewSubmitPaymentBatch(repo, spyMakePayment, fundedIn(models.EUR), newEmptyMetricsClientMock())
+			instructions = batchOf(models.EUR, models.EUR)
+		)
+		instructions[0].Merchant.ContractNumber = "invalid"
+
+		_, err := useCase.Submit(ctx, instructions, "")
+		require.NoError(t, err)
+
+		batch := processed(t, repo)
+		assert.Equal(t, models.PaymentBatchItemRejected, batch.Items[0].Status)
+		assert.Equal(t, "account number is missing", batch.Items[0].Reason)
+		assert.Equal(t, models.PaymentBatchItemAccepted, batch.Items[1].Status)
+	})
+
+	t.Run("gives every item an idempotency key of its own", func(t *testing.T) {
+		var (
+			ctx            = context.Background()
+			repo           = newRepo()
+			spyMakePayment = newSucceedingMakePaymentMock()
+			useCase        = use_cases.NewSubmitPaymentBatch(repo, spyMakePayment, fundedIn(models.EUR), newEmptyMetricsClientMock())
+		)
+
+		_, err := useCase.Submit(ctx, batchOf(models.EUR, models.EUR), "some-key")
+		require.NoError(t, err)
+		processed(t, repo)
+
+		require.Len(t, spyMakePayment.ExecuteCalls(), 2)
+		assert.Equal(t, "some-key-0", spyMakePayment.ExecuteCalls()[0].IncomingInstruction.IdempotencyKey)
+		assert.Equal(t, "some-key-1", spyMakePayment.ExecuteCalls()[1].IncomingInstruction.IdempotencyKey)
+	})
+
+	t.Run("rejects a batch mixing high risk and other payments without paying any of them", func(t *testing.T) {
+		var (
+			ctx            = context.Background()
+			repo           = newRepo()
+			spyMakePayment = newSucceedingMakePaymentMock()
+			useCase        = use_cases.NewSubmitPaymentBatch(repo, spyMakePayment, fundedIn(models.EUR), newEmptyMetricsClientMock())
+			instructions   = models.IncomingInstructions{
+				testhelpers.NewIncomingInstructionBuilder().Build(),
+				testhelpers.NewIncomingInstructionBuilder().WithHighRIsk().Build(),
+			}
+		)
+
+		_, err := useCase.Submit(ctx, instructions, "")
+
+		var rejected models.PaymentBatchRejectedError
+		assert.True(t, errors.As(err, &rejected))
+		assert.Empty(t, spyMakePayment.ExecuteCalls())
+		assert.Empty(t, repo.SavePaymentBatchCalls())
+	})
+
+	t.Run("rejects an empty batch", func(t *testing.T) {
+		useCase := use_cases.NewSubmitPaymentBatch(newRepo(), newSucceedingMakePaymentMock(), fundedIn(), newEmptyMetricsClientMock())
+
+		_, err := useCase.Submit(context.Background(), models.IncomingInstructions{}, "")
+
+		assert.Equal(t, models.PaymentBatchRejectedError{Reason: "the batch has no incoming instructions"}, err)
+	})
+
+	t.Run("carries on with the batch once the request is cancelled", func(t *testing.T) {
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			repo        = newRepo()
+			makePayment = &mocks.MakePaymentMock{
+				ExecuteFunc: func(ctx context.Context, incomingInstruction models.IncomingInstruction) (models.PaymentInstructionID, error) {
+					return "id", ctx.Err()
+				},
+			}
+			useCase = use_cases.NewSubmitPaymentBatch(repo, makePayment, fundedIn(models.EUR), newEmptyMetricsClientMock())
+		)
+
+		// the client has gone away by the time the items are processed
+		cancel()
+		_, err := useCase.Submit(ctx, batchOf(models.EUR, models.EUR), "")
+		require.NoError(t, err)
+
+		batch := processed(t, repo)
+		assert.Equal(t, 2, batch.Count(models.PaymentBatchItemAccepted))
+	})
+
+	t.Run("rejects a batch with too many incoming instructions", func(t *testing.T) {
+		var (
+			repo           = newRepo()
+			spyMakePayment = newSucceedingMakePaymentMock()
+			useCase        = use_cases.NewSubmitPaymentBatch(repo, spyMakePayment, fundedIn(models.EUR), newEmptyMetricsClientMock())
+			instructions   = make(models.IncomingInstructions, use_cases.MaxPaymentBatchItems+1)
+		)
+
+		_, err := useCase.Submit(context.Background(), instructions, "")
+
+		var rejected models.PaymentBatchRejectedError
+		assert.True(t, errors.As(err, &rejected))
+		assert.Empty(t, repo.SavePaymentBatchCalls())
+		assert.Empty(t, spyMakePayment.ExecuteCalls())
+	})
+}
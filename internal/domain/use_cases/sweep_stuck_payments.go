@@ -0,0 +1,158 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+)
+
+const (
+	stuckPaymentInstructionMetricName         = "app_payment_instruction_stuck"
+	stuckPaymentInstructionResolvedMetricName = "app_payment_instruction_stuck_resolved"
+
+	// reasons a stuck payment instruction is flagged for someone to look into
+	stuckWithoutProviderPaymentID = "no_provider_payment_id"
+	stuckWithoutStatusChecker     = "no_status_checker"
+	stuckStatusCheckFailed        = "status_check_failed"
+	stuckPendingWithProvider      = "pending_with_payment_provider"
+	stuckOutcomeNotTracked        = "outcome_not_tracked"
+)
+
+type SweepStuckPayments struct {
+	repo                ports.StuckPaymentInstructionRepo
+	statusCheckers      map[models.PaymentProviderType]ports.PaymentProviderStatusChecker
+	thresholds          map[models.PaymentProviderType]time.Duration
+	trackPaymentOutcome ports.TrackPaymentOutcome
+	metricsClient       ports.MetricsClient
+	now                 func() time.Time
+}
+
+var _ ports.SweepStuckPayments = SweepStuckPayments{}
+
+// NewSweepStuckPayments sweeps the payment instructions of every payment provider with a threshold, a payment instruction
+// is stuck once it goes without any event for longer than the threshold of its payment provider.
+func NewSweepStuckPayments(
+	repo ports.StuckPaymentInstructionRepo,
+	statusCheckers map[models.PaymentProviderType]ports.PaymentProviderStatusChecker,
+	thresholds map[models.PaymentProviderType]time.Duration,
+	trackPaymentOutcome ports.TrackPaymentOutcome,
+	metricsClient ports.MetricsClient,
+) SweepStuckPayments {
+	return SweepStuckPayments{
+		repo:                repo,
+		statusCheckers:      statusCheckers,
+		thresholds:          thresholds,
+		trackPaymentOutcome: trackPaymentOutcome,
+		metricsClient:       metricsClient,
+		now:                 time.Now,
+	}
+}
+
+// Sweep tracks the outcome of every stuck payment instruction its payment provider has settled,
+// the others stay stuck and are flagged again on the next sweep.
+func (s SweepStuckPayments) Sweep(ctx context.Context) {
+	for _, provider := range s.providers() {
+		stuck, err := s.stuckOf(ctx, provider)
+		if err != nil {
+			zapctx.Error(ctx, "[SweepStuckPayments] (Sweep) error listing stuck payment instructions",
+				zap.String("payment_provider", string(provider)),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		for _, instruction := range stuck {
+			s.recheck(ctx, provider, instruction)
+		}
+	}
+}
+
+func (s SweepStuckPayments) List(ctx context.Context) ([]models.StuckPaymentInstruction, error) {
+	var all []models.StuckPaymentInstruction
+	for _, provider := range s.providers() {
+		stuck, err := s.stuckOf(ctx, provider)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, stuck...)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].StuckSince.Before(all[j].StuckSince)
+	})
+	return all, nil
+}
+
+func (s SweepStuckPayments) stuckOf(ctx context.Context, provider models.PaymentProviderType) ([]models.StuckPaymentInstruction, error) {
+	return s.repo.GetStuckPaymentInstructions(ctx, provider, models.InFlightStatuses, s.now().Add(-s.thresholds[provider]))
+}
+
+func (s SweepStuckPayments) providers() []models.PaymentProviderType {
+	providers := make([]models.PaymentProviderType, 0, len(s.thresholds))
+	for provider := range s.thresholds {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+	return providers
+}
+
+func (s SweepStuckPayments) recheck(ctx context.Context, provider models.PaymentProviderType, stuck models.StuckPaymentInstruction) {
+	if !stuck.CanBeRechecked() {
+		s.flag(ctx, provider, stuck, stuckWithoutProviderPaymentID, nil)
+		return
+	}
+
+	statusChecker, found := s.statusCheckers[provider]
+	if !found {
+		s.flag(ctx, provider, stuck, stuckWithoutStatusChecker, nil)
+		return
+	}
+
+	event, settled, err := statusChecker.CheckPaymentStatus(ctx, stuck)
+	if err != nil {
+		s.flag(ctx, provider, stuck, stuckStatusCheckFailed, err)
+		return
+	}
+	if !settled {
+		s.flag(ctx, provider, stuck, stuckPendingWithProvider, nil)
+		return
+	}
+
+	// a rejected status transition is recorded on the payment instruction already, and a concurrent update means its outcome has arrived
+	var (
+		illegalTransition models.IllegalTransitionError
+		conflict          models.VersionConflictError
+	)
+	if err := s.trackPaymentOutcome.Execute(ctx, event); err != nil && !errors.As(err, &illegalTransition) && !errors.As(err, &conflict) {
+		s.flag(ctx, provider, stuck, stuckOutcomeNotTracked, err)
+		return
+	}
+
+	zapctx.Info(ctx, "[SweepStuckPayments] (recheck) tracked the outcome of a stuck payment instruction",
+		zap.String("payment_instruction_id", string(stuck.PaymentInstruction.ID())),
+		zap.String("payment_provider", string(provider)),
+		zap.String("event_type", string(event.Type)),
+	)
+	s.metricsClient.Count(ctx, stuckPaymentInstructionResolvedMetricName, 1, []string{string(provider), string(event.Type)})
+}
+
+func (s SweepStuckPayments) flag(ctx context.Context, provider models.PaymentProviderType, stuck models.StuckPaymentInstruction, reason string, err error) {
+	zapctx.Warn(ctx, "[SweepStuckPayments] (flag) payment instruction is stuck waiting for its payment provider",
+		zap.String("payment_instruction_id", string(stuck.PaymentInstruction.ID())),
+		zap.String("contract_number", stuck.PaymentInstruction.ContractNumber()),
+		zap.String("payment_provider", string(provider)),
+		zap.String("status", string(stuck.PaymentInstruction.GetStatus())),
+		zap.String("provider_payment_id", string(stuck.ProviderPaymentID)),
+		zap.Time("stuck_since", stuck.StuckSince),
+		zap.String("reason", reason),
+		zap.Error(err),
+	)
+	s.metricsClient.Count(ctx, stuckPaymentInstructionMetricName, 1, []string{string(provider), reason})
+}
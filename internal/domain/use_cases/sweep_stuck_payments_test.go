@@ -0,0 +1,185 @@
+//go:build unit
+// +build unit
+
+package use_cases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saltpay/settlements-payments-system/internal/domain/models"
+	"github.com/saltpay/settlements-payments-system/internal/domain/models/testhelpers"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
+	"github.com/saltpay/settlements-payments-system/internal/domain/ports/mocks"
+	"github.com/saltpay/settlements-payments-system/internal/domain/use_cases"
+)
+
+func TestSweepStuckPayments(t *testing.T) {
+	stuckWith := func(provider models.PaymentProviderType, providerPaymentID models.ProviderPaymentID, stuckSince time.Time) models.StuckPaymentInstruction {
+		instruction := testhelpers.NewPaymentInstructionBuilder().WithPaymentProvider(provider).Build()
+		instruction.SubmitForProcessing()
+		return models.StuckPaymentInstruction{PaymentInstruction: instruction, ProviderPaymentID: providerPaymentID, StuckSince: stuckSince}
+	}
+
+	newRepo := func(stuck ...models.StuckPaymentInstruction) *mocks.StuckPaymentInstructionRepoMock {
+		return &mocks.StuckPaymentInstructionRepoMock{
+			GetStuckPaymentInstructionsFunc: func(ctx context.Context, provider models.PaymentProviderType, statuses []models.PaymentInstructionStatus, stuckBefore time.Time) ([]models.StuckPaymentInstruction, error) {
+				var ofProvider []models.StuckPaymentInstruction
+				for _, instruction := range stuck {
+					if instruction.PaymentInstruction.PaymentProvider() == provider {
+						ofProvider = append(ofProvider, instruction)
+					}
+				}
+				return ofProvider, nil
+			},
+		}
+	}
+
+	newTrackPaymentOutcome := func() *mocks.TrackPaymentOutcomeMock {
+		return &mocks.TrackPaymentOutcomeMock{ExecuteFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+			return nil
+		}}
+	}
+
+	// flagged returns the reason tag of every stuck payment instruction flagged
+	flagged := func(metricsClient *mocks.MetricsClientMock) []string {
+		var reasons []string
+		for _, call := range metricsClient.CountCalls() {
+			if call.Name == "app_payment_instruction_stuck" {
+				reasons = append(reasons, call.Tags[1])
+			}
+		}
+		return reasons
+	}
+
+	thresholds := map[models.PaymentProviderType]time.Duration{
+		models.BankingCircle: 2 * time.Hour,
+		models.Islandsbanki:  6 * time.Hour,
+	}
+
+	t.Run("looks for the payment instructions stuck for longer than the threshold of their payment provider", func(t *testing.T) {
+		repo := newRepo()
+		useCase := use_cases.NewSweepStuckPayments(repo, nil, thresholds, newTrackPaymentOutcome(), newEmptyMetricsClientMock())
+
+		before := time.Now()
+		useCase.Sweep(context.Background())
+
+		require.Len(t, repo.GetStuckPaymentInstructionsCalls(), 2)
+		for _, call := range repo.GetStuckPaymentInstructionsCalls() {
+			assert.Equal(t, models.InFlightStatuses, call.Statuses)
+			assert.WithinDuration(t, before.Add(-thresholds[call.Provider]), call.StuckBefore, time.Second)
+		}
+	})
+
+	t.Run("tracks the outcome the payment provider settled a stuck payment instruction with", func(t *testing.T) {
+		var (
+			stuck            = stuckWith(models.BankingCircle, "bc-payment-id", time.Now().Add(-3*time.Hour))
+			outcome          = models.PaymentProviderEvent{Type: models.Processed, PaymentInstruction: stuck.PaymentInstruction, PaymentProviderPaymentID: "bc-payment-id"}
+			spyTrack         = newTrackPaymentOutcome()
+			spyMetrics       = newEmptyMetricsClientMock()
+			spyBankingCircle = &mocks.PaymentProviderStatusCheckerMock{CheckPaymentStatusFunc: func(ctx context.Context, stuck models.StuckPaymentInstruction) (models.PaymentProviderEvent, bool, error) {
+				return outcome, true, nil
+			}}
+		)
+		useCase := use_cases.NewSweepStuckPayments(
+			newRepo(stuck),
+			map[models.PaymentProviderType]ports.PaymentProviderStatusChecker{models.BankingCircle: spyBankingCircle},
+			thresholds,
+			spyTrack,
+			spyMetrics,
+		)
+
+		useCase.Sweep(context.Background())
+
+		require.Len(t, spyBankingCircle.CheckPaymentStatusCalls(), 1)
+		assert.Equal(t, stuck, spyBankingCircle.CheckPaymentStatusCalls()[0].Stuck)
+		require.Len(t, spyTrack.ExecuteCalls(), 1)
+		assert.Equal(t, outcome, spyTrack.ExecuteCalls()[0].PpEvent)
+		assert.Empty(t, flagged(spyMetrics))
+	})
+
+	t.Run("flags the stuck payment instructions whose outcome can't be found out", func(t *testing.T) {
+		var (
+			unaccepted        = stuckWith(models.BankingCircle, "", time.Now().Add(-5*time.Hour))
+			pending           = stuckWith(models.BankingCircle, "pending-payment-id", time.Now().Add(-4*time.Hour))
+			unreachable       = stuckWith(models.BankingCircle, "unreachable-payment-id", time.Now().Add(-3*time.Hour))
+			islandsbanki      = stuckWith(models.Islandsbanki, "isb-payment-id", time.Now().Add(-7*time.Hour))
+			spyTrack          = newTrackPaymentOutcome()
+			spyMetrics        = newEmptyMetricsClientMock()
+			stubBankingCircle = &mocks.PaymentProviderStatusCheckerMock{CheckPaymentStatusFunc: func(ctx context.Context, stuck models.StuckPaymentInstruction) (models.PaymentProviderEvent, bool, error) {
+				if stuck.ProviderPaymentID == "unreachable-payment-id" {
+					return models.PaymentProviderEvent{}, false, errors.New("banking circle is down")
+				}
+				return models.PaymentProviderEvent{}, false, nil
+			}}
+		)
+		useCase := use_cases.NewSweepStuckPayments(
+			newRepo(unaccepted, pending, unreachable, islandsbanki),
+			map[models.PaymentProviderType]ports.PaymentProviderStatusChecker{models.BankingCircle: stubBankingCircle},
+			thresholds,
+			spyTrack,
+			spyMetrics,
+		)
+
+		useCase.Sweep(context.Background())
+
+		assert.Len(t, stubBankingCircle.CheckPaymentStatusCalls(), 2, "a payment instruction without the payment provider's payment ID can't be checked")
+		assert.Empty(t, spyTrack.ExecuteCalls())
+		assert.Equal(t, []string{"no_provider_payment_id", "pending_with_payment_provider", "status_check_failed", "no_status_checker"}, flagged(spyMetrics))
+	})
+
+	t.Run("flags the stuck payment instruction when its outcome can't be tracked", func(t *testing.T) {
+		var (
+			stuck      = stuckWith(models.BankingCircle, "bc-payment-id", time.Now().Add(-3*time.Hour))
+			spyMetrics = newEmptyMetricsClientMock()
+			stubTrack  = &mocks.TrackPaymentOutcomeMock{ExecuteFunc: func(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
+				return errors.New("db is down")
+			}}
+			stubBankingCircle = &mocks.PaymentProviderStatusCheckerMock{CheckPaymentStatusFunc: func(ctx context.Context, stuck models.StuckPaymentInstruction) (models.PaymentProviderEvent, bool, error) {
+				return models.PaymentProviderEvent{Type: models.Failure}, true, nil
+			}}
+		)
+		useCase := use_cases.NewSweepStuckPayments(
+			newRepo(stuck),
+			map[models.PaymentProviderType]ports.PaymentProviderStatusChecker{models.BankingCircle: stubBankingCircle},
+			thresholds,
+			stubTrack,
+			spyMetrics,
+		)
+
+		useCase.Sweep(context.Background())
+
+		assert.Equal(t, []string{"outcome_not_tracked"}, flagged(spyMetrics))
+	})
+
+	t.Run("lists the stuck payment instructions of every payment provider, the longest stuck first", func(t *testing.T) {
+		var (
+			bankingCircle = stuckWith(models.BankingCircle, "bc-payment-id", time.Now().Add(-3*time.Hour))
+			islandsbanki  = stuckWith(models.Islandsbanki, "", time.Now().Add(-7*time.Hour))
+		)
+		useCase := use_cases.NewSweepStuckPayments(newRepo(bankingCircle, islandsbanki), nil, thresholds, newTrackPaymentOutcome(), newEmptyMetricsClientMock())
+
+		stuck, err := useCase.List(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.StuckPaymentInstruction{islandsbanki, bankingCircle}, stuck)
+	})
+
+	t.Run("returns the error when the stuck payment instructions can't be read", func(t *testing.T) {
+		failingRepo := &mocks.StuckPaymentInstructionRepoMock{
+			GetStuckPaymentInstructionsFunc: func(ctx context.Context, provider models.PaymentProviderType, statuses []models.PaymentInstructionStatus, stuckBefore time.Time) ([]models.StuckPaymentInstruction, error) {
+				return nil, errors.New("db is down")
+			},
+		}
+		useCase := use_cases.NewSweepStuckPayments(failingRepo, nil, thresholds, newTrackPaymentOutcome(), newEmptyMetricsClientMock())
+
+		_, err := useCase.List(context.Background())
+
+		assert.EqualError(t, err, "db is down")
+	})
+}
@@ -2,6 +2,10 @@ package use_cases
 
 import (
 	"context"
+	"errors"
+
+	zapctx "github.com/saltpay/go-zap-ctx"
+	"go.uber.org/zap"
 
 	"github.com/saltpay/settlements-payments-system/internal/domain/models"
 	"github.com/saltpay/settlements-payments-system/internal/domain/ports"
@@ -12,34 +16,80 @@ type TrackPaymentOutcome struct {
 	paymentInstructionRepo  ports.StorePaymentInstructionToRepo
 	paymentExporterProducer ports.PaymentExporterProducer
 	eventValidator          validation.PPEventValidator
+	metrics                 ports.MetricsClient
+	liquidityLedger         ports.LiquidityLedger
 }
 
 func NewTrackPaymentOutcome(
 	repo ports.StorePaymentInstructionToRepo,
 	eventValidator validation.PPEventValidator,
 	acquiringHostProducer ports.PaymentExporterProducer,
+	metrics ports.MetricsClient,
 ) TrackPaymentOutcome {
 	return TrackPaymentOutcome{
 		paymentInstructionRepo:  repo,
 		eventValidator:          eventValidator,
 		paymentExporterProducer: acquiringHostProducer,
+		metrics:                 metrics,
 	}
 }
 
+// ReleaseFundsWith releases the funds reserved by a payment instruction once its payment provider reports its outcome.
+func (u *TrackPaymentOutcome) ReleaseFundsWith(liquidityLedger ports.LiquidityLedger) {
+	u.liquidityLedger = liquidityLedger
+}
+
 func (u TrackPaymentOutcome) Execute(ctx context.Context, ppEvent models.PaymentProviderEvent) error {
 	if err := u.eventValidator.Validate(ppEvent); err != nil {
 		return err
 	}
 
 	pi := &ppEvent.PaymentInstruction
-	pi.TrackPPEvent(ppEvent)
+	if pi.GetStatus() == ppEvent.OutcomeStatus() {
+		// a redelivered outcome, it was tracked already
+		return nil
+	}
+
+	var illegalTransition models.IllegalTransitionError
+	if errors.As(pi.ValidateTransition(ppEvent.OutcomeStatus()), &illegalTransition) {
+		if ppEvent.Type == models.Submitted {
+			// the outcome of the payment overtook its acceptance, there is nothing left to track
+			return nil
+		}
+		return rejectTransition(ctx, u.paymentInstructionRepo, u.metrics, *pi, illegalTransition)
+	}
+
+	expectedVersion := pi.Version()
+	if err := pi.TrackPPEvent(ppEvent); err != nil {
+		return err
+	}
 
 	lastEvent := pi.Events()[len(pi.Events())-1]
 
-	err := u.paymentInstructionRepo.UpdatePayment(ctx, pi.ID(), pi.GetStatus(), lastEvent)
+	err := u.paymentInstructionRepo.UpdatePayment(ctx, pi.ID(), expectedVersion, pi.GetStatus(), lastEvent)
 	if err != nil {
 		return err
 	}
 
+	// the acquiring host is only interested in the outcome of the payment
+	if ppEvent.Type == models.Submitted {
+		return nil
+	}
+
+	u.releaseFunds(ctx, pi.ID())
+
 	return u.paymentExporterProducer.ReportPaymentStatus(ctx, ppEvent)
 }
+
+// releaseFunds leaves a reservation it couldn't release outstanding, the outcome is tracked regardless.
+func (u TrackPaymentOutcome) releaseFunds(ctx context.Context, id models.PaymentInstructionID) {
+	if u.liquidityLedger == nil {
+		return
+	}
+	if err := u.liquidityLedger.Release(ctx, id); err != nil {
+		zapctx.Error(ctx, "[TrackPaymentOutcome] (releaseFunds) unable to release the funds of the payment instruction",
+			zap.String("id", string(id)),
+			zap.Error(err),
+		)
+	}
+}
@@ -2,6 +2,7 @@ package use_cases
 
 import (
 	"context"
+	"errors"
 
 	zapctx "github.com/saltpay/go-zap-ctx"
 
@@ -13,18 +14,41 @@ import (
 
 type UpdatePaymentState struct {
 	paymentStore ports.StorePaymentInstructionToRepo
+	paymentRepo  ports.GetPaymentInstructionFromRepo
 	metrics      ports.MetricsClient
 }
 
-func NewUpdatePaymentState(paymentStore ports.StorePaymentInstructionToRepo, metrics ports.MetricsClient) *UpdatePaymentState {
+func NewUpdatePaymentState(paymentStore ports.StorePaymentInstructionToRepo, paymentRepo ports.GetPaymentInstructionFromRepo, metrics ports.MetricsClient) *UpdatePaymentState {
 	return &UpdatePaymentState{
 		paymentStore: paymentStore,
+		paymentRepo:  paymentRepo,
 		metrics:      metrics,
 	}
 }
 
+// Execute applies the state update on top of the current version of the payment instruction,
+// it fails with a models.VersionConflictError if the payment instruction changes in the meantime.
+// A state the payment instruction already has changes nothing, a state it can't move to is recorded as a rejected transition and returned as a models.IllegalTransitionError.
 func (tps *UpdatePaymentState) Execute(ctx context.Context, paymentInstructionID string, state models.PaymentInstructionStatus, event models.PaymentInstructionEvent) error {
-	err := tps.paymentStore.UpdatePayment(ctx, models.PaymentInstructionID(paymentInstructionID), state, event)
+	id := models.PaymentInstructionID(paymentInstructionID)
+	current, err := tps.paymentRepo.Get(ctx, id)
+	if err != nil {
+		zapctx.Error(ctx, "Unable to read payment to update its state", zap.Error(err), zap.String("paymentInstructionID", paymentInstructionID))
+		return err
+	}
+
+	if current.GetStatus() == state {
+		// a redelivered state update, the payment instruction has the state already
+		zapctx.Info(ctx, "payment instruction already has the state", zap.String("paymentInstructionID", paymentInstructionID), zap.String("state", string(state)))
+		return nil
+	}
+
+	var illegalTransition models.IllegalTransitionError
+	if errors.As(current.ValidateTransition(state), &illegalTransition) {
+		return rejectTransition(ctx, tps.paymentStore, tps.metrics, current, illegalTransition)
+	}
+
+	err = tps.paymentStore.UpdatePayment(ctx, id, current.Version(), state, event)
 	if err != nil {
 		zapctx.Error(ctx, "Unable to execute update payment state", zap.Error(err), zap.String("paymentInstructionID", paymentInstructionID), zap.String("state", string(state)))
 		return err
@@ -23,21 +23,13 @@ func (prv IncomingInstructionValidator) ValidateIncomingInstruction(incomingInst
 	merchantCheck := validateMerchant(incomingInstruction.Merchant)
 	metadataCheck := validateMetadata(incomingInstruction.Metadata)
 	paymentCheck := validatePayment(incomingInstruction.Payment)
-	senderContractCheck := validateSenderContract(incomingInstruction)
 
 	// TODO: technical-debt, should not depend on Sender at this level
 	if models.IsISLSender(incomingInstruction.Metadata.Sender) {
 		merchantCheck = validateIslMerchant(incomingInstruction.Merchant)
 	}
 
-	return mergeValidationErrors(merchantCheck, metadataCheck, paymentCheck, senderContractCheck)
-}
-
-func validateSenderContract(incomingInstruction models.IncomingInstruction) IncomingInstructionValidationResult {
-	if models.IsSaxoSender(incomingInstruction.Metadata.Sender) && incomingInstruction.Payment.Currency.IsoCode == models.ISK {
-		return Invalid("SAXO files should not contain ISK currencies")
-	}
-	return Valid
+	return mergeValidationErrors(merchantCheck, metadataCheck, paymentCheck)
 }
 
 func validateAccount(account models.Account) IncomingInstructionValidationResult {
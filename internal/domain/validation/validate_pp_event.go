@@ -26,7 +26,7 @@ func ValidatePaymentProviderEvent(event paymentproviderevents.PaymentProviderEve
 	}
 
 	switch event.Type {
-	case paymentproviderevents.Processed:
+	case paymentproviderevents.Submitted, paymentproviderevents.Processed, paymentproviderevents.Reversal:
 		if event.PaymentProviderPaymentID == "" {
 			missingFields = append(missingFields, "PaymentProviderPaymentID")
 		}
@@ -41,6 +41,28 @@ func TestValidatePaymentProviderEvent(t *testing.T) {
 					FailureReason:            models.FailureReason{},
 				},
 			},
+			{
+				Name: "invalid submitted payments: missing payment provider id",
+				Event: models.PaymentProviderEvent{
+					CreatedOn:                time.Time{},
+					Type:                     models.Submitted,
+					PaymentInstruction:       models.PaymentInstruction{},
+					PaymentProviderName:      "bc",
+					PaymentProviderPaymentID: "",
+					FailureReason:            models.FailureReason{},
+				},
+			},
+			{
+				Name: "invalid reversed payments: missing payment provider id",
+				Event: models.PaymentProviderEvent{
+					CreatedOn:                time.Time{},
+					Type:                     models.Reversal,
+					PaymentInstruction:       models.PaymentInstruction{},
+					PaymentProviderName:      "bc",
+					PaymentProviderPaymentID: "",
+					FailureReason:            models.FailureReason{},
+				},
+			},
 			{
 				Name: "invalid failed payments: missing failure reason",
 				Event: models.PaymentProviderEvent{
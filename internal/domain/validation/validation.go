@@ -32,6 +32,11 @@ func Invalid(reasons ...string) IncomingInstructionValidationResult {
 	return IncomingInstructionValidationResult{success: false, errors: reasons}
 }
 
+// Merge has the errors of both results, e.g. to add the rejection of a routing rule to a validation.
+func (v IncomingInstructionValidationResult) Merge(other IncomingInstructionValidationResult) IncomingInstructionValidationResult {
+	return mergeValidationErrors(v, other)
+}
+
 func mergeValidationErrors(results ...IncomingInstructionValidationResult) IncomingInstructionValidationResult {
 	errors := make([]string, 0)
 	for _, result := range results {
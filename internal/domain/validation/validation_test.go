@@ -159,17 +159,6 @@ func TestIncomingInstructionValidator_ValidateIncomingInstruction(t *testing.T)
 			assertAValidationError(t, validationResult)
 		})
 
-		t.Run("ISK payment in SAXO file", func(t *testing.T) {
-			incomingInstruction := testhelpers.NewIncomingInstructionBuilder().WithMetadataSender("SAXO").WithPaymentCurrencyISOCode(models.ISK).Build()
-
-			validationResult := prv.ValidateIncomingInstruction(incomingInstruction)
-
-			assert.NotNil(t, validationResult)
-			assert.True(t, validationResult.Failed())
-			assert.Len(t, validationResult.GetErrors(), 1)
-			assert.Equal(t, "SAXO files should not contain ISK currencies", validationResult.GetErrors()[0])
-		})
-
 		t.Run("a few errors", func(t *testing.T) {
 			is := is.New(t)
 